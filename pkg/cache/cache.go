@@ -12,6 +12,10 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// RetryPolicy is the retry policy name Connect looks up before falling back
+// to its own backoff settings.
+const RetryPolicy = "redis"
+
 var (
 	ErrConnectionFailed = fault.New(
 		"redis connection failed after retries",
@@ -82,16 +86,16 @@ func (c *Cache) Connect(ctx context.Context) error {
 		return ErrAlreadyConnected
 	}
 
+	retryConfig := c.getRetryConfig()
+	retryConfig.Logger = c.logger
+
 	c.logger.InfoContext(ctx, "Connecting to Redis",
 		"host", c.config.GetHost(),
 		"port", c.config.GetPort(),
 		"db", c.config.GetDB(),
-		"max_retries", c.config.GetBackoffRetries(),
+		"max_retries", retryConfig.MaxAttempts,
 	)
 
-	retryConfig := c.getRetryConfig()
-	retryConfig.Logger = c.logger
-
 	err := retry.Do(ctx, retryConfig, func(ctx context.Context) error {
 		return c.connect(ctx)
 	})
@@ -173,8 +177,13 @@ func (c *Cache) Close() error {
 	return nil
 }
 
-// getRetryConfig converts the config to a retry.Config
+// getRetryConfig returns the RetryPolicy registered with the retry package,
+// or one built from the CACHE_REDIS_CONNECT_BACKOFF_* settings.
 func (c *Cache) getRetryConfig() *retry.Config {
+	if policy, err := retry.Policy(RetryPolicy); err == nil {
+		return policy
+	}
+
 	strategy := retry.NewExponentialBackoff(retry.ExponentialBackoffConfig{
 		Min:    c.config.GetBackoffMin(),
 		Max:    c.config.GetBackoffMax(),
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrIncompatible is returned by CheckVersion when the server is older than
+// the service requires.
+var ErrIncompatible = fault.New(
+	"redis does not meet the service requirements",
+	fault.WithCode(fault.InfraError),
+)
+
+// CheckVersion fails with ErrIncompatible when the server is older than
+// minVersion (e.g. "7" or "6.2.5"). Run it at startup so a command the
+// server does not support fails the deploy instead of a request.
+func (c *Cache) CheckVersion(ctx context.Context, minVersion string) error {
+	if c.client == nil {
+		return ErrNotConnected
+	}
+
+	want, err := parseVersion(minVersion)
+	if err != nil {
+		return fault.Wrap(ErrInvalidConfig, err.Error())
+	}
+
+	info, err := c.client.Info(ctx, "server").Result()
+	if err != nil {
+		return fault.Wrap(ErrOperationFailed, "info failed",
+			fault.WithWrappedErr(err),
+		)
+	}
+
+	raw := infoField(info, "redis_version")
+	got, err := parseVersion(raw)
+	if err != nil {
+		return fault.Wrap(ErrOperationFailed, err.Error())
+	}
+
+	if compareVersions(got, want) < 0 {
+		return fault.Wrap(ErrIncompatible,
+			fmt.Sprintf("redis %s is older than the required %s", raw, minVersion),
+			fault.WithContext("version", raw),
+			fault.WithContext("min_version", minVersion),
+		)
+	}
+
+	return nil
+}
+
+// infoField returns the value of key in an INFO reply.
+func infoField(info, key string) string {
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), key+":"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// parseVersion parses "7", "7.2" or "7.2.4" into major, minor and patch.
+func parseVersion(version string) ([3]int, error) {
+	var nums [3]int
+
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) > 3 {
+		return nums, fmt.Errorf("invalid redis version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nums, fmt.Errorf("invalid redis version %q", version)
+		}
+		nums[i] = n
+	}
+
+	return nums, nil
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
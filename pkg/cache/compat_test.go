@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"7.2.4", "7", 1},
+		{"6.2.5", "6.2.5", 0},
+		{"6.0.16", "6.2", -1},
+		{"7.10.0", "7.9", 1},
+	}
+
+	for _, tt := range tests {
+		a, err := parseVersion(tt.a)
+		if err != nil {
+			t.Fatalf("parseVersion(%q) error = %v", tt.a, err)
+		}
+		b, err := parseVersion(tt.b)
+		if err != nil {
+			t.Fatalf("parseVersion(%q) error = %v", tt.b, err)
+		}
+		if got := compareVersions(a, b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := parseVersion("seven"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestInfoField(t *testing.T) {
+	info := "# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n"
+
+	if got := infoField(info, "redis_version"); got != "7.2.4" {
+		t.Errorf("expected 7.2.4, got %q", got)
+	}
+	if got := infoField(info, "missing"); got != "" {
+		t.Errorf("expected empty value, got %q", got)
+	}
+}
+
+func TestCheckVersionNotConnected(t *testing.T) {
+	c, err := New(&Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := c.CheckVersion(context.Background(), "7"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
@@ -2,11 +2,9 @@ package cache
 
 import (
 "fmt"
-"os"
-"path/filepath"
-"strings"
 "time"
 
+"github.com/marcelofabianov/config"
 "github.com/marcelofabianov/retry"
 "github.com/spf13/viper"
 )
@@ -61,17 +59,21 @@ DB       int
 
 var _ ConfigProvider = (*Config)(nil)
 
+// LoadConfig loads the CACHE_* settings with config.Load. Services that load
+// several packages should call config.Load once and use LoadConfigFrom.
 func LoadConfig() (*Config, error) {
-v := viper.New()
-v.SetEnvPrefix("CACHE")
-v.AutomaticEnv()
-v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-if envFile := findEnvFile(); envFile != "" {
-v.SetConfigFile(envFile)
-_ = v.ReadInConfig()
+c, err := config.Load()
+if err != nil {
+return nil, err
+}
+return LoadConfigFrom(c)
 }
 
+// LoadConfigFrom loads the cache section of c: CACHE_* variables over the
+// cache section of the config file.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+v := c.Section("cache", "CACHE")
+
 setDefaults(v)
 
 cfg := &Config{
@@ -121,27 +123,6 @@ v.SetDefault("redis.pool.max_idle_conns", 10)
 v.SetDefault("redis.pool.max_active_conns", 20)
 }
 
-func findEnvFile() string {
-dir, err := os.Getwd()
-if err != nil {
-return ""
-}
-
-for i := 0; i < 5; i++ {
-envPath := filepath.Join(dir, ".env")
-if _, err := os.Stat(envPath); err == nil {
-return envPath
-}
-parent := filepath.Dir(dir)
-if parent == dir {
-break
-}
-dir = parent
-}
-
-return ""
-}
-
 func validateConfig(cfg *Config) error {
 if cfg.Redis.Credentials.Host == "" {
 return fmt.Errorf("redis host cannot be empty")
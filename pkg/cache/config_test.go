@@ -2,6 +2,7 @@ package cache_test
 
 import (
 "os"
+"path/filepath"
 "testing"
 "time"
 
@@ -173,3 +174,40 @@ if retryConfig.Strategy == nil {
 t.Error("Strategy should not be nil")
 }
 }
+
+func TestLoadConfigFile(t *testing.T) {
+path := filepath.Join(t.TempDir(), "config.yaml")
+err := os.WriteFile(path, []byte(`
+cache:
+  redis:
+    host: redis.local
+    pool:
+      max_active_conns: 20
+profiles:
+  staging:
+    cache:
+      redis:
+        host: redis.staging
+`), 0o600)
+if err != nil {
+t.Fatal(err)
+}
+t.Setenv("CONFIG_FILE", path)
+t.Setenv("CONFIG_PROFILE", "staging")
+t.Setenv("CACHE_REDIS_DB", "3")
+
+cfg, err := cache.LoadConfig()
+if err != nil {
+t.Fatalf("LoadConfig() error = %v", err)
+}
+
+if cfg.GetHost() != "redis.staging" {
+t.Errorf("expected the staging profile host, got %s", cfg.GetHost())
+}
+if cfg.GetMaxActiveConns() != 20 {
+t.Errorf("expected pool size from the base section, got %d", cfg.GetMaxActiveConns())
+}
+if cfg.GetDB() != 3 {
+t.Errorf("expected CACHE_REDIS_DB to apply, got %d", cfg.GetDB())
+}
+}
@@ -3,6 +3,7 @@ module github.com/marcelofabianov/cache
 go 1.25.1
 
 require (
+	github.com/marcelofabianov/config v0.0.0
 	github.com/marcelofabianov/fault v1.5.0
 	github.com/marcelofabianov/retry v0.0.0
 	github.com/redis/go-redis/v9 v9.17.3
@@ -34,3 +35,5 @@ require (
 )
 
 replace github.com/marcelofabianov/retry => ../retry
+
+replace github.com/marcelofabianov/config => ../config
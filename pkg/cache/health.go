@@ -0,0 +1,30 @@
+package cache
+
+import "context"
+
+// HealthChecker adapts a Cache to the web.HealthChecker interface so it can
+// be passed straight to web.ReadinessHandler.
+type HealthChecker struct {
+	cache *Cache
+	name  string
+}
+
+// NewHealthChecker returns a checker named "cache". Use WithName to tell
+// several Redis instances apart in the readiness response.
+func NewHealthChecker(c *Cache) *HealthChecker {
+	return &HealthChecker{cache: c, name: "cache"}
+}
+
+// WithName sets the name reported in the readiness response.
+func (h *HealthChecker) WithName(name string) *HealthChecker {
+	h.name = name
+	return h
+}
+
+func (h *HealthChecker) Name() string {
+	return h.name
+}
+
+func (h *HealthChecker) Check(ctx context.Context) error {
+	return h.cache.HealthCheck(ctx)
+}
@@ -0,0 +1,25 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcelofabianov/cache"
+)
+
+func TestHealthChecker(t *testing.T) {
+	c, err := cache.New(&cache.Config{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	checker := cache.NewHealthChecker(c)
+	if checker.Name() != "cache" {
+		t.Errorf("expected name cache, got %s", checker.Name())
+	}
+
+	if err := checker.Check(context.Background()); !errors.Is(err, cache.ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
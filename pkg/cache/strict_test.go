@@ -0,0 +1,31 @@
+package cache_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/cache"
+)
+
+func TestLoadConfigStrict(t *testing.T) {
+	t.Run("accepts known variables", func(t *testing.T) {
+		t.Setenv("CACHE_REDIS_PORT", "6380")
+
+		if _, err := cache.LoadConfigStrict(); err != nil {
+			t.Fatalf("LoadConfigStrict() error = %v", err)
+		}
+	})
+
+	t.Run("rejects misspelled variables", func(t *testing.T) {
+		t.Setenv("CACHE_REDIS_PORTT", "6380")
+
+		_, err := cache.LoadConfigStrict()
+		if !errors.Is(err, cache.ErrUnknownEnv) {
+			t.Fatalf("expected ErrUnknownEnv, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "did you mean CACHE_REDIS_PORT?") {
+			t.Errorf("expected a suggestion in %q", err.Error())
+		}
+	})
+}
@@ -0,0 +1,231 @@
+// Package config loads the configuration of an application once, the .env
+// file, the YAML/TOML config file and its active profile, and hands each
+// package its section, so every LoadConfig reads the same sources the same
+// way.
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/spf13/viper"
+)
+
+const (
+	// FileEnv names the config file, skipping the lookup.
+	FileEnv = "CONFIG_FILE"
+	// ProfileEnv selects the profiles.<name> overrides of the config file.
+	ProfileEnv = "CONFIG_PROFILE"
+	// DefaultProfile is the profile used when ProfileEnv is not set.
+	DefaultProfile = "development"
+)
+
+var fileNames = []string{"config.yaml", "config.yml", "config.toml"}
+
+// ErrInvalidFile is returned by Load when the .env or config file cannot be
+// read or parsed.
+var ErrInvalidFile = fault.New(
+	"invalid config file",
+	fault.WithCode(fault.Invalid),
+)
+
+// Config is the loaded configuration of one application. Load it once in
+// main and pass it to the LoadConfigFrom of each package.
+type Config struct {
+	envFile string
+	path    string
+	profile string
+
+	mu    sync.RWMutex
+	file  *viper.Viper
+	stamp fileStamp
+
+	secrets     *Secrets
+	bindings    []secretBinding
+	rotateHooks []func(name, value string)
+
+	// reloadMu serializes Reload and the WatchSecrets refreshes.
+	reloadMu    sync.Mutex
+	changeHooks []func(Change)
+	errorHooks  []func(error)
+}
+
+// Option configures Load.
+type Option func(*Config)
+
+// WithSecrets makes Load resolve secret references, in the environment and
+// in the config file, with s. See Secrets.
+func WithSecrets(s *Secrets) Option {
+	return func(c *Config) {
+		c.secrets = s
+	}
+}
+
+// Load exports the variables of the .env file found in the working
+// directory or up to 5 parents, keeping those already set, then reads the
+// config file: CONFIG_FILE, or the first config.yaml, config.yml or
+// config.toml found the same way. Both are optional. With WithSecrets,
+// secret references in the environment and the config file are then
+// resolved: Section reads their values, and the environment keeps the
+// references.
+//
+//	cfg, err := config.Load()
+//	webCfg, err := web.LoadConfigFrom(cfg)
+//	dbCfg, err := database.LoadConfigFrom(cfg)
+func Load(opts ...Option) (*Config, error) {
+	c := &Config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if envFile := findFile(".env"); envFile != "" {
+		if err := exportEnvFile(envFile); err != nil {
+			return nil, err
+		}
+		c.envFile = envFile
+	}
+
+	// Read after the .env export, so .env can select the file and profile.
+	c.profile = os.Getenv(ProfileEnv)
+	if c.profile == "" {
+		c.profile = DefaultProfile
+	}
+
+	c.path = os.Getenv(FileEnv)
+	if c.path == "" {
+		c.path = findFile(fileNames...)
+	}
+	if c.path != "" {
+		// A failed stat only makes the first Watch tick reload the file.
+		c.stamp, _ = statFile(c.path)
+		file, err := readFile(c.path)
+		if err != nil {
+			return nil, err
+		}
+		c.file = file
+	}
+
+	if c.secrets != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.secrets.timeout())
+		defer cancel()
+		if err := c.resolveSecrets(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Section returns the settings of one package: a viper reading
+// <envPrefix>_* variables over the name section of the config file, itself
+// overlaid by profiles.<profile>.<name>. Set the defaults on it and read
+// the values as usual. Nested names such as "retry.policies.redis" select
+// nested sections. Variables that held secret references read as their
+// current values. A nil Config reads the environment only.
+func (c *Config) Section(name, envPrefix string) *viper.Viper {
+	v := viper.New()
+	v.SetEnvPrefix(envPrefix)
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	if c == nil {
+		return v
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	overrideEnvSecrets(v, envPrefix, c.bindings)
+	if c.file == nil {
+		return v
+	}
+	for _, key := range []string{name, "profiles." + c.profile + "." + name} {
+		if settings := c.file.GetStringMap(key); len(settings) > 0 {
+			// MergeConfigMap cannot fail for a map.
+			_ = v.MergeConfigMap(settings)
+		}
+	}
+	return v
+}
+
+// Profile returns the active profile.
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// File returns the path of the config file read, or "" without one.
+func (c *Config) File() string {
+	return c.path
+}
+
+// EnvFile returns the path of the .env file exported, or "" without one.
+func (c *Config) EnvFile() string {
+	return c.envFile
+}
+
+func readFile(path string) (*viper.Viper, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fault.Wrap(ErrInvalidFile, err.Error(),
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("path", path),
+		)
+	}
+	return file, nil
+}
+
+// exportEnvFile sets the variables of path that are not set yet, like
+// devsecrets does for .env.enc.
+func exportEnvFile(path string) error {
+	file := viper.New()
+	file.SetConfigFile(path)
+	file.SetConfigType("env")
+	if err := file.ReadInConfig(); err != nil {
+		return fault.Wrap(ErrInvalidFile, err.Error(),
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("path", path),
+		)
+	}
+
+	for _, key := range file.AllKeys() {
+		name := strings.ToUpper(key)
+		if _, set := os.LookupEnv(name); set {
+			continue
+		}
+		if err := os.Setenv(name, file.GetString(key)); err != nil {
+			return fault.Wrap(err, "failed to export .env variable",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("name", name),
+			)
+		}
+	}
+	return nil
+}
+
+// findFile returns the first of names found in the working directory or up
+// to 5 parents.
+func findFile(names ...string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for i := 0; i < 5; i++ {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return ""
+}
@@ -0,0 +1,134 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcelofabianov/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("without files", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		t.Setenv(config.FileEnv, "")
+		t.Setenv(config.ProfileEnv, "")
+
+		cfg, err := config.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.File() != "" || cfg.EnvFile() != "" {
+			t.Errorf("expected no files, got %q and %q", cfg.File(), cfg.EnvFile())
+		}
+		if cfg.Profile() != config.DefaultProfile {
+			t.Errorf("expected profile %s, got %s", config.DefaultProfile, cfg.Profile())
+		}
+
+		v := cfg.Section("web", "WEB")
+		v.SetDefault("http.port", 8080)
+		if got := v.GetInt("http.port"); got != 8080 {
+			t.Errorf("expected the default port, got %d", got)
+		}
+	})
+
+	t.Run("exports .env without overriding the environment", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, ".env"), "CONFIGTEST_LEVEL=debug\nCONFIGTEST_NAME=from-file\n")
+		t.Chdir(dir)
+		t.Setenv(config.FileEnv, "")
+		t.Setenv("CONFIGTEST_NAME", "from-env")
+		// Unset, but restored after the test since Load exports it.
+		t.Setenv("CONFIGTEST_LEVEL", "")
+		os.Unsetenv("CONFIGTEST_LEVEL")
+
+		cfg, err := config.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.EnvFile() != filepath.Join(dir, ".env") {
+			t.Errorf("expected the .env of %s, got %q", dir, cfg.EnvFile())
+		}
+
+		v := cfg.Section("configtest", "CONFIGTEST")
+		if got := v.GetString("level"); got != "debug" {
+			t.Errorf("expected level from .env, got %q", got)
+		}
+		if got := v.GetString("name"); got != "from-env" {
+			t.Errorf("expected the environment to win over .env, got %q", got)
+		}
+	})
+
+	t.Run("invalid config file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		writeFile(t, path, "web: [unclosed")
+		t.Setenv(config.FileEnv, path)
+
+		if _, err := config.Load(); !errors.Is(err, config.ErrInvalidFile) {
+			t.Errorf("expected ErrInvalidFile, got %v", err)
+		}
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		t.Setenv(config.FileEnv, filepath.Join(t.TempDir(), "missing.toml"))
+
+		if _, err := config.Load(); !errors.Is(err, config.ErrInvalidFile) {
+			t.Errorf("expected ErrInvalidFile, got %v", err)
+		}
+	})
+}
+
+func TestSection(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), `
+web:
+  http:
+    port: 9000
+    host: 0.0.0.0
+retry:
+  max_attempts: 3
+  policies:
+    redis:
+      max_attempts: 5
+profiles:
+  production:
+    web:
+      http:
+        port: 443
+`)
+	t.Chdir(dir)
+	t.Setenv(config.FileEnv, "")
+	t.Setenv(config.ProfileEnv, "production")
+	t.Setenv("WEB_HTTP_HOST", "127.0.0.1")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.File() != filepath.Join(dir, "config.yaml") {
+		t.Errorf("expected the config.yaml of %s, got %q", dir, cfg.File())
+	}
+
+	web := cfg.Section("web", "WEB")
+	if got := web.GetInt("http.port"); got != 443 {
+		t.Errorf("expected the production port 443, got %d", got)
+	}
+	if got := web.GetString("http.host"); got != "127.0.0.1" {
+		t.Errorf("expected WEB_HTTP_HOST to win over the file, got %q", got)
+	}
+
+	if got := cfg.Section("retry.policies.redis", "RETRY_REDIS").GetInt("max_attempts"); got != 5 {
+		t.Errorf("expected the nested policy section, got %d", got)
+	}
+	if got := cfg.Section("cache", "CACHE").AllKeys(); len(got) != 0 {
+		t.Errorf("expected an empty section, got %v", got)
+	}
+}
@@ -0,0 +1,479 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultSecretTTL is how long a fetched secret is reused.
+	DefaultSecretTTL = 5 * time.Minute
+	// DefaultSecretTimeout bounds the resolution of every reference in Load.
+	DefaultSecretTimeout = 10 * time.Second
+)
+
+// secretClient sends the requests of the built-in providers configured
+// without a Client. Its timeout bounds each call even when the context has
+// no deadline, as in WatchSecrets.
+var secretClient = &http.Client{Timeout: DefaultSecretTimeout}
+
+var (
+	ErrInvalidSecretRef = fault.New(
+		"invalid secret reference",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrSecretNotFound = fault.New(
+		"secret not found",
+		fault.WithCode(fault.NotFound),
+	)
+
+	ErrSecretBackend = fault.New(
+		"secret backend failed",
+		fault.WithCode(fault.InfraError),
+	)
+)
+
+// SecretProvider fetches secrets from one backend. Secret returns the
+// fields of the secret at path; a secret holding a single value returns it
+// under the "" field.
+type SecretProvider interface {
+	Secret(ctx context.Context, path string) (map[string]string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider.
+type SecretProviderFunc func(ctx context.Context, path string) (map[string]string, error)
+
+func (f SecretProviderFunc) Secret(ctx context.Context, path string) (map[string]string, error) {
+	return f(ctx, path)
+}
+
+// Secrets resolves secret references: values written as
+// <provider>://<path>#<field>, such as vault://secret/course/db#password.
+// The field may be left out for secrets with a single one. A fetched secret
+// is reused for TTL, so the fields of one secret cost one call.
+//
+// NewSecrets registers the built-in providers under "vault", "awssm" and
+// "sops"; a zero Secrets has none. The built-in providers read their
+// settings from the environment when first used, so the .env file exported
+// by Load can hold them.
+type Secrets struct {
+	// TTL is how long a fetched secret is reused; zero uses
+	// DefaultSecretTTL.
+	TTL time.Duration
+	// Timeout bounds the resolution in Load; zero uses
+	// DefaultSecretTimeout.
+	Timeout time.Duration
+	// OnError, if set, receives the failures of WatchSecrets refreshes,
+	// which keep the previous value.
+	OnError func(err error)
+
+	mu        sync.Mutex
+	providers map[string]SecretProvider
+	cache     map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	fields  map[string]string
+	fetched time.Time
+}
+
+// NewSecrets returns Secrets with the built-in providers registered.
+func NewSecrets() *Secrets {
+	s := &Secrets{
+		providers: map[string]SecretProvider{},
+		cache:     map[string]cachedSecret{},
+	}
+	s.Register("vault", NewVaultProvider(VaultConfig{}))
+	s.Register("awssm", NewAWSSecretsProvider(AWSSecretsConfig{}))
+	s.Register("sops", NewSOPSProvider(SOPSConfig{}))
+	return s
+}
+
+// Register makes references with the scheme name resolve through provider,
+// replacing any provider registered under it.
+func (s *Secrets) Register(name string, provider SecretProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.providers == nil {
+		s.providers = map[string]SecretProvider{}
+	}
+	s.providers[name] = provider
+}
+
+// IsRef reports whether value is a reference to a registered provider.
+func (s *Secrets) IsRef(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, registered := s.providers[scheme]
+	return registered
+}
+
+// Resolve returns the secret value references, or value itself when it is
+// not a reference.
+func (s *Secrets) Resolve(ctx context.Context, value string) (string, error) {
+	if !s.IsRef(value) {
+		return value, nil
+	}
+
+	scheme, rest, _ := strings.Cut(value, "://")
+	path, field, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return "", fault.Wrap(ErrInvalidSecretRef, "expected <provider>://<path>#<field>",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("provider", scheme),
+		)
+	}
+
+	fields, err := s.fetch(ctx, scheme, path)
+	if err != nil {
+		return "", err
+	}
+
+	if field == "" && len(fields) == 1 {
+		for _, value := range fields {
+			return value, nil
+		}
+	}
+	secret, ok := fields[field]
+	if !ok {
+		// Name the fields, never their values.
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fault.Wrap(ErrSecretNotFound, "secret has no such field",
+			fault.WithCode(fault.NotFound),
+			fault.WithContext("provider", scheme),
+			fault.WithContext("path", path),
+			fault.WithContext("field", field),
+			fault.WithContext("fields", names),
+		)
+	}
+	return secret, nil
+}
+
+func (s *Secrets) fetch(ctx context.Context, scheme, path string) (map[string]string, error) {
+	key := scheme + "://" + path
+
+	s.mu.Lock()
+	provider := s.providers[scheme]
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+
+	if ok && time.Since(cached.fetched) < s.ttl() {
+		return cached.fields, nil
+	}
+
+	fields, err := provider.Secret(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.cache == nil {
+		s.cache = map[string]cachedSecret{}
+	}
+	s.cache[key] = cachedSecret{fields: fields, fetched: time.Now()}
+	s.mu.Unlock()
+
+	return fields, nil
+}
+
+// expire drops the cache, so the next Resolve of each secret fetches it.
+func (s *Secrets) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.cache)
+}
+
+func (s *Secrets) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return DefaultSecretTTL
+}
+
+func (s *Secrets) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return DefaultSecretTimeout
+}
+
+// secretBinding is a variable or config file key holding a reference. The
+// environment keeps the reference; Section reads value instead.
+type secretBinding struct {
+	// name is the variable, or the dotted key in the config file.
+	name  string
+	ref   string
+	env   bool
+	value string
+}
+
+// resolveSecrets resolves the references in the environment and replaces
+// those of the config file with their values, remembering where they were
+// for Section and WatchSecrets. The environment is left as it is.
+func (c *Config) resolveSecrets(ctx context.Context) error {
+	for _, entry := range os.Environ() {
+		name, ref, _ := strings.Cut(entry, "=")
+		if !c.secrets.IsRef(ref) {
+			continue
+		}
+		value, err := c.secrets.Resolve(ctx, ref)
+		if err != nil {
+			return fault.Wrap(err, "failed to resolve secret",
+				codeOf(err),
+				fault.WithContext("variable", name),
+			)
+		}
+		c.bindings = append(c.bindings, secretBinding{name: name, ref: ref, env: true, value: value})
+	}
+
+	if c.file == nil {
+		return nil
+	}
+	file, bindings, err := c.resolveFileSecrets(ctx, c.file)
+	if err != nil {
+		return err
+	}
+	c.file = file
+	c.bindings = append(c.bindings, bindings...)
+	return nil
+}
+
+// resolveFileSecrets returns file with its references replaced, and where
+// they were.
+func (c *Config) resolveFileSecrets(ctx context.Context, file *viper.Viper) (*viper.Viper, []secretBinding, error) {
+	settings := file.AllSettings()
+	var bindings []secretBinding
+	var walkErr error
+	walkStrings(settings, "", func(key, ref string) string {
+		if walkErr != nil || !c.secrets.IsRef(ref) {
+			return ref
+		}
+		value, err := c.secrets.Resolve(ctx, ref)
+		if err != nil {
+			walkErr = fault.Wrap(err, "failed to resolve secret",
+				codeOf(err),
+				fault.WithContext("path", c.path),
+				fault.WithContext("key", key),
+			)
+			return ref
+		}
+		bindings = append(bindings, secretBinding{name: key, ref: ref, value: value})
+		return value
+	})
+	if walkErr != nil {
+		return nil, nil, walkErr
+	}
+	return settingsViper(settings), bindings, nil
+}
+
+// OnSecretRotate registers fn to run when WatchSecrets sees a new value for
+// name, the variable or dotted config file key that held the reference.
+// The new value is already in place, so fn can reload the package it
+// configures.
+//
+//	cfg.OnSecretRotate(func(name, _ string) {
+//		if name == "DATABASE_PASSWORD" {
+//			db.InvalidatePool()
+//		}
+//	})
+func (c *Config) OnSecretRotate(fn func(name, value string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rotateHooks = append(c.rotateHooks, fn)
+}
+
+// WatchSecrets fetches the secrets resolved by Load again every interval
+// until ctx is done, for backends that rotate them. A changed value is
+// handed to the next Section calls, then passed to the OnSecretRotate hooks and, as a Change, to the OnChange hooks. Run it in
+// its own goroutine.
+func (c *Config) WatchSecrets(ctx context.Context, interval time.Duration) {
+	if c.secrets == nil || len(c.bindings) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refreshSecrets(ctx)
+		}
+	}
+}
+
+func (c *Config) refreshSecrets(ctx context.Context) {
+	c.reloadMu.Lock()
+	c.secrets.expire()
+
+	c.mu.RLock()
+	bindings := slices.Clone(c.bindings)
+	c.mu.RUnlock()
+
+	// Fetched without the lock, so Section is not held up by the backends.
+	values := make([]string, len(bindings))
+	for i, b := range bindings {
+		value, err := c.secrets.Resolve(ctx, b.ref)
+		if err != nil {
+			if c.secrets.OnError != nil {
+				c.secrets.OnError(fault.Wrap(err, "failed to refresh secret",
+					codeOf(err),
+					fault.WithContext("name", b.name),
+				))
+			}
+			value = b.value
+		}
+		values[i] = value
+	}
+
+	var rotated []secretBinding
+	c.mu.Lock()
+	var settings map[string]any
+	for i := range c.bindings {
+		b := &c.bindings[i]
+		if values[i] == b.value {
+			continue
+		}
+
+		b.value = values[i]
+		if !b.env {
+			if settings == nil {
+				settings = c.file.AllSettings()
+			}
+			setPath(settings, b.name, b.value)
+		}
+		rotated = append(rotated, *b)
+	}
+	if settings != nil {
+		c.file = settingsViper(settings)
+	}
+	hooks := slices.Clone(c.rotateHooks)
+	c.mu.Unlock()
+	c.reloadMu.Unlock()
+
+	if len(rotated) == 0 {
+		return
+	}
+	names := make([]string, len(rotated))
+	for i, b := range rotated {
+		names[i] = b.name
+		for _, hook := range hooks {
+			hook(b.name, b.value)
+		}
+	}
+	sort.Strings(names)
+	c.notify(Change{Keys: names})
+}
+
+// codeOf keeps the code of err on the error wrapping it.
+func codeOf(err error) fault.Option {
+	if f, ok := fault.AsFault(err); ok && f.Code != "" {
+		return fault.WithCode(f.Code)
+	}
+	return fault.WithCode(fault.Internal)
+}
+
+// walkStrings calls fn with the dotted key of every string in settings and
+// stores what it returns.
+func walkStrings(settings map[string]any, prefix string, fn func(key, value string) string) {
+	for key, value := range settings {
+		switch value := value.(type) {
+		case string:
+			settings[key] = fn(prefix+key, value)
+		case map[string]any:
+			walkStrings(value, prefix+key+".", fn)
+		}
+	}
+}
+
+// overrideEnvSecrets sets on v the resolved value of every variable under
+// envPrefix that held a reference. Viper reads the environment itself, where
+// the reference stays, so the value is set on every key the variable may
+// stand for: DATABASE_POOL_MAX_OPEN on pool.max_open, pool_max.open and so
+// on.
+func overrideEnvSecrets(v *viper.Viper, envPrefix string, bindings []secretBinding) {
+	prefix := strings.ToUpper(envPrefix) + "_"
+	for _, b := range bindings {
+		rest, ok := strings.CutPrefix(b.name, prefix)
+		if !b.env || !ok || rest == "" {
+			continue
+		}
+		for _, key := range envKeys(strings.ToLower(rest)) {
+			v.Set(key, b.value)
+		}
+	}
+}
+
+// envKeys returns the keys name stands for, each "_" being a "." or itself.
+func envKeys(name string) []string {
+	parts := strings.Split(name, "_")
+	keys := []string{parts[0]}
+	for _, part := range parts[1:] {
+		next := make([]string, 0, 2*len(keys))
+		for _, key := range keys {
+			next = append(next, key+"."+part, key+"_"+part)
+		}
+		keys = next
+	}
+	return keys
+}
+
+func setPath(settings map[string]any, key, value string) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := settings[part].(map[string]any)
+		if !ok {
+			return
+		}
+		settings = next
+	}
+	settings[parts[len(parts)-1]] = value
+}
+
+func settingsViper(settings map[string]any) *viper.Viper {
+	v := viper.New()
+	_ = v.MergeConfigMap(settings)
+	return v
+}
+
+// flattenSecret turns a decoded secret into fields, nested objects joined
+// with dots and non-string values as JSON.
+func flattenSecret(fields map[string]string, prefix string, data map[string]any) {
+	for key, value := range data {
+		switch value := value.(type) {
+		case string:
+			fields[prefix+key] = value
+		case map[string]any:
+			flattenSecret(fields, prefix+key+".", value)
+		default:
+			encoded, _ := json.Marshal(value)
+			fields[prefix+key] = string(encoded)
+		}
+	}
+}
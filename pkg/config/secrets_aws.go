@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// AWSSecretsConfig configures the AWS Secrets Manager provider. Empty fields
+// are read when a secret is fetched from AWS_REGION (or
+// AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN and AWS_ENDPOINT_URL_SECRETS_MANAGER (or
+// AWS_ENDPOINT_URL). Only static or session credentials are supported;
+// export the ones of the task or pod role into those variables.
+type AWSSecretsConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint overrides https://secretsmanager.<region>.amazonaws.com.
+	Endpoint string
+	// Client sends the requests; nil uses a client that gives up after
+	// DefaultSecretTimeout.
+	Client *http.Client
+}
+
+type awsSecretsProvider struct {
+	cfg AWSSecretsConfig
+	now func() time.Time
+}
+
+// NewAWSSecretsProvider returns a provider for AWS Secrets Manager. The path
+// is the secret name or ARN; a SecretString holding a JSON object is split
+// into its fields, any other is returned whole:
+// awssm://prod/course/db#password.
+func NewAWSSecretsProvider(cfg AWSSecretsConfig) SecretProvider {
+	return &awsSecretsProvider{cfg: cfg, now: time.Now}
+}
+
+func (p *awsSecretsProvider) Secret(ctx context.Context, path string) (map[string]string, error) {
+	cfg := p.cfg
+	if cfg.Region == "" {
+		cfg.Region = firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = firstEnv("AWS_ENDPOINT_URL_SECRETS_MANAGER", "AWS_ENDPOINT_URL")
+	}
+	if cfg.Endpoint == "" && cfg.Region != "" {
+		cfg.Endpoint = "https://secretsmanager." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Client == nil {
+		cfg.Client = secretClient
+	}
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fault.Wrap(ErrSecretBackend, "AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required",
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+		)
+	}
+
+	payload, _ := json.Marshal(map[string]string{"SecretId": path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(cfg.Endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+		)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+	}
+	signV4(req, payload, cfg, "secretsmanager", p.now().UTC())
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+		)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+		)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type string `json:"__type"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		// The type may be prefixed with its namespace, "...#ResourceNotFoundException".
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return nil, fault.Wrap(ErrSecretNotFound, "no secret with that id",
+				fault.WithCode(fault.NotFound),
+				fault.WithContext("provider", "awssm"),
+				fault.WithContext("path", path),
+			)
+		}
+		return nil, fault.Wrap(ErrSecretBackend, "unexpected status "+resp.Status,
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+			fault.WithContext("path", path),
+			fault.WithContext("error_type", apiErr.Type),
+		)
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, "invalid response: "+err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "awssm"),
+			fault.WithContext("path", path),
+		)
+	}
+
+	if out.SecretString == nil {
+		return map[string]string{"": string(out.SecretBinary)}, nil
+	}
+	var object map[string]any
+	if json.Unmarshal([]byte(*out.SecretString), &object) == nil && object != nil {
+		fields := make(map[string]string, len(object))
+		flattenSecret(fields, "", object)
+		return fields, nil
+	}
+	return map[string]string{"": *out.SecretString}, nil
+}
+
+// signV4 adds the AWS Signature Version 4 headers to req, signing the
+// headers already set plus Host and X-Amz-Date.
+func signV4(req *http.Request, payload []byte, cfg AWSSecretsConfig, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + cfg.Region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+cfg.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, val := range vals {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(val))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape is url.QueryEscape with spaces as %20, as SigV4 requires.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
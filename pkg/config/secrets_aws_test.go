@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignV4(t *testing.T) {
+	// The example request of the AWS Signature Version 4 documentation.
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	cfg := AWSSecretsConfig{
+		Region:          "us-east-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	signV4(req, nil, cfg, "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAWSSecretsProvider(t *testing.T) {
+	secrets := map[string]string{
+		"prod/course/db": `{"username":"course","password":"s3cret"}`,
+		"prod/api-key":   "plain-value",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("unexpected target %q", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("unsigned request: %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Errorf("expected the session token header")
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var in struct{ SecretId string }
+		_ = json.Unmarshal(body, &in)
+
+		value, ok := secrets[in.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"not found"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": value})
+	}))
+	defer srv.Close()
+
+	provider := NewAWSSecretsProvider(AWSSecretsConfig{
+		Region:          "sa-east-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Endpoint:        srv.URL,
+	})
+
+	fields, err := provider.Secret(context.Background(), "prod/course/db")
+	if err != nil {
+		t.Fatalf("Secret() error = %v", err)
+	}
+	if fields["password"] != "s3cret" || fields["username"] != "course" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+
+	fields, err = provider.Secret(context.Background(), "prod/api-key")
+	if err != nil {
+		t.Fatalf("Secret() error = %v", err)
+	}
+	if fields[""] != "plain-value" {
+		t.Errorf("expected a plain secret under the empty field, got %v", fields)
+	}
+
+	if _, err := provider.Secret(context.Background(), "prod/missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestAWSSecretsProviderRequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+
+	_, err := NewAWSSecretsProvider(AWSSecretsConfig{}).Secret(context.Background(), "prod/course/db")
+	if !errors.Is(err, ErrSecretBackend) {
+		t.Errorf("expected ErrSecretBackend, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/marcelofabianov/config"
+)
+
+func TestSOPSProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of sops")
+	}
+
+	// A stand-in for sops that checks its arguments and prints the
+	// decrypted document.
+	binary := filepath.Join(t.TempDir(), "sops")
+	script := `#!/bin/sh
+[ "$1 $2 $3 $4" = "--decrypt --output-type json secrets.enc.yaml" ] || { echo "unexpected args: $*" >&2; exit 1; }
+echo '{"database":{"password":"s3cret"},"csrf_secret":"abc"}'
+`
+	if err := os.WriteFile(binary, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := config.NewSOPSProvider(config.SOPSConfig{Binary: binary})
+	fields, err := provider.Secret(context.Background(), "secrets.enc.yaml")
+	if err != nil {
+		t.Fatalf("Secret() error = %v", err)
+	}
+	if fields["database.password"] != "s3cret" || fields["csrf_secret"] != "abc" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+
+	if _, err := provider.Secret(context.Background(), "other.enc.yaml"); !errors.Is(err, config.ErrSecretBackend) {
+		t.Errorf("expected ErrSecretBackend when sops fails, got %v", err)
+	}
+
+	missing := config.NewSOPSProvider(config.SOPSConfig{Binary: filepath.Join(t.TempDir(), "no-sops")})
+	if _, err := missing.Secret(context.Background(), "secrets.enc.yaml"); !errors.Is(err, config.ErrSecretBackend) {
+		t.Errorf("expected ErrSecretBackend without the binary, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/config"
+)
+
+// fakeSecrets serves secrets from a map, counting the calls.
+type fakeSecrets struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+	calls   atomic.Int32
+}
+
+func (f *fakeSecrets) Secret(_ context.Context, path string) (map[string]string, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fields, ok := f.secrets[path]
+	if !ok {
+		return nil, config.ErrSecretNotFound
+	}
+	return fields, nil
+}
+
+func (f *fakeSecrets) set(path, field, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.secrets[path] = map[string]string{field: value}
+}
+
+func TestSecretsResolve(t *testing.T) {
+	fake := &fakeSecrets{secrets: map[string]map[string]string{
+		"course/db": {"user": "course", "password": "s3cret"},
+		"csrf":      {"": "token"},
+	}}
+	secrets := &config.Secrets{}
+	secrets.Register("fake", fake)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"field", "fake://course/db#password", "s3cret", nil},
+		{"single field without name", "fake://csrf", "token", nil},
+		{"plain value", "localhost", "localhost", nil},
+		{"unregistered scheme", "https://example.com", "https://example.com", nil},
+		{"missing field", "fake://course/db#host", "", config.ErrSecretNotFound},
+		{"field required", "fake://course/db", "", config.ErrSecretNotFound},
+		{"missing secret", "fake://course/cache#password", "", config.ErrSecretNotFound},
+		{"empty path", "fake://#password", "", config.ErrInvalidSecretRef},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := secrets.Resolve(ctx, tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	fake.calls.Store(0)
+	for _, field := range []string{"user", "password"} {
+		if _, err := secrets.Resolve(ctx, "fake://course/db#"+field); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := fake.calls.Load(); got != 0 {
+		t.Errorf("expected the cached secret to be reused, got %d calls", got)
+	}
+}
+
+func TestLoadWithSecrets(t *testing.T) {
+	fake := &fakeSecrets{secrets: map[string]map[string]string{
+		"course/db": {"password": "s3cret"},
+		"csrf":      {"": "token"},
+	}}
+	secrets := &config.Secrets{}
+	secrets.Register("fake", fake)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), `
+web:
+  security:
+    csrf_secret: fake://csrf
+  http:
+    host: 0.0.0.0
+`)
+	t.Chdir(dir)
+	t.Setenv(config.FileEnv, "")
+	t.Setenv("DATABASE_PASSWORD", "fake://course/db#password")
+	t.Setenv("WEB_HTTP_CSRF_SECRET", "fake://csrf")
+
+	cfg, err := config.Load(config.WithSecrets(secrets))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Section("database", "DATABASE").GetString("password"); got != "s3cret" {
+		t.Errorf("expected the variable to read as the secret, got %q", got)
+	}
+	if got := os.Getenv("DATABASE_PASSWORD"); got != "fake://course/db#password" {
+		t.Errorf("expected the environment to keep the reference, got %q", got)
+	}
+	web := cfg.Section("web", "WEB")
+	if got := web.GetString("http.csrf.secret"); got != "token" {
+		t.Errorf("expected the nested key to read as the secret, got %q", got)
+	}
+	if got := web.GetString("security.csrf_secret"); got != "token" {
+		t.Errorf("expected the file key to hold the secret, got %q", got)
+	}
+	if got := web.GetString("http.host"); got != "0.0.0.0" {
+		t.Errorf("expected other keys to be kept, got %q", got)
+	}
+
+	t.Run("unresolved reference fails the load", func(t *testing.T) {
+		t.Setenv("DATABASE_PASSWORD", "fake://course/missing#password")
+
+		if _, err := config.Load(config.WithSecrets(secrets)); !errors.Is(err, config.ErrSecretNotFound) {
+			t.Errorf("expected ErrSecretNotFound, got %v", err)
+		}
+	})
+}
+
+func TestWatchSecrets(t *testing.T) {
+	fake := &fakeSecrets{secrets: map[string]map[string]string{
+		"course/db": {"password": "old"},
+	}}
+	secrets := &config.Secrets{}
+	secrets.Register("fake", fake)
+
+	t.Chdir(t.TempDir())
+	t.Setenv(config.FileEnv, "")
+	t.Setenv("DATABASE_PASSWORD", "fake://course/db#password")
+
+	cfg, err := config.Load(config.WithSecrets(secrets))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	rotated := make(chan string, 1)
+	cfg.OnSecretRotate(func(name, value string) {
+		if name == "DATABASE_PASSWORD" {
+			rotated <- value
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cfg.WatchSecrets(ctx, 10*time.Millisecond)
+
+	fake.set("course/db", "password", "new")
+
+	select {
+	case value := <-rotated:
+		if value != "new" {
+			t.Errorf("expected the rotated value, got %q", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the rotation hook to run")
+	}
+	if got := cfg.Section("database", "DATABASE").GetString("password"); got != "new" {
+		t.Errorf("expected the variable to read as the rotated value, got %q", got)
+	}
+	if got := os.Getenv("DATABASE_PASSWORD"); got != "fake://course/db#password" {
+		t.Errorf("expected the environment to keep the reference, got %q", got)
+	}
+}
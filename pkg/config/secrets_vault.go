@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// VaultConfig configures the Vault provider. Empty fields are read from
+// VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE when a secret is fetched.
+type VaultConfig struct {
+	Address   string
+	Token     string
+	Namespace string
+	// Client sends the requests; nil uses a client that gives up after
+	// DefaultSecretTimeout.
+	Client *http.Client
+}
+
+type vaultProvider struct {
+	cfg VaultConfig
+}
+
+// NewVaultProvider returns a provider for the KV version 2 secrets engine.
+// The path starts with the mount: vault://secret/course/db#password reads
+// the password field of course/db in the secret mount.
+func NewVaultProvider(cfg VaultConfig) SecretProvider {
+	return &vaultProvider{cfg: cfg}
+}
+
+func (p *vaultProvider) Secret(ctx context.Context, path string) (map[string]string, error) {
+	cfg := p.cfg
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if cfg.Client == nil {
+		cfg.Client = secretClient
+	}
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, fault.Wrap(ErrSecretBackend, "VAULT_ADDR and VAULT_TOKEN are required",
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "vault"),
+		)
+	}
+
+	mount, name, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || name == "" {
+		return nil, fault.Wrap(ErrInvalidSecretRef, "expected vault://<mount>/<path>",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("path", path),
+		)
+	}
+
+	url := strings.TrimRight(cfg.Address, "/") + "/v1/" + mount + "/data/" + name
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "vault"),
+		)
+	}
+	req.Header.Set("X-Vault-Token", cfg.Token)
+	if cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", cfg.Namespace)
+	}
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "vault"),
+		)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fault.Wrap(ErrSecretNotFound, "no secret at path",
+			fault.WithCode(fault.NotFound),
+			fault.WithContext("provider", "vault"),
+			fault.WithContext("path", path),
+		)
+	case resp.StatusCode != http.StatusOK:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fault.Wrap(ErrSecretBackend, "unexpected status "+resp.Status,
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "vault"),
+			fault.WithContext("path", path),
+		)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fault.Wrap(ErrSecretBackend, "invalid response: "+err.Error(),
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("provider", "vault"),
+			fault.WithContext("path", path),
+		)
+	}
+
+	fields := make(map[string]string, len(body.Data.Data))
+	flattenSecret(fields, "", body.Data.Data)
+	return fields, nil
+}
@@ -0,0 +1,59 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcelofabianov/config"
+)
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("X-Vault-Namespace") != "school" {
+			t.Errorf("expected the namespace header, got %q", r.Header.Get("X-Vault-Namespace"))
+		}
+		if r.URL.Path != "/v1/secret/data/course/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"data": map[string]any{"password": "s3cret", "port": 5432},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("VAULT_NAMESPACE", "school")
+	provider := config.NewVaultProvider(config.VaultConfig{})
+
+	fields, err := provider.Secret(context.Background(), "secret/course/db")
+	if err != nil {
+		t.Fatalf("Secret() error = %v", err)
+	}
+	if fields["password"] != "s3cret" || fields["port"] != "5432" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+
+	if _, err := provider.Secret(context.Background(), "secret/course/missing"); !errors.Is(err, config.ErrSecretNotFound) {
+		t.Errorf("expected ErrSecretNotFound, got %v", err)
+	}
+	if _, err := provider.Secret(context.Background(), "secret"); !errors.Is(err, config.ErrInvalidSecretRef) {
+		t.Errorf("expected ErrInvalidSecretRef for a path without mount, got %v", err)
+	}
+
+	denied := config.NewVaultProvider(config.VaultConfig{Token: "wrong"})
+	if _, err := denied.Secret(context.Background(), "secret/course/db"); !errors.Is(err, config.ErrSecretBackend) {
+		t.Errorf("expected ErrSecretBackend, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/spf13/viper"
+)
+
+// ErrUnknownEnv is returned by CheckUnknownEnv for variables that match no
+// setting.
+var ErrUnknownEnv = fault.New(
+	"unknown environment variable",
+	fault.WithCode(fault.Invalid),
+)
+
+// CheckUnknownEnv fails with ErrUnknownEnv when the environment holds an
+// <envPrefix>_* variable that matches none of the keys of defaults, the
+// viper a package sets its defaults on, nor the extra keys, read without a
+// default, naming the closest known variable when one is within two edits
+// (e.g. WEB_HTTP_PORTT). Call it after Load, which exports the .env file
+// into the environment.
+func CheckUnknownEnv(envPrefix string, defaults *viper.Viper, extra ...string) error {
+	prefix := envPrefix + "_"
+
+	known := make(map[string]bool)
+	for _, key := range append(defaults.AllKeys(), extra...) {
+		known[prefix+strings.ToUpper(strings.ReplaceAll(key, ".", "_"))] = true
+	}
+
+	var unknown []string
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, prefix) && !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	described := make([]string, len(unknown))
+	for i, name := range unknown {
+		described[i] = name
+		if suggestion := closestName(name, known); suggestion != "" {
+			described[i] += " (did you mean " + suggestion + "?)"
+		}
+	}
+
+	return fault.Wrap(ErrUnknownEnv, strings.Join(described, ", "),
+		fault.WithCode(fault.Invalid),
+		fault.WithContext("variables", unknown),
+	)
+}
+
+// closestName returns the known name within two edits of name, if any.
+func closestName(name string, known map[string]bool) string {
+	best, bestDistance := "", 3
+	for candidate := range known {
+		if d := editDistance(name, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
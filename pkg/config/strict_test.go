@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/marcelofabianov/config"
+)
+
+func TestCheckUnknownEnv(t *testing.T) {
+	defaults := viper.New()
+	defaults.SetDefault("http.port", 8080)
+	defaults.SetDefault("http.host", "0.0.0.0")
+
+	t.Run("accepts known variables", func(t *testing.T) {
+		t.Setenv("APP_HTTP_PORT", "8081")
+		t.Setenv("OTHER_HTTP_PORTT", "8081")
+
+		if err := config.CheckUnknownEnv("APP", defaults); err != nil {
+			t.Fatalf("CheckUnknownEnv() error = %v", err)
+		}
+	})
+
+	t.Run("accepts extra keys", func(t *testing.T) {
+		t.Setenv("APP_HTTP_LISTEN", ":8080")
+
+		if err := config.CheckUnknownEnv("APP", defaults, "http.listen"); err != nil {
+			t.Fatalf("CheckUnknownEnv() error = %v", err)
+		}
+	})
+
+	t.Run("rejects unknown variables", func(t *testing.T) {
+		t.Setenv("APP_HTTP_PORTT", "8081")
+		t.Setenv("APP_UNRELATED", "x")
+
+		err := config.CheckUnknownEnv("APP", defaults)
+		if !errors.Is(err, config.ErrUnknownEnv) {
+			t.Fatalf("expected ErrUnknownEnv, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "APP_HTTP_PORTT (did you mean APP_HTTP_PORT?), APP_UNRELATED") {
+			t.Errorf("unexpected message %q", err.Error())
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DefaultWatchInterval is how often Watch checks the config file. A stat
+// per interval is cheap, and Kubernetes takes about a minute to propagate
+// an updated ConfigMap anyway.
+const DefaultWatchInterval = 10 * time.Second
+
+// Change describes a reload: the dotted keys of the config file that were
+// added, changed or removed, sorted, plus the variables and keys whose
+// secret WatchSecrets rotated.
+type Change struct {
+	Keys []string
+}
+
+// Has reports whether key, or a key nested under it, changed:
+// Has("web.cors") is true for web.cors.allowed_origins. Profile overrides
+// change under profiles.<name>.
+func (ch Change) Has(key string) bool {
+	for _, changed := range ch.Keys {
+		if changed == key || strings.HasPrefix(changed, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// fileStamp identifies a version of a file. Stat follows symlinks, so the
+// atomic ..data swap done by the kubelet for a mounted ConfigMap shows up
+// as a change too.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, fault.Wrap(err, "failed to read config file",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("path", path),
+		)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// OnChange registers fn to run after every reload that changed something.
+// Sections read after the reload see the new values. For a typed view of
+// one package use Subscribe.
+func (c *Config) OnChange(fn func(Change)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.changeHooks = append(c.changeHooks, fn)
+}
+
+// OnReloadError registers fn to receive the failures of Watch reloads and
+// of Subscribe loads. The previous settings stay in place on failure.
+func (c *Config) OnReloadError(fn func(err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errorHooks = append(c.errorHooks, fn)
+}
+
+// Subscribe loads T with load, then again after every change, and calls
+// apply with the previous and new values when they differ. A load that
+// fails, such as a LoadConfigFrom rejecting an invalid value, is passed to
+// the OnReloadError hooks and the previous value is kept.
+//
+//	err := config.Subscribe(appCfg, logger.LoadConfigFrom, func(old, new *logger.Config) {
+//		if old.Level != new.Level {
+//			log.SetLevel(new.Level)
+//		}
+//	})
+func Subscribe[T any](c *Config, load func(*Config) (T, error), apply func(old, new T)) error {
+	current, err := load(c)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	c.OnChange(func(Change) {
+		next, err := load(c)
+		if err != nil {
+			c.reportError(fault.Wrap(err, "failed to apply reloaded config", codeOf(err)))
+			return
+		}
+
+		mu.Lock()
+		old := current
+		changed := !reflect.DeepEqual(old, next)
+		if changed {
+			current = next
+		}
+		mu.Unlock()
+
+		if changed {
+			apply(old, next)
+		}
+	})
+	return nil
+}
+
+// Reload reads the config file again, resolving its secret references,
+// and runs the OnChange hooks if any value changed. On error the current
+// settings stay in place. Variables, including those of .env, are read
+// when a section is loaded and need no reload. Call it from a SIGHUP
+// handler or an admin endpoint, or let Watch call it.
+func (c *Config) Reload() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.reloadMu.Lock()
+	// A failed stat only makes the next Watch tick reload again.
+	stamp, _ := statFile(c.path)
+	file, err := readFile(c.path)
+	if err != nil {
+		c.reloadMu.Unlock()
+		return err
+	}
+
+	var bindings []secretBinding
+	if c.secrets != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.secrets.timeout())
+		file, bindings, err = c.resolveFileSecrets(ctx, file)
+		cancel()
+		if err != nil {
+			c.reloadMu.Unlock()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	keys := changedKeys(c.file.AllSettings(), file.AllSettings())
+	c.file = file
+	c.stamp = stamp
+	if c.secrets != nil {
+		c.bindings = slices.DeleteFunc(c.bindings, func(b secretBinding) bool { return !b.env })
+		c.bindings = append(c.bindings, bindings...)
+	}
+	c.mu.Unlock()
+	c.reloadMu.Unlock()
+
+	if len(keys) > 0 {
+		c.notify(Change{Keys: keys})
+	}
+	return nil
+}
+
+// Watch reloads the config file whenever it changes, checking every
+// interval until ctx is done; failed reloads go to the OnReloadError hooks.
+// Run it in its own goroutine. Without a config file it returns at once.
+func (c *Config) Watch(ctx context.Context, interval time.Duration) {
+	if c.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !c.fileChanged() {
+			continue
+		}
+		if err := c.Reload(); err != nil {
+			c.reportError(err)
+		}
+	}
+}
+
+func (c *Config) fileChanged() bool {
+	stamp, err := statFile(c.path)
+	if err != nil {
+		// Mid-swap or removed: keep the settings and check again later.
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return stamp != c.stamp
+}
+
+func (c *Config) notify(ch Change) {
+	c.mu.RLock()
+	hooks := slices.Clone(c.changeHooks)
+	c.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(ch)
+	}
+}
+
+func (c *Config) reportError(err error) {
+	c.mu.RLock()
+	hooks := slices.Clone(c.errorHooks)
+	c.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(err)
+	}
+}
+
+// changedKeys returns the dotted keys whose value differs between the
+// settings, sorted.
+func changedKeys(oldSettings, newSettings map[string]any) []string {
+	before := map[string]any{}
+	after := map[string]any{}
+	flattenSettings(before, "", oldSettings)
+	flattenSettings(after, "", newSettings)
+
+	var keys []string
+	for key, value := range before {
+		if other, ok := after[key]; !ok || !reflect.DeepEqual(value, other) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flattenSettings(flat map[string]any, prefix string, settings map[string]any) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			flattenSettings(flat, prefix+key+".", nested)
+			continue
+		}
+		flat[prefix+key] = value
+	}
+}
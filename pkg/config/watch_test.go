@@ -0,0 +1,204 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/config"
+)
+
+type limits struct {
+	Requests int
+	Origins  []string
+}
+
+func loadLimits(c *config.Config) (limits, error) {
+	v := c.Section("web", "WEB")
+	l := limits{
+		Requests: v.GetInt("rate_limit.requests"),
+		Origins:  v.GetStringSlice("cors.allowed_origins"),
+	}
+	if l.Requests <= 0 {
+		return l, errors.New("rate_limit.requests must be positive")
+	}
+	return l, nil
+}
+
+func loadWatched(t *testing.T, content string) (*config.Config, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, content)
+	t.Setenv(config.FileEnv, path)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return cfg, path
+}
+
+func TestReload(t *testing.T) {
+	cfg, path := loadWatched(t, `
+web:
+  rate_limit:
+    requests: 100
+  cors:
+    allowed_origins: [https://a.example]
+logger:
+  level: info
+`)
+
+	var changes []config.Change
+	cfg.OnChange(func(ch config.Change) { changes = append(changes, ch) })
+
+	var applied [][2]limits
+	err := config.Subscribe(cfg, loadLimits, func(old, new limits) {
+		applied = append(applied, [2]limits{old, new})
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	t.Run("unchanged file", func(t *testing.T) {
+		if err := cfg.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if len(changes) != 0 || len(applied) != 0 {
+			t.Errorf("expected no events, got %v and %v", changes, applied)
+		}
+	})
+
+	t.Run("changed keys", func(t *testing.T) {
+		writeFile(t, path, `
+web:
+  rate_limit:
+    requests: 50
+  cors:
+    allowed_origins: [https://a.example]
+logger:
+  level: debug
+  format: text
+`)
+		if err := cfg.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+
+		if len(changes) != 1 {
+			t.Fatalf("expected one change, got %v", changes)
+		}
+		want := []string{"logger.format", "logger.level", "web.rate_limit.requests"}
+		if !slices.Equal(changes[0].Keys, want) {
+			t.Errorf("expected keys %v, got %v", want, changes[0].Keys)
+		}
+		if !changes[0].Has("logger") || !changes[0].Has("web.rate_limit") || changes[0].Has("web.cors") {
+			t.Errorf("unexpected Has results for %v", changes[0].Keys)
+		}
+
+		if got := cfg.Section("logger", "LOGGER").GetString("level"); got != "debug" {
+			t.Errorf("expected sections to read the new file, got %q", got)
+		}
+		if len(applied) != 1 || applied[0][0].Requests != 100 || applied[0][1].Requests != 50 {
+			t.Errorf("expected the subscriber to get 100 -> 50, got %v", applied)
+		}
+	})
+
+	t.Run("change outside the subscription", func(t *testing.T) {
+		applied = nil
+		writeFile(t, path, `
+web:
+  rate_limit:
+    requests: 50
+  cors:
+    allowed_origins: [https://a.example]
+logger:
+  level: warn
+`)
+		if err := cfg.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if len(applied) != 0 {
+			t.Errorf("expected the subscriber to be skipped, got %v", applied)
+		}
+	})
+
+	t.Run("invalid file keeps the settings", func(t *testing.T) {
+		writeFile(t, path, "web: [unclosed")
+
+		if err := cfg.Reload(); !errors.Is(err, config.ErrInvalidFile) {
+			t.Errorf("expected ErrInvalidFile, got %v", err)
+		}
+		if got := cfg.Section("web", "WEB").GetInt("rate_limit.requests"); got != 50 {
+			t.Errorf("expected the previous settings, got %d", got)
+		}
+	})
+
+	t.Run("rejected value keeps the subscriber value", func(t *testing.T) {
+		var reported []error
+		cfg.OnReloadError(func(err error) { reported = append(reported, err) })
+		applied = nil
+
+		writeFile(t, path, `
+web:
+  rate_limit:
+    requests: 0
+`)
+		if err := cfg.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		if len(reported) != 1 || len(applied) != 0 {
+			t.Errorf("expected one reported error and no apply, got %v and %v", reported, applied)
+		}
+	})
+}
+
+func TestWatch(t *testing.T) {
+	cfg, path := loadWatched(t, "logger:\n  level: info\n")
+
+	changed := make(chan config.Change, 1)
+	cfg.OnChange(func(ch config.Change) { changed <- ch })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cfg.Watch(ctx, 10*time.Millisecond)
+
+	// A different size, so the change is seen even within the mtime
+	// resolution of the filesystem.
+	writeFile(t, path, "logger:\n  level: debug\n")
+
+	select {
+	case ch := <-changed:
+		if !slices.Equal(ch.Keys, []string{"logger.level"}) {
+			t.Errorf("expected logger.level, got %v", ch.Keys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Watch to reload the file")
+	}
+}
+
+func TestWatchWithoutFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.FileEnv, "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.Reload(); err != nil {
+		t.Errorf("Reload() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cfg.Watch(context.Background(), time.Millisecond)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Watch to return without a config file")
+	}
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const DefaultAuditTrailTable = "audit_trail"
+
+const (
+	AuditInsert = "insert"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+var ErrAuditFailed = fault.New(
+	"failed to write audit trail",
+	fault.WithCode(fault.Internal),
+)
+
+type actorKey struct{}
+
+// WithActor stores the user or service performing the request, usually set
+// by the authentication middleware.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, if any.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// AuditColumns names the audit columns of a table. An empty name leaves that
+// column out.
+type AuditColumns struct {
+	CreatedAt string
+	UpdatedAt string
+	CreatedBy string
+	UpdatedBy string
+}
+
+// DefaultAuditColumns matches the created_at/updated_at/created_by
+// convention used by the services' schemas.
+var DefaultAuditColumns = AuditColumns{
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	CreatedBy: "created_by",
+}
+
+type auditValues struct {
+	columns AuditColumns
+	now     time.Time
+	actor   any
+}
+
+// WithAudit fills the default audit columns: inserts and upserts get
+// created_at, updated_at and created_by, batch updates get updated_at. The
+// actor comes from ctx (NULL when absent).
+func WithAudit(ctx context.Context) BuildOption {
+	return WithAuditColumns(ctx, DefaultAuditColumns)
+}
+
+// WithAuditColumns is WithAudit for tables with other audit column names.
+func WithAuditColumns(ctx context.Context, columns AuditColumns) BuildOption {
+	values := &auditValues{
+		columns: columns,
+		now:     time.Now().UTC(),
+	}
+	if actor, ok := ActorFromContext(ctx); ok {
+		values.actor = actor
+	}
+
+	return func(o *buildOptions) {
+		o.audit = values
+	}
+}
+
+// insertColumns returns the audit columns and values written on insert.
+func (a *auditValues) insertColumns() ([]string, []any) {
+	var columns []string
+	var values []any
+
+	add := func(column string, value any) {
+		if column != "" {
+			columns = append(columns, column)
+			values = append(values, value)
+		}
+	}
+	add(a.columns.CreatedAt, a.now)
+	add(a.columns.UpdatedAt, a.now)
+	add(a.columns.CreatedBy, a.actor)
+	add(a.columns.UpdatedBy, a.actor)
+
+	return columns, values
+}
+
+// updateColumns returns the audit columns and values written on update.
+func (a *auditValues) updateColumns() ([]string, []any) {
+	var columns []string
+	var values []any
+
+	if a.columns.UpdatedAt != "" {
+		columns = append(columns, a.columns.UpdatedAt)
+		values = append(values, a.now)
+	}
+	if a.columns.UpdatedBy != "" {
+		columns = append(columns, a.columns.UpdatedBy)
+		values = append(values, a.actor)
+	}
+
+	return columns, values
+}
+
+// updateCasts adds a timestamptz cast for updated_at, since a VALUES list
+// would otherwise type the placeholder as text.
+func (a *auditValues) updateCasts(casts map[string]string) map[string]string {
+	if a.columns.UpdatedAt == "" {
+		return casts
+	}
+	if _, ok := casts[a.columns.UpdatedAt]; ok {
+		return casts
+	}
+
+	merged := make(map[string]string, len(casts)+1)
+	for column, cast := range casts {
+		merged[column] = cast
+	}
+	merged[a.columns.UpdatedAt] = "timestamptz"
+
+	return merged
+}
+
+// withAuditValues appends the audit columns to columns and their values to
+// every row, leaving the inputs untouched.
+func withAuditValues(columns []string, rows [][]any, auditColumns []string, auditArgs []any) ([]string, [][]any) {
+	if len(auditColumns) == 0 {
+		return columns, rows
+	}
+
+	columns = append(append([]string{}, columns...), auditColumns...)
+
+	audited := make([][]any, len(rows))
+	for i, row := range rows {
+		audited[i] = append(append([]any{}, row...), auditArgs...)
+	}
+
+	return columns, audited
+}
+
+// Execer is satisfied by *DB and *sql.Tx, so audit rows can be written in
+// the same transaction as the change they describe.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// AuditEntry is one row of the audit trail.
+type AuditEntry struct {
+	Table    string
+	Action   string
+	RecordID string
+	Changes  any
+}
+
+// AuditTrail writes audit entries to a table with the columns table_name,
+// action, record_id, actor, changes (jsonb) and created_at.
+type AuditTrail struct {
+	table string
+}
+
+func NewAuditTrail(table string) *AuditTrail {
+	if table == "" {
+		table = DefaultAuditTrailTable
+	}
+
+	return &AuditTrail{table: table}
+}
+
+// Record writes entry through exec, taking the actor from ctx.
+func (at *AuditTrail) Record(ctx context.Context, exec Execer, entry AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fault.Wrap(ErrAuditFailed, "failed to encode changes",
+			fault.WithWrappedErr(err),
+			fault.WithContext("table", entry.Table),
+		)
+	}
+
+	var actor any
+	if a, ok := ActorFromContext(ctx); ok {
+		actor = a
+	}
+
+	query, args, err := BuildInsert(at.table,
+		[]string{"table_name", "action", "record_id", "actor", "changes", "created_at"},
+		[][]any{{entry.Table, entry.Action, entry.RecordID, actor, string(changes), time.Now().UTC()}},
+		WithCasts(map[string]string{"changes": "jsonb"}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return fault.Wrap(ErrAuditFailed, "failed to insert audit entry",
+			fault.WithWrappedErr(err),
+			fault.WithContext("table", entry.Table),
+			fault.WithContext("action", entry.Action),
+			fault.WithContext("record_id", entry.RecordID),
+		)
+	}
+
+	return nil
+}
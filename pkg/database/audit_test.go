@@ -0,0 +1,134 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/database"
+)
+
+var (
+	_ database.Execer = (*database.DB)(nil)
+	_ database.Execer = (*sql.Tx)(nil)
+)
+
+type recordingExecer struct {
+	query string
+	args  []any
+}
+
+func (e *recordingExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	e.query = query
+	e.args = args
+	return nil, nil
+}
+
+func TestActorFromContext(t *testing.T) {
+	if _, ok := database.ActorFromContext(context.Background()); ok {
+		t.Error("expected no actor on a bare context")
+	}
+
+	actor, ok := database.ActorFromContext(database.WithActor(context.Background(), "user-42"))
+	if !ok || actor != "user-42" {
+		t.Errorf("expected actor user-42, got %q (ok=%v)", actor, ok)
+	}
+}
+
+func TestBuildInsertWithAudit(t *testing.T) {
+	ctx := database.WithActor(context.Background(), "user-42")
+
+	query, args, err := database.BuildInsert("students", []string{"name"}, [][]any{{"Ana"}, {"Bruno"}},
+		database.WithAudit(ctx),
+	)
+	if err != nil {
+		t.Fatalf("BuildInsert() error = %v", err)
+	}
+
+	expected := `INSERT INTO "students" ("name", "created_at", "updated_at", "created_by") VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+	if len(args) != 8 {
+		t.Fatalf("expected 8 args, got %d", len(args))
+	}
+	if _, ok := args[1].(time.Time); !ok {
+		t.Errorf("expected created_at to be a time, got %T", args[1])
+	}
+	if args[3] != "user-42" || args[7] != "user-42" {
+		t.Errorf("expected created_by user-42 on every row, got %v and %v", args[3], args[7])
+	}
+
+	_, args, err = database.BuildInsert("students", []string{"name"}, [][]any{{"Ana"}},
+		database.WithAudit(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("BuildInsert() error = %v", err)
+	}
+	if args[3] != nil {
+		t.Errorf("expected NULL created_by without an actor, got %v", args[3])
+	}
+}
+
+func TestBuildUpsertWithAudit(t *testing.T) {
+	query, _, err := database.BuildUpsert("students", []string{"id", "name"}, [][]any{{1, "Ana"}},
+		[]string{"id"}, []string{"name"},
+		database.WithAudit(context.Background()),
+	)
+	if err != nil {
+		t.Fatalf("BuildUpsert() error = %v", err)
+	}
+
+	if !strings.HasSuffix(query, `DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = EXCLUDED."updated_at"`) {
+		t.Errorf("expected updated_at to be refreshed on conflict, got %s", query)
+	}
+}
+
+func TestBuildBatchUpdateWithAudit(t *testing.T) {
+	ctx := database.WithActor(context.Background(), "user-42")
+	columns := database.DefaultAuditColumns
+	columns.UpdatedBy = "updated_by"
+
+	query, args, err := database.BuildBatchUpdate("students", []string{"id"}, []string{"name"}, [][]any{{1, "Ana"}},
+		database.WithAuditColumns(ctx, columns),
+	)
+	if err != nil {
+		t.Fatalf("BuildBatchUpdate() error = %v", err)
+	}
+
+	expected := `UPDATE "students" AS t SET "name" = v."name", "updated_at" = v."updated_at", "updated_by" = v."updated_by" FROM (VALUES ($1, $2, $3::timestamptz, $4)) AS v ("id", "name", "updated_at", "updated_by") WHERE t."id" = v."id"`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+	if args[3] != "user-42" {
+		t.Errorf("expected updated_by user-42, got %v", args[3])
+	}
+}
+
+func TestAuditTrailRecord(t *testing.T) {
+	ctx := database.WithActor(context.Background(), "user-42")
+	exec := &recordingExecer{}
+
+	err := database.NewAuditTrail("").Record(ctx, exec, database.AuditEntry{
+		Table:    "students",
+		Action:   database.AuditUpdate,
+		RecordID: "7",
+		Changes:  map[string]any{"name": "Ana"},
+	})
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	expected := `INSERT INTO "audit_trail" ("table_name", "action", "record_id", "actor", "changes", "created_at") VALUES ($1, $2, $3, $4, $5::jsonb, $6)`
+	if exec.query != expected {
+		t.Errorf("expected %s, got %s", expected, exec.query)
+	}
+	if exec.args[3] != "user-42" {
+		t.Errorf("expected actor user-42, got %v", exec.args[3])
+	}
+	if exec.args[4] != `{"name":"Ana"}` {
+		t.Errorf("unexpected changes %v", exec.args[4])
+	}
+}
@@ -0,0 +1,247 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/marcelofabianov/fault"
+)
+
+// maxParams is the PostgreSQL limit on bind parameters per statement.
+const maxParams = 65535
+
+var ErrBuildFailed = fault.New(
+	"failed to build SQL statement",
+	fault.WithCode(fault.Invalid),
+)
+
+type buildOptions struct {
+	returning []string
+	casts     map[string]string
+	audit     *auditValues
+}
+
+type BuildOption func(*buildOptions)
+
+// WithReturning appends a RETURNING clause with columns.
+func WithReturning(columns ...string) BuildOption {
+	return func(o *buildOptions) {
+		o.returning = columns
+	}
+}
+
+// WithCasts casts the first row of placeholders per column (e.g.
+// {"id": "bigint"}). BuildBatchUpdate needs it for non-text columns because
+// Postgres types a VALUES list from its first row.
+func WithCasts(casts map[string]string) BuildOption {
+	return func(o *buildOptions) {
+		o.casts = casts
+	}
+}
+
+// BuildInsert builds a multi-row INSERT with sequentially numbered
+// placeholders and returns it with the flattened arguments.
+func BuildInsert(table string, columns []string, rows [][]any, opts ...BuildOption) (string, []any, error) {
+	options := newBuildOptions(opts)
+	columns, rows = options.insertAudit(columns, rows)
+
+	values, args, err := buildValues(columns, rows, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(quoteTable(table))
+	sb.WriteString(" (")
+	sb.WriteString(quoteColumns(columns))
+	sb.WriteString(") VALUES ")
+	sb.WriteString(values)
+	writeReturning(&sb, options)
+
+	return sb.String(), args, nil
+}
+
+// BuildUpsert builds a multi-row INSERT ... ON CONFLICT (conflict) DO UPDATE
+// setting each update column from EXCLUDED. With no update columns the
+// conflict is ignored (DO NOTHING).
+func BuildUpsert(table string, columns []string, rows [][]any, conflict []string, update []string, opts ...BuildOption) (string, []any, error) {
+	if len(conflict) == 0 {
+		return "", nil, fault.Wrap(ErrBuildFailed, "conflict columns are required")
+	}
+
+	options := newBuildOptions(opts)
+	columns, rows = options.insertAudit(columns, rows)
+	if len(update) > 0 && options.audit != nil {
+		auditColumns, _ := options.audit.updateColumns()
+		update = append(append([]string{}, update...), auditColumns...)
+	}
+
+	values, args, err := buildValues(columns, rows, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(quoteTable(table))
+	sb.WriteString(" (")
+	sb.WriteString(quoteColumns(columns))
+	sb.WriteString(") VALUES ")
+	sb.WriteString(values)
+	sb.WriteString(" ON CONFLICT (")
+	sb.WriteString(quoteColumns(conflict))
+	sb.WriteString(") ")
+
+	if len(update) == 0 {
+		sb.WriteString("DO NOTHING")
+	} else {
+		sb.WriteString("DO UPDATE SET ")
+		for i, column := range update {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			quoted := quoteIdent(column)
+			sb.WriteString(quoted + " = EXCLUDED." + quoted)
+		}
+	}
+	writeReturning(&sb, options)
+
+	return sb.String(), args, nil
+}
+
+// BuildBatchUpdate builds a single UPDATE ... FROM (VALUES ...) that updates
+// many rows at once. Each row holds the key values followed by the column
+// values, in the order of keys then columns.
+func BuildBatchUpdate(table string, keys []string, columns []string, rows [][]any, opts ...BuildOption) (string, []any, error) {
+	if len(keys) == 0 || len(columns) == 0 {
+		return "", nil, fault.Wrap(ErrBuildFailed, "key and update columns are required")
+	}
+
+	options := newBuildOptions(opts)
+	if options.audit != nil {
+		auditColumns, auditArgs := options.audit.updateColumns()
+		columns, rows = withAuditValues(columns, rows, auditColumns, auditArgs)
+		options.casts = options.audit.updateCasts(options.casts)
+	}
+	all := append(append([]string{}, keys...), columns...)
+
+	values, args, err := buildValues(all, rows, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("UPDATE ")
+	sb.WriteString(quoteTable(table))
+	sb.WriteString(" AS t SET ")
+	for i, column := range columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		quoted := quoteIdent(column)
+		sb.WriteString(quoted + " = v." + quoted)
+	}
+	sb.WriteString(" FROM (VALUES ")
+	sb.WriteString(values)
+	sb.WriteString(") AS v (")
+	sb.WriteString(quoteColumns(all))
+	sb.WriteString(") WHERE ")
+	for i, key := range keys {
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+		quoted := quoteIdent(key)
+		sb.WriteString("t." + quoted + " = v." + quoted)
+	}
+	writeReturning(&sb, options)
+
+	return sb.String(), args, nil
+}
+
+func newBuildOptions(opts []BuildOption) buildOptions {
+	var options buildOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// insertAudit appends the insert audit columns when WithAudit is set.
+func (o buildOptions) insertAudit(columns []string, rows [][]any) ([]string, [][]any) {
+	if o.audit == nil {
+		return columns, rows
+	}
+	auditColumns, auditArgs := o.audit.insertColumns()
+	return withAuditValues(columns, rows, auditColumns, auditArgs)
+}
+
+func buildValues(columns []string, rows [][]any, options buildOptions) (string, []any, error) {
+	if len(columns) == 0 {
+		return "", nil, fault.Wrap(ErrBuildFailed, "columns are required")
+	}
+	if len(rows) == 0 {
+		return "", nil, fault.Wrap(ErrBuildFailed, "at least one row is required")
+	}
+	if len(rows)*len(columns) > maxParams {
+		return "", nil, fault.Wrap(ErrBuildFailed, "too many parameters, split the batch",
+			fault.WithContext("params", len(rows)*len(columns)),
+			fault.WithContext("max_params", maxParams),
+		)
+	}
+
+	var sb strings.Builder
+	args := make([]any, 0, len(rows)*len(columns))
+
+	for r, row := range rows {
+		if len(row) != len(columns) {
+			return "", nil, fault.Wrap(ErrBuildFailed, "row length does not match columns",
+				fault.WithContext("row", r),
+				fault.WithContext("expected", len(columns)),
+				fault.WithContext("got", len(row)),
+			)
+		}
+
+		if r > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for c, value := range row {
+			if c > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			sb.WriteString("$" + strconv.Itoa(len(args)))
+			if cast, ok := options.casts[columns[c]]; ok && r == 0 {
+				sb.WriteString("::" + cast)
+			}
+		}
+		sb.WriteByte(')')
+	}
+
+	return sb.String(), args, nil
+}
+
+func writeReturning(sb *strings.Builder, options buildOptions) {
+	if len(options.returning) > 0 {
+		sb.WriteString(" RETURNING ")
+		sb.WriteString(quoteColumns(options.returning))
+	}
+}
+
+func quoteTable(table string) string {
+	return pgx.Identifier(strings.Split(table, ".")).Sanitize()
+}
+
+func quoteIdent(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdent(column)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,99 @@
+package database_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/marcelofabianov/database"
+)
+
+func TestBuildInsert(t *testing.T) {
+	query, args, err := database.BuildInsert("public.students", []string{"name", "email"}, [][]any{
+		{"Ana", "ana@example.com"},
+		{"Bruno", "bruno@example.com"},
+	}, database.WithReturning("id"))
+	if err != nil {
+		t.Fatalf("BuildInsert() error = %v", err)
+	}
+
+	expected := `INSERT INTO "public"."students" ("name", "email") VALUES ($1, $2), ($3, $4) RETURNING "id"`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+	if !reflect.DeepEqual(args, []any{"Ana", "ana@example.com", "Bruno", "bruno@example.com"}) {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestBuildUpsert(t *testing.T) {
+	rows := [][]any{{1, "Ana"}, {2, "Bruno"}}
+
+	query, _, err := database.BuildUpsert("students", []string{"id", "name"}, rows, []string{"id"}, []string{"name"})
+	if err != nil {
+		t.Fatalf("BuildUpsert() error = %v", err)
+	}
+	expected := `INSERT INTO "students" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+
+	query, _, err = database.BuildUpsert("students", []string{"id", "name"}, rows, []string{"id"}, nil)
+	if err != nil {
+		t.Fatalf("BuildUpsert() error = %v", err)
+	}
+	expected = `INSERT INTO "students" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO NOTHING`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+}
+
+func TestBuildBatchUpdate(t *testing.T) {
+	query, args, err := database.BuildBatchUpdate("students", []string{"id"}, []string{"name"}, [][]any{
+		{1, "Ana"},
+		{2, "Bruno"},
+	}, database.WithCasts(map[string]string{"id": "bigint"}))
+	if err != nil {
+		t.Fatalf("BuildBatchUpdate() error = %v", err)
+	}
+
+	expected := `UPDATE "students" AS t SET "name" = v."name" FROM (VALUES ($1::bigint, $2), ($3, $4)) AS v ("id", "name") WHERE t."id" = v."id"`
+	if query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+	if len(args) != 4 {
+		t.Errorf("expected 4 args, got %d", len(args))
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"no rows", func() error {
+			_, _, err := database.BuildInsert("t", []string{"a"}, nil)
+			return err
+		}},
+		{"row length mismatch", func() error {
+			_, _, err := database.BuildInsert("t", []string{"a", "b"}, [][]any{{1}})
+			return err
+		}},
+		{"upsert without conflict", func() error {
+			_, _, err := database.BuildUpsert("t", []string{"a"}, [][]any{{1}}, nil, nil)
+			return err
+		}},
+		{"too many params", func() error {
+			_, _, err := database.BuildInsert("t", []string{"a"}, make([][]any, 70000))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); !errors.Is(err, database.ErrBuildFailed) {
+				t.Errorf("expected ErrBuildFailed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrIncompatible is returned by CheckCompatibility when the server does
+// not meet the service requirements.
+var ErrIncompatible = fault.New(
+	"database does not meet the service requirements",
+	fault.WithCode(fault.InfraError),
+)
+
+// DefaultSchemaQuery reads the schema version from the golang-migrate
+// schema_migrations table.
+const DefaultSchemaQuery = "SELECT version FROM schema_migrations"
+
+// Requirements is what a service needs from its database, checked once at
+// startup so a missing extension or pending migration fails the deploy
+// instead of the first query that depends on it.
+type Requirements struct {
+	// MinVersion is the lowest PostgreSQL server version accepted, e.g. "15"
+	// or "14.5". Empty skips the check.
+	MinVersion string
+	// Extensions must be installed in the database, e.g. pgcrypto, pg_trgm.
+	Extensions []string
+	// SchemaVersion is the lowest migration version the service runs
+	// against. Zero skips the check.
+	SchemaVersion int64
+	// SchemaQuery returns the current schema version as a single integer.
+	// Defaults to DefaultSchemaQuery.
+	SchemaQuery string
+}
+
+// CheckCompatibility runs every check in req and returns ErrIncompatible
+// listing all that failed, so one deploy attempt shows everything to fix.
+func (db *DB) CheckCompatibility(ctx context.Context, req Requirements) error {
+	if db.conn == nil {
+		return ErrNotConnected
+	}
+
+	var problems []string
+
+	if req.MinVersion != "" {
+		if problem := db.checkServerVersion(ctx, req.MinVersion); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(req.Extensions) > 0 {
+		if problem := db.checkExtensions(ctx, req.Extensions); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if req.SchemaVersion > 0 {
+		if problem := db.checkSchemaVersion(ctx, req); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fault.Wrap(ErrIncompatible, strings.Join(problems, "; "),
+		fault.WithContext("problems", problems),
+		fault.WithContext("database", db.config.Database.Credentials.Name),
+	)
+}
+
+func (db *DB) checkServerVersion(ctx context.Context, minVersion string) string {
+	want, err := parseServerVersion(minVersion)
+	if err != nil {
+		return err.Error()
+	}
+
+	var raw string
+	if err := db.QueryRowContext(ctx, "SHOW server_version_num").Scan(&raw); err != nil {
+		return fmt.Sprintf("server version unavailable: %v", err)
+	}
+	got, err := strconv.Atoi(raw)
+	if err != nil {
+		return fmt.Sprintf("invalid server_version_num %q", raw)
+	}
+
+	if got < want {
+		return fmt.Sprintf("postgres %s is older than the required %s", formatServerVersion(got), minVersion)
+	}
+	return ""
+}
+
+func (db *DB) checkExtensions(ctx context.Context, extensions []string) string {
+	rows, err := db.QueryContext(ctx, "SELECT extname FROM pg_extension")
+	if err != nil {
+		return fmt.Sprintf("extensions unavailable: %v", err)
+	}
+	defer rows.Close()
+
+	installed := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Sprintf("extensions unavailable: %v", err)
+		}
+		installed[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Sprintf("extensions unavailable: %v", err)
+	}
+
+	var missing []string
+	for _, name := range extensions {
+		if !installed[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return "missing extensions: " + strings.Join(missing, ", ")
+}
+
+func (db *DB) checkSchemaVersion(ctx context.Context, req Requirements) string {
+	query := req.SchemaQuery
+	if query == "" {
+		query = DefaultSchemaQuery
+	}
+
+	var got int64
+	if err := db.QueryRowContext(ctx, query).Scan(&got); err != nil {
+		return fmt.Sprintf("schema version unavailable (expected %d): %v", req.SchemaVersion, err)
+	}
+
+	if got < req.SchemaVersion {
+		return fmt.Sprintf("schema version %d is behind the required %d", got, req.SchemaVersion)
+	}
+	return ""
+}
+
+// parseServerVersion converts "15", "14.5" or "9.6.24" into the
+// server_version_num format (150000, 140005, 90624).
+func parseServerVersion(version string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid postgres version %q", version)
+	}
+
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid postgres version %q", version)
+		}
+		nums[i] = n
+	}
+
+	// Since PostgreSQL 10 the version is major.minor; before it,
+	// major.major.minor.
+	if nums[0] >= 10 {
+		return nums[0]*10000 + nums[1], nil
+	}
+	return nums[0]*10000 + nums[1]*100 + nums[2], nil
+}
+
+func formatServerVersion(num int) string {
+	if num >= 100000 {
+		return fmt.Sprintf("%d.%d", num/10000, num%10000)
+	}
+	return fmt.Sprintf("%d.%d.%d", num/10000, num/100%100, num%100)
+}
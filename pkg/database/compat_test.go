@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+	}{
+		{"15", 150000},
+		{"14.5", 140005},
+		{"16.10", 160010},
+		{"9.6.24", 90624},
+	}
+
+	for _, tt := range tests {
+		got, err := parseServerVersion(tt.version)
+		if err != nil {
+			t.Errorf("parseServerVersion(%q) error = %v", tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseServerVersion(%q) = %d, want %d", tt.version, got, tt.want)
+		}
+		if back, _ := parseServerVersion(formatServerVersion(got)); back != got {
+			t.Errorf("formatServerVersion(%d) = %q does not round-trip", got, formatServerVersion(got))
+		}
+	}
+
+	for _, invalid := range []string{"", "fifteen", "15.x", "1.2.3.4"} {
+		if _, err := parseServerVersion(invalid); err == nil {
+			t.Errorf("parseServerVersion(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestCheckCompatibilityNotConnected(t *testing.T) {
+	db, err := New(&Config{}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	err = db.CheckCompatibility(context.Background(), Requirements{MinVersion: "15"})
+	if !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
@@ -2,11 +2,14 @@ package database
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/marcelofabianov/config"
 	"github.com/spf13/viper"
 )
 
@@ -21,12 +24,23 @@ type DatabaseConfig struct {
 }
 
 type DatabaseCredentialsConfig struct {
+	// Host is a single host or a comma-separated failover list, each entry
+	// optionally with its own port ("pg-a:5432,pg-b:5433").
 	Host     string
 	Port     int
 	User     string
 	Password string
 	Name     string
 	SSLMode  string
+	// TargetSessionAttrs selects which host of a multi-host list to use,
+	// e.g. "read-write" to always land on the current primary.
+	TargetSessionAttrs string
+	// ApplicationName labels connections in pg_stat_activity. When empty,
+	// DefaultApplicationName derives one from the build info.
+	ApplicationName string
+	// Options holds extra connection parameters, such as those in the query
+	// string of DATABASE_URL or DATABASE_PARAMS (connect_timeout, ...).
+	Options map[string]string
 }
 
 type DatabaseConnectConfig struct {
@@ -47,16 +61,21 @@ type DatabasePoolConfig struct {
 	HealthCheckPeriod time.Duration
 }
 
+// LoadConfig loads the DATABASE_* settings with config.Load. Services that
+// load several packages should call config.Load once and use
+// LoadConfigFrom.
 func LoadConfig() (*Config, error) {
-	v := viper.New()
-	v.SetEnvPrefix("DATABASE")
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	if envFile := findEnvFile(); envFile != "" {
-		v.SetConfigFile(envFile)
-		_ = v.ReadInConfig()
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
 	}
+	return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom loads the database section of c: DATABASE_* variables over
+// the database section of the config file.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+	v := c.Section("database", "DATABASE")
 
 	setDefaults(v)
 
@@ -89,6 +108,35 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	if rawURL := v.GetString("url"); rawURL != "" {
+		creds, err := ParseDatabaseURL(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Database.Credentials = creds
+	}
+
+	if attrs := v.GetString("target_session_attrs"); attrs != "" {
+		cfg.Database.Credentials.TargetSessionAttrs = attrs
+	}
+
+	if name := v.GetString("application_name"); name != "" {
+		cfg.Database.Credentials.ApplicationName = name
+	}
+
+	params, err := parseParams(v.GetString("params"))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range params {
+		if cfg.Database.Credentials.Options == nil {
+			cfg.Database.Credentials.Options = make(map[string]string)
+		}
+		if _, ok := cfg.Database.Credentials.Options[key]; !ok {
+			cfg.Database.Credentials.Options[key] = value
+		}
+	}
+
 	if err := ValidateConfig(cfg); err != nil {
 		return nil, err
 	}
@@ -96,13 +144,73 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ParseDatabaseURL parses a postgres:// or postgresql:// URL, as handed out
+// by Heroku, Railway or RDS proxies, into credentials. sslmode comes from
+// the query string (default "disable"); other query parameters are kept in
+// Options.
+func ParseDatabaseURL(rawURL string) (DatabaseCredentialsConfig, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return DatabaseCredentialsConfig{}, fmt.Errorf("invalid database url: %w", err)
+	}
+
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return DatabaseCredentialsConfig{}, fmt.Errorf("database url scheme must be postgres or postgresql, got %q", u.Scheme)
+	}
+
+	creds := DatabaseCredentialsConfig{
+		Host:    u.Hostname(),
+		Port:    5432,
+		Name:    strings.TrimPrefix(u.Path, "/"),
+		SSLMode: "disable",
+	}
+
+	if strings.Contains(u.Host, ",") {
+		creds.Host = u.Host
+	} else if port := u.Port(); port != "" {
+		creds.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return DatabaseCredentialsConfig{}, fmt.Errorf("invalid database url port %q", port)
+		}
+	}
+
+	if u.User != nil {
+		creds.User = u.User.Username()
+		creds.Password, _ = u.User.Password()
+	}
+
+	for key, values := range u.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		if key == "sslmode" {
+			creds.SSLMode = values[0]
+			continue
+		}
+		if key == "target_session_attrs" {
+			creds.TargetSessionAttrs = values[0]
+			continue
+		}
+		if creds.Options == nil {
+			creds.Options = make(map[string]string)
+		}
+		creds.Options[key] = values[0]
+	}
+
+	return creds, nil
+}
+
 func setDefaults(v *viper.Viper) {
+	v.SetDefault("url", "")
 	v.SetDefault("host", "localhost")
 	v.SetDefault("port", 5432)
 	v.SetDefault("user", "postgres")
 	v.SetDefault("password", "")
 	v.SetDefault("name", "postgres")
 	v.SetDefault("sslmode", "disable")
+	v.SetDefault("target_session_attrs", "")
+	v.SetDefault("application_name", "")
+	v.SetDefault("params", "")
 	v.SetDefault("connect.query_timeout", 5*time.Second)
 	v.SetDefault("connect.exec_timeout", 10*time.Second)
 	v.SetDefault("connect.backoff_min", 500*time.Millisecond)
@@ -117,27 +225,6 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("pool.health_check_period", 30*time.Second)
 }
 
-func findEnvFile() string {
-	dir, err := os.Getwd()
-	if err != nil {
-		return ""
-	}
-
-	for i := 0; i < 5; i++ {
-		envPath := filepath.Join(dir, ".env")
-		if _, err := os.Stat(envPath); err == nil {
-			return envPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-
-	return ""
-}
-
 func ValidateConfig(cfg *Config) error {
 	if cfg.Database.Credentials.Host == "" {
 		return fmt.Errorf("database host cannot be empty")
@@ -165,13 +252,89 @@ func ValidateConfig(cfg *Config) error {
 
 func (c *Config) GetDatabaseDSN() string {
 	creds := c.Database.Credentials
-	return fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		creds.Host,
-		creds.Port,
-		creds.User,
-		creds.Password,
-		creds.Name,
-		creds.SSLMode,
+	hosts, ports := splitHosts(creds.Host, creds.Port)
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dsnValue(hosts),
+		ports,
+		dsnValue(creds.User),
+		dsnValue(creds.Password),
+		dsnValue(creds.Name),
+		dsnValue(creds.SSLMode),
 	)
+
+	options := make(map[string]string, len(creds.Options)+1)
+	for key, value := range creds.Options {
+		options[key] = value
+	}
+	if _, ok := options["target_session_attrs"]; !ok && creds.TargetSessionAttrs != "" {
+		options["target_session_attrs"] = creds.TargetSessionAttrs
+	}
+	if _, ok := options["application_name"]; !ok {
+		options["application_name"] = creds.ApplicationName
+		if options["application_name"] == "" {
+			options["application_name"] = DefaultApplicationName()
+		}
+	}
+
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		dsn += " " + key + "=" + dsnValue(options[key])
+	}
+
+	return dsn
+}
+
+// splitHosts turns "pg-a:5432,pg-b" into the parallel host and port lists
+// of a keyword DSN, using defaultPort where an entry has none.
+func splitHosts(host string, defaultPort int) (string, string) {
+	entries := strings.Split(host, ",")
+	hosts := make([]string, 0, len(entries))
+	ports := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		h, p, err := net.SplitHostPort(entry)
+		if err != nil {
+			h, p = entry, strconv.Itoa(defaultPort)
+		}
+		hosts = append(hosts, h)
+		ports = append(ports, p)
+	}
+
+	return strings.Join(hosts, ","), strings.Join(ports, ",")
+}
+
+// parseParams parses DATABASE_PARAMS, a comma-separated list of key=value
+// connection parameters.
+func parseParams(raw string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid database param %q, expected key=value", pair)
+		}
+		params[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return params, nil
+}
+
+// dsnValue quotes a keyword/value DSN value when it is empty or contains
+// spaces, quotes or backslashes.
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
 }
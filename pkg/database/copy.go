@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/marcelofabianov/fault"
+)
+
+const defaultCopyChunkSize = 5000
+
+var ErrCopyFailed = fault.New(
+	"failed to copy rows",
+	fault.WithCode(fault.Internal),
+)
+
+// ChunkError reports a chunk that could not be copied. Offset is the index
+// of the chunk's first row in the input slice.
+type ChunkError struct {
+	Chunk  int
+	Offset int
+	Rows   int
+	Err    error
+}
+
+// CopyResult summarizes a CopyFrom call.
+type CopyResult struct {
+	Inserted int64
+	Chunks   int
+	Failed   []ChunkError
+}
+
+type copyOptions struct {
+	chunkSize       int
+	continueOnError bool
+}
+
+type CopyOption func(*copyOptions)
+
+// WithCopyChunkSize sets how many rows are sent per COPY statement.
+func WithCopyChunkSize(size int) CopyOption {
+	return func(o *copyOptions) {
+		if size > 0 {
+			o.chunkSize = size
+		}
+	}
+}
+
+// WithCopyContinueOnError keeps copying the remaining chunks after a chunk
+// fails instead of stopping at the first failure.
+func WithCopyContinueOnError() CopyOption {
+	return func(o *copyOptions) {
+		o.continueOnError = true
+	}
+}
+
+// CopyFrom bulk loads rows into table using the PostgreSQL COPY protocol.
+// Rows are sent in chunks, each one atomic on its own: a failing chunk does
+// not roll back the chunks already copied. Failures are reported per chunk
+// in the result and summarized in the returned error.
+func (db *DB) CopyFrom(ctx context.Context, table string, columns []string, rows [][]any, opts ...CopyOption) (*CopyResult, error) {
+	if db.conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	if table == "" || len(columns) == 0 {
+		return nil, fault.Wrap(ErrCopyFailed, "table and columns are required",
+			fault.WithCode(fault.Invalid),
+		)
+	}
+
+	options := copyOptions{chunkSize: defaultCopyChunkSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	result := &CopyResult{}
+	identifier := pgx.Identifier(strings.Split(table, "."))
+
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, fault.Wrap(ErrCopyFailed, "failed to acquire connection",
+			fault.WithWrappedErr(err),
+		)
+	}
+	defer conn.Close()
+
+	for offset := 0; offset < len(rows); offset += options.chunkSize {
+		end := min(offset+options.chunkSize, len(rows))
+		chunk := rows[offset:end]
+		result.Chunks++
+
+		inserted, err := db.copyChunk(ctx, conn.Raw, identifier, columns, chunk)
+		if err != nil {
+			db.logger.Error("Copy chunk failed",
+				"table", table,
+				"chunk", result.Chunks-1,
+				"offset", offset,
+				"rows", len(chunk),
+				"error", err.Error(),
+			)
+
+			result.Failed = append(result.Failed, ChunkError{
+				Chunk:  result.Chunks - 1,
+				Offset: offset,
+				Rows:   len(chunk),
+				Err:    err,
+			})
+
+			if !options.continueOnError {
+				break
+			}
+			continue
+		}
+
+		result.Inserted += inserted
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fault.Wrap(ErrCopyFailed, fmt.Sprintf("%d chunk(s) failed", len(result.Failed)),
+			fault.WithWrappedErr(result.Failed[0].Err),
+			fault.WithContext("table", table),
+			fault.WithContext("inserted", result.Inserted),
+			fault.WithContext("failed_chunks", len(result.Failed)),
+		)
+	}
+
+	return result, nil
+}
+
+func (db *DB) copyChunk(ctx context.Context, raw func(func(any) error) error, table pgx.Identifier, columns []string, chunk [][]any) (int64, error) {
+	execCtx, cancel := context.WithTimeout(ctx, db.config.Database.Connect.ExecTimeout)
+	defer cancel()
+
+	var inserted int64
+	err := raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+
+		n, err := stdConn.Conn().CopyFrom(execCtx, table, columns, pgx.CopyFromRows(chunk))
+		inserted = n
+		return err
+	})
+
+	return inserted, err
+}
@@ -0,0 +1,257 @@
+// Package databasetest provides a disposable PostgreSQL for repository tests.
+//
+// NewPostgres connects to DATABASE_TEST_DSN when it is set, or starts a
+// Postgres container shared by every test in the package binary. Migrations
+// are applied once per database and each test runs inside its own
+// transaction, rolled back on cleanup, so tests never see each other's rows.
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/database"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+const (
+	// DSNEnv points the harness at an existing database instead of a
+	// container, e.g. a CI service container.
+	DSNEnv = "DATABASE_TEST_DSN"
+
+	DefaultImage = "postgres:16-alpine"
+
+	migrationsTable = "databasetest_migrations"
+)
+
+// Postgres is the handle a test receives. DB is shared by the package; Tx is
+// private to the test and rolled back when it ends.
+type Postgres struct {
+	DB  *database.DB
+	Tx  *sql.Tx
+	DSN string
+}
+
+type options struct {
+	image      string
+	migrations fs.FS
+	pattern    string
+}
+
+type Option func(*options)
+
+// WithImage overrides the container image. It only takes effect for the
+// call that starts the shared container.
+func WithImage(image string) Option {
+	return func(o *options) {
+		if image != "" {
+			o.image = image
+		}
+	}
+}
+
+// WithMigrations applies the .sql files in fsys matching pattern, in lexical
+// order, before handing out the transaction. Each file is applied once per
+// database and recorded in databasetest_migrations.
+func WithMigrations(fsys fs.FS, pattern string) Option {
+	return func(o *options) {
+		o.migrations = fsys
+		o.pattern = pattern
+	}
+}
+
+var shared struct {
+	once sync.Once
+	db   *database.DB
+	dsn  string
+	err  error
+
+	migrate sync.Mutex
+}
+
+// NewPostgres returns a database handle with a per-test transaction. The
+// test is skipped when neither DATABASE_TEST_DSN nor a container runtime is
+// available, and in -short mode.
+func NewPostgres(t *testing.T, opts ...Option) *Postgres {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("databasetest: skipping database test in short mode")
+	}
+
+	o := options{image: DefaultImage, pattern: "*.sql"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	sharedDB(t, o.image)
+	ctx := context.Background()
+
+	if o.migrations != nil {
+		if err := migrate(ctx, shared.db, o.migrations, o.pattern); err != nil {
+			t.Fatalf("databasetest: %v", err)
+		}
+	}
+
+	tx, err := shared.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+
+	return &Postgres{
+		DB:  shared.db,
+		Tx:  tx,
+		DSN: shared.dsn,
+	}
+}
+
+// sharedDB starts or connects to the package's shared database, skipping
+// the test when no database is available.
+func sharedDB(t *testing.T, image string) {
+	t.Helper()
+
+	if os.Getenv(DSNEnv) == "" {
+		testcontainers.SkipIfProviderIsNotHealthy(t)
+	}
+
+	shared.once.Do(func() {
+		shared.db, shared.dsn, shared.err = connect(image)
+	})
+	if shared.err != nil {
+		t.Fatalf("databasetest: %v", shared.err)
+	}
+}
+
+func connect(image string) (*database.DB, string, error) {
+	ctx := context.Background()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		// The container is reaped by testcontainers once the test binary
+		// exits, so it is not terminated here.
+		container, err := postgres.Run(ctx, image,
+			postgres.WithDatabase("test"),
+			postgres.WithUsername("test"),
+			postgres.WithPassword("test"),
+			postgres.BasicWaitStrategies(),
+		)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to start postgres container: %w", err)
+		}
+
+		dsn, err = container.ConnectionString(ctx, "sslmode=disable")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read container DSN: %w", err)
+		}
+	}
+
+	creds, err := database.ParseDatabaseURL(dsn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	db, err := open(ctx, creds)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return db, dsn, nil
+}
+
+// open connects to the database described by creds with settings suited to
+// tests.
+func open(ctx context.Context, creds database.DatabaseCredentialsConfig) (*database.DB, error) {
+	creds.ApplicationName = "databasetest"
+
+	cfg := &database.Config{
+		Database: database.DatabaseConfig{
+			Credentials: creds,
+			Connect: database.DatabaseConnectConfig{
+				QueryTimeout: 30 * time.Second,
+				ExecTimeout:  time.Minute,
+			},
+			Pool: database.DatabasePoolConfig{
+				MaxOpenConns:      10,
+				MaxIdleConns:      5,
+				ConnMaxLifetime:   5 * time.Minute,
+				ConnMaxIdleTime:   time.Minute,
+				HealthCheckPeriod: time.Minute,
+			},
+		},
+	}
+
+	db, err := database.New(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrate applies pending files in a single transaction, serialized by an
+// advisory lock so test binaries sharing DATABASE_TEST_DSN do not race.
+func migrate(ctx context.Context, db *database.DB, fsys fs.FS, pattern string) error {
+	shared.migrate.Lock()
+	defer shared.migrate.Unlock()
+
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("invalid migration pattern %q: %w", pattern, err)
+	}
+	sort.Strings(files)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.AdvisoryXactLock(ctx, tx, database.AdvisoryKey(migrationsTable)); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationsTable+
+		" (name text PRIMARY KEY, applied_at timestamptz NOT NULL DEFAULT now())"); err != nil {
+		return fmt.Errorf("failed to create %s: %w", migrationsTable, err)
+	}
+
+	for _, file := range files {
+		var applied bool
+		row := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+migrationsTable+" WHERE name = $1)", file)
+		if err := row.Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", file, err)
+		}
+		if applied {
+			continue
+		}
+
+		script, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("migration %s failed: %w", file, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO "+migrationsTable+" (name) VALUES ($1)", file); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", file, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,59 @@
+package databasetest_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/marcelofabianov/database/databasetest"
+)
+
+var migrations = os.DirFS("testdata")
+
+func TestNewPostgresMigrates(t *testing.T) {
+	pg := databasetest.NewPostgres(t, databasetest.WithMigrations(migrations, "*.sql"))
+
+	var exists bool
+	err := pg.Tx.QueryRowContext(context.Background(),
+		"SELECT to_regclass('public.students') IS NOT NULL").Scan(&exists)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if !exists {
+		t.Error("expected students table to be migrated")
+	}
+}
+
+func TestNewPostgresIsolatesTests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("insert", func(t *testing.T) {
+		pg := databasetest.NewPostgres(t, databasetest.WithMigrations(migrations, "*.sql"))
+
+		if _, err := pg.Tx.ExecContext(ctx, "INSERT INTO students (name) VALUES ('Ana')"); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	})
+
+	t.Run("rolled back", func(t *testing.T) {
+		pg := databasetest.NewPostgres(t, databasetest.WithMigrations(migrations, "*.sql"))
+
+		var count int
+		if err := pg.Tx.QueryRowContext(ctx, "SELECT count(*) FROM students").Scan(&count); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("expected previous test's rows to be rolled back, got %d", count)
+		}
+	})
+}
+
+func TestCheckReversible(t *testing.T) {
+	fsys, err := fs.Sub(os.DirFS("testdata"), "reversible")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	databasetest.CheckReversible(t, fsys)
+}
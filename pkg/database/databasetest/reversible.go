@@ -0,0 +1,224 @@
+package databasetest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/marcelofabianov/database"
+)
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// schemaQueries describe the user-visible schema, one line per object, so
+// two captures can be compared line by line.
+var schemaQueries = []string{
+	`SELECT 'schema ' || nspname FROM pg_namespace
+	  WHERE nspname NOT LIKE 'pg\_%' AND nspname <> 'information_schema'`,
+	`SELECT 'column ' || table_schema || '.' || table_name || '.' || column_name || ' ' ||
+	        data_type || ' nullable=' || is_nullable || ' default=' || coalesce(column_default, '')
+	   FROM information_schema.columns
+	  WHERE table_schema NOT IN ('pg_catalog', 'information_schema')`,
+	`SELECT 'index ' || schemaname || ' ' || indexdef FROM pg_indexes
+	  WHERE schemaname NOT IN ('pg_catalog', 'information_schema')`,
+	`SELECT 'constraint ' || c.conrelid::regclass::text || ' ' || c.conname || ' ' || pg_get_constraintdef(c.oid)
+	   FROM pg_constraint c JOIN pg_namespace n ON n.oid = c.connamespace
+	  WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+	`SELECT 'sequence ' || sequence_schema || '.' || sequence_name FROM information_schema.sequences`,
+	`SELECT 'enum ' || n.nspname || '.' || t.typname || ' ' || string_agg(e.enumlabel, ',' ORDER BY e.enumsortorder)
+	   FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid JOIN pg_namespace n ON n.oid = t.typnamespace
+	  GROUP BY n.nspname, t.typname`,
+	`SELECT 'function ' || n.nspname || '.' || p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')'
+	   FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+	  WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')`,
+}
+
+type migrationPair struct {
+	name string
+	up   string
+	down string
+}
+
+// CheckReversible verifies that the migrations in fsys can be rolled back
+// and re-applied. In a scratch database it applies every up file, captures
+// the schema, applies every down file in reverse order, checks nothing is
+// left behind, applies the up files again and compares the schema with the
+// first capture. Migrations are paired by name: 001_students.up.sql and
+// 001_students.down.sql; an up file without its down file fails the check.
+//
+// The scratch database is created on the shared server, so the
+// DATABASE_TEST_DSN user needs CREATEDB.
+func CheckReversible(t *testing.T, fsys fs.FS, opts ...Option) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("databasetest: skipping database test in short mode")
+	}
+
+	o := options{image: DefaultImage}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+
+	sharedDB(t, o.image)
+	db := scratchDB(t)
+	ctx := context.Background()
+
+	baseline := captureSchema(t, db)
+
+	applyUp(t, db, migrations)
+	first := captureSchema(t, db)
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, err := db.ExecContext(ctx, migrations[i].down); err != nil {
+			t.Fatalf("databasetest: down migration %s failed: %v", migrations[i].name, err)
+		}
+	}
+	if added, removed := diffSchema(baseline, captureSchema(t, db)); len(added)+len(removed) > 0 {
+		t.Errorf("databasetest: down migrations did not restore the empty schema\nleft behind:\n  %s\nmissing:\n  %s",
+			strings.Join(added, "\n  "), strings.Join(removed, "\n  "))
+	}
+
+	applyUp(t, db, migrations)
+	if added, removed := diffSchema(first, captureSchema(t, db)); len(added)+len(removed) > 0 {
+		t.Errorf("databasetest: re-applying migrations produced a different schema\nonly after re-apply:\n  %s\nonly after first apply:\n  %s",
+			strings.Join(added, "\n  "), strings.Join(removed, "\n  "))
+	}
+}
+
+// scratchDB creates an empty database on the shared server and drops it
+// when the test ends.
+func scratchDB(t *testing.T) *database.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	name := fmt.Sprintf("databasetest_%d", time.Now().UnixNano())
+	quoted := pgx.Identifier{name}.Sanitize()
+
+	if _, err := shared.db.ExecContext(ctx, "CREATE DATABASE "+quoted); err != nil {
+		t.Fatalf("databasetest: failed to create scratch database: %v", err)
+	}
+
+	creds, err := database.ParseDatabaseURL(shared.dsn)
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+	creds.Name = name
+
+	db, err := open(ctx, creds)
+	if err != nil {
+		t.Fatalf("databasetest: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+		_, _ = shared.db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+quoted+" WITH (FORCE)")
+	})
+
+	return db
+}
+
+func applyUp(t *testing.T, db *database.DB, migrations []migrationPair) {
+	t.Helper()
+
+	for _, m := range migrations {
+		if _, err := db.ExecContext(context.Background(), m.up); err != nil {
+			t.Fatalf("databasetest: up migration %s failed: %v", m.name, err)
+		}
+	}
+}
+
+func captureSchema(t *testing.T, db *database.DB) []string {
+	t.Helper()
+	ctx := context.Background()
+
+	var lines []string
+	for _, query := range schemaQueries {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatalf("databasetest: failed to capture schema: %v", err)
+		}
+		for rows.Next() {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				rows.Close()
+				t.Fatalf("databasetest: failed to capture schema: %v", err)
+			}
+			lines = append(lines, line)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			t.Fatalf("databasetest: failed to capture schema: %v", err)
+		}
+	}
+
+	sort.Strings(lines)
+	return lines
+}
+
+// loadMigrations pairs up and down files and returns them in lexical order.
+func loadMigrations(fsys fs.FS) ([]migrationPair, error) {
+	ups, err := fs.Glob(fsys, "*"+upSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if len(ups) == 0 {
+		return nil, fmt.Errorf("no %s files found", upSuffix)
+	}
+	sort.Strings(ups)
+
+	migrations := make([]migrationPair, 0, len(ups))
+	for _, upFile := range ups {
+		name := strings.TrimSuffix(upFile, upSuffix)
+
+		up, err := fs.ReadFile(fsys, upFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", upFile, err)
+		}
+		down, err := fs.ReadFile(fsys, name+downSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no readable %s file: %w", name, downSuffix, err)
+		}
+
+		migrations = append(migrations, migrationPair{name: name, up: string(up), down: string(down)})
+	}
+
+	return migrations, nil
+}
+
+// diffSchema returns the lines only in after (added) and only in before
+// (removed). Both inputs must be sorted.
+func diffSchema(before, after []string) (added, removed []string) {
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			i++
+			j++
+		case before[i] < after[j]:
+			removed = append(removed, before[i])
+			i++
+		default:
+			added = append(added, after[j])
+			j++
+		}
+	}
+	removed = append(removed, before[i:]...)
+	added = append(added, after[j:]...)
+
+	return added, removed
+}
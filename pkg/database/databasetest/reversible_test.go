@@ -0,0 +1,55 @@
+package databasetest
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_email.up.sql":      {Data: []byte("ALTER TABLE students ADD COLUMN email text;")},
+		"002_email.down.sql":    {Data: []byte("ALTER TABLE students DROP COLUMN email;")},
+		"001_students.up.sql":   {Data: []byte("CREATE TABLE students (id int);")},
+		"001_students.down.sql": {Data: []byte("DROP TABLE students;")},
+	}
+
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	if len(migrations) != 2 || migrations[0].name != "001_students" || migrations[1].name != "002_email" {
+		t.Fatalf("expected migrations in lexical order, got %+v", migrations)
+	}
+	if migrations[0].down != "DROP TABLE students;" {
+		t.Errorf("unexpected down script %q", migrations[0].down)
+	}
+
+	delete(fsys, "002_email.down.sql")
+	if _, err := loadMigrations(fsys); err == nil {
+		t.Error("expected an error for an up migration without a down file")
+	}
+
+	if _, err := loadMigrations(fstest.MapFS{}); err == nil {
+		t.Error("expected an error when there are no migrations")
+	}
+}
+
+func TestDiffSchema(t *testing.T) {
+	before := []string{"column public.students.id", "column public.students.name", "index students_pkey"}
+	after := []string{"column public.students.id", "column public.students.nickname", "index students_pkey"}
+
+	added, removed := diffSchema(before, after)
+
+	if !reflect.DeepEqual(added, []string{"column public.students.nickname"}) {
+		t.Errorf("unexpected added %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"column public.students.name"}) {
+		t.Errorf("unexpected removed %v", removed)
+	}
+
+	if added, removed := diffSchema(before, before); len(added)+len(removed) != 0 {
+		t.Errorf("expected no diff for identical schemas, got %v %v", added, removed)
+	}
+}
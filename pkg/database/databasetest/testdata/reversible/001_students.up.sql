@@ -0,0 +1,4 @@
+CREATE TABLE students (
+    id   bigserial PRIMARY KEY,
+    name text NOT NULL
+);
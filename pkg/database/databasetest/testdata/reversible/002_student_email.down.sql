@@ -0,0 +1,2 @@
+DROP INDEX students_email_idx;
+ALTER TABLE students DROP COLUMN email;
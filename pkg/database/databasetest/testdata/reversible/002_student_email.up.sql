@@ -0,0 +1,2 @@
+ALTER TABLE students ADD COLUMN email text;
+CREATE UNIQUE INDEX students_email_idx ON students (email);
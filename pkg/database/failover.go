@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	// readOnlyTransactionCode is SQLSTATE read_only_sql_transaction, raised
+	// when a write lands on a standby, typically a demoted primary.
+	readOnlyTransactionCode = "25006"
+
+	poolGenerationKey = "database.pool_generation"
+)
+
+// IsReadOnlyError reports whether err was raised because the statement ran
+// on a read-only (standby) server.
+func IsReadOnlyError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == readOnlyTransactionCode
+}
+
+// InvalidatePool marks every connection opened so far as stale. Idle
+// connections are dropped on their next checkout and busy ones once they are
+// returned, so new connections go through host selection again and find the
+// promoted primary.
+func (db *DB) InvalidatePool() {
+	generation := db.poolGeneration.Add(1)
+	db.logger.Warn("Database pool invalidated", "generation", generation)
+}
+
+// openPool opens the pool with hooks that tag each connection with the pool
+// generation it was created in and discard it once InvalidatePool moves on.
+func (db *DB) openPool(dsn string) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return stdlib.OpenDB(*connConfig,
+		stdlib.OptionAfterConnect(func(_ context.Context, conn *pgx.Conn) error {
+			conn.PgConn().CustomData()[poolGenerationKey] = db.poolGeneration.Load()
+			return nil
+		}),
+		stdlib.OptionResetSession(func(_ context.Context, conn *pgx.Conn) error {
+			generation, _ := conn.PgConn().CustomData()[poolGenerationKey].(uint64)
+			if generation < db.poolGeneration.Load() {
+				return driver.ErrBadConn
+			}
+			return nil
+		}),
+	), nil
+}
+
+// checkFailover invalidates the pool when err shows the connection is
+// talking to a server that is no longer the primary.
+func (db *DB) checkFailover(err error) {
+	if IsReadOnlyError(err) {
+		db.InvalidatePool()
+	}
+}
@@ -0,0 +1,27 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/marcelofabianov/database"
+)
+
+func TestIsReadOnlyError(t *testing.T) {
+	readOnly := &pgconn.PgError{Code: "25006", Message: "cannot execute INSERT in a read-only transaction"}
+
+	if !database.IsReadOnlyError(readOnly) {
+		t.Error("expected read-only error to be detected")
+	}
+	if !database.IsReadOnlyError(fmt.Errorf("exec: %w", readOnly)) {
+		t.Error("expected wrapped read-only error to be detected")
+	}
+	if database.IsReadOnlyError(&pgconn.PgError{Code: "23505"}) {
+		t.Error("expected unique violation not to be a read-only error")
+	}
+	if database.IsReadOnlyError(errors.New("connection refused")) {
+		t.Error("expected plain error not to be a read-only error")
+	}
+}
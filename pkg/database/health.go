@@ -0,0 +1,30 @@
+package database
+
+import "context"
+
+// HealthChecker adapts a DB to the web.HealthChecker interface so it can be
+// passed straight to web.ReadinessHandler.
+type HealthChecker struct {
+	db   *DB
+	name string
+}
+
+// NewHealthChecker returns a checker named "database". Use WithName to tell
+// several databases apart in the readiness response.
+func NewHealthChecker(db *DB) *HealthChecker {
+	return &HealthChecker{db: db, name: "database"}
+}
+
+// WithName sets the name reported in the readiness response.
+func (h *HealthChecker) WithName(name string) *HealthChecker {
+	h.name = name
+	return h
+}
+
+func (h *HealthChecker) Name() string {
+	return h.name
+}
+
+func (h *HealthChecker) Check(ctx context.Context) error {
+	return h.db.HealthCheck(ctx)
+}
@@ -0,0 +1,28 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcelofabianov/database"
+)
+
+func TestHealthChecker(t *testing.T) {
+	db, err := database.New(&database.Config{}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	checker := database.NewHealthChecker(db)
+	if checker.Name() != "database" {
+		t.Errorf("expected name database, got %s", checker.Name())
+	}
+	if checker.WithName("primary").Name() != "primary" {
+		t.Errorf("expected name primary, got %s", checker.Name())
+	}
+
+	if err := checker.Check(context.Background()); !errors.Is(err, database.ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
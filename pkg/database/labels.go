@@ -0,0 +1,57 @@
+package database
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+)
+
+// maxApplicationName is the length at which Postgres truncates
+// application_name (NAMEDATALEN - 1).
+const maxApplicationName = 63
+
+// DefaultApplicationName labels connections as "service/version@pod" so DBAs
+// can tell which deployment holds a connection. The service and version come
+// from the binary's build info, the pod from HOSTNAME; missing parts are
+// left out.
+func DefaultApplicationName() string {
+	service := filepath.Base(os.Args[0])
+	version := ""
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path != "" {
+			service = path.Base(info.Main.Path)
+		}
+		if info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+	}
+
+	name := service
+	if version != "" {
+		name += "/" + version
+	}
+
+	pod := os.Getenv("HOSTNAME")
+	if pod == "" {
+		pod, _ = os.Hostname()
+	}
+	if pod != "" {
+		name += "@" + pod
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if len(name) > maxApplicationName {
+		name = name[:maxApplicationName]
+	}
+
+	return name
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+var (
+	ErrLockFailed = fault.New(
+		"failed to acquire advisory lock",
+		fault.WithCode(fault.Internal),
+	)
+
+	ErrUnlockFailed = fault.New(
+		"failed to release advisory lock",
+		fault.WithCode(fault.Internal),
+	)
+)
+
+// unlockTimeout bounds pg_advisory_unlock, which runs even when the caller's
+// context is already done so the lock does not outlive its holder.
+const unlockTimeout = 5 * time.Second
+
+// AdvisoryKey derives a lock key from a name, so callers can lock on
+// "scheduler:daily-report" instead of agreeing on magic numbers.
+func AdvisoryKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// AdvisoryLock is a session-scoped Postgres advisory lock. It pins the
+// pooled connection it was taken on until Unlock is called.
+type AdvisoryLock struct {
+	mu     sync.Mutex
+	conn   *sql.Conn
+	key    int64
+	logger *slog.Logger
+}
+
+// Key returns the lock key.
+func (l *AdvisoryLock) Key() int64 {
+	return l.key
+}
+
+// Unlock releases the lock and returns the connection to the pool. It runs
+// even if ctx is already cancelled, and when the release fails the
+// connection is discarded so the session, and the lock with it, ends. It is
+// safe to call more than once.
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	conn := l.conn
+	l.conn = nil
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), unlockTimeout)
+	defer cancel()
+
+	var released bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", l.key).Scan(&released); err != nil {
+		discard(conn)
+		l.logger.Error("Advisory unlock failed", "key", l.key, "error", err.Error())
+		return fault.Wrap(ErrUnlockFailed, "unlock failed",
+			fault.WithWrappedErr(err),
+			fault.WithContext("key", l.key),
+		)
+	}
+
+	if !released {
+		return fault.Wrap(ErrUnlockFailed, "lock was not held",
+			fault.WithContext("key", l.key),
+		)
+	}
+
+	return nil
+}
+
+// AdvisoryLock blocks until the session-scoped lock for key is acquired or
+// ctx is done. Release it with Unlock.
+func (db *DB) AdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	conn, err := db.lockConn(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		discard(conn)
+		return nil, db.lockError(err, key)
+	}
+
+	return &AdvisoryLock{conn: conn, key: key, logger: db.logger}, nil
+}
+
+// TryAdvisoryLock attempts to take the session-scoped lock for key without
+// waiting. It returns a nil lock and false when another session holds it.
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, bool, error) {
+	conn, err := db.lockConn(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		discard(conn)
+		return nil, false, db.lockError(err, key)
+	}
+
+	if !acquired {
+		// Nothing is held on this session, so the connection can be reused.
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &AdvisoryLock{conn: conn, key: key, logger: db.logger}, true, nil
+}
+
+// AdvisoryXactLock blocks until the transaction-scoped lock for key is
+// acquired. The lock is released when tx commits or rolls back.
+func (db *DB) AdvisoryXactLock(ctx context.Context, tx *sql.Tx, key int64) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", key); err != nil {
+		return db.lockError(err, key)
+	}
+
+	return nil
+}
+
+// TryAdvisoryXactLock attempts to take the transaction-scoped lock for key
+// without waiting and reports whether it was acquired.
+func (db *DB) TryAdvisoryXactLock(ctx context.Context, tx *sql.Tx, key int64) (bool, error) {
+	var acquired bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", key).Scan(&acquired); err != nil {
+		return false, db.lockError(err, key)
+	}
+
+	return acquired, nil
+}
+
+func (db *DB) lockConn(ctx context.Context, key int64) (*sql.Conn, error) {
+	if db.conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, db.lockError(err, key)
+	}
+
+	return conn, nil
+}
+
+// discard closes conn instead of returning it to the pool. A failed lock or
+// unlock call leaves it unknown whether the session still holds the lock,
+// and ending the session is the only way to be sure it does not.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+func (db *DB) lockError(err error, key int64) error {
+	db.logger.Error("Advisory lock failed", "key", key, "error", err.Error())
+	return fault.Wrap(ErrLockFailed, "lock failed",
+		fault.WithWrappedErr(err),
+		fault.WithContext("key", key),
+	)
+}
@@ -0,0 +1,36 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcelofabianov/database"
+)
+
+func TestAdvisoryKey(t *testing.T) {
+	a := database.AdvisoryKey("scheduler:daily-report")
+	b := database.AdvisoryKey("scheduler:daily-report")
+	c := database.AdvisoryKey("migrations")
+
+	if a != b {
+		t.Errorf("expected stable key, got %d and %d", a, b)
+	}
+	if a == c {
+		t.Errorf("expected different names to produce different keys")
+	}
+}
+
+func TestAdvisoryLockNotConnected(t *testing.T) {
+	db, err := database.New(&database.Config{}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := db.AdvisoryLock(context.Background(), 1); !errors.Is(err, database.ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+	if _, _, err := db.TryAdvisoryLock(context.Background(), 1); !errors.Is(err, database.ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/marcelofabianov/fault"
+)
+
+var (
+	ErrNoRows = fault.New(
+		"no rows in result set",
+		fault.WithCode(fault.NotFound),
+	)
+
+	ErrScanFailed = fault.New(
+		"failed to scan rows",
+		fault.WithCode(fault.Internal),
+	)
+)
+
+// ScannableRows is the part of *sql.Rows the scanning helpers read, so they
+// take both *sql.Rows and the *Rows returned by QueryContext.
+type ScannableRows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// columnCache maps a struct type to its column name -> field index path.
+var columnCache sync.Map
+
+// ScanAll reads every remaining row into dest, mapping columns to struct
+// fields through `db` tags. Untagged fields use their lowercased name and
+// `db:"-"` skips a field. Rows are closed before returning.
+func ScanAll[T any](rows ScannableRows, dest *[]T) error {
+	if rows == nil {
+		return fault.Wrap(ErrScanFailed, "rows cannot be nil")
+	}
+	defer rows.Close()
+
+	if dest == nil {
+		return fault.Wrap(ErrScanFailed, "destination cannot be nil")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fault.Wrap(ErrScanFailed, "failed to read columns",
+			fault.WithWrappedErr(err),
+		)
+	}
+
+	result := make([]T, 0)
+	for rows.Next() {
+		var item T
+		if err := scanRow(rows, columns, &item); err != nil {
+			return err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fault.Wrap(ErrScanFailed, "rows iteration failed",
+			fault.WithWrappedErr(err),
+		)
+	}
+
+	*dest = result
+	return nil
+}
+
+// ScanOne reads the first row into dest and returns ErrNoRows when the
+// result set is empty. Remaining rows are discarded and rows are closed.
+func ScanOne[T any](rows ScannableRows, dest *T) error {
+	if rows == nil {
+		return fault.Wrap(ErrScanFailed, "rows cannot be nil")
+	}
+	defer rows.Close()
+
+	if dest == nil {
+		return fault.Wrap(ErrScanFailed, "destination cannot be nil")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fault.Wrap(ErrScanFailed, "failed to read columns",
+			fault.WithWrappedErr(err),
+		)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fault.Wrap(ErrScanFailed, "rows iteration failed",
+				fault.WithWrappedErr(err),
+			)
+		}
+		return ErrNoRows
+	}
+
+	return scanRow(rows, columns, dest)
+}
+
+func scanRow(rows ScannableRows, columns []string, dest any) error {
+	val := reflect.ValueOf(dest).Elem()
+	if val.Kind() != reflect.Struct {
+		return fault.Wrap(ErrScanFailed, "destination must be a struct",
+			fault.WithContext("type", val.Type().String()),
+		)
+	}
+
+	fields := columnFields(val.Type())
+	targets := make([]any, len(columns))
+
+	for i, column := range columns {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return fault.Wrap(ErrScanFailed, "column has no matching struct field",
+				fault.WithContext("column", column),
+				fault.WithContext("type", val.Type().String()),
+			)
+		}
+		targets[i] = val.FieldByIndex(index).Addr().Interface()
+	}
+
+	if err := rows.Scan(targets...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoRows
+		}
+		return fault.Wrap(ErrScanFailed, "scan failed",
+			fault.WithWrappedErr(err),
+			fault.WithContext("type", val.Type().String()),
+		)
+	}
+
+	return nil
+}
+
+func columnFields(typ reflect.Type) map[string][]int {
+	if cached, ok := columnCache.Load(typ); ok {
+		return cached.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	collectColumnFields(typ, nil, fields)
+
+	columnCache.Store(typ, fields)
+	return fields
+}
+
+func collectColumnFields(typ reflect.Type, parent []int, fields map[string][]int) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			collectColumnFields(field.Type, index, fields)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		if _, exists := fields[strings.ToLower(name)]; exists && len(parent) > 0 {
+			continue
+		}
+		fields[strings.ToLower(name)] = index
+	}
+}
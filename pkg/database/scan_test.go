@@ -0,0 +1,158 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/marcelofabianov/database"
+	"github.com/marcelofabianov/fault"
+)
+
+// stubDriver serves a fixed result set for any query, which is enough to
+// exercise the scanning helpers without a running Postgres.
+type stubDriver struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) { return &stubConn{d: d}, nil }
+
+type stubConn struct{ d *stubDriver }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) { return &stubStmt{d: c.d}, nil }
+func (c *stubConn) Close() error                        { return nil }
+func (c *stubConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type stubStmt struct{ d *stubDriver }
+
+func (s *stubStmt) Close() error  { return nil }
+func (s *stubStmt) NumInput() int { return -1 }
+func (s *stubStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &stubRows{d: s.d}, nil
+}
+
+type stubRows struct {
+	d   *stubDriver
+	pos int
+}
+
+func (r *stubRows) Columns() []string { return r.d.columns }
+func (r *stubRows) Close() error      { return nil }
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.d.rows) {
+		return io.EOF
+	}
+	copy(dest, r.d.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+type timestamps struct {
+	CreatedAt string `db:"created_at"`
+}
+
+type course struct {
+	timestamps
+	ID       int64  `db:"id"`
+	Title    string `db:"title"`
+	Internal string `db:"-"`
+}
+
+func openStub(t *testing.T, name string, d *stubDriver) *sql.DB {
+	t.Helper()
+	sql.Register(name, d)
+	conn, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestScanAll(t *testing.T) {
+	conn := openStub(t, "stub-scan-all", &stubDriver{
+		columns: []string{"id", "title", "created_at"},
+		rows: [][]driver.Value{
+			{int64(1), "Go", "2024-01-01"},
+			{int64(2), "SQL", "2024-02-01"},
+		},
+	})
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT")
+	if err != nil {
+		t.Fatalf("QueryContext() error = %v", err)
+	}
+
+	var courses []course
+	if err := database.ScanAll(rows, &courses); err != nil {
+		t.Fatalf("ScanAll() error = %v", err)
+	}
+
+	if len(courses) != 2 {
+		t.Fatalf("expected 2 courses, got %d", len(courses))
+	}
+	if courses[1].ID != 2 || courses[1].Title != "SQL" || courses[1].CreatedAt != "2024-02-01" {
+		t.Errorf("unexpected course %+v", courses[1])
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	t.Run("scans first row", func(t *testing.T) {
+		conn := openStub(t, "stub-scan-one", &stubDriver{
+			columns: []string{"id", "title"},
+			rows:    [][]driver.Value{{int64(7), "Go"}},
+		})
+
+		rows, err := conn.QueryContext(context.Background(), "SELECT")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+
+		var c course
+		if err := database.ScanOne(rows, &c); err != nil {
+			t.Fatalf("ScanOne() error = %v", err)
+		}
+		if c.ID != 7 || c.Title != "Go" {
+			t.Errorf("unexpected course %+v", c)
+		}
+	})
+
+	t.Run("returns not found on empty result", func(t *testing.T) {
+		conn := openStub(t, "stub-scan-empty", &stubDriver{columns: []string{"id"}})
+
+		rows, err := conn.QueryContext(context.Background(), "SELECT")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+
+		var c course
+		err = database.ScanOne(rows, &c)
+		if !errors.Is(err, database.ErrNoRows) || !fault.IsNotFound(err) {
+			t.Errorf("expected ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown columns", func(t *testing.T) {
+		conn := openStub(t, "stub-scan-unknown", &stubDriver{
+			columns: []string{"id", "unknown"},
+			rows:    [][]driver.Value{{int64(1), "x"}},
+		})
+
+		rows, err := conn.QueryContext(context.Background(), "SELECT")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+
+		var c course
+		if err := database.ScanOne(rows, &c); !errors.Is(err, database.ErrScanFailed) {
+			t.Errorf("expected ErrScanFailed, got %v", err)
+		}
+	})
+}
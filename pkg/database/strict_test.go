@@ -0,0 +1,31 @@
+package database_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/database"
+)
+
+func TestLoadConfigStrict(t *testing.T) {
+	t.Run("accepts known variables", func(t *testing.T) {
+		t.Setenv("DATABASE_HOST", "db")
+
+		if _, err := database.LoadConfigStrict(); err != nil {
+			t.Fatalf("LoadConfigStrict() error = %v", err)
+		}
+	})
+
+	t.Run("rejects misspelled variables", func(t *testing.T) {
+		t.Setenv("DATABASE_HOTS", "db")
+
+		_, err := database.LoadConfigStrict()
+		if !errors.Is(err, database.ErrUnknownEnv) {
+			t.Fatalf("expected ErrUnknownEnv, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "did you mean DATABASE_HOST?") {
+			t.Errorf("expected a suggestion in %q", err.Error())
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout returns a copy of ctx under which ExecContext,
+// QueryContext and QueryRowContext use d instead of the configured timeout.
+// A zero d leaves those calls bounded only by ctx:
+//
+//	rows, err := db.QueryContext(database.WithQueryTimeout(ctx, time.Minute), query, id)
+func WithQueryTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, d)
+}
+
+// callTimeout resolves the timeout for a call on ctx, falling back to def.
+func callTimeout(ctx context.Context, def time.Duration) time.Duration {
+	if d, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return def
+}
+
+// withTimeout derives the call context. A zero timeout leaves ctx as is.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Rows is the result of QueryContext. The query timeout keeps covering
+// iteration and is released by Close.
+type Rows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+// Close closes the rows and releases the query timeout.
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	r.cancel()
+	return err
+}
+
+// Row is the result of QueryRowContext. The query timeout is released by
+// Scan.
+type Row struct {
+	*sql.Row
+	cancel context.CancelFunc
+}
+
+// Scan copies the row into dest and releases the query timeout.
+func (r *Row) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.Row.Scan(dest...)
+}
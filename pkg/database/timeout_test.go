@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCallTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	if got := callTimeout(ctx, 5*time.Second); got != 5*time.Second {
+		t.Errorf("expected default timeout 5s, got %s", got)
+	}
+	if got := callTimeout(WithQueryTimeout(ctx, time.Minute), 5*time.Second); got != time.Minute {
+		t.Errorf("expected timeout 1m, got %s", got)
+	}
+	if got := callTimeout(WithQueryTimeout(ctx, 0), 5*time.Second); got != 0 {
+		t.Errorf("expected no timeout, got %s", got)
+	}
+}
+
+func TestQueryTimeoutReleased(t *testing.T) {
+	d := &boolDriver{}
+	cfg := &Config{}
+	cfg.Database.Connect.QueryTimeout = time.Hour
+	db := &DB{conn: openBool(t, "bool-query-timeout", d), config: cfg, logger: slog.Default()}
+
+	lastCtx := func() context.Context {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.lastCtx
+	}
+
+	t.Run("rows close", func(t *testing.T) {
+		rows, err := db.QueryContext(context.Background(), "SELECT")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+		if err := lastCtx().Err(); err != nil {
+			t.Fatalf("expected the timeout to cover iteration, got %v", err)
+		}
+
+		_ = rows.Close()
+		if err := lastCtx().Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected Close to release the timeout, got %v", err)
+		}
+	})
+
+	t.Run("row scan", func(t *testing.T) {
+		var ok bool
+		if err := db.QueryRowContext(context.Background(), "SELECT").Scan(&ok); err != nil || !ok {
+			t.Fatalf("Scan() = %v, %v", ok, err)
+		}
+		if err := lastCtx().Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected Scan to release the timeout, got %v", err)
+		}
+	})
+}
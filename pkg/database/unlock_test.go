@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// boolDriver answers every query with a single boolean, or with err when
+// it is set. It counts the connections it closes and keeps the context of
+// the last query.
+type boolDriver struct {
+	err    error
+	closed atomic.Int32
+
+	mu      sync.Mutex
+	lastCtx context.Context
+}
+
+func (d *boolDriver) Open(string) (driver.Conn, error) { return &boolConn{d: d}, nil }
+
+type boolConn struct{ d *boolDriver }
+
+func (c *boolConn) Prepare(string) (driver.Stmt, error) { return &boolStmt{d: c.d}, nil }
+func (c *boolConn) Close() error                        { c.d.closed.Add(1); return nil }
+func (c *boolConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type boolStmt struct{ d *boolDriver }
+
+func (s *boolStmt) Close() error  { return nil }
+func (s *boolStmt) NumInput() int { return -1 }
+func (s *boolStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *boolStmt) QueryContext(ctx context.Context, _ []driver.NamedValue) (driver.Rows, error) {
+	s.d.mu.Lock()
+	s.d.lastCtx = ctx
+	s.d.mu.Unlock()
+
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	return &boolRows{}, nil
+}
+
+func (s *boolStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+type boolRows struct{ done bool }
+
+func (r *boolRows) Columns() []string { return []string{"ok"} }
+func (r *boolRows) Close() error      { return nil }
+func (r *boolRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0] = true
+	r.done = true
+	return nil
+}
+
+func openBool(t *testing.T, name string, d *boolDriver) *sql.DB {
+	t.Helper()
+	sql.Register(name, d)
+	pool, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
+
+func lockOn(t *testing.T, name string, d *boolDriver) (*AdvisoryLock, *sql.DB) {
+	t.Helper()
+	pool := openBool(t, name, d)
+
+	conn, err := pool.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Conn() error = %v", err)
+	}
+	return &AdvisoryLock{conn: conn, key: 1, logger: slog.Default()}, pool
+}
+
+func TestUnlockWithCancelledContext(t *testing.T) {
+	d := &boolDriver{}
+	lock, pool := lockOn(t, "unlock-cancelled", d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := lock.Unlock(ctx); err != nil {
+		t.Fatalf("expected unlock to run despite the cancelled context, got %v", err)
+	}
+	if got := pool.Stats().Idle; got != 1 {
+		t.Errorf("expected the connection back in the pool, got %d idle", got)
+	}
+}
+
+func TestUnlockFailureDiscardsConnection(t *testing.T) {
+	d := &boolDriver{err: errors.New("connection reset")}
+	lock, pool := lockOn(t, "unlock-failed", d)
+
+	if err := lock.Unlock(context.Background()); !errors.Is(err, ErrUnlockFailed) {
+		t.Fatalf("expected ErrUnlockFailed, got %v", err)
+	}
+	if got := d.closed.Load(); got != 1 {
+		t.Errorf("expected the connection to be closed, got %d closes", got)
+	}
+	if got := pool.Stats().Idle; got != 0 {
+		t.Errorf("expected no idle connections, got %d", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Command devsecrets manages the encrypted .env.enc used for local
+// development.
+//
+//	devsecrets keygen               writes a new key to .env.key
+//	devsecrets encrypt [.env]       encrypts .env into .env.enc
+//	devsecrets decrypt [.env.enc]   prints the decrypted variables
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/marcelofabianov/devsecrets"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen()
+	case "encrypt":
+		err = encrypt(arg(2, ".env"))
+	case "decrypt":
+		err = decrypt(arg(2, devsecrets.DefaultFile))
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "devsecrets:", err)
+		os.Exit(1)
+	}
+}
+
+func keygen() error {
+	if _, err := os.Stat(devsecrets.DefaultKeyFile); err == nil {
+		return fmt.Errorf("%s already exists; delete it first to rotate the key", devsecrets.DefaultKeyFile)
+	}
+
+	key, err := devsecrets.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(devsecrets.DefaultKeyFile, []byte(key+"\n"), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %s; share it out of band and keep it out of git\n", devsecrets.DefaultKeyFile)
+	return nil
+}
+
+func encrypt(path string) error {
+	key, err := readKey()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := devsecrets.Parse(plaintext); err != nil {
+		return err
+	}
+
+	data, err := devsecrets.Encrypt(key, plaintext)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(devsecrets.DefaultFile, data, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %s\n", devsecrets.DefaultFile)
+	return nil
+}
+
+func decrypt(path string) error {
+	key, err := readKey()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := devsecrets.Decrypt(key, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(plaintext)
+	return err
+}
+
+func readKey() (string, error) {
+	if key := os.Getenv(devsecrets.KeyEnv); key != "" {
+		return key, nil
+	}
+
+	data, err := os.ReadFile(devsecrets.DefaultKeyFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%s not found; run `devsecrets keygen` or set %s", devsecrets.DefaultKeyFile, devsecrets.KeyEnv)
+	}
+	return string(data), err
+}
+
+func arg(i int, fallback string) string {
+	if len(os.Args) > i {
+		return os.Args[i]
+	}
+	return fallback
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: devsecrets keygen | encrypt [.env] | decrypt [.env.enc]")
+	os.Exit(2)
+}
@@ -0,0 +1,233 @@
+package devsecrets
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const (
+	// DefaultFile is the encrypted overrides file, safe to commit.
+	DefaultFile = ".env.enc"
+	// DefaultKeyFile holds the base64 key next to DefaultFile. Never commit it.
+	DefaultKeyFile = ".env.key"
+	// KeyEnv holds the base64 key directly, taking precedence over the key
+	// file (e.g. for CI).
+	KeyEnv = "DEVSECRETS_KEY"
+
+	header  = "DEVSECRETS v1\n"
+	keySize = 32
+)
+
+var (
+	ErrKeyNotFound = fault.New(
+		"dev secrets key not found",
+		fault.WithCode(fault.NotFound),
+	)
+
+	ErrInvalidKey = fault.New(
+		"invalid dev secrets key",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrDecrypt = fault.New(
+		"failed to decrypt dev secrets",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrInvalidLine = fault.New(
+		"invalid dev secrets line",
+		fault.WithCode(fault.Invalid),
+	)
+)
+
+// GenerateKey returns a new random key, base64 encoded as stored in
+// DefaultKeyFile.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fault.Wrap(err, "failed to generate dev secrets key", fault.WithCode(fault.Internal))
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt seals plaintext (.env contents) with AES-256-GCM under the base64
+// key.
+func Encrypt(key string, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fault.Wrap(err, "failed to generate nonce", fault.WithCode(fault.Internal))
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(header))
+	return []byte(header + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt opens data produced by Encrypt. A wrong key and a tampered file
+// both fail with ErrDecrypt.
+func Decrypt(key string, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	body, ok := bytes.CutPrefix(data, []byte(header))
+	if !ok {
+		return nil, fault.Wrap(ErrDecrypt, "missing "+strings.TrimSpace(header)+" header")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(body)))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fault.Wrap(ErrDecrypt, "malformed payload")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(header))
+	if err != nil {
+		return nil, fault.Wrap(ErrDecrypt, "wrong key or modified file")
+	}
+	return plaintext, nil
+}
+
+// Parse reads .env contents: KEY=VALUE lines, optionally prefixed with
+// "export", values optionally quoted; blank lines and # comments are
+// skipped.
+func Parse(data []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			// Never echo the line: it may hold a secret.
+			return nil, fault.Wrap(ErrInvalidLine, "expected KEY=VALUE", fault.WithContext("line", n))
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[name] = value
+	}
+
+	return vars, scanner.Err()
+}
+
+// Load looks for DefaultFile in the working directory and up to 5 parents,
+// like every package's .env lookup, decrypts it and exports its variables
+// so LoadConfig picks them up. Variables already set in the environment
+// win. It is a no-op when there is no encrypted file, so production images
+// are unaffected; with a file but no key it fails with ErrKeyNotFound.
+// Call it first thing in main.
+func Load() error {
+	path := findEncryptedFile()
+	if path == "" {
+		return nil
+	}
+	return LoadFile(path, filepath.Join(filepath.Dir(path), DefaultKeyFile))
+}
+
+// LoadFile decrypts path with the key from KeyEnv or keyPath and exports
+// its variables, keeping any already set.
+func LoadFile(path, keyPath string) error {
+	key, err := readKey(keyPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fault.Wrap(err, "failed to read dev secrets", fault.WithCode(fault.Internal), fault.WithContext("path", path))
+	}
+
+	plaintext, err := Decrypt(key, data)
+	if err != nil {
+		return err
+	}
+
+	vars, err := Parse(plaintext)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range vars {
+		if _, set := os.LookupEnv(name); set {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fault.Wrap(err, "failed to export dev secret", fault.WithCode(fault.Internal), fault.WithContext("name", name))
+		}
+	}
+	return nil
+}
+
+func readKey(keyPath string) (string, error) {
+	if key := os.Getenv(KeyEnv); key != "" {
+		return key, nil
+	}
+
+	data, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fault.Wrap(ErrKeyNotFound, "set "+KeyEnv+" or create the key file", fault.WithContext("key_file", keyPath))
+	}
+	if err != nil {
+		return "", fault.Wrap(err, "failed to read dev secrets key", fault.WithCode(fault.Internal), fault.WithContext("key_file", keyPath))
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(raw) != keySize {
+		return nil, fault.Wrap(ErrInvalidKey, "expected 32 bytes, base64 encoded")
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fault.Wrap(ErrInvalidKey, err.Error())
+	}
+	return cipher.NewGCM(block)
+}
+
+func findEncryptedFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(dir, DefaultFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return ""
+}
@@ -0,0 +1,119 @@
+package devsecrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("DATABASE_PASSWORD=s3cret\n")
+	data, err := Encrypt(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decrypt(key, data)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("got %q, want %q", got, plaintext)
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, _ := GenerateKey()
+		if _, err := Decrypt(other, data); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected ErrDecrypt, got %v", err)
+		}
+	})
+
+	t.Run("tampered file", func(t *testing.T) {
+		tampered := append([]byte(nil), data...)
+		tampered[len(header)+10] ^= 1
+		if _, err := Decrypt(key, tampered); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected ErrDecrypt, got %v", err)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		if _, err := Encrypt("c2hvcnQ=", plaintext); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey, got %v", err)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	vars, err := Parse([]byte(`
+# database
+DATABASE_HOST=localhost
+export DATABASE_PASSWORD="p@ss=word"
+CACHE_REDIS_PASSWORD='x'
+EMPTY=
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"DATABASE_HOST":        "localhost",
+		"DATABASE_PASSWORD":    "p@ss=word",
+		"CACHE_REDIS_PASSWORD": "x",
+		"EMPTY":                "",
+	}
+	for name, value := range want {
+		if vars[name] != value {
+			t.Errorf("%s = %q, want %q", name, vars[name], value)
+		}
+	}
+
+	if _, err := Parse([]byte("not a variable")); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf("expected ErrInvalidLine, got %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := GenerateKey()
+
+	data, err := Encrypt(key, []byte("DEVSECRETS_TEST_NEW=from-file\nDEVSECRETS_TEST_SET=from-file\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, DefaultFile)
+	keyPath := filepath.Join(dir, DefaultKeyFile)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		if err := LoadFile(path, keyPath); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("expected ErrKeyNotFound, got %v", err)
+		}
+	})
+
+	if err := os.WriteFile(keyPath, []byte(key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEVSECRETS_TEST_SET", "from-env")
+	t.Setenv("DEVSECRETS_TEST_NEW", "")
+	os.Unsetenv("DEVSECRETS_TEST_NEW")
+
+	if err := LoadFile(path, keyPath); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if got := os.Getenv("DEVSECRETS_TEST_NEW"); got != "from-file" {
+		t.Errorf("expected variable exported from file, got %q", got)
+	}
+	if got := os.Getenv("DEVSECRETS_TEST_SET"); got != "from-env" {
+		t.Errorf("expected environment to win, got %q", got)
+	}
+}
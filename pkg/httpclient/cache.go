@@ -0,0 +1,380 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Response header set on every response served by the caching
+	// transport: HIT, MISS, REVALIDATED or STALE.
+	CacheStatusHeader = "X-Cache"
+
+	// validatorRetention keeps expired entries that carry an ETag or
+	// Last-Modified around for conditional requests.
+	validatorRetention = 24 * time.Hour
+
+	// heuristicMaxAge caps the freshness guessed from Last-Modified.
+	heuristicMaxAge = 24 * time.Hour
+)
+
+// Store is where cached responses live. *cache.Cache from pkg/cache
+// satisfies it; Get must return an error for a missing key.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// CacheConfig controls NewCacheTransport. StaleIfError is how long past
+// expiry a response may still be served when the upstream fails, unless
+// the response sets its own stale-if-error. Bodies over MaxBodySize are not
+// cached.
+type CacheConfig struct {
+	KeyPrefix    string
+	StaleIfError time.Duration
+	MaxBodySize  int64
+}
+
+type cachedResponse struct {
+	StatusCode int               `json:"status"`
+	Header     http.Header       `json:"header"`
+	Body       []byte            `json:"body"`
+	StoredAt   time.Time         `json:"stored_at"`
+	Vary       map[string]string `json:"vary,omitempty"`
+}
+
+type cacheTransport struct {
+	base   http.RoundTripper
+	store  Store
+	config CacheConfig
+	now    func() time.Time
+}
+
+// NewCacheTransport wraps base with a shared HTTP cache for GET requests,
+// following Cache-Control (max-age, no-cache, no-store, private, public,
+// must-revalidate, stale-if-error), Expires, ETag and Last-Modified. Fresh responses are
+// served from store; stale ones are revalidated with If-None-Match /
+// If-Modified-Since; and when the upstream fails (network error or 5xx) a
+// stale response is served within the stale-if-error window, so an upstream
+// blip doesn't break CEP or CNPJ lookups. Place it outermost so cache hits
+// skip the rate limiter. A nil base uses http.DefaultTransport.
+func NewCacheTransport(base http.RoundTripper, store Store, cfg CacheConfig) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cacheTransport{base: base, store: store, config: cfg, now: time.Now}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || hasDirective(req.Header, "no-store") {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	key := t.key(req)
+	now := t.now()
+
+	entry, ok := t.load(ctx, key, req)
+	if ok && !hasDirective(req.Header, "no-cache") && entry.fresh(now) {
+		return entry.response(req, "HIT"), nil
+	}
+
+	outReq := req
+	if ok {
+		outReq = conditional(req, entry)
+	}
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		if ok && entry.staleUsable(now, t.config.StaleIfError) {
+			return entry.response(req, "STALE"), nil
+		}
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		drain(resp)
+		entry.refresh(resp.Header, now)
+		if storable(req, entry.Header) {
+			t.save(ctx, key, entry)
+		} else {
+			_ = t.store.Delete(ctx, key)
+		}
+		return entry.response(req, "REVALIDATED"), nil
+	}
+
+	if ok && resp.StatusCode >= 500 && entry.staleUsable(now, t.config.StaleIfError) {
+		drain(resp)
+		return entry.response(req, "STALE"), nil
+	}
+
+	if !cacheableStatus(resp.StatusCode) || !storable(req, resp.Header) {
+		resp.Header.Set(CacheStatusHeader, "MISS")
+		return resp, nil
+	}
+
+	return t.capture(ctx, key, req, resp, now)
+}
+
+// capture reads the body so it can be stored, and hands the caller an
+// equivalent response. Bodies over MaxBodySize pass through uncached.
+func (t *cacheTransport) capture(ctx context.Context, key string, req *http.Request, resp *http.Response, now time.Time) (*http.Response, error) {
+	resp.Header.Set(CacheStatusHeader, "MISS")
+
+	limit := t.config.MaxBodySize
+	if limit <= 0 {
+		limit = 1 << 20
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	if int64(len(body)) > limit {
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &cachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   now,
+		Vary:       varyValues(req, resp.Header),
+	}
+	entry.Header.Del(CacheStatusHeader)
+	t.save(ctx, key, entry)
+
+	return resp, nil
+}
+
+func (t *cacheTransport) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return t.config.KeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// load returns the stored entry for req. Store failures count as misses:
+// the cache is best effort.
+func (t *cacheTransport) load(ctx context.Context, key string, req *http.Request) (*cachedResponse, bool) {
+	raw, err := t.store.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cachedResponse
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		return nil, false
+	}
+
+	for name, value := range entry.Vary {
+		if req.Header.Get(name) != value {
+			return nil, false
+		}
+	}
+
+	return &entry, true
+}
+
+func (t *cacheTransport) save(ctx context.Context, key string, entry *cachedResponse) {
+	ttl := entry.freshness() + max(t.config.StaleIfError, entry.staleIfError())
+	if entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != "" {
+		ttl = max(ttl, entry.freshness()+validatorRetention)
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = t.store.Set(ctx, key, string(data), ttl)
+}
+
+// freshness is how long the response is fresh after it was stored.
+func (e *cachedResponse) freshness() time.Duration {
+	if hasDirective(e.Header, "no-cache") {
+		return 0
+	}
+
+	var lifetime time.Duration
+	if maxAge, ok := directiveSeconds(e.Header, "max-age"); ok {
+		lifetime = maxAge
+	} else if expires, err := http.ParseTime(e.Header.Get("Expires")); err == nil {
+		lifetime = expires.Sub(e.date())
+	} else if modified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+		lifetime = min(e.date().Sub(modified)/10, heuristicMaxAge)
+	}
+
+	return max(0, lifetime-e.initialAge())
+}
+
+func (e *cachedResponse) fresh(now time.Time) bool {
+	return now.Sub(e.StoredAt) < e.freshness()
+}
+
+// staleUsable reports whether the entry may be served after an upstream
+// failure.
+func (e *cachedResponse) staleUsable(now time.Time, window time.Duration) bool {
+	if hasDirective(e.Header, "must-revalidate") {
+		return false
+	}
+	window = max(window, e.staleIfError())
+	return now.Sub(e.StoredAt) < e.freshness()+window
+}
+
+func (e *cachedResponse) staleIfError() time.Duration {
+	window, _ := directiveSeconds(e.Header, "stale-if-error")
+	return window
+}
+
+func (e *cachedResponse) date() time.Time {
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return date
+	}
+	return e.StoredAt
+}
+
+func (e *cachedResponse) initialAge() time.Duration {
+	age, err := strconv.Atoi(e.Header.Get("Age"))
+	if err != nil || age < 0 {
+		return 0
+	}
+	return time.Duration(age) * time.Second
+}
+
+// refresh applies the headers of a 304 Not Modified to the entry.
+func (e *cachedResponse) refresh(header http.Header, now time.Time) {
+	for _, name := range []string{"Cache-Control", "Date", "Expires", "ETag", "Last-Modified", "Age"} {
+		if values, ok := header[name]; ok {
+			e.Header[name] = values
+		} else if name == "Age" {
+			e.Header.Del(name)
+		}
+	}
+	e.StoredAt = now
+}
+
+func (e *cachedResponse) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	header.Set(CacheStatusHeader, status)
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func conditional(req *http.Request, entry *cachedResponse) *http.Request {
+	etag := entry.Header.Get("ETag")
+	modified := entry.Header.Get("Last-Modified")
+	if etag == "" && modified == "" {
+		return req
+	}
+
+	clone := req.Clone(req.Context())
+	if etag != "" {
+		clone.Header.Set("If-None-Match", etag)
+	}
+	if modified != "" {
+		clone.Header.Set("If-Modified-Since", modified)
+	}
+	return clone
+}
+
+func varyValues(req *http.Request, header http.Header) map[string]string {
+	var values map[string]string
+	for _, line := range header.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" || name == "*" {
+				continue
+			}
+			if values == nil {
+				values = make(map[string]string)
+			}
+			values[name] = req.Header.Get(name)
+		}
+	}
+	return values
+}
+
+func cacheableStatus(status int) bool {
+	switch status {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusMultipleChoices,
+		http.StatusMovedPermanently, http.StatusNotFound, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
+
+// storable reports whether the response to req may be stored. The store is
+// shared by every caller of the client, so private responses are never kept,
+// and responses to requests carrying credentials only when they are marked
+// public or s-maxage (RFC 9111, section 3.5).
+func storable(req *http.Request, header http.Header) bool {
+	if hasDirective(header, "no-store") || hasDirective(header, "private") {
+		return false
+	}
+	if req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" {
+		return hasDirective(header, "public") || hasDirective(header, "s-maxage")
+	}
+	return true
+}
+
+func hasDirective(header http.Header, name string) bool {
+	_, ok := directive(header, name)
+	return ok
+}
+
+func directiveSeconds(header http.Header, name string) (time.Duration, bool) {
+	value, ok := directive(header, name)
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// directive looks up a Cache-Control directive, returning its value if it
+// has one.
+func directive(header http.Header, name string) (string, bool) {
+	for _, line := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if strings.EqualFold(key, name) {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,285 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	mu    sync.Mutex
+	items map[string]string
+	ttls  map[string]time.Duration
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{items: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.items[key]
+	if !ok {
+		return "", errors.New("key not found")
+	}
+	return value, nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = value.(string)
+	s.ttls[key] = expiration
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+func newTestCacheClient(t *testing.T, handler http.HandlerFunc) (*http.Client, string, *time.Time) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	transport := NewCacheTransport(nil, newMemoryStore(), CacheConfig{StaleIfError: time.Hour}).(*cacheTransport)
+	transport.now = func() time.Time { return now }
+
+	return &http.Client{Transport: transport}, server.URL, &now
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	var calls atomic.Int32
+	client, url, now := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte(`{"cep":"01001-000"}`))
+	})
+
+	if resp, _ := get(t, client, url); resp.Header.Get(CacheStatusHeader) != "MISS" {
+		t.Errorf("expected MISS, got %s", resp.Header.Get(CacheStatusHeader))
+	}
+
+	resp, body := get(t, client, url)
+	if resp.Header.Get(CacheStatusHeader) != "HIT" || body != `{"cep":"01001-000"}` {
+		t.Errorf("expected cached body, got %s %q", resp.Header.Get(CacheStatusHeader), body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls.Load())
+	}
+
+	*now = now.Add(2 * time.Minute)
+	get(t, client, url)
+	if calls.Load() != 2 {
+		t.Errorf("expected an expired response to be refetched, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	var calls atomic.Int32
+	client, url, _ := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("company"))
+	})
+
+	get(t, client, url)
+	resp, body := get(t, client, url)
+
+	if resp.Header.Get(CacheStatusHeader) != "REVALIDATED" || resp.StatusCode != http.StatusOK || body != "company" {
+		t.Errorf("expected revalidated 200 with cached body, got %s %d %q", resp.Header.Get(CacheStatusHeader), resp.StatusCode, body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestCacheStaleIfError(t *testing.T) {
+	var failing atomic.Bool
+	client, url, now := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("address"))
+	})
+
+	get(t, client, url)
+	failing.Store(true)
+
+	*now = now.Add(10 * time.Minute)
+	resp, body := get(t, client, url)
+	if resp.Header.Get(CacheStatusHeader) != "STALE" || body != "address" {
+		t.Errorf("expected stale response, got %s %q", resp.Header.Get(CacheStatusHeader), body)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	if resp, _ := get(t, client, url); resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected the upstream error past the stale window, got %d", resp.StatusCode)
+	}
+}
+
+func TestCacheRespectsNoStore(t *testing.T) {
+	var calls atomic.Int32
+	client, url, _ := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write([]byte("secret"))
+	})
+
+	get(t, client, url)
+	get(t, client, url)
+
+	if calls.Load() != 2 {
+		t.Errorf("expected no-store responses not to be cached, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheSkipsPrivateResponses(t *testing.T) {
+	var calls atomic.Int32
+	client, url, _ := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		_, _ = w.Write([]byte("mine"))
+	})
+
+	get(t, client, url)
+	get(t, client, url)
+
+	if calls.Load() != 2 {
+		t.Errorf("expected private responses not to be cached, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheCredentialedRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		cacheControl string
+		wantCalls    int32
+	}{
+		{"authorization", "Authorization", "Bearer token", "max-age=60", 2},
+		{"cookie", "Cookie", "session=abc", "max-age=60", 2},
+		{"authorization public", "Authorization", "Bearer token", "public, max-age=60", 1},
+		{"authorization s-maxage", "Authorization", "Bearer token", "s-maxage=60, max-age=60", 1},
+		{"authorization private", "Authorization", "Bearer token", "private, public, max-age=60", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			client, url, _ := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.Header().Set("Cache-Control", tt.cacheControl)
+				_, _ = w.Write([]byte("account"))
+			})
+
+			for range 2 {
+				req, _ := http.NewRequest(http.MethodGet, url, nil)
+				req.Header.Set(tt.header, tt.value)
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				drain(resp)
+			}
+
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("expected %d upstream calls, got %d", tt.wantCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestCacheVary(t *testing.T) {
+	var calls atomic.Int32
+	client, url, _ := newTestCacheClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		_, _ = w.Write([]byte(r.Header.Get("Accept-Language")))
+	})
+
+	request := func(lang string) string {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Accept-Language", lang)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	request("pt-BR")
+	if body := request("en"); body != "en" {
+		t.Errorf("expected a different variant, got %q", body)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestCacheFreshness(t *testing.T) {
+	date := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=300"}}, 5 * time.Minute},
+		{"max-age minus age", http.Header{"Cache-Control": {"max-age=300"}, "Age": {"100"}}, 200 * time.Second},
+		{"expires", http.Header{"Date": {date.Format(http.TimeFormat)}, "Expires": {date.Add(time.Hour).Format(http.TimeFormat)}}, time.Hour},
+		{"heuristic", http.Header{"Date": {date.Format(http.TimeFormat)}, "Last-Modified": {date.Add(-10 * time.Hour).Format(http.TimeFormat)}}, time.Hour},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache, max-age=300"}}, 0},
+		{"nothing", http.Header{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &cachedResponse{Header: tt.header, StoredAt: date}
+			if got := entry.freshness(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package httpclient
+
+import "net/http"
+
+type options struct {
+	limiter *Limiter
+	store   Store
+}
+
+type Option func(*options)
+
+// WithLimiter uses limiter instead of one built from cfg.RateLimit, e.g. to
+// share budgets between clients or read the quotas upstreams report.
+func WithLimiter(limiter *Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithCache caches GET responses in store (e.g. a connected *cache.Cache)
+// following cfg.Cache; see NewCacheTransport.
+func WithCache(store Store) Option {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// New returns an *http.Client for calls to third-party APIs: it applies
+// cfg.Timeout, waits for each host's rate limit budget before sending and
+// retries 429/503 responses and transient failures per cfg.Retry. With
+// WithCache, GET responses are cached in front of all of that.
+func New(cfg *Config, opts ...Option) *http.Client {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.limiter == nil {
+		o.limiter = NewLimiter(cfg.RateLimit)
+	}
+
+	transport := NewLimitedTransport(http.DefaultTransport, o.limiter)
+	transport = NewRetryTransport(transport, cfg.Retry)
+	if o.store != nil {
+		transport = NewCacheTransport(transport, o.store, cfg.Cache)
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,141 @@
+package httpclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcelofabianov/config"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Timeout   time.Duration
+	RateLimit RateLimitConfig
+	Retry     RetryConfig
+	Cache     CacheConfig
+}
+
+// RateLimitConfig sets the outbound request budgets. Default applies to
+// every host without an entry in Hosts; a zero Default.Requests leaves
+// those hosts unlimited.
+type RateLimitConfig struct {
+	Default      HostLimit
+	Hosts        map[string]HostLimit
+	MaxQueue     int
+	QueueTimeout time.Duration
+	Jitter       time.Duration
+}
+
+// HostLimit allows Requests per Per to one host, spaced evenly, with up to
+// Burst of them sent back to back.
+type HostLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// LoadConfig loads the HTTPCLIENT_* settings with config.Load.
+func LoadConfig() (*Config, error) {
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom loads the httpclient section of c: HTTPCLIENT_* variables
+// over the httpclient section of the config file.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+	v := c.Section("httpclient", "HTTPCLIENT")
+
+	setDefaults(v)
+
+	defaultLimit := HostLimit{
+		Requests: v.GetInt("rate_limit.requests"),
+		Per:      v.GetDuration("rate_limit.per"),
+		Burst:    v.GetInt("rate_limit.burst"),
+	}
+
+	hosts, err := parseHostLimits(v.GetString("rate_limit.hosts"), defaultLimit.Burst)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		Timeout: v.GetDuration("timeout"),
+		RateLimit: RateLimitConfig{
+			Default:      defaultLimit,
+			Hosts:        hosts,
+			MaxQueue:     v.GetInt("rate_limit.max_queue"),
+			QueueTimeout: v.GetDuration("rate_limit.queue_timeout"),
+			Jitter:       v.GetDuration("rate_limit.jitter"),
+		},
+		Retry: RetryConfig{
+			MaxAttempts:   v.GetInt("retry.max_attempts"),
+			BackoffMin:    v.GetDuration("retry.backoff_min"),
+			BackoffMax:    v.GetDuration("retry.backoff_max"),
+			MaxRetryAfter: v.GetDuration("retry.max_retry_after"),
+		},
+		Cache: CacheConfig{
+			KeyPrefix:    v.GetString("cache.key_prefix"),
+			StaleIfError: v.GetDuration("cache.stale_if_error"),
+			MaxBodySize:  v.GetInt64("cache.max_body_size"),
+		},
+	}, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("timeout", 30*time.Second)
+	v.SetDefault("rate_limit.requests", 0)
+	v.SetDefault("rate_limit.per", time.Minute)
+	v.SetDefault("rate_limit.burst", 1)
+	v.SetDefault("rate_limit.max_queue", 1000)
+	v.SetDefault("rate_limit.queue_timeout", time.Minute)
+	v.SetDefault("rate_limit.jitter", 100*time.Millisecond)
+	v.SetDefault("rate_limit.hosts", "")
+	v.SetDefault("retry.max_attempts", 2)
+	v.SetDefault("retry.backoff_min", 200*time.Millisecond)
+	v.SetDefault("retry.backoff_max", 5*time.Second)
+	v.SetDefault("retry.max_retry_after", 30*time.Second)
+	v.SetDefault("cache.key_prefix", "httpclient:")
+	v.SetDefault("cache.stale_if_error", time.Hour)
+	v.SetDefault("cache.max_body_size", 1<<20)
+}
+
+// parseHostLimits parses "viacep.com.br=60/1m,brasilapi.com.br=5/1s".
+func parseHostLimits(spec string, burst int) (map[string]HostLimit, error) {
+	hosts := make(map[string]HostLimit)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		host, budget, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid host rate limit %q: expected host=requests/period", entry)
+		}
+
+		count, period, ok := strings.Cut(budget, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid host rate limit %q: expected host=requests/period", entry)
+		}
+
+		requests, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("invalid host rate limit %q: requests must be a positive integer", entry)
+		}
+
+		per, err := time.ParseDuration(strings.TrimSpace(period))
+		if err != nil || per <= 0 {
+			return nil, fmt.Errorf("invalid host rate limit %q: period must be a positive duration", entry)
+		}
+
+		hosts[strings.ToLower(strings.TrimSpace(host))] = HostLimit{Requests: requests, Per: per, Burst: burst}
+	}
+
+	return hosts, nil
+}
@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	origHosts := os.Getenv("HTTPCLIENT_RATE_LIMIT_HOSTS")
+	defer os.Setenv("HTTPCLIENT_RATE_LIMIT_HOSTS", origHosts)
+
+	t.Run("loads defaults when no env vars set", func(t *testing.T) {
+		os.Unsetenv("HTTPCLIENT_RATE_LIMIT_HOSTS")
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cfg.Timeout != 30*time.Second {
+			t.Errorf("expected timeout 30s, got %v", cfg.Timeout)
+		}
+		if cfg.RateLimit.Default.Requests != 0 {
+			t.Errorf("expected unlimited default, got %d requests", cfg.RateLimit.Default.Requests)
+		}
+		if len(cfg.RateLimit.Hosts) != 0 {
+			t.Errorf("expected no host limits, got %v", cfg.RateLimit.Hosts)
+		}
+	})
+
+	t.Run("parses host limits", func(t *testing.T) {
+		os.Setenv("HTTPCLIENT_RATE_LIMIT_HOSTS", "ViaCEP.com.br=60/1m, brasilapi.com.br=5/1s")
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		viacep := cfg.RateLimit.Hosts["viacep.com.br"]
+		if viacep.Requests != 60 || viacep.Per != time.Minute || viacep.Burst != 1 {
+			t.Errorf("unexpected viacep limit: %+v", viacep)
+		}
+		if brasilapi := cfg.RateLimit.Hosts["brasilapi.com.br"]; brasilapi.Requests != 5 || brasilapi.Per != time.Second {
+			t.Errorf("unexpected brasilapi limit: %+v", brasilapi)
+		}
+	})
+
+	t.Run("rejects malformed host limits", func(t *testing.T) {
+		for _, spec := range []string{"viacep.com.br", "viacep.com.br=60", "viacep.com.br=x/1m", "viacep.com.br=60/soon"} {
+			os.Setenv("HTTPCLIENT_RATE_LIMIT_HOSTS", spec)
+
+			if _, err := LoadConfig(); err == nil {
+				t.Errorf("expected error for %q", spec)
+			}
+		}
+	})
+}
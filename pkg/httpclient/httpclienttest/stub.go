@@ -0,0 +1,259 @@
+// Package httpclienttest provides stub third-party APIs for tests.
+//
+// A Stub is a local HTTP server answering canned responses per route, with
+// latency, error and dropped-connection injection, and records every
+// request so tests can assert what was sent. Point the integration (payment
+// gateway, CEP lookup, SSO) at Stub.URL instead of the real host, so tests
+// need neither credentials nor network access.
+package httpclienttest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Request is a request received by a Stub.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the request body into v, failing the test on error.
+func (r Request) JSON(t testing.TB, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("httpclienttest: decode %s %s body: %v", r.Method, r.Path, err)
+	}
+}
+
+// Response is a canned response. Body is written as is when it is a string
+// or []byte and JSON encoded otherwise.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   any
+	// Delay is waited before responding, e.g. to trigger client timeouts.
+	Delay time.Duration
+	// Drop closes the connection without responding, like a network failure.
+	Drop bool
+}
+
+// Route answers requests to one method and path. Responses are served in
+// order; the last one repeats.
+type Route struct {
+	method    string
+	path      string
+	responses []Response
+	calls     int
+}
+
+// Reply queues a response with status and body.
+func (r *Route) Reply(status int, body any) *Route {
+	return r.ReplyWith(Response{Status: status, Body: body})
+}
+
+// ReplyWith queues resp.
+func (r *Route) ReplyWith(resp Response) *Route {
+	r.responses = append(r.responses, resp)
+	return r
+}
+
+// FailTimes queues n responses with status before the ones that follow,
+// e.g. FailTimes(2, 503).Reply(200, ok) to exercise retries.
+func (r *Route) FailTimes(n, status int) *Route {
+	for i := 0; i < n; i++ {
+		r.Reply(status, map[string]string{"error": http.StatusText(status)})
+	}
+	return r
+}
+
+// Delay adds latency to every response queued so far.
+func (r *Route) Delay(d time.Duration) *Route {
+	for i := range r.responses {
+		r.responses[i].Delay = d
+	}
+	return r
+}
+
+// Drop queues a response that closes the connection without answering.
+func (r *Route) Drop() *Route {
+	return r.ReplyWith(Response{Drop: true})
+}
+
+// Stub is a stub third-party API. It is closed when the test ends.
+type Stub struct {
+	URL string
+
+	t        testing.TB
+	server   *httptest.Server
+	mu       sync.Mutex
+	routes   []*Route
+	requests []Request
+}
+
+// NewStub starts a stub server. Requests to routes that were not set up
+// with On fail the test and get 501 Not Implemented.
+func NewStub(t testing.TB) *Stub {
+	t.Helper()
+
+	s := &Stub{t: t}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.URL = s.server.URL
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Client returns an *http.Client for the stub.
+func (s *Stub) Client() *http.Client {
+	return s.server.Client()
+}
+
+// On returns the route for method and path (the query string is ignored),
+// creating it on first use. A route without responses answers 200 with an
+// empty body.
+func (s *Stub) On(method, path string) *Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if route := s.route(method, path); route != nil {
+		return route
+	}
+	route := &Route{method: method, path: path}
+	s.routes = append(s.routes, route)
+	return route
+}
+
+// Requests returns every request received, in order.
+func (s *Stub) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Calls counts the requests received for method and path.
+func (s *Stub) Calls(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if route := s.route(method, path); route != nil {
+		return route.calls
+	}
+	return 0
+}
+
+// AssertCalled fails the test unless method and path received exactly n
+// requests.
+func (s *Stub) AssertCalled(t testing.TB, method, path string, n int) {
+	t.Helper()
+	if got := s.Calls(method, path); got != n {
+		t.Errorf("httpclienttest: expected %d calls to %s %s, got %d", n, method, path, got)
+	}
+}
+
+// LastRequest returns the last request received for method and path,
+// failing the test when there was none.
+func (s *Stub) LastRequest(t testing.TB, method, path string) Request {
+	t.Helper()
+
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == method && requests[i].Path == path {
+			return requests[i]
+		}
+	}
+	t.Fatalf("httpclienttest: no request to %s %s", method, path)
+	return Request{}
+}
+
+func (s *Stub) route(method, path string) *Route {
+	for _, route := range s.routes {
+		if route.method == method && route.path == path {
+			return route
+		}
+	}
+	return nil
+}
+
+func (s *Stub) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	route := s.route(r.Method, r.URL.Path)
+	if route == nil {
+		s.mu.Unlock()
+		s.t.Errorf("httpclienttest: unexpected request %s %s", r.Method, r.URL.Path)
+		http.Error(w, "no stub for "+r.Method+" "+r.URL.Path, http.StatusNotImplemented)
+		return
+	}
+
+	resp := Response{Status: http.StatusOK}
+	if n := len(route.responses); n > 0 {
+		resp = route.responses[min(route.calls, n-1)]
+	}
+	route.calls++
+	s.mu.Unlock()
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if resp.Drop {
+		if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+			_ = conn.Close()
+		}
+		return
+	}
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	var payload []byte
+	switch body := resp.Body.(type) {
+	case nil:
+	case string:
+		payload = []byte(body)
+	case []byte:
+		payload = body
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			s.t.Errorf("httpclienttest: encode response for %s %s: %v", r.Method, r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		payload = encoded
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+	}
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(payload)
+}
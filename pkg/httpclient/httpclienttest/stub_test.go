@@ -0,0 +1,106 @@
+package httpclienttest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/httpclient"
+	"github.com/marcelofabianov/httpclient/httpclienttest"
+)
+
+func TestStubCannedResponses(t *testing.T) {
+	stub := httpclienttest.NewStub(t)
+	stub.On(http.MethodGet, "/ws/01001000/json/").Reply(http.StatusOK, map[string]string{
+		"cep":        "01001-000",
+		"localidade": "São Paulo",
+	})
+
+	resp, err := stub.Client().Get(stub.URL + "/ws/01001000/json/?callback=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected JSON content type, got %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `"cep":"01001-000"`) {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	req := stub.LastRequest(t, http.MethodGet, "/ws/01001000/json/")
+	if req.Query.Get("callback") != "x" {
+		t.Errorf("expected query to be captured, got %v", req.Query)
+	}
+}
+
+func TestStubCapturesRequests(t *testing.T) {
+	stub := httpclienttest.NewStub(t)
+	stub.On(http.MethodPost, "/v1/charges").Reply(http.StatusCreated, `{"id":"ch_1"}`)
+
+	req, _ := http.NewRequest(http.MethodPost, stub.URL+"/v1/charges", strings.NewReader(`{"amount":1990}`))
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := stub.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	stub.AssertCalled(t, http.MethodPost, "/v1/charges", 1)
+
+	got := stub.LastRequest(t, http.MethodPost, "/v1/charges")
+	if got.Header.Get("Authorization") != "Bearer test" {
+		t.Errorf("expected Authorization header, got %q", got.Header.Get("Authorization"))
+	}
+	var charge struct{ Amount int }
+	got.JSON(t, &charge)
+	if charge.Amount != 1990 {
+		t.Errorf("expected amount 1990, got %d", charge.Amount)
+	}
+}
+
+func TestStubErrorInjection(t *testing.T) {
+	stub := httpclienttest.NewStub(t)
+	stub.On(http.MethodGet, "/token").FailTimes(2, http.StatusServiceUnavailable).Reply(http.StatusOK, "ok")
+
+	cfg := &httpclient.Config{Timeout: 5 * time.Second}
+	cfg.Retry.MaxAttempts = 3
+	cfg.Retry.BackoffMin = time.Millisecond
+	cfg.Retry.BackoffMax = time.Millisecond
+
+	resp, err := httpclient.New(cfg).Get(stub.URL + "/token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected retries to reach 200, got %d", resp.StatusCode)
+	}
+	stub.AssertCalled(t, http.MethodGet, "/token", 3)
+}
+
+func TestStubLatencyAndDrop(t *testing.T) {
+	stub := httpclienttest.NewStub(t)
+	stub.On(http.MethodGet, "/slow").Reply(http.StatusOK, "late").Delay(time.Second)
+	stub.On(http.MethodGet, "/drop").Drop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, stub.URL+"/slow", nil)
+	if _, err := stub.Client().Do(req); err == nil {
+		t.Error("expected timeout error")
+	}
+
+	if _, err := stub.Client().Get(stub.URL + "/drop"); err == nil {
+		t.Error("expected dropped connection error")
+	}
+}
@@ -0,0 +1,247 @@
+package httpclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrRateLimited is returned when a request would wait longer than the
+// queue timeout, or the host's queue is full.
+var ErrRateLimited = fault.New(
+	"outbound rate limit exceeded",
+	fault.WithCode(fault.InfraError),
+)
+
+type hostBucket struct {
+	// next is when the following request may start if no burst credit is
+	// left (the GCRA theoretical arrival time).
+	next    time.Time
+	waiting int
+
+	// pausedUntil holds every request to the host, set when the upstream
+	// asks us to back off.
+	pausedUntil time.Time
+
+	quota    Quota
+	hasQuota bool
+}
+
+// Limiter enforces per-host request budgets on outbound calls, so bulk
+// imports stay within what third-party APIs allow. Requests are spaced
+// evenly over the period instead of being sent in bursts at the start of
+// each window; requests over budget wait in a per-host queue. It is safe for
+// concurrent use.
+type Limiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostBucket
+}
+
+func NewLimiter(cfg RateLimitConfig) *Limiter {
+	return &Limiter{
+		config: cfg,
+		now:    time.Now,
+		hosts:  make(map[string]*hostBucket),
+	}
+}
+
+// limitFor returns the budget of host; ok is false when it is unlimited.
+func (l *Limiter) limitFor(host string) (HostLimit, bool) {
+	limit, ok := l.config.Hosts[host]
+	if !ok {
+		limit = l.config.Default
+	}
+	if limit.Requests <= 0 || limit.Per <= 0 {
+		return HostLimit{}, false
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return limit, true
+}
+
+// Wait blocks until a request to host fits its budget and any pause the
+// upstream asked for is over. It fails with ErrRateLimited when the wait
+// would exceed QueueTimeout or MaxQueue requests are already waiting, and
+// with the context error when ctx ends first. A cancelled wait still
+// consumes its slot, erring on the side of the upstream's budget.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	host = strings.ToLower(host)
+	limit, limited := l.limitFor(host)
+
+	l.mu.Lock()
+	if _, ok := l.hosts[host]; !ok && !limited {
+		l.mu.Unlock()
+		return nil
+	}
+	bucket := l.bucket(host)
+
+	now := l.now()
+	start := now
+	var interval time.Duration
+	if limited {
+		interval = limit.Per / time.Duration(limit.Requests)
+		start = bucket.next
+		if floor := now.Add(-time.Duration(limit.Burst-1) * interval); start.Before(floor) {
+			start = floor
+		}
+	}
+	if bucket.pausedUntil.After(start) {
+		start = bucket.pausedUntil
+	}
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		if limited {
+			bucket.next = start.Add(interval)
+		}
+		l.mu.Unlock()
+		return nil
+	}
+
+	if bucket.waiting >= l.config.MaxQueue || delay > l.config.QueueTimeout {
+		waiting := bucket.waiting
+		l.mu.Unlock()
+		return fault.Wrap(ErrRateLimited, "request over the host budget",
+			fault.WithContext("host", host),
+			fault.WithContext("wait", delay.String()),
+			fault.WithContext("queued", waiting),
+		)
+	}
+
+	if limited {
+		bucket.next = start.Add(interval)
+	}
+	bucket.waiting++
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		bucket.waiting--
+		l.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(delay + l.jitter())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause holds every request to host until until, e.g. after a 429 with
+// Retry-After or a quota that reports nothing remaining. It applies to
+// unlimited hosts too. A shorter pause never cuts an existing one.
+func (l *Limiter) Pause(host string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.bucket(strings.ToLower(host)).pause(until)
+}
+
+// Quota returns the last quota host reported in its RateLimit headers.
+func (l *Limiter) Quota(host string) (Quota, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.hosts[strings.ToLower(host)]
+	if !ok || !bucket.hasQuota {
+		return Quota{}, false
+	}
+	return bucket.quota, true
+}
+
+// observe records the rate limit information of a response from host and
+// pauses the host when the upstream asks for it. It returns the
+// Retry-After delay, if any.
+func (l *Limiter) observe(host string, resp *http.Response) (time.Duration, bool) {
+	now := l.now()
+	quota, hasQuota := parseQuota(resp.Header, now)
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+
+	if !hasQuota && !hasRetryAfter {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.bucket(strings.ToLower(host))
+	if hasQuota {
+		bucket.quota, bucket.hasQuota = quota, true
+		if quota.Remaining <= 0 && quota.Reset > 0 {
+			bucket.pause(now.Add(quota.Reset))
+		}
+	}
+	if hasRetryAfter && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		bucket.pause(now.Add(retryAfter))
+	}
+
+	return retryAfter, hasRetryAfter
+}
+
+// bucket must be called with l.mu held.
+func (l *Limiter) bucket(host string) *hostBucket {
+	bucket, ok := l.hosts[host]
+	if !ok {
+		bucket = &hostBucket{}
+		l.hosts[host] = bucket
+	}
+	return bucket
+}
+
+func (b *hostBucket) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// jitter spreads queued requests so workers released by the same slot
+// boundary don't hit the upstream in lockstep.
+func (l *Limiter) jitter() time.Duration {
+	if l.config.Jitter <= 0 {
+		return 0
+	}
+	//nolint:gosec // G404: math/rand acceptable for jitter (non-cryptographic use)
+	return time.Duration(rand.Int63n(int64(l.config.Jitter)))
+}
+
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+// NewLimitedTransport wraps base so every request waits for its host's
+// budget before being sent. A nil base uses http.DefaultTransport.
+func NewLimitedTransport(base http.RoundTripper, limiter *Limiter) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &limitedTransport{base: base, limiter: limiter}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if err := t.limiter.Wait(req.Context(), host); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.limiter.observe(host, resp)
+	return resp, nil
+}
@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRateLimit(limit HostLimit) RateLimitConfig {
+	return RateLimitConfig{
+		Hosts:        map[string]HostLimit{"viacep.com.br": limit},
+		MaxQueue:     10,
+		QueueTimeout: time.Second,
+	}
+}
+
+func TestLimiterUnlimitedHost(t *testing.T) {
+	limiter := NewLimiter(testRateLimit(HostLimit{Requests: 1, Per: time.Hour}))
+
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(context.Background(), "example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	limiter := NewLimiter(testRateLimit(HostLimit{Requests: 10, Per: 200 * time.Millisecond, Burst: 2}))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background(), "VIACEP.com.br"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// Two requests go out as a burst, the other two 20ms apart.
+	if elapsed < 35*time.Millisecond {
+		t.Errorf("expected requests to be spaced, took %v", elapsed)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("expected requests to be released promptly, took %v", elapsed)
+	}
+}
+
+func TestLimiterRejectsOverQueueTimeout(t *testing.T) {
+	cfg := testRateLimit(HostLimit{Requests: 1, Per: time.Hour})
+	limiter := NewLimiter(cfg)
+
+	if err := limiter.Wait(context.Background(), "viacep.com.br"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := limiter.Wait(context.Background(), "viacep.com.br")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestLimiterRejectsWhenQueueFull(t *testing.T) {
+	cfg := testRateLimit(HostLimit{Requests: 1, Per: 100 * time.Millisecond})
+	cfg.MaxQueue = 1
+	limiter := NewLimiter(cfg)
+
+	_ = limiter.Wait(context.Background(), "viacep.com.br")
+
+	queued := make(chan error, 1)
+	go func() { queued <- limiter.Wait(context.Background(), "viacep.com.br") }()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := limiter.Wait(context.Background(), "viacep.com.br"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited with a full queue, got %v", err)
+	}
+	if err := <-queued; err != nil {
+		t.Errorf("expected the queued request to go through, got %v", err)
+	}
+}
+
+func TestLimiterContextCancelled(t *testing.T) {
+	limiter := NewLimiter(testRateLimit(HostLimit{Requests: 1, Per: 500 * time.Millisecond}))
+	_ = limiter.Wait(context.Background(), "viacep.com.br")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "viacep.com.br"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNewLimitsTransport(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	host := mustHostname(t, server.URL)
+	client := New(&Config{
+		Timeout: time.Second,
+		RateLimit: RateLimitConfig{
+			Hosts:        map[string]HostLimit{host: {Requests: 1, Per: time.Hour}},
+			MaxQueue:     10,
+			QueueTimeout: time.Second,
+		},
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 request to reach the server, got %d", calls.Load())
+	}
+}
+
+func mustHostname(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Hostname()
+}
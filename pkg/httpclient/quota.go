@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochThreshold separates reset values sent as a unix timestamp
+// (X-RateLimit-Reset on some APIs) from values sent as seconds to wait.
+const epochThreshold = 1_000_000_000
+
+// Quota is the upstream's view of our request budget, as reported in its
+// RateLimit headers.
+type Quota struct {
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+// ParseQuota reads the quota from the standard RateLimit header
+// ("limit=100, remaining=42, reset=30"), the RateLimit-Limit /
+// RateLimit-Remaining / RateLimit-Reset fields, or their X-RateLimit-*
+// equivalents. ok is false when the response carries none of them.
+func ParseQuota(header http.Header) (Quota, bool) {
+	return parseQuota(header, time.Now())
+}
+
+func parseQuota(header http.Header, now time.Time) (Quota, bool) {
+	if combined := header.Get("RateLimit"); combined != "" {
+		if quota, ok := parseCombinedQuota(combined); ok {
+			return quota, true
+		}
+	}
+
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		remaining, ok := leadingInt(header.Get(prefix + "Remaining"))
+		if !ok {
+			continue
+		}
+
+		quota := Quota{Remaining: remaining}
+		quota.Limit, _ = leadingInt(header.Get(prefix + "Limit"))
+		if reset, ok := leadingInt(header.Get(prefix + "Reset")); ok {
+			quota.Reset = resetDuration(reset, now)
+		}
+		return quota, true
+	}
+
+	return Quota{}, false
+}
+
+func parseCombinedQuota(value string) (Quota, bool) {
+	var quota Quota
+	found := false
+
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "limit":
+			quota.Limit = n
+		case "remaining", "r":
+			quota.Remaining, found = n, true
+		case "reset", "t":
+			quota.Reset = time.Duration(n) * time.Second
+		}
+	}
+
+	return quota, found
+}
+
+// ParseRetryAfter parses a Retry-After value, either delay-seconds or an
+// HTTP date.
+func ParseRetryAfter(value string) (time.Duration, bool) {
+	return parseRetryAfter(value, time.Now())
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(seconds)*time.Second), true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(0, at.Sub(now)), true
+	}
+
+	return 0, false
+}
+
+// leadingInt parses the first integer of values like "100" or
+// "100, 100;w=60".
+func leadingInt(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	return n, err == nil
+}
+
+func resetDuration(reset int, now time.Time) time.Duration {
+	if reset >= epochThreshold {
+		return max(0, time.Unix(int64(reset), 0).Sub(now))
+	}
+	return max(0, time.Duration(reset)*time.Second)
+}
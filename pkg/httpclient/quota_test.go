@@ -0,0 +1,85 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseQuota(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   Quota
+		ok     bool
+	}{
+		{
+			name:   "structured field",
+			header: http.Header{"Ratelimit": {"limit=100, remaining=42, reset=30"}},
+			want:   Quota{Limit: 100, Remaining: 42, Reset: 30 * time.Second},
+			ok:     true,
+		},
+		{
+			name: "separate fields with policy",
+			header: http.Header{
+				"Ratelimit-Limit":     {"60, 60;w=60"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"12"},
+			},
+			want: Quota{Limit: 60, Remaining: 0, Reset: 12 * time.Second},
+			ok:   true,
+		},
+		{
+			name: "x-ratelimit with epoch reset",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"4999"},
+				"X-Ratelimit-Reset":     {"1768046460"}, // now + 60s
+			},
+			want: Quota{Limit: 5000, Remaining: 4999, Reset: time.Minute},
+			ok:   true,
+		},
+		{
+			name:   "no rate limit headers",
+			header: http.Header{"Content-Type": {"application/json"}},
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseQuota(tt.header, now)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"Sat, 10 Jan 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Sat, 10 Jan 2026 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; expected %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
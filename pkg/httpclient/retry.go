@@ -0,0 +1,162 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/marcelofabianov/retry"
+)
+
+// errRetryableStatus signals retry.Do to try again; it never reaches
+// callers, who get the last response instead.
+var errRetryableStatus = errors.New("retryable status")
+
+// RetryConfig controls how NewRetryTransport retries failed requests.
+// MaxAttempts is the number of retries after the first attempt (0 disables
+// retries). A Retry-After longer than MaxRetryAfter is not waited for; the
+// response is returned as is.
+type RetryConfig struct {
+	MaxAttempts   int
+	BackoffMin    time.Duration
+	BackoffMax    time.Duration
+	MaxRetryAfter time.Duration
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	config RetryConfig
+}
+
+// NewRetryTransport wraps base so failed requests are retried:
+//
+//   - 429 and 503 are retried for every method, since the upstream rejected
+//     the request without processing it, waiting for Retry-After when given.
+//   - 502, 504 and network errors are retried for idempotent methods only.
+//
+// Requests with a body are only retried when it can be replayed
+// (http.NewRequest sets GetBody for common body types). When retries run
+// out, the last response is returned, so callers still see the 429 or 503.
+// Place it outside NewLimitedTransport so every attempt waits for the host
+// budget. A nil base uses http.DefaultTransport.
+func NewRetryTransport(base http.RoundTripper, cfg RetryConfig) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{base: base, config: cfg}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.MaxAttempts <= 0 || !replayable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	cfg := &retry.Config{
+		MaxAttempts: t.config.MaxAttempts,
+		Strategy: retry.NewExponentialBackoff(retry.ExponentialBackoffConfig{
+			Min:    t.config.BackoffMin,
+			Max:    t.config.BackoffMax,
+			Factor: 2.0,
+			Jitter: true,
+		}),
+	}
+
+	var last *http.Response
+	keep := func(resp *http.Response) {
+		if last != nil {
+			drain(last)
+		}
+		last = resp
+	}
+
+	attempt := 0
+	err := retry.Do(req.Context(), cfg, func(ctx context.Context) error {
+		attemptReq, err := rewind(ctx, req, attempt)
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		attempt++
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			keep(nil)
+			if errors.Is(err, ErrRateLimited) || !idempotent(req.Method) {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+
+		keep(resp)
+		if !retryableStatus(req.Method, resp.StatusCode) {
+			return nil
+		}
+
+		if delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if t.config.MaxRetryAfter > 0 && delay > t.config.MaxRetryAfter {
+				return retry.Permanent(errRetryableStatus)
+			}
+			return retry.RetryAfter(errRetryableStatus, delay)
+		}
+		return errRetryableStatus
+	})
+
+	if last != nil && (err == nil || req.Context().Err() == nil) {
+		return last, nil
+	}
+	if last != nil {
+		drain(last)
+	}
+	return nil, err
+}
+
+func retryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(method)
+	default:
+		return false
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the request for the given attempt, with a fresh body for
+// every attempt after the first.
+func rewind(ctx context.Context, req *http.Request, attempt int) (*http.Request, error) {
+	clone := req.WithContext(ctx)
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// drain discards a response that will not be returned, so its connection
+// can be reused.
+func drain(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
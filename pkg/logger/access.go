@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// AccessMessage is the message of every access log record.
+const AccessMessage = "request completed"
+
+// Access log attributes, the same in every service so one dashboard can
+// query them all.
+const (
+	AccessMethodKey    = "method"
+	AccessPathKey      = "path"
+	AccessStatusKey    = "status"
+	AccessBytesKey     = "bytes"
+	AccessDurationKey  = "duration_ms"
+	AccessRequestIDKey = "request_id"
+	AccessClientIPKey  = "client_ip"
+)
+
+// AccessEntry is one HTTP request served.
+type AccessEntry struct {
+	Method    string
+	Path      string
+	Status    int
+	Bytes     int64
+	Duration  time.Duration
+	RequestID string
+	// ClientIP may be a host:port address, such as http.Request.RemoteAddr;
+	// the port is dropped.
+	ClientIP string
+}
+
+// Attrs returns the entry in the canonical schema. Duration is written as
+// fractional milliseconds and empty request IDs are omitted.
+func (e AccessEntry) Attrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(AccessMethodKey, e.Method),
+		slog.String(AccessPathKey, e.Path),
+		slog.Int(AccessStatusKey, e.Status),
+		slog.Int64(AccessBytesKey, e.Bytes),
+		slog.Float64(AccessDurationKey, float64(e.Duration)/float64(time.Millisecond)),
+	}
+	if e.RequestID != "" {
+		attrs = append(attrs, slog.String(AccessRequestIDKey, e.RequestID))
+	}
+	if ip := clientIP(e.ClientIP); ip != "" {
+		attrs = append(attrs, slog.String(AccessClientIPKey, ip))
+	}
+	return attrs
+}
+
+// Level is Error for 5xx responses and Info otherwise.
+func (e AccessEntry) Level() slog.Level {
+	if e.Status >= http.StatusInternalServerError {
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// AccessLog writes entry to l in the canonical access log schema. It takes
+// a *slog.Logger so it serves both Logger and plain slog users, such as the
+// web Logger middleware.
+func AccessLog(ctx context.Context, l *slog.Logger, entry AccessEntry) {
+	if l == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	l.LogAttrs(ctx, entry.Level(), AccessMessage, entry.Attrs()...)
+}
+
+// AccessLog writes entry in the canonical access log schema.
+func (l *Logger) AccessLog(ctx context.Context, entry AccessEntry) {
+	AccessLog(ctx, l.logger, entry)
+}
+
+func clientIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	t.Run("escreve o esquema canonico", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf})
+
+		log.AccessLog(context.Background(), AccessEntry{
+			Method:    http.MethodGet,
+			Path:      "/courses/42",
+			Status:    http.StatusOK,
+			Bytes:     512,
+			Duration:  1500 * time.Microsecond,
+			RequestID: "req-1",
+			ClientIP:  "[2001:db8::1]:443",
+		})
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "INFO", entry["level"])
+		assert.Equal(t, AccessMessage, entry["msg"])
+		assert.Equal(t, http.MethodGet, entry[AccessMethodKey])
+		assert.Equal(t, "/courses/42", entry[AccessPathKey])
+		assert.Equal(t, float64(200), entry[AccessStatusKey])
+		assert.Equal(t, float64(512), entry[AccessBytesKey])
+		assert.Equal(t, 1.5, entry[AccessDurationKey])
+		assert.Equal(t, "req-1", entry[AccessRequestIDKey])
+		assert.Equal(t, "2001:db8::1", entry[AccessClientIPKey])
+	})
+
+	t.Run("registra erros do servidor como ERROR", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf})
+
+		log.AccessLog(context.Background(), AccessEntry{Method: http.MethodPost, Path: "/", Status: http.StatusBadGateway})
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "ERROR", entry["level"])
+		assert.NotContains(t, entry, AccessRequestIDKey)
+		assert.NotContains(t, entry, AccessClientIPKey)
+	})
+}
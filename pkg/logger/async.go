@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AsyncPolicy is what AsyncHandler does when its queue is full.
+type AsyncPolicy string
+
+const (
+	// AsyncBlock waits for room in the queue: no record is lost, but a
+	// stalled output slows the callers down.
+	AsyncBlock AsyncPolicy = "block"
+	// AsyncDrop discards the record and counts it, so logging never
+	// blocks the hot path.
+	AsyncDrop AsyncPolicy = "drop"
+)
+
+// asyncFlushTimeout bounds how long Sync waits for the queue to drain, so a
+// stalled output cannot hang Fatal.
+const asyncFlushTimeout = 5 * time.Second
+
+// AsyncOptions configures an AsyncHandler.
+type AsyncOptions struct {
+	// QueueSize bounds the records waiting to be written. Defaults to 1024.
+	QueueSize int
+	// BatchSize is how many queued records are written before Flush is
+	// called. Defaults to 64.
+	BatchSize int
+	// Policy defaults to AsyncBlock.
+	Policy AsyncPolicy
+	// Flush is called after each batch, e.g. to flush a bufio.Writer the
+	// handler writes to. Optional.
+	Flush func() error
+}
+
+// AsyncHandler moves formatting and writing off the calling goroutine:
+// records are queued to a bounded channel and written by a background
+// goroutine in batches. Call Close on shutdown to write what is queued.
+type AsyncHandler struct {
+	next  slog.Handler
+	queue *asyncQueue
+}
+
+type asyncRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	// done, when set, marks a Flush barrier instead of a record.
+	done chan struct{}
+}
+
+type asyncQueue struct {
+	opts    AsyncOptions
+	records chan asyncRecord
+	stopped chan struct{}
+	dropped atomic.Uint64
+
+	// mu guards closed; Handle holds it shared while sending, so Close
+	// never closes the channel under a sender.
+	mu     sync.RWMutex
+	closed bool
+	// syncMu serializes the synchronous writes made after Close.
+	syncMu sync.Mutex
+}
+
+func NewAsyncHandler(next slog.Handler, opts AsyncOptions) *AsyncHandler {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 64
+	}
+	if opts.Policy == "" {
+		opts.Policy = AsyncBlock
+	}
+
+	q := &asyncQueue{
+		opts:    opts,
+		records: make(chan asyncRecord, opts.QueueSize),
+		stopped: make(chan struct{}),
+	}
+	go q.run()
+
+	return &AsyncHandler{next: next, queue: q}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle queues r. Once the handler is closed, records are written
+// synchronously instead.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	q := h.queue
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return q.write(ctx, h.next, r)
+	}
+	defer q.mu.RUnlock()
+
+	// The record is handled after the call returns, possibly after ctx is
+	// canceled; keep its values but not its cancellation.
+	item := asyncRecord{ctx: context.WithoutCancel(ctx), handler: h.next, record: r.Clone()}
+	if q.opts.Policy == AsyncDrop {
+		select {
+		case q.records <- item:
+		default:
+			q.dropped.Add(1)
+		}
+		return nil
+	}
+	q.records <- item
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), queue: h.queue}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), queue: h.queue}
+}
+
+// Close writes the records queued in async mode and stops the background
+// goroutine; call it on shutdown. Without async mode it only flushes the
+// outputs (see Sync).
+func (l *Logger) Close() error {
+	if l.async == nil {
+		return l.Sync()
+	}
+	return errors.Join(l.async.Close(), l.Sync())
+}
+
+// Dropped counts the records discarded by AsyncDrop.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.queue.dropped.Load()
+}
+
+// Flush waits until every record queued before the call is written and
+// flushed, or ctx is done.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.queue
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil
+	}
+	done := make(chan struct{})
+	select {
+	case q.records <- asyncRecord{done: done}:
+		q.mu.RUnlock()
+	case <-ctx.Done():
+		q.mu.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes the queued records, reports how many were dropped and stops
+// the background goroutine. Records logged afterwards are written
+// synchronously. It is safe to call more than once.
+func (h *AsyncHandler) Close() error {
+	q := h.queue
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.records)
+	q.mu.Unlock()
+
+	<-q.stopped
+
+	if dropped := q.dropped.Load(); dropped > 0 {
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, "async logger dropped records", 0)
+		r.AddAttrs(slog.Uint64("dropped", dropped))
+		return q.write(context.Background(), h.next, r)
+	}
+	return nil
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.stopped)
+
+	for item := range q.records {
+		q.handle(item)
+
+		// Drain what is already queued, up to a batch, before flushing.
+	drain:
+		for n := 1; n < q.opts.BatchSize; n++ {
+			select {
+			case next, ok := <-q.records:
+				if !ok {
+					break drain
+				}
+				q.handle(next)
+			default:
+				break drain
+			}
+		}
+		q.flush()
+	}
+}
+
+func (q *asyncQueue) handle(item asyncRecord) {
+	if item.done != nil {
+		q.flush()
+		close(item.done)
+		return
+	}
+	_ = item.handler.Handle(item.ctx, item.record)
+}
+
+func (q *asyncQueue) flush() {
+	if q.opts.Flush != nil {
+		_ = q.opts.Flush()
+	}
+}
+
+func (q *asyncQueue) write(ctx context.Context, handler slog.Handler, r slog.Record) error {
+	q.syncMu.Lock()
+	defer q.syncMu.Unlock()
+
+	err := handler.Handle(ctx, r)
+	if q.opts.Flush != nil {
+		err = errors.Join(err, q.opts.Flush())
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// safeBuffer is read by the test while the async goroutine writes to it.
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// gateWriter blocks every write until release is closed.
+type gateWriter struct {
+	release chan struct{}
+	out     safeBuffer
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.out.Write(p)
+}
+
+func TestAsync(t *testing.T) {
+	t.Run("escreve em segundo plano e descarrega no Sync", func(t *testing.T) {
+		var out safeBuffer
+		log := New(&Config{Output: &out, Async: &AsyncOptions{}})
+		defer log.Close()
+
+		for i := 0; i < 100; i++ {
+			log.With("i", i).Info("request processed")
+		}
+		require.NoError(t, log.Sync())
+
+		assert.Equal(t, 100, strings.Count(out.String(), "request processed"))
+	})
+
+	t.Run("Drop descarta com a fila cheia e informa no Close", func(t *testing.T) {
+		output := &gateWriter{release: make(chan struct{})}
+		log := New(&Config{Output: output, Async: &AsyncOptions{QueueSize: 1, Policy: AsyncDrop}})
+
+		for i := 0; i < 50; i++ {
+			log.Info("request processed")
+		}
+		assert.Positive(t, log.async.Dropped())
+
+		close(output.release)
+		require.NoError(t, log.Close())
+
+		assert.Contains(t, output.out.String(), "async logger dropped records")
+	})
+
+	t.Run("registra de forma síncrona após o Close", func(t *testing.T) {
+		var out safeBuffer
+		log := New(&Config{Output: &out, Async: &AsyncOptions{}})
+		require.NoError(t, log.Close())
+
+		log.Info("after close")
+
+		assert.Contains(t, out.String(), "after close")
+		assert.NoError(t, log.Close())
+	})
+
+	t.Run("LOGGER_ASYNC ativa o modo assíncrono", func(t *testing.T) {
+		t.Setenv("LOGGER_ASYNC", "true")
+		t.Setenv("LOGGER_ASYNC_POLICY", "drop")
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		require.NotNil(t, cfg.Async)
+		assert.Equal(t, AsyncDrop, cfg.Async.Policy)
+		assert.Equal(t, 1024, cfg.Async.QueueSize)
+	})
+}
+
+func BenchmarkAsyncLogger(b *testing.B) {
+	for _, mode := range []struct {
+		name  string
+		async *AsyncOptions
+	}{
+		{"sync", nil},
+		{"async block", &AsyncOptions{}},
+		{"async drop", &AsyncOptions{Policy: AsyncDrop}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			log := New(&Config{Output: io.Discard, Async: mode.async})
+			defer log.Close()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					log.Info("benchmark message", "user_id", "123", "action", "test")
+				}
+			})
+		})
+	}
+}
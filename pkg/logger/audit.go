@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Attributes of audit records. Actor, resource and outcome are mandatory.
+const (
+	AuditActionKey   = "action"
+	AuditActorKey    = "actor"
+	AuditResourceKey = "resource"
+	AuditOutcomeKey  = "outcome"
+	AuditSeqKey      = "seq"
+	AuditPrevHashKey = "prev_hash"
+	AuditHashKey     = "hash"
+)
+
+// Common audit outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+var (
+	// ErrAuditIncomplete is returned by Audit when a mandatory field is
+	// missing; nothing is written.
+	ErrAuditIncomplete = errors.New("audit record incomplete")
+	// ErrAuditUnavailable is returned by Audit on loggers built with
+	// NewFromSlog, which have no audit channel.
+	ErrAuditUnavailable = errors.New("audit channel unavailable")
+	// ErrAuditTampered is returned by VerifyAudit when the chain is broken.
+	ErrAuditTampered = errors.New("audit log tampered")
+)
+
+// Audit writes a compliance record for action to the audit channel
+// (Config.AuditOutput, LOGGER_AUDIT_FILE), separate from the application
+// logs:
+//
+//	err := log.Audit(ctx, "course.delete",
+//		slog.String(logger.AuditResourceKey, "course:42"),
+//		slog.String(logger.AuditOutcomeKey, logger.OutcomeSuccess),
+//	)
+//
+// actor defaults to the user ID of ctx (WithUserID). Audit records bypass
+// the level, sampling and async mode, carry the service, environment and
+// context IDs but not the attributes of With, and are chained: each has a
+// sequence number, the hash of the previous record and its own hash (an
+// HMAC with Config.AuditKey), so VerifyAudit detects edited, removed or
+// reordered records.
+func (l *Logger) Audit(ctx context.Context, action string, attrs ...slog.Attr) error {
+	if l.audit == nil {
+		return ErrAuditUnavailable
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	present := make(map[string]bool, len(attrs))
+	for _, a := range attrs {
+		if !a.Value.Equal(slog.StringValue("")) {
+			present[a.Key] = true
+		}
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "audit", 0)
+	r.AddAttrs(slog.String(AuditActionKey, action))
+	if !present[AuditActorKey] {
+		if userID := UserIDFromContext(ctx); userID != "" {
+			r.AddAttrs(slog.String(AuditActorKey, userID))
+			present[AuditActorKey] = true
+		}
+	}
+	r.AddAttrs(attrs...)
+
+	var missing []string
+	if action == "" {
+		missing = append(missing, AuditActionKey)
+	}
+	for _, key := range []string{AuditActorKey, AuditResourceKey, AuditOutcomeKey} {
+		if !present[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %v", ErrAuditIncomplete, missing)
+	}
+
+	return l.audit.write(ctx, r)
+}
+
+// auditLog is shared by a logger and its children, so the sequence is
+// per process.
+type auditLog struct {
+	mu      sync.Mutex
+	w       io.Writer
+	buf     bytes.Buffer
+	handler slog.Handler
+	key     []byte
+	seq     uint64
+	prev    string
+}
+
+func newAuditLog(cfg *Config) *auditLog {
+	a := &auditLog{w: cfg.AuditOutput, key: cfg.AuditKey}
+	if a.w == nil {
+		a.w = cfg.Output
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey && len(groups) == 0 {
+				return slog.String(slog.LevelKey, "AUDIT")
+			}
+			return cfg.replaceAttr(groups, attr)
+		},
+	}
+	a.handler = NewContextHandler(slog.NewJSONHandler(&a.buf, opts), cfg.ContextKeys...).WithAttrs([]slog.Attr{
+		slog.String("service", cfg.ServiceName),
+		slog.String("environment", cfg.Environment),
+	})
+	return a
+}
+
+// write formats r with the chain fields and appends its hash, computed over
+// the line without the hash field.
+func (a *auditLog) write(ctx context.Context, r slog.Record) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seq := a.seq + 1
+	r.AddAttrs(slog.Uint64(AuditSeqKey, seq), slog.String(AuditPrevHashKey, a.prev))
+
+	a.buf.Reset()
+	if err := a.handler.Handle(ctx, r); err != nil {
+		return err
+	}
+	body := bytes.TrimSuffix(a.buf.Bytes(), []byte("\n"))
+	sum := auditSum(a.key, body)
+
+	line := make([]byte, 0, len(body)+len(sum)+12)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, `,"`+AuditHashKey+`":"`+sum+`"}`+"\n"...)
+	if _, err := a.w.Write(line); err != nil {
+		return err
+	}
+
+	a.seq = seq
+	a.prev = sum
+	return nil
+}
+
+func auditSum(key, body []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyAudit reads an audit log written by Audit and checks every record
+// against the previous one: the hash (with the same key), the link to the
+// previous hash and the sequence. A sequence restarting at 1, with no
+// previous hash, marks a process restart. It returns ErrAuditTampered with
+// the line of the first broken record.
+func VerifyAudit(r io.Reader, key []byte) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var prev string
+	var seq uint64
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var record struct {
+			Seq      uint64 `json:"seq"`
+			PrevHash string `json:"prev_hash"`
+			Hash     string `json:"hash"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrAuditTampered, n, err)
+		}
+
+		marker := []byte(`,"` + AuditHashKey + `":"`)
+		i := bytes.LastIndex(line, marker)
+		if i < 0 {
+			return fmt.Errorf("%w: line %d: no hash", ErrAuditTampered, n)
+		}
+		body := append(append([]byte(nil), line[:i]...), '}')
+		if !hmac.Equal([]byte(auditSum(key, body)), []byte(record.Hash)) {
+			return fmt.Errorf("%w: line %d: hash mismatch", ErrAuditTampered, n)
+		}
+
+		restart := record.Seq == 1 && record.PrevHash == ""
+		if !restart && (record.Seq != seq+1 || record.PrevHash != prev) {
+			return fmt.Errorf("%w: line %d: expected seq %d after %q", ErrAuditTampered, n, seq+1, prev)
+		}
+
+		seq = record.Seq
+		prev = record.Hash
+	}
+	return scanner.Err()
+}
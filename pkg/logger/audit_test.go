@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditRecord(resource, outcome string) []slog.Attr {
+	return []slog.Attr{
+		slog.String(AuditResourceKey, resource),
+		slog.String(AuditOutcomeKey, outcome),
+	}
+}
+
+func TestAudit(t *testing.T) {
+	t.Run("escreve no canal de auditoria separado", func(t *testing.T) {
+		var app, audit bytes.Buffer
+		log := New(&Config{Level: LevelError, Output: &app, AuditOutput: &audit, ServiceName: "course"})
+
+		ctx := WithRequestID(WithUserID(context.Background(), "user-7"), "req-1")
+		err := log.With("component", "api").Audit(ctx, "course.delete", auditRecord("course:42", OutcomeSuccess)...)
+		require.NoError(t, err)
+
+		assert.Empty(t, app.String())
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(audit.Bytes(), &entry))
+		assert.Equal(t, "AUDIT", entry["level"])
+		assert.Equal(t, "course.delete", entry[AuditActionKey])
+		assert.Equal(t, "user-7", entry[AuditActorKey])
+		assert.Equal(t, "course:42", entry[AuditResourceKey])
+		assert.Equal(t, OutcomeSuccess, entry[AuditOutcomeKey])
+		assert.Equal(t, "course", entry["service"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, float64(1), entry[AuditSeqKey])
+		assert.Equal(t, "", entry[AuditPrevHashKey])
+		assert.Len(t, entry[AuditHashKey], 64)
+		assert.NotContains(t, entry, "component")
+	})
+
+	t.Run("exige actor, resource e outcome", func(t *testing.T) {
+		var audit bytes.Buffer
+		log := New(&Config{Output: &audit})
+
+		err := log.Audit(context.Background(), "course.delete", slog.String(AuditResourceKey, "course:42"))
+		require.ErrorIs(t, err, ErrAuditIncomplete)
+		assert.Contains(t, err.Error(), AuditActorKey)
+		assert.Contains(t, err.Error(), AuditOutcomeKey)
+		assert.Empty(t, audit.String())
+	})
+
+	t.Run("encadeia os registros entre loggers filhos", func(t *testing.T) {
+		var audit bytes.Buffer
+		log := New(&Config{Output: &audit, AuditKey: []byte("secret")})
+		ctx := WithUserID(context.Background(), "admin")
+
+		require.NoError(t, log.Audit(ctx, "grade.update", auditRecord("grade:1", OutcomeSuccess)...))
+		require.NoError(t, log.ForModule("enrollment").Audit(ctx, "enrollment.cancel", auditRecord("enrollment:9", OutcomeDenied)...))
+
+		lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+		require.Len(t, lines, 2)
+		var first, second map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+		assert.Equal(t, float64(2), second[AuditSeqKey])
+		assert.Equal(t, first[AuditHashKey], second[AuditPrevHashKey])
+
+		assert.NoError(t, VerifyAudit(strings.NewReader(audit.String()), []byte("secret")))
+		assert.ErrorIs(t, VerifyAudit(strings.NewReader(audit.String()), []byte("other")), ErrAuditTampered)
+	})
+
+	t.Run("detecta registros alterados, removidos ou reordenados", func(t *testing.T) {
+		var audit bytes.Buffer
+		log := New(&Config{Output: &audit})
+		ctx := WithUserID(context.Background(), "admin")
+		for _, resource := range []string{"course:1", "course:2", "course:3"} {
+			require.NoError(t, log.Audit(ctx, "course.publish", auditRecord(resource, OutcomeSuccess)...))
+		}
+		lines := strings.SplitAfter(strings.TrimSpace(audit.String()), "\n")
+		require.NoError(t, VerifyAudit(strings.NewReader(audit.String()), nil))
+
+		edited := strings.Replace(audit.String(), "course:2", "course:9", 1)
+		removed := lines[0] + lines[2]
+		reordered := lines[1] + lines[0] + lines[2]
+
+		for _, log := range []string{edited, removed, reordered} {
+			assert.ErrorIs(t, VerifyAudit(strings.NewReader(log), nil), ErrAuditTampered)
+		}
+	})
+
+	t.Run("loggers sem configuracao nao tem canal de auditoria", func(t *testing.T) {
+		log := NewFromSlog(slog.Default(), "svc", "test")
+		err := log.Audit(context.Background(), "course.delete", auditRecord("course:1", OutcomeSuccess)...)
+		assert.ErrorIs(t, err, ErrAuditUnavailable)
+	})
+}
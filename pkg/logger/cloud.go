@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+// Cloud provider presets: JSON with the keys and values the provider's log
+// explorer expects, so severities, messages and trace links show up
+// correctly.
+const (
+	// FormatGCP writes severity, message and timestamp for Cloud Logging,
+	// and links records to Cloud Trace through logging.googleapis.com/trace
+	// (set Config.GCPProjectID) and logging.googleapis.com/spanId.
+	FormatGCP LogFormat = "gcp"
+	// FormatAWS writes level, message and timestamp for CloudWatch, with the
+	// trace ID converted to the X-Ray format as xray_trace_id.
+	FormatAWS LogFormat = "aws"
+)
+
+const (
+	gcpTraceKey  = "logging.googleapis.com/trace"
+	gcpSpanKey   = "logging.googleapis.com/spanId"
+	gcpSourceKey = "logging.googleapis.com/sourceLocation"
+	awsTraceKey  = "xray_trace_id"
+)
+
+var w3cTraceID = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// cloudAttr renames the top-level attributes of a as format expects.
+// Attributes inside groups are left alone.
+func (cfg *Config) cloudAttr(format LogFormat, groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch format {
+	case FormatGCP:
+		switch a.Key {
+		case slog.LevelKey:
+			if level, ok := a.Value.Any().(slog.Level); ok {
+				return slog.String("severity", gcpSeverity(level))
+			}
+			a.Key = "severity"
+		case slog.MessageKey:
+			a.Key = "message"
+		case slog.TimeKey:
+			a.Key = "timestamp"
+		case slog.SourceKey:
+			a.Key = gcpSourceKey
+		case "trace_id":
+			traceID := a.Value.String()
+			if cfg.GCPProjectID != "" {
+				traceID = "projects/" + cfg.GCPProjectID + "/traces/" + traceID
+			}
+			return slog.String(gcpTraceKey, traceID)
+		case "span_id":
+			a.Key = gcpSpanKey
+		}
+	case FormatAWS:
+		switch a.Key {
+		case slog.MessageKey:
+			a.Key = "message"
+		case slog.TimeKey:
+			a.Key = "timestamp"
+		case "trace_id":
+			return slog.String(awsTraceKey, xrayTraceID(a.Value.String()))
+		}
+	}
+	return a
+}
+
+// gcpSeverity maps slog levels to the Cloud Logging LogSeverity names.
+func gcpSeverity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	case level < levelPanic:
+		return "ERROR"
+	case level < levelFatal:
+		return "CRITICAL"
+	default:
+		return "ALERT"
+	}
+}
+
+// xrayTraceID converts a W3C trace ID (32 hex digits) into the X-Ray
+// format, 1-{8 digits}-{24 digits}. Other IDs are returned as is.
+func xrayTraceID(traceID string) string {
+	if !w3cTraceID.MatchString(traceID) {
+		return traceID
+	}
+	return "1-" + traceID[:8] + "-" + traceID[8:]
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestCloudFormats(t *testing.T) {
+	ctx := WithTrace(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+	t.Run("FormatGCP usa os campos do Cloud Logging", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, Format: FormatGCP, GCPProjectID: "studion-prod"})
+
+		log.WarnContext(ctx, "slow query", "duration_ms", 120)
+
+		entry := decodeEntry(t, &buf)
+		assert.Equal(t, "WARNING", entry["severity"])
+		assert.Equal(t, "slow query", entry["message"])
+		assert.Contains(t, entry, "timestamp")
+		assert.Equal(t, "projects/studion-prod/traces/4bf92f3577b34da6a3ce929d0e0e4736", entry[gcpTraceKey])
+		assert.Equal(t, "00f067aa0ba902b7", entry[gcpSpanKey])
+		assert.NotContains(t, entry, "level")
+		assert.NotContains(t, entry, "msg")
+	})
+
+	t.Run("FormatGCP mapeia Fatal para ALERT", func(t *testing.T) {
+		exit = func(int) {}
+		t.Cleanup(func() { exit = os.Exit })
+
+		var buf bytes.Buffer
+		New(&Config{Output: &buf, Format: FormatGCP}).Fatal("failed to start")
+
+		assert.Equal(t, "ALERT", decodeEntry(t, &buf)["severity"])
+	})
+
+	t.Run("FormatAWS converte o trace para X-Ray", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, Format: FormatAWS})
+
+		log.ErrorContext(ctx, "payment failed")
+
+		entry := decodeEntry(t, &buf)
+		assert.Equal(t, "ERROR", entry["level"])
+		assert.Equal(t, "payment failed", entry["message"])
+		assert.Contains(t, entry, "timestamp")
+		assert.Equal(t, "1-4bf92f35-77b34da6a3ce929d0e0e4736", entry[awsTraceKey])
+	})
+
+	t.Run("LOGGER_FORMAT sobrepõe o formato do ambiente", func(t *testing.T) {
+		t.Setenv("LOGGER_FORMAT", "gcp")
+		t.Setenv("LOGGER_ENVIRONMENT", "development")
+		t.Setenv("GOOGLE_CLOUD_PROJECT", "studion-dev")
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, FormatGCP, cfg.Format)
+		assert.Equal(t, "studion-dev", cfg.GCPProjectID)
+	})
+}
@@ -1,13 +1,17 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/marcelofabianov/config"
 	"github.com/spf13/viper"
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/marcelofabianov/redact"
 )
 
 // Config holds the logger configuration
@@ -19,38 +23,143 @@ type Config struct {
 	Environment string
 	AddSource   bool
 	TimeFormat  string
+
+	// GCPProjectID prefixes trace IDs with projects/<id>/traces/ in
+	// FormatGCP, so Cloud Logging links records to Cloud Trace.
+	GCPProjectID string
+
+	// Redactor masks sensitive attributes (passwords, tokens, CPFs) before
+	// they are written. Nil logs attributes as is.
+	Redactor *redact.Redactor
+
+	// SensitiveFields are masked in full in addition to the Redactor rules,
+	// e.g. service-specific keys such as "boleto_barcode". Nested keys and
+	// attributes added with With are covered too (see RedactHandler).
+	SensitiveFields []string
+
+	// ContextKeys are extracted from the context in addition to
+	// DefaultContextKeys, e.g. {Attr: "request_id", Key: middleware.RequestIDKey}.
+	ContextKeys []ContextKey
+
+	// Destinations receive every record in addition to Output, each with its
+	// own format and level.
+	Destinations []Destination
+
+	// Async, when set, writes records from a background goroutine (see
+	// AsyncHandler). Call Logger.Close on shutdown.
+	Async *AsyncOptions
+
+	// ErrorStack adds the stack of the logging call to WithError.
+	ErrorStack bool
+
+	// Sampling drops repetitive records per level (see SamplingHandler).
+	// Levels without a rule are never sampled.
+	Sampling map[LogLevel]Sampling
+
+	// AuditOutput receives the records of Logger.Audit. Defaults to Output.
+	AuditOutput io.Writer
+	// AuditKey, when set, makes the audit chain hashes HMAC-SHA256, so
+	// records cannot be rewritten without it.
+	AuditKey []byte
+
+	// LevelOverrides sets the level of the loggers returned by ForModule,
+	// e.g. {"cache": LevelDebug}.
+	LevelOverrides map[string]LogLevel
+
+	// LoggerProvider, when set, also exports every record through
+	// OpenTelemetry (see OTelHandler), at the logger level.
+	LoggerProvider otellog.LoggerProvider
 }
 
-// LoadConfig loads logger configuration from environment variables using Viper.
-// It looks for a .env file in the current directory and up to 5 parent directories.
+// LoadConfig loads the LOGGER_* settings with config.Load. Services that
+// load several packages should call config.Load once and use
+// LoadConfigFrom.
 func LoadConfig() (*Config, error) {
-	v := viper.New()
-
-	// Find and load .env file
-	envFile := findEnvFile()
-	if envFile != "" {
-		v.SetConfigFile(envFile)
-		v.SetConfigType("env")
-		_ = v.ReadInConfig() // Ignore error, we have defaults
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
 	}
+	return LoadConfigFrom(c)
+}
 
-	// Environment variables take precedence
-	v.AutomaticEnv()
-	v.SetEnvPrefix("LOGGER")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+// LoadConfigFrom loads the logger section of c: LOGGER_* variables over the
+// logger section of the config file, with the redact section for Redactor.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+	v := c.Section("logger", "LOGGER")
 
 	// Set defaults
 	setDefaults(v)
 
+	redactCfg, err := redact.LoadConfigFrom(c)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build config
 	cfg := &Config{
 		Level:       parseLevel(v.GetString("level")),
-		Format:      determineFormat(v.GetString("environment")),
+		Format:      determineFormat(v.GetString("format"), v.GetString("environment")),
 		Output:      os.Stdout,
 		ServiceName: v.GetString("service_name"),
 		Environment: v.GetString("environment"),
 		AddSource:   shouldAddSource(v.GetString("environment")),
 		TimeFormat:  time.RFC3339,
+		Redactor:    redact.New(redactCfg),
+		ErrorStack:  v.GetBool("error_stack"),
+	}
+	cfg.GCPProjectID = v.GetString("gcp_project_id")
+	if cfg.GCPProjectID == "" {
+		cfg.GCPProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+
+	if v.GetBool("async") {
+		cfg.Async = &AsyncOptions{
+			QueueSize: v.GetInt("async_queue_size"),
+			Policy:    AsyncPolicy(strings.ToLower(v.GetString("async_policy"))),
+		}
+	}
+
+	if path := v.GetString("audit_file"); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("open audit file %s: %w", path, err)
+		}
+		cfg.AuditOutput = file
+	}
+	if key := v.GetString("audit_key"); key != "" {
+		cfg.AuditKey = []byte(key)
+	}
+
+	if spec := v.GetString("level_overrides"); spec != "" {
+		overrides, err := parseLevelOverrides(spec)
+		if err != nil {
+			return nil, err
+		}
+		if len(overrides) > 0 {
+			cfg.LevelOverrides = overrides
+		}
+	}
+
+	if path := v.GetString("file"); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open log file %s: %w", path, err)
+		}
+		dest := Destination{Output: file, Format: FormatJSON}
+		if level := v.GetString("file_level"); level != "" {
+			dest.Level = parseLevel(level)
+		}
+		cfg.Destinations = append(cfg.Destinations, dest)
+	}
+
+	if first := v.GetInt("sampling_first"); first > 0 {
+		rule := Sampling{First: first, Thereafter: v.GetInt("sampling_thereafter")}
+		cfg.Sampling = map[LogLevel]Sampling{
+			LevelDebug: rule,
+			LevelInfo:  rule,
+			LevelWarn:  rule,
+			LevelError: rule,
+		}
 	}
 
 	return cfg, nil
@@ -60,24 +169,20 @@ func LoadConfig() (*Config, error) {
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("level", "info")
 	v.SetDefault("environment", "development")
+	v.SetDefault("format", "")
+	v.SetDefault("gcp_project_id", "")
 	v.SetDefault("service_name", "app")
-}
-
-// findEnvFile searches for .env file in current and parent directories (up to 5 levels)
-func findEnvFile() string {
-	dir, _ := os.Getwd()
-	for i := 0; i < 5; i++ {
-		envPath := filepath.Join(dir, ".env")
-		if _, err := os.Stat(envPath); err == nil {
-			return envPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-	return ".env" // fallback
+	v.SetDefault("level_overrides", "")
+	v.SetDefault("file", "")
+	v.SetDefault("file_level", "")
+	v.SetDefault("audit_file", "")
+	v.SetDefault("audit_key", "")
+	v.SetDefault("error_stack", false)
+	v.SetDefault("async", false)
+	v.SetDefault("async_queue_size", 1024)
+	v.SetDefault("async_policy", string(AsyncBlock))
+	v.SetDefault("sampling_first", 0)
+	v.SetDefault("sampling_thereafter", 0)
 }
 
 // parseLevel converts string log level to LogLevel
@@ -99,13 +204,31 @@ func parseLevel(level string) LogLevel {
 	}
 }
 
-// determineFormat returns the appropriate log format based on environment
-func determineFormat(env string) LogFormat {
+// determineFormat returns LOGGER_FORMAT when set, otherwise the appropriate
+// log format based on environment
+func determineFormat(format, env string) LogFormat {
+	switch LogFormat(strings.ToLower(strings.TrimSpace(format))) {
+	case FormatJSON:
+		return FormatJSON
+	case FormatText:
+		return FormatText
+	case FormatGCP:
+		return FormatGCP
+	case FormatAWS:
+		return FormatAWS
+	case FormatPretty:
+		return FormatPretty
+	}
+
 	env = strings.ToLower(env)
-	if env == "production" || env == "prod" || env == "staging" {
+	switch env {
+	case "production", "prod", "staging":
 		return FormatJSON
+	case "development", "dev":
+		return FormatPretty
+	default:
+		return FormatText
 	}
-	return FormatText
 }
 
 // shouldAddSource determines if source location should be added to logs
@@ -1,10 +1,13 @@
 package logger_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/marcelofabianov/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -16,7 +19,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, logger.LevelInfo, cfg.Level)
 	assert.Equal(t, "development", cfg.Environment)
 	assert.Equal(t, "app", cfg.ServiceName)
-	assert.Equal(t, logger.FormatText, cfg.Format)
+	assert.Equal(t, logger.FormatPretty, cfg.Format)
 	assert.True(t, cfg.AddSource)
 }
 
@@ -32,3 +35,29 @@ func TestLoadConfigWithLogger(t *testing.T) {
 	log.Info("test message")
 	log.Debug("debug message")
 }
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+logger:
+  service_name: course
+  level: debug
+profiles:
+  production:
+    logger:
+      environment: production
+      level: warn
+`), 0o600)
+	require.NoError(t, err)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("CONFIG_PROFILE", "production")
+	t.Setenv("LOGGER_SERVICE_NAME", "course-api")
+
+	cfg, err := logger.LoadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, logger.LevelWarn, cfg.Level)
+	assert.Equal(t, "production", cfg.Environment)
+	assert.Equal(t, logger.FormatJSON, cfg.Format)
+	assert.Equal(t, "course-api", cfg.ServiceName, "LOGGER_SERVICE_NAME wins over the file")
+}
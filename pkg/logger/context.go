@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceIDKey
+	spanIDKey
+	userIDKey
+)
+
+// ContextKey maps a context key to the attribute ContextHandler writes when
+// the context carries a value for it. Use it to pick up values stored by
+// other packages, e.g. chi's middleware.RequestIDKey.
+type ContextKey struct {
+	Attr string
+	Key  any
+}
+
+// DefaultContextKeys are the keys set by WithRequestID, WithTrace and
+// WithUserID.
+var DefaultContextKeys = []ContextKey{
+	{Attr: "request_id", Key: requestIDKey},
+	{Attr: "trace_id", Key: traceIDKey},
+	{Attr: "span_id", Key: spanIDKey},
+	{Attr: "user_id", Key: userIDKey},
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// WithTrace returns a copy of ctx carrying the trace and span IDs.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	ctx = context.WithValue(ctx, traceIDKey, traceID)
+	return context.WithValue(ctx, spanIDKey, spanID)
+}
+
+// WithUserID returns a copy of ctx carrying the authenticated user ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, requestIDKey)
+}
+
+func TraceIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, traceIDKey)
+}
+
+func SpanIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, spanIDKey)
+}
+
+func UserIDFromContext(ctx context.Context) string {
+	return stringFromContext(ctx, userIDKey)
+}
+
+func stringFromContext(ctx context.Context, key contextKey) string {
+	if ctx == nil {
+		return ""
+	}
+	value, _ := ctx.Value(key).(string)
+	return value
+}
+
+// ContextHandler appends the correlation values found in the record's
+// context (request ID, trace/span IDs, user ID) to every record, so
+// InfoContext and friends correlate with the request without manual With
+// calls. Empty values are skipped and the first key found wins when several
+// map to the same attribute. Attributes are added at record level, so
+// they land inside any group opened with WithGroup.
+type ContextHandler struct {
+	next slog.Handler
+	keys []ContextKey
+}
+
+// NewContextHandler wraps next, extracting DefaultContextKeys plus any extra
+// keys.
+func NewContextHandler(next slog.Handler, keys ...ContextKey) *ContextHandler {
+	all := make([]ContextKey, 0, len(DefaultContextKeys)+len(keys))
+	all = append(all, DefaultContextKeys...)
+	all = append(all, keys...)
+
+	return &ContextHandler{next: next, keys: all}
+}
+
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		added := make(map[string]bool, len(h.keys))
+		for _, k := range h.keys {
+			if added[k.Attr] {
+				continue
+			}
+			value := ctx.Value(k.Key)
+			if value == nil || value == "" {
+				continue
+			}
+			r.AddAttrs(slog.Any(k.Attr, value))
+			added[k.Attr] = true
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{next: h.next.WithAttrs(attrs), keys: h.keys}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{next: h.next.WithGroup(name), keys: h.keys}
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type externalKey struct{}
+
+func TestContextHandler(t *testing.T) {
+	newLogger := func(buf *bytes.Buffer, keys ...ContextKey) *Logger {
+		return New(&Config{
+			Level:       LevelInfo,
+			Format:      FormatJSON,
+			Output:      buf,
+			ServiceName: "test-service",
+			Environment: "test",
+			ContextKeys: keys,
+		})
+	}
+
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]any {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		return entry
+	}
+
+	t.Run("adiciona IDs de correlação do contexto", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf)
+
+		ctx := WithRequestID(context.Background(), "req-1")
+		ctx = WithTrace(ctx, "trace-1", "span-1")
+		ctx = WithUserID(ctx, "user-1")
+		log.InfoContext(ctx, "request processed")
+
+		entry := decode(t, &buf)
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, "trace-1", entry["trace_id"])
+		assert.Equal(t, "span-1", entry["span_id"])
+		assert.Equal(t, "user-1", entry["user_id"])
+	})
+
+	t.Run("omite valores ausentes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf)
+
+		log.InfoContext(WithRequestID(context.Background(), "req-2"), "request processed")
+		log.Info("no context")
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		var withCtx, withoutCtx map[string]any
+		require.NoError(t, json.Unmarshal(lines[0], &withCtx))
+		require.NoError(t, json.Unmarshal(lines[1], &withoutCtx))
+		assert.Equal(t, "req-2", withCtx["request_id"])
+		assert.NotContains(t, withCtx, "trace_id")
+		assert.NotContains(t, withoutCtx, "request_id")
+	})
+
+	t.Run("usa chaves de outros pacotes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf, ContextKey{Attr: "request_id", Key: externalKey{}})
+
+		ctx := context.WithValue(context.Background(), externalKey{}, "req-ext")
+		log.With("component", "test").InfoContext(ctx, "request processed")
+
+		entry := decode(t, &buf)
+		assert.Equal(t, "req-ext", entry["request_id"])
+		assert.Equal(t, "test", entry["component"])
+	})
+
+	t.Run("primeira chave encontrada prevalece", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf, ContextKey{Attr: "request_id", Key: externalKey{}})
+
+		ctx := context.WithValue(context.Background(), externalKey{}, "req-ext")
+		ctx = WithRequestID(ctx, "req-own")
+		log.InfoContext(ctx, "request processed")
+
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"request_id"`)))
+		assert.Equal(t, "req-own", decode(t, &buf)["request_id"])
+	})
+
+	t.Run("getters retornam valores do contexto", func(t *testing.T) {
+		ctx := WithUserID(WithRequestID(context.Background(), "req-3"), "user-3")
+
+		assert.Equal(t, "req-3", RequestIDFromContext(ctx))
+		assert.Equal(t, "user-3", UserIDFromContext(ctx))
+		assert.Empty(t, TraceIDFromContext(ctx))
+		assert.Empty(t, SpanIDFromContext(context.Background()))
+	})
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrorKey is the attribute key used by ErrAttr and WithError.
+const ErrorKey = "error"
+
+// maxStackDepth bounds the frames captured by ErrAttrWithStack.
+const maxStackDepth = 32
+
+// ErrorValue is an slog.LogValuer that expands an error into structured
+// fields instead of a flat string: the full message, the first fault code
+// in the chain, the message of each wrapping level, the merged fault
+// context (outer levels win) and, when captured, the stack.
+//
+//	{"error":{"message":"...","code":"infra_error","chain":["...","..."],
+//	  "context":{"host":"db-1"},"stack":["main.run main.go:42", ...]}}
+type ErrorValue struct {
+	Err   error
+	Stack []uintptr
+}
+
+// ErrAttr returns err as an "error" attribute expanded by ErrorValue.
+func ErrAttr(err error) slog.Attr {
+	return slog.Any(ErrorKey, ErrorValue{Err: err})
+}
+
+// ErrAttrWithStack is ErrAttr plus the stack of its caller. fault errors
+// carry no stack, so it is captured where the error is logged.
+func ErrAttrWithStack(err error) slog.Attr {
+	return slog.Any(ErrorKey, ErrorValue{Err: err, Stack: callers(3)})
+}
+
+// WithError returns a child logger carrying err as an "error" attribute,
+// with the stack when Config.ErrorStack is set:
+//
+//	log.WithError(err).Error("failed to enroll student")
+func (l *Logger) WithError(err error) *Logger {
+	attr := ErrAttr(err)
+	if l.config != nil && l.config.ErrorStack {
+		attr = slog.Any(ErrorKey, ErrorValue{Err: err, Stack: callers(3)})
+	}
+	return l.With(attr)
+}
+
+func (v ErrorValue) LogValue() slog.Value {
+	if v.Err == nil {
+		return slog.StringValue("<nil>")
+	}
+
+	attrs := []slog.Attr{slog.String("message", v.Err.Error())}
+
+	var (
+		code    fault.Code
+		chain   []string
+		context = make(map[string]any)
+	)
+	for err := v.Err; err != nil; err = errors.Unwrap(err) {
+		inner := errors.Unwrap(err)
+
+		f, ok := err.(*fault.Error)
+		if !ok {
+			chain = append(chain, ownMessage(err, inner))
+			continue
+		}
+
+		chain = append(chain, f.Message)
+		if code == "" {
+			code = f.Code
+		}
+		for key, value := range f.Context {
+			if _, ok := context[key]; !ok {
+				context[key] = value
+			}
+		}
+	}
+
+	if code != "" {
+		attrs = append(attrs, slog.String("code", string(code)))
+	}
+	if len(chain) > 1 {
+		attrs = append(attrs, slog.Any("chain", chain))
+	}
+	if len(context) > 0 {
+		keys := make([]string, 0, len(context))
+		for key := range context {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		members := make([]any, 0, len(keys)*2)
+		for _, key := range keys {
+			members = append(members, key, context[key])
+		}
+		attrs = append(attrs, slog.Group("context", members...))
+	}
+	if len(v.Stack) > 0 {
+		attrs = append(attrs, slog.Any("stack", stackFrames(v.Stack)))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// ownMessage returns the part of err's message that is not inner's, so a
+// fmt.Errorf("loading course: %w", err) level reads "loading course".
+func ownMessage(err, inner error) string {
+	msg := err.Error()
+	if inner == nil {
+		return msg
+	}
+	if trimmed, ok := strings.CutSuffix(msg, ": "+inner.Error()); ok {
+		return trimmed
+	}
+	return msg
+}
+
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	return pcs[:runtime.Callers(skip, pcs)]
+}
+
+func stackFrames(pcs []uintptr) []string {
+	frames := runtime.CallersFrames(pcs)
+	var out []string
+	for {
+		frame, more := frames.Next()
+		out = append(out, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			return out
+		}
+	}
+}
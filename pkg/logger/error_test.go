@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = fault.New("course not found",
+	fault.WithCode(fault.NotFound),
+	fault.WithContext("table", "courses"),
+)
+
+func decodeError(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	field, ok := entry[ErrorKey].(map[string]any)
+	require.True(t, ok, "expected error group, got %v", entry[ErrorKey])
+	return field
+}
+
+func TestErrorValue(t *testing.T) {
+	t.Run("expande código, cadeia e contexto de erros fault", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf})
+
+		err := fmt.Errorf("loading enrollment: %w",
+			fault.Wrap(errNotFound, "find course", fault.WithContext("course_id", "c-1")))
+		log.WithError(err).Error("failed to enroll student")
+
+		field := decodeError(t, &buf)
+		assert.Equal(t, err.Error(), field["message"])
+		assert.Equal(t, string(fault.NotFound), field["code"])
+		assert.Equal(t, []any{"loading enrollment", "find course", "course not found"}, field["chain"])
+		assert.Equal(t, map[string]any{"course_id": "c-1", "table": "courses"}, field["context"])
+		assert.NotContains(t, field, "stack")
+	})
+
+	t.Run("erros comuns registram apenas a mensagem", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf})
+
+		log.Error("request failed", ErrAttr(errors.New("connection reset")))
+
+		assert.Equal(t, map[string]any{"message": "connection reset"}, decodeError(t, &buf))
+	})
+
+	t.Run("ErrorStack inclui a pilha da chamada", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, ErrorStack: true})
+
+		log.WithError(errNotFound).Error("failed to enroll student")
+
+		stack, ok := decodeError(t, &buf)["stack"].([]any)
+		require.True(t, ok)
+		require.NotEmpty(t, stack)
+		assert.Contains(t, stack[0], "TestErrorValue")
+	})
+
+	t.Run("contexto sensível é mascarado", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := defaultConfig()
+		cfg.Output = &buf
+		log := New(cfg)
+
+		log.Error("login failed", ErrAttr(fault.New("invalid credentials",
+			fault.WithCode(fault.Unauthorized),
+			fault.WithContext("password", "s3cret"),
+		)))
+
+		assert.NotContains(t, buf.String(), "s3cret")
+	})
+}
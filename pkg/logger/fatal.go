@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"syscall"
+)
+
+// Levels above Error used by Panic and Fatal. They print as PANIC and
+// FATAL and map to the OpenTelemetry ERROR3 and FATAL severities.
+const (
+	levelPanic = slog.LevelError + 2
+	levelFatal = slog.LevelError + 4
+)
+
+// exit is replaced in tests.
+var exit = os.Exit
+
+// Fatal logs msg at FATAL, flushes the outputs and exits with status 1.
+// Deferred functions do not run.
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.logger.Log(context.Background(), levelFatal, msg, args...)
+	_ = l.Sync()
+	exit(1)
+}
+
+// Fatalf is Fatal with a formatted message.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.Fatal(fmt.Sprintf(format, args...))
+}
+
+// Panic logs msg at PANIC, flushes the outputs and panics with msg, so
+// deferred recovers (e.g. the web Recoverer middleware) still run.
+func (l *Logger) Panic(msg string, args ...any) {
+	l.logger.Log(context.Background(), levelPanic, msg, args...)
+	_ = l.Sync()
+	panic(msg)
+}
+
+// Sync flushes every output that buffers writes, such as the files of
+// LOGGER_FILE, after the records queued in async mode are written, and
+// returns the joined errors. Outputs that cannot be
+// synced (a terminal or a pipe on stdout) are skipped.
+func (l *Logger) Sync() error {
+	if l.config == nil {
+		return nil
+	}
+
+	var errs []error
+	if l.async != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), asyncFlushTimeout)
+		errs = append(errs, l.async.Flush(ctx))
+		cancel()
+	}
+
+	outputs := []any{l.config.Output}
+	for _, dest := range l.config.Destinations {
+		outputs = append(outputs, dest.Output)
+	}
+	if l.config.AuditOutput != nil {
+		outputs = append(outputs, l.config.AuditOutput)
+	}
+
+	for _, output := range outputs {
+		switch w := output.(type) {
+		case *os.File:
+			// Sync fails with EINVAL or ENOTTY on pipes and terminals,
+			// which have nothing to flush.
+			if err := w.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+				errs = append(errs, err)
+			}
+		case interface{ Flush() error }:
+			if err := w.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		case interface{ Sync() error }:
+			if err := w.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// levelName renders the levels above Error by name.
+func levelName(level slog.Level) (string, bool) {
+	switch level {
+	case levelPanic:
+		return "PANIC", true
+	case levelFatal:
+		return "FATAL", true
+	default:
+		return "", false
+	}
+}
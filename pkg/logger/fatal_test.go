@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatal(t *testing.T) {
+	var code int
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = os.Exit })
+
+	t.Run("registra em FATAL, descarrega e sai com 1", func(t *testing.T) {
+		var buf bytes.Buffer
+		file := bufio.NewWriter(&buf)
+		log := New(&Config{Output: &bytes.Buffer{}, Destinations: []Destination{{Output: file}}})
+
+		log.Fatalf("failed to load config: %s", "missing WEB_HTTP_PORT")
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, buf.String(), `"level":"FATAL"`)
+		assert.Contains(t, buf.String(), "failed to load config: missing WEB_HTTP_PORT")
+	})
+
+	t.Run("Panic registra em PANIC e entra em pânico", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, Format: FormatText})
+
+		assert.PanicsWithValue(t, "invariant broken", func() {
+			log.Panic("invariant broken", "course_id", "c-1")
+		})
+		assert.Contains(t, buf.String(), "level=PANIC")
+		assert.Contains(t, buf.String(), "course_id=c-1")
+	})
+}
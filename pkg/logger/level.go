@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/marcelofabianov/config"
+)
+
+// levelRevert holds the pending restore of a temporary level change. It is
+// shared by a logger and its children, like the level itself.
+type levelRevert struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	previous slog.Level
+	// gen invalidates timers that fire after a newer change.
+	gen uint64
+}
+
+// SetLevel changes the minimum level of l and every logger derived from it
+// with With or WithGroup, without restarting. It cancels any pending revert
+// from SetLevelFor. Loggers built with NewFromSlog are unaffected.
+func (l *Logger) SetLevel(level LogLevel) {
+	if l.level == nil {
+		return
+	}
+
+	l.revert.mu.Lock()
+	defer l.revert.mu.Unlock()
+
+	l.revert.cancel()
+	l.level.Set(parseLogLevel(level))
+}
+
+// SetLevelFor changes the level for d, then restores the level set before,
+// e.g. to turn on debug logging for ten minutes while investigating.
+// Repeated calls extend the change and still restore the original level.
+func (l *Logger) SetLevelFor(level LogLevel, d time.Duration) {
+	if l.level == nil {
+		return
+	}
+
+	l.revert.mu.Lock()
+	defer l.revert.mu.Unlock()
+
+	if l.revert.timer == nil {
+		l.revert.previous = l.level.Level()
+	}
+	l.revert.cancel()
+	l.level.Set(parseLogLevel(level))
+
+	gen := l.revert.gen
+	l.revert.timer = time.AfterFunc(d, func() {
+		l.revert.mu.Lock()
+		defer l.revert.mu.Unlock()
+		if l.revert.gen == gen {
+			l.level.Set(l.revert.previous)
+			l.revert.timer = nil
+		}
+	})
+}
+
+func (r *levelRevert) cancel() {
+	r.gen++
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+// Level reports the current minimum level.
+func (l *Logger) Level() LogLevel {
+	if l.level == nil {
+		return ""
+	}
+	return fromSlogLevel(l.level.Level())
+}
+
+type levelRequest struct {
+	Level    LogLevel `json:"level"`
+	Duration string   `json:"duration,omitempty"`
+}
+
+type levelResponse struct {
+	Level LogLevel `json:"level"`
+}
+
+// LevelHandler serves the current level on GET and changes it on PUT or
+// POST with {"level": "debug"}; an optional "duration" (e.g. "10m") reverts
+// the change after that long. Mount it on an admin route that is not
+// exposed publicly.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req); err != nil {
+				writeLevelError(w, http.StatusBadRequest, "invalid JSON body")
+				return
+			}
+
+			level, ok := lookupLevel(string(req.Level))
+			if !ok {
+				writeLevelError(w, http.StatusBadRequest, "level must be debug, info, warn or error")
+				return
+			}
+
+			if req.Duration == "" {
+				l.SetLevel(level)
+			} else {
+				d, err := time.ParseDuration(req.Duration)
+				if err != nil || d <= 0 {
+					writeLevelError(w, http.StatusBadRequest, "duration must be a positive Go duration such as 10m")
+					return
+				}
+				l.SetLevelFor(level, d)
+			}
+
+			l.Info("log level changed", "level", level, "duration", req.Duration, "remote_addr", r.RemoteAddr)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			writeLevelError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelResponse{Level: l.Level()})
+	})
+}
+
+// ReloadLevelOnSIGHUP re-reads LOGGER_LEVEL (environment and .env) and
+// applies it whenever the process receives SIGHUP, until ctx is done.
+func (l *Logger) ReloadLevelOnSIGHUP(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				cfg, err := LoadConfig()
+				if err != nil {
+					l.Error("failed to reload log level", ErrAttr(err))
+					continue
+				}
+				l.SetLevel(cfg.Level)
+				l.Info("log level reloaded", "level", cfg.Level)
+			}
+		}
+	}()
+}
+
+// ReloadLevelOnChange applies the level loaded from c whenever a reload of
+// c changes it (see config.Config.Watch). Changes made with SetLevel or
+// LevelHandler stay until the config file changes the level again.
+func (l *Logger) ReloadLevelOnChange(c *config.Config) error {
+	return config.Subscribe(c, loadLevel, func(_, level LogLevel) {
+		l.SetLevel(level)
+		l.Info("log level reloaded", "level", level)
+	})
+}
+
+func loadLevel(c *config.Config) (LogLevel, error) {
+	cfg, err := LoadConfigFrom(c)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Level, nil
+}
+
+func writeLevelError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// lookupLevel is parseLevel without the fallback, for input that must be
+// rejected when invalid.
+func lookupLevel(level string) (LogLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error":
+		return LevelError, true
+	default:
+		return "", false
+	}
+}
+
+func fromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level <= slog.LevelDebug:
+		return LevelDebug
+	case level <= slog.LevelInfo:
+		return LevelInfo
+	case level <= slog.LevelWarn:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLevelTestLogger(buf *bytes.Buffer) *Logger {
+	return New(&Config{
+		Level:       LevelInfo,
+		Format:      FormatJSON,
+		Output:      buf,
+		ServiceName: "test-service",
+		Environment: "test",
+	})
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Run("altera o nível em tempo de execução", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLevelTestLogger(&buf)
+		child := log.With("component", "test")
+
+		child.Debug("hidden")
+		assert.Empty(t, buf.String())
+
+		log.SetLevel(LevelDebug)
+		child.Debug("visible")
+		assert.Contains(t, buf.String(), "visible")
+		assert.Equal(t, LevelDebug, child.Level())
+		assert.True(t, log.Enabled(context.Background(), LevelDebug))
+	})
+
+	t.Run("restaura o nível após a duração", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLevelTestLogger(&buf)
+
+		log.SetLevelFor(LevelDebug, 20*time.Millisecond)
+		log.SetLevelFor(LevelDebug, 20*time.Millisecond)
+		assert.Equal(t, LevelDebug, log.Level())
+
+		assert.Eventually(t, func() bool {
+			return log.Level() == LevelInfo
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("SetLevel cancela a restauração pendente", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLevelTestLogger(&buf)
+
+		log.SetLevelFor(LevelDebug, 10*time.Millisecond)
+		log.SetLevel(LevelWarn)
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, LevelWarn, log.Level())
+	})
+
+	t.Run("ignora loggers criados com NewFromSlog", func(t *testing.T) {
+		log := NewFromSlog(New(nil).Slog(), "svc", "test")
+		log.SetLevel(LevelDebug)
+		assert.Empty(t, log.Level())
+	})
+}
+
+func TestLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := newLevelTestLogger(&buf)
+	handler := log.LevelHandler()
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"info"}`, w.Body.String())
+
+	w = do(http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, w.Body.String())
+	assert.Equal(t, LevelDebug, log.Level())
+
+	w = do(http.MethodPost, `{"level":"warn","duration":"1h"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, LevelWarn, log.Level())
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, `{"level":"verbose"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, `{"level":"info","duration":"-1s"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, `not json`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodDelete, "").Code)
+	assert.Equal(t, LevelWarn, log.Level())
+}
+
+func TestReloadLevelOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("logger:\n  level: info\n"), 0o600))
+	t.Setenv(config.FileEnv, path)
+	t.Setenv("LOGGER_LEVEL", "")
+	os.Unsetenv("LOGGER_LEVEL")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	log := newLevelTestLogger(&buf)
+	require.NoError(t, log.ReloadLevelOnChange(cfg))
+
+	require.NoError(t, os.WriteFile(path, []byte("logger:\n  level: debug\n"), 0o600))
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, LevelDebug, log.Level())
+	assert.Contains(t, buf.String(), "log level reloaded")
+
+	// Other keys leave a level set at runtime alone.
+	log.SetLevel(LevelWarn)
+	require.NoError(t, os.WriteFile(path, []byte("logger:\n  level: debug\n  format: text\n"), 0o600))
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, LevelWarn, log.Level())
+}
@@ -1,10 +1,15 @@
 package logger
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"os"
 	"time"
+
+	"github.com/marcelofabianov/redact"
 )
 
 type LogLevel string
@@ -26,8 +31,12 @@ const (
 type Logger struct {
 	logger      *slog.Logger
 	config      *Config
+	level       *slog.LevelVar
 	serviceName string
 	environment string
+	revert      *levelRevert
+	async       *AsyncHandler
+	audit       *auditLog
 }
 
 func New(cfg *Config) *Logger {
@@ -48,29 +57,94 @@ func New(cfg *Config) *Logger {
 		cfg.Environment = "development"
 	}
 
-	level := parseLogLevel(cfg.Level)
+	level := new(slog.LevelVar)
+	level.Set(parseLogLevel(cfg.Level))
 
-	handlerOpts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: cfg.AddSource,
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-			if a.Key == slog.TimeKey {
-				if t, ok := a.Value.Any().(time.Time); ok {
-					a.Value = slog.StringValue(t.Format(cfg.TimeFormat))
-				}
+	// With level overrides the handlers accept the lowest overridden level
+	// and the levelFilter added last applies the logger or module level.
+	var handlerLevel slog.Leveler = level
+	floor, overridden := cfg.lowestOverride()
+	if overridden {
+		handlerLevel = floorLeveler{base: level, floor: floor}
+	}
+
+	// In async mode the background goroutine is the only writer, so the
+	// outputs are buffered and flushed once per batch.
+	var buffers []*bufio.Writer
+	output := func(w io.Writer) io.Writer {
+		if cfg.Async == nil {
+			return w
+		}
+		buffer := bufio.NewWriter(w)
+		buffers = append(buffers, buffer)
+		return buffer
+	}
+
+	handler := newFormatHandler(output(cfg.Output), cfg.Format, cfg.handlerOptions(cfg.Format, handlerLevel))
+	if len(cfg.Destinations) > 0 || cfg.LoggerProvider != nil {
+		handlers := []slog.Handler{handler}
+		for _, dest := range cfg.Destinations {
+			if dest.Output == nil {
+				continue
 			}
-			return a
-		},
+			destLevel := handlerLevel
+			if dest.Level != "" {
+				destLevel = parseLogLevel(dest.Level)
+			}
+			format := dest.Format
+			if format == "" {
+				format = FormatJSON
+			}
+			handlers = append(handlers, newFormatHandler(output(dest.Output), format, cfg.handlerOptions(format, destLevel)))
+		}
+		if cfg.LoggerProvider != nil {
+			otelOpts := OTelOptions{
+				ServiceName: cfg.ServiceName,
+				Environment: cfg.Environment,
+				Level:       handlerLevel,
+			}
+			if cfg.Redactor != nil {
+				otelOpts.ReplaceAttr = cfg.Redactor.ReplaceAttr
+			}
+			handlers = append(handlers, NewOTelHandler(cfg.LoggerProvider, otelOpts))
+		}
+		handler = NewTeeHandler(handlers...)
 	}
 
-	var handler slog.Handler
-	switch cfg.Format {
-	case FormatJSON:
-		handler = slog.NewJSONHandler(cfg.Output, handlerOpts)
-	case FormatText:
-		handler = slog.NewTextHandler(cfg.Output, handlerOpts)
-	default:
-		handler = slog.NewJSONHandler(cfg.Output, handlerOpts)
+	var async *AsyncHandler
+	if cfg.Async != nil {
+		opts := *cfg.Async
+		flush := opts.Flush
+		opts.Flush = func() error {
+			var errs []error
+			for _, buffer := range buffers {
+				errs = append(errs, buffer.Flush())
+			}
+			if flush != nil {
+				errs = append(errs, flush())
+			}
+			return errors.Join(errs...)
+		}
+		async = NewAsyncHandler(handler, opts)
+		handler = async
+	}
+
+	if len(cfg.SensitiveFields) > 0 {
+		mask := redact.DefaultMask
+		if cfg.Redactor != nil {
+			mask = cfg.Redactor.Mask()
+		}
+		handler = NewRedactHandler(handler, redact.New(&redact.Config{Mask: mask, Fields: cfg.SensitiveFields}))
+	}
+
+	if len(cfg.Sampling) > 0 {
+		handler = NewSamplingHandler(handler, cfg.Sampling)
+	}
+
+	handler = NewContextHandler(handler, cfg.ContextKeys...)
+
+	if overridden {
+		handler = &levelFilter{next: handler, level: level}
 	}
 
 	baseLogger := slog.New(handler)
@@ -82,11 +156,61 @@ func New(cfg *Config) *Logger {
 	return &Logger{
 		logger:      baseLogger,
 		config:      cfg,
+		level:       level,
+		revert:      &levelRevert{},
+		async:       async,
+		audit:       newAuditLog(cfg),
 		serviceName: cfg.ServiceName,
 		environment: cfg.Environment,
 	}
 }
 
+func (cfg *Config) handlerOptions(format LogFormat, level slog.Leveler) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level:     level,
+		AddSource: cfg.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// GCP severities are mapped from the slog level itself.
+			if format == FormatGCP && a.Key == slog.LevelKey {
+				return cfg.cloudAttr(format, groups, a)
+			}
+			return cfg.cloudAttr(format, groups, cfg.replaceAttr(groups, a))
+		},
+	}
+}
+
+func (cfg *Config) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			if name, ok := levelName(level); ok {
+				a.Value = slog.StringValue(name)
+			}
+		}
+		return a
+	}
+	if a.Key == slog.TimeKey {
+		if t, ok := a.Value.Any().(time.Time); ok {
+			a.Value = slog.StringValue(t.Format(cfg.TimeFormat))
+		}
+		return a
+	}
+	if cfg.Redactor != nil {
+		return cfg.Redactor.ReplaceAttr(groups, a)
+	}
+	return a
+}
+
+func newFormatHandler(w io.Writer, format LogFormat, opts *slog.HandlerOptions) slog.Handler {
+	switch format {
+	case FormatText:
+		return slog.NewTextHandler(w, opts)
+	case FormatPretty:
+		return NewPrettyHandler(w, opts)
+	default:
+		return slog.NewJSONHandler(w, opts)
+	}
+}
+
 func defaultConfig() *Config {
 	return &Config{
 		Level:       LevelInfo,
@@ -96,6 +220,7 @@ func defaultConfig() *Config {
 		Environment: "development",
 		AddSource:   false,
 		TimeFormat:  time.RFC3339,
+		Redactor:    redact.Default(),
 	}
 }
 
@@ -150,6 +275,10 @@ func (l *Logger) With(args ...any) *Logger {
 	return &Logger{
 		logger:      l.logger.With(args...),
 		config:      l.config,
+		level:       l.level,
+		revert:      l.revert,
+		async:       l.async,
+		audit:       l.audit,
 		serviceName: l.serviceName,
 		environment: l.environment,
 	}
@@ -159,6 +288,10 @@ func (l *Logger) WithGroup(name string) *Logger {
 	return &Logger{
 		logger:      l.logger.WithGroup(name),
 		config:      l.config,
+		level:       l.level,
+		revert:      l.revert,
+		async:       l.async,
+		audit:       l.audit,
 		serviceName: l.serviceName,
 		environment: l.environment,
 	}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/marcelofabianov/redact"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -182,6 +183,37 @@ func TestLogWithFields(t *testing.T) {
 	assert.Equal(t, float64(150), jsonLog["duration_ms"])
 }
 
+func TestLogRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := &Config{
+		Level:       LevelInfo,
+		Format:      FormatJSON,
+		Output:      &buf,
+		ServiceName: "test",
+		Environment: "test",
+		Redactor:    redact.Default(),
+	}
+
+	logger := New(cfg)
+	logger.WithGroup("request").Info("login attempt",
+		"email", "ana@example.com",
+		"password", "hunter2",
+		"cpf", "123.456.789-01",
+		"body", map[string]any{"access_token": "abc"},
+	)
+
+	var jsonLog map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &jsonLog)
+	require.NoError(t, err)
+
+	request := jsonLog["request"].(map[string]interface{})
+	assert.Equal(t, "ana@example.com", request["email"])
+	assert.Equal(t, redact.DefaultMask, request["password"])
+	assert.Equal(t, "***.***.**9-01", request["cpf"])
+	assert.Equal(t, redact.DefaultMask, request["body"].(map[string]interface{})["access_token"])
+	assert.Equal(t, "test", jsonLog["service"])
+}
+
 func TestLogWithContext(t *testing.T) {
 	var buf bytes.Buffer
 	cfg := &Config{
@@ -0,0 +1,239 @@
+// Package loggertest captures what a logger.Logger writes so tests can
+// assert on records instead of decoding JSON from a bytes.Buffer.
+//
+// The captured logger runs the same pipeline as production (context IDs,
+// redaction, module levels), so an assertion sees what would be shipped:
+//
+//	log, logs := loggertest.New(t)
+//	svc := NewService(log)
+//	svc.Enroll(ctx, studentID)
+//	logs.AssertLogged(t, logger.LevelInfo, "student enrolled", "student_id", studentID)
+package loggertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/logger"
+)
+
+// Entry is a captured record. Attrs holds every attribute but time, level
+// and message, with the keys of groups joined by dots ("database.query",
+// "error.code") and the values as decoded from JSON.
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Attrs   map[string]any
+}
+
+// Inspector holds the records written by the logger returned by New.
+type Inspector struct {
+	t       testing.TB
+	mu      sync.Mutex
+	pending []byte
+	entries []Entry
+}
+
+// New returns a logger at debug level whose records are captured by the
+// returned Inspector.
+func New(t testing.TB) (*logger.Logger, *Inspector) {
+	t.Helper()
+	return NewWithConfig(t, &logger.Config{Level: logger.LevelDebug})
+}
+
+// NewWithConfig is New with cfg, e.g. to test LevelOverrides or
+// SensitiveFields. Output and Format are replaced; ServiceName and
+// Environment default to "test".
+func NewWithConfig(t testing.TB, cfg *logger.Config) (*logger.Logger, *Inspector) {
+	t.Helper()
+
+	inspector := &Inspector{t: t}
+
+	c := *cfg
+	c.Output = inspector
+	c.Format = logger.FormatJSON
+	c.Destinations = nil
+	c.Async = nil
+	if c.ServiceName == "" {
+		c.ServiceName = "test"
+	}
+	if c.Environment == "" {
+		c.Environment = "test"
+	}
+	if c.TimeFormat == "" {
+		c.TimeFormat = time.RFC3339Nano
+	}
+
+	return logger.New(&c), inspector
+}
+
+// Write decodes the JSON lines written by the logger.
+func (i *Inspector) Write(p []byte) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.pending = append(i.pending, p...)
+	for {
+		end := bytes.IndexByte(i.pending, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		line := i.pending[:end]
+		i.pending = i.pending[end+1:]
+
+		entry, err := decodeEntry(line)
+		if err != nil {
+			i.t.Errorf("loggertest: decode record %s: %v", line, err)
+			continue
+		}
+		i.entries = append(i.entries, entry)
+	}
+}
+
+// Entries returns the records captured so far, in order.
+func (i *Inspector) Entries() []Entry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]Entry(nil), i.entries...)
+}
+
+// Filter returns the captured records at level with message msg.
+func (i *Inspector) Filter(level logger.LogLevel, msg string) []Entry {
+	var matched []Entry
+	for _, e := range i.Entries() {
+		if strings.EqualFold(e.Level, string(level)) && e.Message == msg {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Reset discards the captured records.
+func (i *Inspector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries = nil
+}
+
+// AssertLogged fails the test unless a record at level with message msg
+// has the attributes in attrs, given as key-value pairs or slog.Attr like
+// the logging calls. Other attributes of the record are ignored.
+func (i *Inspector) AssertLogged(t testing.TB, level logger.LogLevel, msg string, attrs ...any) bool {
+	t.Helper()
+
+	want, err := expectedAttrs(attrs)
+	if err != nil {
+		t.Errorf("loggertest: %v", err)
+		return false
+	}
+
+	candidates := i.Filter(level, msg)
+	for _, e := range candidates {
+		if hasAttrs(e, want) {
+			return true
+		}
+	}
+
+	if len(candidates) == 0 {
+		t.Errorf("loggertest: no %s record %q; logged:\n%s", level, msg, i.dump())
+		return false
+	}
+	t.Errorf("loggertest: %s record %q has no attributes %v; found:\n%s", level, msg, want, dump(candidates))
+	return false
+}
+
+// AssertNotLogged fails the test if a record at level with message msg was
+// captured.
+func (i *Inspector) AssertNotLogged(t testing.TB, level logger.LogLevel, msg string) bool {
+	t.Helper()
+	if matched := i.Filter(level, msg); len(matched) > 0 {
+		t.Errorf("loggertest: unexpected %s record %q:\n%s", level, msg, dump(matched))
+		return false
+	}
+	return true
+}
+
+func (i *Inspector) dump() string {
+	return dump(i.Entries())
+}
+
+func dump(entries []Entry) string {
+	if len(entries) == 0 {
+		return "  (none)"
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "  %s %q %v\n", e.Level, e.Message, e.Attrs)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func decodeEntry(line []byte) (Entry, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Attrs: make(map[string]any)}
+	if s, ok := raw[slog.TimeKey].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	entry.Level, _ = raw[slog.LevelKey].(string)
+	entry.Message, _ = raw[slog.MessageKey].(string)
+	delete(raw, slog.TimeKey)
+	delete(raw, slog.LevelKey)
+	delete(raw, slog.MessageKey)
+
+	flatten(entry.Attrs, "", raw)
+	return entry, nil
+}
+
+func flatten(dst map[string]any, prefix string, src map[string]any) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if group, ok := v.(map[string]any); ok {
+			flatten(dst, key, group)
+			continue
+		}
+		dst[key] = v
+	}
+}
+
+// expectedAttrs turns the key-value pairs into the form decoded from JSON,
+// so 42 matches the float64 the record holds.
+func expectedAttrs(args []any) (map[string]any, error) {
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)
+	r.Add(args...)
+
+	var buf bytes.Buffer
+	if err := slog.NewJSONHandler(&buf, nil).Handle(context.Background(), r); err != nil {
+		return nil, fmt.Errorf("encode expected attributes: %w", err)
+	}
+	entry, err := decodeEntry(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("decode expected attributes: %w", err)
+	}
+	return entry.Attrs, nil
+}
+
+func hasAttrs(e Entry, want map[string]any) bool {
+	for k, v := range want {
+		got, ok := e.Attrs[k]
+		if !ok || !reflect.DeepEqual(got, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package loggertest_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/logger"
+	"github.com/marcelofabianov/logger/loggertest"
+)
+
+// recorder captures the failures of the assertions under test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestInspector(t *testing.T) {
+	t.Run("captura os registros com atributos achatados", func(t *testing.T) {
+		log, logs := loggertest.New(t)
+
+		log.WithGroup("database").Debug("query executed", "table", "courses", "duration_ms", 12)
+		log.Warn("slow request")
+
+		entries := logs.Entries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "DEBUG", entries[0].Level)
+		assert.Equal(t, "query executed", entries[0].Message)
+		assert.Equal(t, "courses", entries[0].Attrs["database.table"])
+		assert.Equal(t, float64(12), entries[0].Attrs["database.duration_ms"])
+		assert.Equal(t, "test", entries[1].Attrs["service"])
+		assert.False(t, entries[1].Time.IsZero())
+	})
+
+	t.Run("AssertLogged compara nivel, mensagem e atributos", func(t *testing.T) {
+		log, logs := loggertest.New(t)
+		log.Info("student enrolled", "student_id", 42, "course", "go-101")
+
+		assert.True(t, logs.AssertLogged(t, logger.LevelInfo, "student enrolled", "student_id", 42))
+		assert.True(t, logs.AssertLogged(t, logger.LevelInfo, "student enrolled", slog.String("course", "go-101")))
+
+		rec := &recorder{TB: t}
+		assert.False(t, logs.AssertLogged(rec, logger.LevelInfo, "student enrolled", "student_id", 7))
+		assert.False(t, logs.AssertLogged(rec, logger.LevelWarn, "student enrolled"))
+		assert.Len(t, rec.failures, 2)
+	})
+
+	t.Run("AssertNotLogged e Reset", func(t *testing.T) {
+		log, logs := loggertest.New(t)
+		log.Error("payment failed")
+
+		rec := &recorder{TB: t}
+		assert.False(t, logs.AssertNotLogged(rec, logger.LevelError, "payment failed"))
+
+		logs.Reset()
+		assert.Empty(t, logs.Entries())
+		assert.True(t, logs.AssertNotLogged(t, logger.LevelError, "payment failed"))
+	})
+
+	t.Run("usa o pipeline do logger", func(t *testing.T) {
+		log, logs := loggertest.NewWithConfig(t, &logger.Config{
+			Level:           logger.LevelInfo,
+			SensitiveFields: []string{"pix_key"},
+			LevelOverrides:  map[string]logger.LogLevel{"cache": logger.LevelDebug},
+		})
+
+		log.Debug("root debug")
+		log.ForModule("cache").Debug("cache miss")
+		log.Info("pix sent", "pix_key", "maria@example.com")
+		log.WithError(errors.New("timeout")).Error("charge failed")
+
+		logs.AssertNotLogged(t, logger.LevelDebug, "root debug")
+		logs.AssertLogged(t, logger.LevelDebug, "cache miss", "module", "cache")
+		entry := logs.Filter(logger.LevelInfo, "pix sent")
+		require.Len(t, entry, 1)
+		assert.NotEqual(t, "maria@example.com", entry[0].Attrs["pix_key"])
+		logs.AssertLogged(t, logger.LevelError, "charge failed", "error.message", "timeout")
+	})
+}
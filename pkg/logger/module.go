@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ModuleKey is the attribute ForModule adds to every record.
+const ModuleKey = "module"
+
+// ForModule returns a child logger for a subsystem, tagged with
+// module=name. When Config.LevelOverrides (LOGGER_LEVEL_OVERRIDES) sets a
+// level for name, the child logs at that level instead of the logger's, so
+// one subsystem can be made verbose, or quiet, on its own:
+//
+//	cacheLog := log.ForModule("cache") // LOGGER_LEVEL_OVERRIDES=cache=debug
+func (l *Logger) ForModule(name string) *Logger {
+	child := l.With(slog.String(ModuleKey, name))
+	if l.config == nil {
+		return child
+	}
+
+	override, ok := l.config.LevelOverrides[name]
+	if !ok {
+		return child
+	}
+	if filter, ok := child.logger.Handler().(*levelFilter); ok {
+		child.logger = slog.New(&levelFilter{next: filter.next, level: parseLogLevel(override)})
+	}
+	return child
+}
+
+// levelFilter is the outermost handler when there are level overrides: the
+// handlers below it accept the lowest overridden level, and levelFilter
+// applies the level of the logger, or of its module.
+type levelFilter struct {
+	next  slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelFilter) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.next.Enabled(ctx, level)
+}
+
+func (h *levelFilter) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelFilter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelFilter{next: h.next.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelFilter) WithGroup(name string) slog.Handler {
+	return &levelFilter{next: h.next.WithGroup(name), level: h.level}
+}
+
+// floorLeveler is base, lowered to floor when floor is below it.
+type floorLeveler struct {
+	base  slog.Leveler
+	floor slog.Level
+}
+
+func (l floorLeveler) Level() slog.Level {
+	return min(l.base.Level(), l.floor)
+}
+
+// lowestOverride returns the lowest level in LevelOverrides.
+func (cfg *Config) lowestOverride() (slog.Level, bool) {
+	if len(cfg.LevelOverrides) == 0 {
+		return 0, false
+	}
+	lowest := levelFatal
+	for _, level := range cfg.LevelOverrides {
+		lowest = min(lowest, parseLogLevel(level))
+	}
+	return lowest, true
+}
+
+// parseLevelOverrides parses "cache=debug,database=warn".
+func parseLevelOverrides(spec string) (map[string]LogLevel, error) {
+	overrides := make(map[string]LogLevel)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		module, level, ok := strings.Cut(entry, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid level override %q: expected module=level", entry)
+		}
+		parsed, ok := lookupLevel(strings.TrimSpace(level))
+		if !ok {
+			return nil, fmt.Errorf("invalid level override %q: unknown level", entry)
+		}
+
+		overrides[module] = parsed
+	}
+
+	return overrides, nil
+}
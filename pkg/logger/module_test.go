@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForModule(t *testing.T) {
+	t.Run("aplica o nivel do modulo sem alterar os demais", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Level:          LevelInfo,
+			Output:         &buf,
+			LevelOverrides: map[string]LogLevel{"cache": LevelDebug, "database": LevelWarn},
+		})
+
+		log.Debug("root debug")
+		log.ForModule("cache").Debug("cache debug")
+		log.ForModule("database").Info("database info")
+		log.ForModule("database").Warn("database warn")
+		log.ForModule("http").Info("http info")
+
+		out := buf.String()
+		assert.NotContains(t, out, "root debug")
+		assert.Contains(t, out, "cache debug")
+		assert.NotContains(t, out, "database info")
+		assert.Contains(t, out, "database warn")
+		assert.Contains(t, out, "http info")
+		assert.Contains(t, out, `"module":"cache"`)
+	})
+
+	t.Run("mantem o nivel do modulo em loggers filhos", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Output:         &buf,
+			LevelOverrides: map[string]LogLevel{"cache": LevelDebug},
+		})
+
+		log.ForModule("cache").With("key", "course:1").WithGroup("redis").Debug("cache miss")
+		log.With("key", "course:1").Debug("root debug")
+
+		assert.Contains(t, buf.String(), "cache miss")
+		assert.NotContains(t, buf.String(), "root debug")
+	})
+
+	t.Run("segue mudancas de nivel em tempo de execucao", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Output:         &buf,
+			LevelOverrides: map[string]LogLevel{"cache": LevelDebug},
+		})
+
+		log.SetLevel(LevelDebug)
+		log.ForModule("http").Debug("http debug")
+
+		assert.Contains(t, buf.String(), "http debug")
+	})
+
+	t.Run("sem override apenas adiciona o modulo", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf})
+
+		log.ForModule("cache").Info("cache hit")
+		log.ForModule("cache").Debug("cache debug")
+
+		assert.Contains(t, buf.String(), `"module":"cache"`)
+		assert.NotContains(t, buf.String(), "cache debug")
+	})
+}
+
+func TestParseLevelOverrides(t *testing.T) {
+	t.Run("interpreta modulo=nivel separados por virgula", func(t *testing.T) {
+		overrides, err := parseLevelOverrides(" cache=debug, database=WARN ,")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]LogLevel{"cache": LevelDebug, "database": LevelWarn}, overrides)
+	})
+
+	t.Run("rejeita entradas invalidas", func(t *testing.T) {
+		for _, spec := range []string{"cache", "=debug", "cache=verbose"} {
+			_, err := parseLevelOverrides(spec)
+			require.Error(t, err, spec)
+			assert.True(t, strings.Contains(err.Error(), "invalid level override"), spec)
+		}
+	})
+
+	t.Run("carrega LOGGER_LEVEL_OVERRIDES", func(t *testing.T) {
+		t.Setenv("LOGGER_LEVEL_OVERRIDES", "cache=debug")
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]LogLevel{"cache": LevelDebug}, cfg.LevelOverrides)
+	})
+}
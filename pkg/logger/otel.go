@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelScope is the instrumentation scope name of records exported by
+// OTelHandler.
+const otelScope = "github.com/marcelofabianov/logger"
+
+// OTelOptions configures an OTelHandler.
+type OTelOptions struct {
+	// ServiceName and Environment are set as the service.name and
+	// deployment.environment attributes of the instrumentation scope.
+	ServiceName string
+	Environment string
+	// Level is the minimum level exported. Nil exports every level the
+	// LoggerProvider accepts.
+	Level slog.Leveler
+	// ReplaceAttr rewrites each attribute before it is exported, e.g.
+	// Redactor.ReplaceAttr. Nil exports attributes as is.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+}
+
+// OTelHandler bridges slog records to an OpenTelemetry LoggerProvider, so
+// logs are exported over OTLP to the same backend as traces. The trace and
+// span IDs of the record come from the span in the context or, when there
+// is none, from WithTrace.
+//
+// The provider (and its OTLP exporter) is built and shut down by the
+// service; the handler only emits into it.
+type OTelHandler struct {
+	logger otellog.Logger
+	opts   OTelOptions
+	goas   []groupOrAttrs
+}
+
+// groupOrAttrs is a WithGroup or WithAttrs call, kept in order so attributes
+// nest under the groups opened before them.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewOTelHandler(provider otellog.LoggerProvider, opts OTelOptions) *OTelHandler {
+	var scopeAttrs []attribute.KeyValue
+	if opts.ServiceName != "" {
+		scopeAttrs = append(scopeAttrs, attribute.String("service.name", opts.ServiceName))
+	}
+	if opts.Environment != "" {
+		scopeAttrs = append(scopeAttrs, attribute.String("deployment.environment", opts.Environment))
+	}
+
+	return &OTelHandler{
+		logger: provider.Logger(otelScope, otellog.WithInstrumentationAttributes(scopeAttrs...)),
+		opts:   opts,
+	}
+}
+
+func (h *OTelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.opts.Level != nil && level < h.opts.Level.Level() {
+		return false
+	}
+	return h.logger.Enabled(ctx, otellog.EnabledParameters{Severity: otelSeverity(level)})
+}
+
+func (h *OTelHandler) Handle(ctx context.Context, r slog.Record) error {
+	var record otellog.Record
+	record.SetTimestamp(r.Time)
+	record.SetSeverity(otelSeverity(r.Level))
+	if name, ok := levelName(r.Level); ok {
+		record.SetSeverityText(name)
+	} else {
+		record.SetSeverityText(r.Level.String())
+	}
+	record.SetBody(otellog.StringValue(r.Message))
+
+	groups := h.groups()
+	attrs := make([]otellog.KeyValue, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = h.appendAttr(attrs, groups, a)
+		return true
+	})
+
+	// Nest the record attributes under the open groups, innermost first,
+	// interleaving the attributes added with WithAttrs at each level.
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group != "" {
+			groups = groups[:len(groups)-1]
+			if len(attrs) > 0 {
+				attrs = []otellog.KeyValue{otellog.Map(goa.group, attrs...)}
+			}
+			continue
+		}
+		var prefix []otellog.KeyValue
+		for _, a := range goa.attrs {
+			prefix = h.appendAttr(prefix, groups, a)
+		}
+		attrs = append(prefix, attrs...)
+	}
+	record.AddAttributes(attrs...)
+
+	h.logger.Emit(otelTraceContext(ctx), record)
+	return nil
+}
+
+func (h *OTelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *OTelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *OTelHandler) with(goa groupOrAttrs) *OTelHandler {
+	clone := *h
+	clone.goas = append(append([]groupOrAttrs(nil), h.goas...), goa)
+	return &clone
+}
+
+func (h *OTelHandler) groups() []string {
+	var groups []string
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			groups = append(groups, goa.group)
+		}
+	}
+	return groups
+}
+
+func (h *OTelHandler) appendAttr(kvs []otellog.KeyValue, groups []string, a slog.Attr) []otellog.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup && h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return kvs
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		var members []otellog.KeyValue
+		for _, member := range a.Value.Group() {
+			members = h.appendAttr(members, append(groups[:len(groups):len(groups)], a.Key), member)
+		}
+		if len(members) == 0 {
+			return kvs
+		}
+		if a.Key == "" {
+			return append(kvs, members...)
+		}
+		return append(kvs, otellog.Map(a.Key, members...))
+	}
+
+	return append(kvs, otellog.KeyValue{Key: a.Key, Value: otelValue(a.Value)})
+}
+
+func otelValue(v slog.Value) otellog.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return otellog.StringValue(v.String())
+	case slog.KindInt64:
+		return otellog.Int64Value(v.Int64())
+	case slog.KindUint64:
+		if n := v.Uint64(); n <= math.MaxInt64 {
+			return otellog.Int64Value(int64(n))
+		}
+		return otellog.StringValue(v.String())
+	case slog.KindFloat64:
+		return otellog.Float64Value(v.Float64())
+	case slog.KindBool:
+		return otellog.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return otellog.StringValue(v.Duration().String())
+	case slog.KindTime:
+		return otellog.StringValue(v.Time().Format(time.RFC3339Nano))
+	}
+
+	switch value := v.Any().(type) {
+	case error:
+		return otellog.StringValue(value.Error())
+	case []byte:
+		return otellog.BytesValue(value)
+	case fmt.Stringer:
+		return otellog.StringValue(value.String())
+	default:
+		return otellog.StringValue(fmt.Sprintf("%+v", value))
+	}
+}
+
+// otelSeverity maps slog levels onto the OpenTelemetry severity numbers:
+// Debug is DEBUG (5), Info is INFO (9), Warn is WARN (13), Error is ERROR
+// (17), and levels in between land on DEBUG2, INFO3, etc.
+func otelSeverity(level slog.Level) otellog.Severity {
+	severity := int(level) + int(otellog.SeverityInfo1)
+	return otellog.Severity(min(max(severity, int(otellog.SeverityTrace1)), int(otellog.SeverityFatal4)))
+}
+
+// otelTraceContext returns ctx carrying the span context set with WithTrace
+// when ctx has no OpenTelemetry span, so the exported record is still
+// correlated with its trace.
+func otelTraceContext(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	traceID, err := trace.TraceIDFromHex(TraceIDFromContext(ctx))
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(SpanIDFromContext(ctx))
+	if err != nil {
+		return ctx
+	}
+
+	return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/marcelofabianov/redact"
+)
+
+type emitted struct {
+	record      otellog.Record
+	spanContext trace.SpanContext
+}
+
+type fakeOTelProvider struct {
+	embedded.LoggerProvider
+
+	mu      sync.Mutex
+	scope   otellog.LoggerConfig
+	records []emitted
+}
+
+type fakeOTelLogger struct {
+	embedded.Logger
+	provider *fakeOTelProvider
+}
+
+func (p *fakeOTelProvider) Logger(name string, opts ...otellog.LoggerOption) otellog.Logger {
+	p.scope = otellog.NewLoggerConfig(opts...)
+	return &fakeOTelLogger{provider: p}
+}
+
+func (l *fakeOTelLogger) Emit(ctx context.Context, r otellog.Record) {
+	l.provider.mu.Lock()
+	defer l.provider.mu.Unlock()
+	l.provider.records = append(l.provider.records, emitted{record: r.Clone(), spanContext: trace.SpanContextFromContext(ctx)})
+}
+
+func (l *fakeOTelLogger) Enabled(context.Context, otellog.EnabledParameters) bool {
+	return true
+}
+
+func (p *fakeOTelProvider) last(t *testing.T) emitted {
+	t.Helper()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	require.NotEmpty(t, p.records)
+	return p.records[len(p.records)-1]
+}
+
+func otelAttrs(r *otellog.Record) map[string]otellog.Value {
+	attrs := make(map[string]otellog.Value)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestOTelHandler(t *testing.T) {
+	t.Run("exporta registros com serviço, ambiente e atributos", func(t *testing.T) {
+		var stdout bytes.Buffer
+		provider := &fakeOTelProvider{}
+		log := New(&Config{
+			Level:          LevelInfo,
+			Output:         &stdout,
+			ServiceName:    "course",
+			Environment:    "production",
+			Redactor:       redact.Default(),
+			LoggerProvider: provider,
+		})
+
+		log.WithGroup("db").Warn("slow query", "duration_ms", 120, "password", "s3cret")
+
+		assert.Contains(t, stdout.String(), "slow query")
+		last := provider.last(t)
+		got := &last.record
+		assert.Equal(t, "slow query", got.Body().AsString())
+		assert.Equal(t, otellog.SeverityWarn1, got.Severity())
+		assert.Equal(t, "WARN", got.SeverityText())
+
+		attrs := otelAttrs(got)
+		assert.Equal(t, "course", attrs["service"].AsString())
+		assert.Equal(t, "production", attrs["environment"].AsString())
+
+		db := make(map[string]otellog.Value)
+		for _, kv := range attrs["db"].AsMap() {
+			db[kv.Key] = kv.Value
+		}
+		assert.Equal(t, int64(120), db["duration_ms"].AsInt64())
+		assert.NotEqual(t, "s3cret", db["password"].AsString())
+
+		scope := provider.scope.InstrumentationAttributes()
+		serviceName, _ := scope.Value("service.name")
+		environment, _ := scope.Value("deployment.environment")
+		assert.Equal(t, "course", serviceName.AsString())
+		assert.Equal(t, "production", environment.AsString())
+	})
+
+	t.Run("respeita o nível do logger", func(t *testing.T) {
+		provider := &fakeOTelProvider{}
+		log := New(&Config{Level: LevelWarn, Output: &bytes.Buffer{}, LoggerProvider: provider})
+
+		log.Info("ignored")
+		assert.Empty(t, provider.records)
+
+		log.SetLevel(LevelDebug)
+		log.Debug("cache miss")
+		last := provider.last(t)
+		assert.Equal(t, otellog.SeverityDebug1, last.record.Severity())
+	})
+
+	t.Run("correlaciona com o trace de WithTrace", func(t *testing.T) {
+		provider := &fakeOTelProvider{}
+		log := New(&Config{Output: &bytes.Buffer{}, LoggerProvider: provider})
+
+		ctx := WithTrace(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+		log.InfoContext(ctx, "request processed")
+
+		sc := provider.last(t).spanContext
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+	})
+
+	t.Run("mantém o span do contexto", func(t *testing.T) {
+		provider := &fakeOTelProvider{}
+		handler := NewOTelHandler(provider, OTelOptions{})
+
+		traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+		spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		ctx = WithTrace(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+		require.NoError(t, handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "request processed", 0)))
+		assert.Equal(t, traceID, provider.last(t).spanContext.TraceID())
+	})
+}
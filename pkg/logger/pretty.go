@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatPretty is a colorized console format for local development, picked
+// by LoadConfig when the environment is development.
+const FormatPretty LogFormat = "pretty"
+
+// prettyMessageWidth pads messages so the attributes of consecutive lines
+// start in the same column.
+const prettyMessageWidth = 40
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// PrettyHandler writes one human-friendly line per record:
+//
+//	15:04:05.000 WRN slow query                               component=db duration_ms=120
+//
+// The error attribute is rendered below the line, one field per line, with
+// the fault chain and stack expanded (see ErrorValue). Colors are used when
+// writing to a terminal, unless NO_COLOR is set.
+type PrettyHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	opts  slog.HandlerOptions
+	color bool
+
+	attrs  []prettyField
+	groups []string
+}
+
+type prettyField struct {
+	key   string
+	value slog.Value
+}
+
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) *PrettyHandler {
+	h := &PrettyHandler{w: w, mu: &sync.Mutex{}, color: isTerminal(w)}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func isTerminal(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := append([]prettyField(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = h.appendField(fields, h.groups, a)
+		return true
+	})
+
+	var buf bytes.Buffer
+	h.paint(&buf, ansiDim, r.Time.Format("15:04:05.000"))
+	buf.WriteByte(' ')
+	h.paint(&buf, prettyLevelColor(r.Level), prettyLevel(r.Level))
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+
+	var errs []prettyField
+	inline := 0
+	for _, f := range fields {
+		if f.key == ErrorKey || strings.HasPrefix(f.key, ErrorKey+".") {
+			errs = append(errs, f)
+			continue
+		}
+		if inline == 0 {
+			if pad := prettyMessageWidth - len(r.Message); pad > 0 {
+				buf.WriteString(strings.Repeat(" ", pad))
+			}
+		}
+		inline++
+		buf.WriteByte(' ')
+		h.paint(&buf, ansiCyan, f.key+"=")
+		buf.WriteString(prettyValue(f.value))
+	}
+
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		buf.WriteByte(' ')
+		h.paint(&buf, ansiDim, fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line))
+	}
+	buf.WriteByte('\n')
+
+	for _, f := range errs {
+		buf.WriteString("    ")
+		h.paint(&buf, ansiRed, f.key+":")
+		if lines, ok := f.value.Any().([]string); ok {
+			buf.WriteByte('\n')
+			for _, line := range lines {
+				buf.WriteString("        ")
+				buf.WriteString(line)
+				buf.WriteByte('\n')
+			}
+			continue
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(f.value.String())
+		buf.WriteByte('\n')
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]prettyField(nil), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = clone.appendField(clone.attrs, h.groups, a)
+	}
+	return &clone
+}
+
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// appendField flattens a into dotted keys ("db.duration_ms"), applying
+// ReplaceAttr to each leaf.
+func (h *PrettyHandler) appendField(fields []prettyField, groups []string, a slog.Attr) []prettyField {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, member := range a.Value.Group() {
+			fields = h.appendField(fields, groups, member)
+		}
+		return fields
+	}
+
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+	return append(fields, prettyField{key: key, value: a.Value})
+}
+
+func (h *PrettyHandler) paint(buf *bytes.Buffer, color, s string) {
+	if !h.color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(ansiReset)
+}
+
+func prettyLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DBG"
+	case level < slog.LevelWarn:
+		return "INF"
+	case level < slog.LevelError:
+		return "WRN"
+	case level < levelPanic:
+		return "ERR"
+	case level < levelFatal:
+		return "PNC"
+	default:
+		return "FTL"
+	}
+}
+
+func prettyLevelColor(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return ansiDim
+	case level < slog.LevelWarn:
+		return ansiGreen
+	case level < slog.LevelError:
+		return ansiYellow
+	case level < levelPanic:
+		return ansiRed
+	default:
+		return ansiMagenta
+	}
+}
+
+func prettyValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindTime:
+		s = v.Time().Format(time.RFC3339)
+	default:
+		s = v.String()
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyHandler(t *testing.T) {
+	t.Run("escreve uma linha legível com atributos alinhados", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, Format: FormatPretty, ServiceName: "course"})
+
+		log.WithGroup("db").Warn("slow query", "duration_ms", 120, "query", "SELECT 1")
+
+		line := buf.String()
+		assert.Contains(t, line, " WRN slow query")
+		assert.Contains(t, line, "service=course")
+		assert.Contains(t, line, "db.duration_ms=120")
+		assert.Contains(t, line, `db.query="SELECT 1"`)
+		assert.NotContains(t, line, "\x1b[", "no colors outside a terminal")
+
+		msgEnd := strings.Index(line, "slow query") + len("slow query")
+		attrStart := strings.Index(line, " service=")
+		assert.Equal(t, prettyMessageWidth-len("slow query"), attrStart-msgEnd)
+	})
+
+	t.Run("renderiza erros em várias linhas", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{Output: &buf, Format: FormatPretty, ErrorStack: true})
+
+		err := fault.Wrap(errNotFound, "find course", fault.WithContext("course_id", "c-1"))
+		log.WithError(err).Error("failed to enroll student", "student_id", "s-1")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Greater(t, len(lines), 4)
+		assert.Contains(t, lines[0], "ERR failed to enroll student")
+		assert.Contains(t, lines[0], "student_id=s-1")
+		assert.NotContains(t, lines[0], "course not found")
+		assert.Contains(t, buf.String(), "    error.message: find course: course not found\n")
+		assert.Contains(t, buf.String(), "    error.code: not_found\n")
+		assert.Contains(t, buf.String(), "    error.context.course_id: c-1\n")
+		assert.Contains(t, buf.String(), "    error.stack:\n        ")
+	})
+
+	t.Run("mascara atributos sensíveis", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := defaultConfig()
+		cfg.Output = &buf
+		cfg.Format = FormatPretty
+		New(cfg).Info("login", "password", "s3cret")
+
+		assert.NotContains(t, buf.String(), "s3cret")
+	})
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/marcelofabianov/redact"
+)
+
+// RedactHandler masks sensitive attributes before they reach next, for
+// handlers that take no slog.HandlerOptions (third-party or OpenTelemetry
+// handlers, loggers built with NewFromSlog). Keys are matched by the
+// Redactor rules, including attributes nested in groups and those added
+// with With; string values and the message are also checked against its
+// patterns (CPF, card numbers, bearer tokens).
+type RedactHandler struct {
+	next     slog.Handler
+	redactor *redact.Redactor
+}
+
+func NewRedactHandler(next slog.Handler, redactor *redact.Redactor) *RedactHandler {
+	if redactor == nil {
+		redactor = redact.Default()
+	}
+	return &RedactHandler{next: next, redactor: redactor}
+}
+
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redactor.String(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(nil, a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(nil, a)
+	}
+	return &RedactHandler{next: h.next.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{next: h.next.WithGroup(name), redactor: h.redactor}
+}
+
+func (h *RedactHandler) redact(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return h.redactor.ReplaceAttr(groups, a)
+	}
+	if h.redactor.IsSensitive(a.Key) {
+		return slog.String(a.Key, h.redactor.Mask())
+	}
+
+	members := a.Value.Group()
+	redacted := make([]slog.Attr, len(members))
+	for i, member := range members {
+		redacted[i] = h.redact(append(groups[:len(groups):len(groups)], a.Key), member)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/redact"
+)
+
+func TestRedactHandler(t *testing.T) {
+	t.Run("mascara atributos sensíveis em qualquer handler", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil), redact.Default()))
+
+		log.With("api_key", "k-123").
+			WithGroup("user").
+			Info("login failed for 123.456.789-01",
+				"password", "s3cret",
+				slog.Group("card", "card_number", "4111111111111111"),
+				"email", "ana@example.com",
+			)
+
+		out := buf.String()
+		assert.NotContains(t, out, "k-123")
+		assert.NotContains(t, out, "s3cret")
+		assert.NotContains(t, out, "4111111111111111")
+		assert.NotContains(t, out, "123.456.789-01")
+		assert.Contains(t, out, `"email":"ana@example.com"`)
+		assert.Contains(t, out, redact.DefaultMask)
+	})
+
+	t.Run("SensitiveFields adiciona chaves ao logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Output:          &buf,
+			Redactor:        redact.Default(),
+			SensitiveFields: []string{"pix_key"},
+		})
+
+		log.With("pix_key", "ana@example.com").Info("payment created", "password", "s3cret", "amount", 100)
+
+		out := buf.String()
+		assert.NotContains(t, out, "ana@example.com")
+		assert.NotContains(t, out, "s3cret")
+		assert.Contains(t, out, `"amount":100`)
+	})
+}
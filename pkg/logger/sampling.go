@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sampling limits how many identical records are written. Within each Tick
+// the first First records with the same level and message are written, then
+// one in every Thereafter; the rest are dropped.
+type Sampling struct {
+	First int
+	// Thereafter of zero drops every record after First until the next tick.
+	Thereafter int
+	// Tick defaults to one second.
+	Tick time.Duration
+}
+
+// SamplingHandler drops repetitive records, so a hot error path logs a
+// handful of lines per second instead of millions of identical ones. Rules
+// are per level; levels without a rule are never sampled.
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+func NewSamplingHandler(next slog.Handler, rules map[LogLevel]Sampling) *SamplingHandler {
+	s := &sampler{
+		rules:  make(map[slog.Level]Sampling, len(rules)),
+		counts: make(map[sampleKey]int),
+		resets: make(map[slog.Level]time.Time),
+		now:    time.Now,
+	}
+	for level, rule := range rules {
+		if rule.Tick <= 0 {
+			rule.Tick = time.Second
+		}
+		s.rules[parseLogLevel(level)] = rule
+	}
+	return &SamplingHandler{next: next, sampler: s}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.allow(r.Level, r.Message) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+// sampler counts records per level and message within the current tick. It
+// is shared by the handlers derived with WithAttrs and WithGroup, so child
+// loggers count towards the same budget.
+type sampler struct {
+	rules map[slog.Level]Sampling
+	now   func() time.Time
+
+	mu     sync.Mutex
+	counts map[sampleKey]int
+	resets map[slog.Level]time.Time
+}
+
+func (s *sampler) allow(level slog.Level, message string) bool {
+	rule, ok := s.rules[level]
+	if !ok {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Counters of a level restart together at the end of its tick, which
+	// also keeps the map from growing with messages no longer logged.
+	now := s.now()
+	if reset, ok := s.resets[level]; !ok || !now.Before(reset) {
+		for key := range s.counts {
+			if key.level == level {
+				delete(s.counts, key)
+			}
+		}
+		s.resets[level] = now.Add(rule.Tick)
+	}
+
+	key := sampleKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= rule.First {
+		return true
+	}
+	return rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampling(t *testing.T) {
+	t.Run("escreve os primeiros N e depois um a cada M", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Level:    LevelInfo,
+			Output:   &buf,
+			Sampling: map[LogLevel]Sampling{LevelError: {First: 3, Thereafter: 5}},
+		})
+
+		for i := 0; i < 20; i++ {
+			log.Error("payment gateway unavailable")
+		}
+		for i := 0; i < 4; i++ {
+			log.Info("request processed")
+		}
+
+		// The first 3, then the 5th, 10th and 15th of the 17 that follow.
+		assert.Equal(t, 6, strings.Count(buf.String(), "payment gateway unavailable"))
+		assert.Equal(t, 4, strings.Count(buf.String(), "request processed"))
+	})
+
+	t.Run("conta por mensagem e compartilha entre loggers filhos", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&Config{
+			Output:   &buf,
+			Sampling: map[LogLevel]Sampling{LevelInfo: {First: 1}},
+		})
+
+		log.Info("cache miss")
+		log.With("key", "course:1").Info("cache miss")
+		log.Info("cache hit")
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "cache miss"))
+		assert.Equal(t, 1, strings.Count(buf.String(), "cache hit"))
+	})
+
+	t.Run("reinicia a contagem a cada tick", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := NewSamplingHandler(newFormatHandler(&buf, FormatJSON, nil),
+			map[LogLevel]Sampling{LevelWarn: {First: 1, Tick: time.Minute}})
+
+		now := time.Now()
+		handler.sampler.now = func() time.Time { return now }
+
+		slogger := slog.New(handler)
+
+		slogger.Warn("slow query")
+		slogger.Warn("slow query")
+		now = now.Add(time.Minute)
+		slogger.Warn("slow query")
+
+		assert.Equal(t, 2, strings.Count(buf.String(), "slow query"))
+	})
+
+	t.Run("LOGGER_SAMPLING_FIRST aplica a todos os níveis", func(t *testing.T) {
+		t.Setenv("LOGGER_SAMPLING_FIRST", "100")
+		t.Setenv("LOGGER_SAMPLING_THEREAFTER", "10")
+
+		cfg, err := LoadConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, Sampling{First: 100, Thereafter: 10}, cfg.Sampling[LevelError])
+		assert.Equal(t, Sampling{First: 100, Thereafter: 10}, cfg.Sampling[LevelDebug])
+	})
+}
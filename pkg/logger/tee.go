@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// Destination is an extra output of a Logger, next to Config.Output, e.g.
+// a file or a network sink.
+type Destination struct {
+	Output io.Writer
+	// Format defaults to FormatJSON.
+	Format LogFormat
+	// Level is the minimum level written to this destination. Empty follows
+	// the logger level, including runtime changes made with SetLevel.
+	Level LogLevel
+}
+
+// TeeHandler fans each record out to every handler that accepts its level,
+// so one Logger can write to several destinations, each with its own level
+// and format.
+type TeeHandler struct {
+	handlers []slog.Handler
+}
+
+func NewTeeHandler(handlers ...slog.Handler) *TeeHandler {
+	return &TeeHandler{handlers: handlers}
+}
+
+func (h *TeeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle writes r to every enabled handler, even when one fails, and
+// returns the joined errors.
+func (h *TeeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *TeeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &TeeHandler{handlers: handlers}
+}
+
+func (h *TeeHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &TeeHandler{handlers: handlers}
+}
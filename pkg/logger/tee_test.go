@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinations(t *testing.T) {
+	t.Run("escreve em todos os destinos com níveis próprios", func(t *testing.T) {
+		var stdout, errorsOnly, followsLogger bytes.Buffer
+		log := New(&Config{
+			Level:       LevelInfo,
+			Format:      FormatText,
+			Output:      &stdout,
+			ServiceName: "test-service",
+			Destinations: []Destination{
+				{Output: &errorsOnly, Level: LevelError},
+				{Output: &followsLogger},
+			},
+		})
+
+		log.Info("request processed")
+		log.Error("request failed")
+
+		assert.Contains(t, stdout.String(), "msg=\"request processed\"")
+		assert.Contains(t, stdout.String(), "msg=\"request failed\"")
+		assert.NotContains(t, errorsOnly.String(), "request processed")
+		assert.Contains(t, errorsOnly.String(), `"msg":"request failed"`)
+		assert.Contains(t, followsLogger.String(), `"msg":"request processed"`)
+
+		log.SetLevel(LevelDebug)
+		log.With("component", "db").Debug("query executed")
+		assert.Contains(t, followsLogger.String(), `"component":"db"`)
+		assert.NotContains(t, errorsOnly.String(), "query executed")
+	})
+
+	t.Run("destino com nível menor que o logger recebe debug", func(t *testing.T) {
+		var stdout, debug bytes.Buffer
+		log := New(&Config{
+			Level:        LevelWarn,
+			Output:       &stdout,
+			Destinations: []Destination{{Output: &debug, Level: LevelDebug}},
+		})
+
+		log.Debug("cache miss")
+
+		assert.Empty(t, stdout.String())
+		assert.Contains(t, debug.String(), "cache miss")
+	})
+
+	t.Run("LOGGER_FILE adiciona um destino em arquivo", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		t.Setenv("LOGGER_FILE", path)
+		t.Setenv("LOGGER_FILE_LEVEL", "warn")
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		require.Len(t, cfg.Destinations, 1)
+		assert.Equal(t, LevelWarn, cfg.Destinations[0].Level)
+
+		cfg.Output = &bytes.Buffer{}
+		log := New(cfg)
+		log.Info("ignored")
+		log.Warn("disk almost full")
+		require.NoError(t, cfg.Destinations[0].Output.(*os.File).Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "ignored")
+		assert.Contains(t, string(data), `"msg":"disk almost full"`)
+	})
+}
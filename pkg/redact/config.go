@@ -0,0 +1,139 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/marcelofabianov/config"
+	"github.com/spf13/viper"
+)
+
+// DefaultMask replaces the whole value of a sensitive field.
+const DefaultMask = "***REDACTED***"
+
+// DefaultFields are the keys masked in full everywhere: credentials,
+// secrets and card data.
+var DefaultFields = []string{
+	"password", "senha", "token", "secret", "apikey", "api_key",
+	"credit_card", "card_number", "cvv", "pin", "private_key",
+	"authorization", "cookie", "set_cookie",
+}
+
+// DefaultPartial are the keys masked except for their last characters, so
+// support can still tell records apart ("***.***.**9-01").
+var DefaultPartial = map[string]int{
+	"cpf":  3,
+	"cnpj": 3,
+}
+
+// Pattern masks matches of Regexp inside any string value, whatever its
+// key, keeping the last Keep alphanumeric characters of each match.
+type Pattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+	Keep   int
+}
+
+// DefaultPatterns catch documents and card numbers that end up in free text
+// such as error messages.
+var DefaultPatterns = []Pattern{
+	{Name: "cnpj", Regexp: regexp.MustCompile(`\b\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}\b`), Keep: 3},
+	{Name: "cpf", Regexp: regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`), Keep: 3},
+	{Name: "card", Regexp: regexp.MustCompile(`\b(?:\d{4}[ -]?){3}\d{4}\b`), Keep: 4},
+	{Name: "bearer", Regexp: regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9._~+/=-]+`), Keep: 0},
+}
+
+// Config holds the redaction rules. Fields are masked in full, Partial keys
+// keep their last N characters, and Patterns apply to every string value.
+type Config struct {
+	Mask     string
+	Fields   []string
+	Partial  map[string]int
+	Patterns []Pattern
+}
+
+// DefaultConfig returns the rules shared by logger, validation and audit
+// trails.
+func DefaultConfig() *Config {
+	partial := make(map[string]int, len(DefaultPartial))
+	for key, keep := range DefaultPartial {
+		partial[key] = keep
+	}
+
+	return &Config{
+		Mask:     DefaultMask,
+		Fields:   append([]string(nil), DefaultFields...),
+		Partial:  partial,
+		Patterns: append([]Pattern(nil), DefaultPatterns...),
+	}
+}
+
+// LoadConfig returns DefaultConfig extended from REDACT_* environment
+// variables, loaded with config.Load.
+func LoadConfig() (*Config, error) {
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom returns DefaultConfig extended from the redact section of
+// c, REDACT_* variables first.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+	v := c.Section("redact", "REDACT")
+
+	setDefaults(v)
+
+	cfg := DefaultConfig()
+	cfg.Mask = v.GetString("mask")
+	for _, field := range strings.Split(v.GetString("additional_fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			cfg.Fields = append(cfg.Fields, field)
+		}
+	}
+
+	partial, err := parsePartial(v.GetString("partial_fields"))
+	if err != nil {
+		return nil, err
+	}
+	for key, keep := range partial {
+		cfg.Partial[key] = keep
+	}
+
+	return cfg, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("mask", DefaultMask)
+	v.SetDefault("additional_fields", "")
+	v.SetDefault("partial_fields", "")
+}
+
+// parsePartial parses "phone=4,rg=2".
+func parsePartial(spec string) (map[string]int, error) {
+	partial := make(map[string]int)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, count, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid partial field %q: expected key=keep", entry)
+		}
+
+		keep, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || keep < 0 {
+			return nil, fmt.Errorf("invalid partial field %q: keep must be a non-negative integer", entry)
+		}
+
+		partial[strings.TrimSpace(key)] = keep
+	}
+
+	return partial, nil
+}
@@ -0,0 +1,54 @@
+package redact
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("loads defaults when no env vars set", func(t *testing.T) {
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+
+		if cfg.Mask != DefaultMask {
+			t.Errorf("expected mask %s, got %s", DefaultMask, cfg.Mask)
+		}
+		if len(cfg.Fields) != len(DefaultFields) {
+			t.Errorf("expected %d fields, got %d", len(DefaultFields), len(cfg.Fields))
+		}
+		if cfg.Partial["cpf"] != 3 {
+			t.Errorf("expected cpf to keep 3 characters, got %d", cfg.Partial["cpf"])
+		}
+	})
+
+	t.Run("extends defaults from environment variables", func(t *testing.T) {
+		os.Setenv("REDACT_ADDITIONAL_FIELDS", "session_id,otp")
+		os.Setenv("REDACT_PARTIAL_FIELDS", "phone=4")
+		defer os.Unsetenv("REDACT_ADDITIONAL_FIELDS")
+		defer os.Unsetenv("REDACT_PARTIAL_FIELDS")
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+
+		r := New(cfg)
+		if !r.IsSensitive("otp") || !r.IsSensitive("password") {
+			t.Error("expected additional and default fields to be sensitive")
+		}
+		if got := r.Value("phone", "11987654321"); got != "*******4321" {
+			t.Errorf("expected partial phone, got %v", got)
+		}
+	})
+
+	t.Run("rejects malformed partial fields", func(t *testing.T) {
+		os.Setenv("REDACT_PARTIAL_FIELDS", "phone")
+		defer os.Unsetenv("REDACT_PARTIAL_FIELDS")
+
+		if _, err := LoadConfig(); err == nil {
+			t.Error("expected error for partial field without keep count")
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package redact
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// maxDepth bounds how deep nested values are walked, so self-referencing
+// structures end in the mask instead of a stack overflow.
+const maxDepth = 32
+
+// Redactor masks sensitive values by key and by pattern. Keys match
+// case-insensitively ignoring "_", "-" and "."; entries of five characters
+// or more also match as a suffix, so "password" covers "user_password" and
+// "token" covers "accessToken". None of its methods panic: a value that
+// cannot be walked comes back as the mask. It is safe for concurrent use.
+type Redactor struct {
+	mask     string
+	fields   map[string]bool
+	suffixes []string
+	partial  map[string]int
+	patterns []Pattern
+}
+
+// New builds a Redactor from cfg; nil uses DefaultConfig.
+func New(cfg *Config) *Redactor {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	r := &Redactor{
+		mask:     cfg.Mask,
+		fields:   make(map[string]bool),
+		partial:  make(map[string]int),
+		patterns: cfg.Patterns,
+	}
+	if r.mask == "" {
+		r.mask = DefaultMask
+	}
+
+	for _, field := range cfg.Fields {
+		key := normalize(field)
+		if key == "" || r.fields[key] {
+			continue
+		}
+		r.fields[key] = true
+		if len(key) >= 5 {
+			r.suffixes = append(r.suffixes, key)
+		}
+	}
+	for field, keep := range cfg.Partial {
+		r.partial[normalize(field)] = keep
+	}
+
+	return r
+}
+
+// Default returns a Redactor with DefaultConfig.
+func Default() *Redactor {
+	return New(nil)
+}
+
+// Mask returns the replacement used for sensitive values.
+func (r *Redactor) Mask() string {
+	return r.mask
+}
+
+// IsSensitive reports whether values under key are masked in full.
+func (r *Redactor) IsSensitive(key string) bool {
+	key = normalize(key)
+	if r.fields[key] {
+		return true
+	}
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns v as it may be logged or stored under key: the mask for
+// sensitive keys, a partial mask for partial keys, and otherwise v with
+// nested maps, slices and structs redacted and patterns applied to strings.
+// Structs and maps come back as map[string]any.
+func (r *Redactor) Value(key string, v any) (out any) {
+	defer func() {
+		if recover() != nil {
+			out = r.mask
+		}
+	}()
+	return r.value(key, v, 0)
+}
+
+// Map returns a redacted copy of m.
+func (r *Redactor) Map(m map[string]any) (out map[string]any) {
+	defer func() {
+		if recover() != nil {
+			out = map[string]any{}
+		}
+	}()
+
+	out = make(map[string]any, len(m))
+	for key, v := range m {
+		out[key] = r.value(key, v, 1)
+	}
+	return out
+}
+
+// Struct returns the exported fields of s (a struct or pointer to one),
+// keyed by their JSON names, redacted. Anything else yields an empty map.
+func (r *Redactor) Struct(s any) (out map[string]any) {
+	defer func() {
+		if recover() != nil {
+			out = map[string]any{}
+		}
+	}()
+
+	val := reflect.ValueOf(s)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return map[string]any{}
+	}
+	return r.structValue(val, 1)
+}
+
+// JSON returns data with every sensitive field masked. Input that is not
+// valid JSON is treated as text and only has patterns applied.
+func (r *Redactor) JSON(data []byte) (out []byte) {
+	defer func() {
+		if recover() != nil {
+			out = []byte(`"` + r.mask + `"`)
+		}
+	}()
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(r.String(string(data)))
+	}
+
+	masked, err := json.Marshal(r.value("", doc, 0))
+	if err != nil {
+		return []byte(`"` + r.mask + `"`)
+	}
+	return masked
+}
+
+// String applies the patterns to s.
+func (r *Redactor) String(s string) string {
+	for _, p := range r.patterns {
+		if p.Regexp == nil {
+			continue
+		}
+		s = p.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if p.Keep <= 0 {
+				return r.mask
+			}
+			return Partial(match, p.Keep)
+		})
+	}
+	return s
+}
+
+// ReplaceAttr redacts slog attributes; use it as
+// slog.HandlerOptions.ReplaceAttr.
+func (r *Redactor) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if _, ok := a.Value.Any().(*slog.Source); ok {
+		return a
+	}
+
+	a.Value = slog.AnyValue(r.Value(a.Key, a.Value.Resolve().Any()))
+	return a
+}
+
+func (r *Redactor) value(key string, v any, depth int) any {
+	if v == nil {
+		return nil
+	}
+	if key != "" {
+		if r.IsSensitive(key) {
+			return r.mask
+		}
+		if keep, ok := r.partial[normalize(key)]; ok {
+			if keep <= 0 {
+				return r.mask
+			}
+			return Partial(fmt.Sprint(v), keep)
+		}
+	}
+	if depth > maxDepth {
+		return r.mask
+	}
+
+	switch t := v.(type) {
+	case string:
+		return r.String(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			out[k] = r.value(k, item, depth+1)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = r.value("", item, depth+1)
+		}
+		return out
+	case error:
+		return r.String(t.Error())
+	case json.Marshaler, encoding.TextMarshaler, fmt.Stringer:
+		// Types such as time.Time own their representation.
+		return v
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return r.value("", val.Elem().Interface(), depth+1)
+	case reflect.Struct:
+		return r.structValue(val, depth+1)
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		out := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			out[k] = r.value(k, iter.Value().Interface(), depth+1)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		out := make([]any, val.Len())
+		for i := range out {
+			out[i] = r.value("", val.Index(i).Interface(), depth+1)
+		}
+		return out
+	case reflect.String:
+		return r.String(val.String())
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) structValue(val reflect.Value, depth int) map[string]any {
+	out := make(map[string]any)
+	typ := val.Type()
+
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		out[name] = r.value(name, val.Field(i).Interface(), depth)
+	}
+
+	return out
+}
+
+// Partial masks every letter and digit of s except the last keep,
+// preserving punctuation: Partial("123.456.789-01", 3) is "***.***.**9-01".
+func Partial(s string, keep int) string {
+	runes := []rune(s)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+func normalize(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '.', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
@@ -0,0 +1,143 @@
+package redact
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsSensitive(t *testing.T) {
+	r := Default()
+
+	for _, key := range []string{"password", "Password", "user_password", "accessToken", "API-KEY", "senha"} {
+		if !r.IsSensitive(key) {
+			t.Errorf("expected %q to be sensitive", key)
+		}
+	}
+	for _, key := range []string{"shipping", "name", "spinner", "email"} {
+		if r.IsSensitive(key) {
+			t.Errorf("expected %q not to be sensitive", key)
+		}
+	}
+}
+
+func TestPartial(t *testing.T) {
+	tests := []struct {
+		in   string
+		keep int
+		want string
+	}{
+		{"123.456.789-01", 3, "***.***.**9-01"},
+		{"12345678901", 3, "********901"},
+		{"ab", 3, "ab"},
+		{"", 3, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Partial(tt.in, tt.keep); got != tt.want {
+			t.Errorf("Partial(%q, %d) = %q, want %q", tt.in, tt.keep, got, tt.want)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	r := Default()
+
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Name     string    `json:"name"`
+		Password string    `json:"password"`
+		CPF      string    `json:"cpf"`
+		Address  *address  `json:"address"`
+		Created  time.Time `json:"created"`
+		internal string
+	}
+
+	got, ok := r.Value("user", user{
+		Name:     "Ana",
+		Password: "hunter2",
+		CPF:      "123.456.789-01",
+		Address:  &address{City: "Recife"},
+	}).(map[string]any)
+	if !ok {
+		t.Fatalf("expected struct to become a map, got %T", got)
+	}
+
+	if got["name"] != "Ana" {
+		t.Errorf("expected name to pass through, got %v", got["name"])
+	}
+	if got["password"] != DefaultMask {
+		t.Errorf("expected password masked, got %v", got["password"])
+	}
+	if got["cpf"] != "***.***.**9-01" {
+		t.Errorf("expected cpf partially masked, got %v", got["cpf"])
+	}
+	if city := got["address"].(map[string]any)["city"]; city != "Recife" {
+		t.Errorf("expected nested city, got %v", city)
+	}
+	if _, ok := got["created"].(time.Time); !ok {
+		t.Errorf("expected time to keep its type, got %T", got["created"])
+	}
+	if _, ok := got["internal"]; ok {
+		t.Error("expected unexported field to be skipped")
+	}
+}
+
+func TestValuePatterns(t *testing.T) {
+	r := Default()
+
+	got := r.Value("message", "customer 123.456.789-01 paid with 4111 1111 1111 1111")
+	want := "customer ***.***.**9-01 paid with **** **** **** 1111"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	err := errors.New("upstream rejected Bearer abc.def.ghi")
+	if got := r.Value("error", err); strings.Contains(got.(string), "abc.def") {
+		t.Errorf("expected bearer token masked, got %q", got)
+	}
+}
+
+func TestValueSelfReference(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+
+	// Must terminate rather than overflow the stack.
+	_ = Default().Value("node", n)
+}
+
+func TestJSON(t *testing.T) {
+	r := Default()
+
+	got := string(r.JSON([]byte(`{"user":{"email":"a@b.c","senha":"x","cpf":"12345678901"},"items":[{"token":"t"}]}`)))
+	for _, leak := range []string{`"x"`, `"t"`, "12345678901"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("expected %s masked in %s", leak, got)
+		}
+	}
+	if !strings.Contains(got, `"a@b.c"`) {
+		t.Errorf("expected email to pass through in %s", got)
+	}
+
+	if got := string(r.JSON([]byte(`not json 123.456.789-01`))); got != "not json ***.***.**9-01" {
+		t.Errorf("expected invalid JSON to be pattern masked, got %q", got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	in := map[string]any{"password": "p", "name": "n"}
+	out := Default().Map(in)
+
+	if out["password"] != DefaultMask || out["name"] != "n" {
+		t.Errorf("unexpected result %v", out)
+	}
+	if in["password"] != "p" {
+		t.Error("expected input map to be left untouched")
+	}
+}
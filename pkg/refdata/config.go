@@ -0,0 +1,38 @@
+package refdata
+
+import (
+	"time"
+
+	"github.com/marcelofabianov/config"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	RefreshInterval time.Duration
+	LoadTimeout     time.Duration
+}
+
+// LoadConfig loads the REFDATA_* settings with config.Load; a config file
+// that cannot be read leaves the defaults.
+func LoadConfig() *Config {
+	c, _ := config.Load()
+	return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom loads the refdata section of c: REFDATA_* variables over
+// the refdata section of the config file.
+func LoadConfigFrom(c *config.Config) *Config {
+	v := c.Section("refdata", "REFDATA")
+
+	setDefaults(v)
+
+	return &Config{
+		RefreshInterval: v.GetDuration("refresh_interval"),
+		LoadTimeout:     v.GetDuration("load_timeout"),
+	}
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("refresh_interval", 5*time.Minute)
+	v.SetDefault("load_timeout", 10*time.Second)
+}
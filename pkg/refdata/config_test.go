@@ -0,0 +1,35 @@
+package refdata
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	origInterval := os.Getenv("REFDATA_REFRESH_INTERVAL")
+	defer os.Setenv("REFDATA_REFRESH_INTERVAL", origInterval)
+
+	t.Run("loads defaults when no env vars set", func(t *testing.T) {
+		os.Unsetenv("REFDATA_REFRESH_INTERVAL")
+
+		cfg := LoadConfig()
+
+		if cfg.RefreshInterval != 5*time.Minute {
+			t.Errorf("expected refresh interval 5m, got %v", cfg.RefreshInterval)
+		}
+		if cfg.LoadTimeout != 10*time.Second {
+			t.Errorf("expected load timeout 10s, got %v", cfg.LoadTimeout)
+		}
+	})
+
+	t.Run("loads from environment variables", func(t *testing.T) {
+		os.Setenv("REFDATA_REFRESH_INTERVAL", "30s")
+
+		cfg := LoadConfig()
+
+		if cfg.RefreshInterval != 30*time.Second {
+			t.Errorf("expected refresh interval 30s, got %v", cfg.RefreshInterval)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package refdata
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+var (
+	// ErrLoadFailed is returned when a table could not be (re)loaded.
+	ErrLoadFailed = fault.New(
+		"failed to load reference data",
+		fault.WithCode(fault.InfraError),
+	)
+
+	// ErrInvalidTable is returned when a table is registered without a name
+	// or loader, or under a name already in use.
+	ErrInvalidTable = fault.New(
+		"invalid reference data table",
+		fault.WithCode(fault.Invalid),
+	)
+)
+
+// LoadFunc reads the full contents of a reference table, keyed for lookup.
+type LoadFunc[K comparable, V any] func(ctx context.Context) (map[K]V, error)
+
+// RefreshEvent describes the outcome of a load, passed to OnRefresh hooks.
+type RefreshEvent struct {
+	Table    string
+	Items    int
+	Duration time.Duration
+	Err      error
+}
+
+// Refresher is the untyped view of a Table used by the Registry.
+type Refresher interface {
+	Name() string
+	Refresh(ctx context.Context) error
+}
+
+// Table is an in-memory snapshot of a small reference table (course
+// catalog, document types). Lookups never hit the database; Refresh swaps in
+// a new snapshot and keeps the previous one when the load fails. It is safe
+// for concurrent use.
+type Table[K comparable, V any] struct {
+	name string
+	load LoadFunc[K, V]
+
+	mu       sync.RWMutex
+	items    map[K]V
+	loadedAt time.Time
+	hooks    []func(RefreshEvent)
+}
+
+func NewTable[K comparable, V any](name string, load LoadFunc[K, V]) *Table[K, V] {
+	return &Table[K, V]{
+		name:  name,
+		load:  load,
+		items: make(map[K]V),
+	}
+}
+
+func (t *Table[K, V]) Name() string {
+	return t.name
+}
+
+// OnRefresh registers a hook called after every load, successful or not.
+func (t *Table[K, V]) OnRefresh(hook func(RefreshEvent)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.hooks = append(t.hooks, hook)
+}
+
+// Refresh reloads the table and replaces the snapshot.
+func (t *Table[K, V]) Refresh(ctx context.Context) error {
+	start := time.Now()
+	items, err := t.load(ctx)
+
+	event := RefreshEvent{Table: t.name, Duration: time.Since(start)}
+
+	t.mu.Lock()
+	if err == nil {
+		if items == nil {
+			items = make(map[K]V)
+		}
+		t.items = items
+		t.loadedAt = time.Now()
+	}
+	event.Items = len(t.items)
+	hooks := t.hooks
+	t.mu.Unlock()
+
+	if err != nil {
+		err = fault.Wrap(ErrLoadFailed, "table load failed",
+			fault.WithWrappedErr(err),
+			fault.WithContext("table", t.name),
+		)
+		event.Err = err
+	}
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+
+	return err
+}
+
+// Get returns the value stored under key.
+func (t *Table[K, V]) Get(key K) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	value, ok := t.items[key]
+	return value, ok
+}
+
+// Filter returns the values matching match, in no particular order.
+func (t *Table[K, V]) Filter(match func(V) bool) []V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var values []V
+	for _, value := range t.items {
+		if match(value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// All returns a copy of the current snapshot.
+func (t *Table[K, V]) All() map[K]V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return maps.Clone(t.items)
+}
+
+func (t *Table[K, V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.items)
+}
+
+// LoadedAt returns when the current snapshot was loaded, zero if never.
+func (t *Table[K, V]) LoadedAt() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.loadedAt
+}
+
+// Registry loads its tables at startup and keeps them fresh, on an interval
+// and whenever Invalidate is called (e.g. from a change notification).
+type Registry struct {
+	config *Config
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	tables  map[string]Refresher
+	order   []string
+	pending map[string]struct{}
+	wake    chan struct{}
+}
+
+func NewRegistry(cfg *Config, logger *slog.Logger) *Registry {
+	if cfg == nil {
+		cfg = LoadConfig()
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Registry{
+		config:  cfg,
+		logger:  logger,
+		tables:  make(map[string]Refresher),
+		pending: make(map[string]struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Register adds tables to the registry. Names must be unique.
+func (r *Registry) Register(tables ...Refresher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, table := range tables {
+		if table == nil || table.Name() == "" {
+			return fault.Wrap(ErrInvalidTable, "table must have a name")
+		}
+		if _, exists := r.tables[table.Name()]; exists {
+			return fault.Wrap(ErrInvalidTable, "table already registered",
+				fault.WithContext("table", table.Name()),
+			)
+		}
+
+		r.tables[table.Name()] = table
+		r.order = append(r.order, table.Name())
+	}
+
+	return nil
+}
+
+// Load loads every table once, typically at startup before serving
+// traffic. It stops at the first failure.
+func (r *Registry) Load(ctx context.Context) error {
+	for _, table := range r.snapshot(nil) {
+		if err := r.refresh(ctx, table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Invalidate schedules a refresh of the named tables, or of every table
+// when no name is given. It does not block; the refresh runs in the
+// goroutine started by Start.
+func (r *Registry) Invalidate(names ...string) {
+	r.mu.Lock()
+	if len(names) == 0 {
+		names = r.order
+	}
+	for _, name := range names {
+		if _, ok := r.tables[name]; !ok {
+			r.logger.Warn("Invalidate called for unknown reference table", "table", name)
+			continue
+		}
+		r.pending[name] = struct{}{}
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start refreshes every table on RefreshInterval and invalidated tables as
+// soon as possible, until ctx is done. A failed refresh is logged and the
+// previous snapshot keeps being served.
+func (r *Registry) Start(ctx context.Context) {
+	interval := r.config.RefreshInterval
+
+	go func() {
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				r.refreshAll(ctx, r.snapshot(nil))
+			case <-r.wake:
+				r.refreshAll(ctx, r.takePending())
+			}
+		}
+	}()
+
+	r.logger.Info("Reference data refresh started",
+		"tables", len(r.snapshot(nil)),
+		"interval", interval.String(),
+	)
+}
+
+func (r *Registry) refreshAll(ctx context.Context, tables []Refresher) {
+	for _, table := range tables {
+		if err := r.refresh(ctx, table); err != nil {
+			r.logger.Error("Reference data refresh failed",
+				"table", table.Name(),
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (r *Registry) refresh(ctx context.Context, table Refresher) error {
+	loadCtx := ctx
+	if r.config.LoadTimeout > 0 {
+		var cancel context.CancelFunc
+		loadCtx, cancel = context.WithTimeout(ctx, r.config.LoadTimeout)
+		defer cancel()
+	}
+
+	return table.Refresh(loadCtx)
+}
+
+// snapshot returns the registered tables in registration order, limited to
+// names when it is non-nil.
+func (r *Registry) snapshot(names map[string]struct{}) []Refresher {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tables := make([]Refresher, 0, len(r.order))
+	for _, name := range r.order {
+		if names != nil {
+			if _, ok := names[name]; !ok {
+				continue
+			}
+		}
+		tables = append(tables, r.tables[name])
+	}
+	return tables
+}
+
+func (r *Registry) takePending() []Refresher {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]struct{})
+	r.mu.Unlock()
+
+	return r.snapshot(pending)
+}
@@ -0,0 +1,165 @@
+package refdata
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type documentType struct {
+	Code   string
+	Name   string
+	Active bool
+}
+
+func TestTableRefresh(t *testing.T) {
+	fail := false
+	table := NewTable("document_types", func(ctx context.Context) (map[string]documentType, error) {
+		if fail {
+			return nil, errors.New("connection refused")
+		}
+		return map[string]documentType{
+			"cpf": {Code: "cpf", Name: "CPF", Active: true},
+			"rg":  {Code: "rg", Name: "RG", Active: false},
+		}, nil
+	})
+
+	var events []RefreshEvent
+	table.OnRefresh(func(e RefreshEvent) {
+		events = append(events, e)
+	})
+
+	if err := table.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	doc, ok := table.Get("cpf")
+	if !ok || doc.Name != "CPF" {
+		t.Errorf("expected CPF document type, got %+v (ok=%v)", doc, ok)
+	}
+	if _, ok := table.Get("cnh"); ok {
+		t.Error("expected unknown key to be missing")
+	}
+	if active := table.Filter(func(d documentType) bool { return d.Active }); len(active) != 1 {
+		t.Errorf("expected 1 active document type, got %d", len(active))
+	}
+	if table.LoadedAt().IsZero() {
+		t.Error("expected LoadedAt to be set")
+	}
+
+	fail = true
+	if err := table.Refresh(context.Background()); !errors.Is(err, ErrLoadFailed) {
+		t.Fatalf("expected ErrLoadFailed, got %v", err)
+	}
+	if table.Len() != 2 {
+		t.Errorf("expected previous snapshot to be kept, got %d items", table.Len())
+	}
+
+	if len(events) != 2 || events[0].Err != nil || events[1].Err == nil {
+		t.Errorf("unexpected refresh events %+v", events)
+	}
+	if events[0].Table != "document_types" || events[0].Items != 2 {
+		t.Errorf("unexpected first event %+v", events[0])
+	}
+}
+
+func TestTableAllReturnsCopy(t *testing.T) {
+	table := NewTable("courses", func(ctx context.Context) (map[int]string, error) {
+		return map[int]string{1: "Math"}, nil
+	})
+	if err := table.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	all := table.All()
+	all[2] = "History"
+
+	if table.Len() != 1 {
+		t.Errorf("expected mutation of All() not to leak, got %d items", table.Len())
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry(&Config{}, nil)
+	load := func(ctx context.Context) (map[int]string, error) { return nil, nil }
+
+	if err := registry.Register(NewTable("courses", load)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register(NewTable("courses", load)); !errors.Is(err, ErrInvalidTable) {
+		t.Errorf("expected ErrInvalidTable for duplicate name, got %v", err)
+	}
+	if err := registry.Register(NewTable("", load)); !errors.Is(err, ErrInvalidTable) {
+		t.Errorf("expected ErrInvalidTable for empty name, got %v", err)
+	}
+}
+
+func TestRegistryLoad(t *testing.T) {
+	registry := NewRegistry(&Config{LoadTimeout: time.Second}, nil)
+
+	courses := NewTable("courses", func(ctx context.Context) (map[int]string, error) {
+		return map[int]string{1: "Math"}, nil
+	})
+	broken := NewTable("broken", func(ctx context.Context) (map[int]string, error) {
+		return nil, errors.New("relation does not exist")
+	})
+
+	if err := registry.Register(courses); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if name, _ := courses.Get(1); name != "Math" {
+		t.Errorf("expected courses to be loaded, got %q", name)
+	}
+
+	if err := registry.Register(broken); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Load(context.Background()); !errors.Is(err, ErrLoadFailed) {
+		t.Errorf("expected ErrLoadFailed, got %v", err)
+	}
+}
+
+func TestRegistryInvalidate(t *testing.T) {
+	registry := NewRegistry(&Config{}, nil)
+
+	var loads atomic.Int32
+	refreshed := make(chan struct{}, 1)
+
+	courses := NewTable("courses", func(ctx context.Context) (map[int]string, error) {
+		loads.Add(1)
+		return map[int]string{1: "Math"}, nil
+	})
+	courses.OnRefresh(func(RefreshEvent) {
+		refreshed <- struct{}{}
+	})
+
+	other := NewTable("other", func(ctx context.Context) (map[int]string, error) {
+		t.Error("expected only the invalidated table to be refreshed")
+		return nil, nil
+	})
+
+	if err := registry.Register(courses, other); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry.Start(ctx)
+
+	registry.Invalidate("courses")
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("expected invalidated table to be refreshed")
+	}
+
+	if loads.Load() != 1 {
+		t.Errorf("expected 1 load, got %d", loads.Load())
+	}
+}
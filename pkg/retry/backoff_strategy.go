@@ -162,3 +162,114 @@ func (l *LinearBackoff) NextDelay(attempt int) time.Duration {
 func (l *LinearBackoff) Reset() {
 	// Stateless strategy, nothing to reset
 }
+
+// DecorrelatedJitterBackoff implements the AWS "decorrelated jitter"
+// strategy: each delay is drawn uniformly from [base, previous*3), capped at
+// max. Because every delay depends on the previous random one, clients that
+// failed together drift apart instead of retrying in lockstep, which plain
+// exponential jitter does not prevent under heavy load.
+// It is safe for concurrent use, and Do gives every call its own sequence,
+// so one strategy can back a shared Config or policy.
+type DecorrelatedJitterBackoff struct {
+	mu    sync.Mutex
+	base  time.Duration
+	max   time.Duration
+	sleep time.Duration
+}
+
+// NewDecorrelatedJitterBackoff creates a decorrelated jitter strategy.
+// Base must be > 0 and max must be >= base.
+func NewDecorrelatedJitterBackoff(base, max time.Duration) *DecorrelatedJitterBackoff {
+	if base <= 0 {
+		base = 1 * time.Second
+	}
+	if max < base {
+		max = base
+	}
+	return &DecorrelatedJitterBackoff{
+		base:  base,
+		max:   max,
+		sleep: base,
+	}
+}
+
+// NextDelay returns min(max, random_between(base, previous*3)). Attempt 0
+// starts a new sequence, so each Do call begins from base.
+func (d *DecorrelatedJitterBackoff) NextDelay(attempt int) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if attempt <= 0 {
+		d.sleep = d.base
+	}
+
+	upper := float64(d.sleep) * 3
+	if upper > float64(d.max) {
+		upper = float64(d.max)
+	}
+
+	//nolint:gosec // G404: math/rand acceptable for jitter (non-cryptographic use)
+	delay := float64(d.base) + rand.Float64()*(upper-float64(d.base))
+
+	d.sleep = time.Duration(delay)
+	return d.sleep
+}
+
+// Reset restarts the sequence from base.
+func (d *DecorrelatedJitterBackoff) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sleep = d.base
+}
+
+// newSequence returns a copy starting from base, for one Do call.
+func (d *DecorrelatedJitterBackoff) newSequence() Strategy {
+	return NewDecorrelatedJitterBackoff(d.base, d.max)
+}
+
+// FibonacciBackoff implements a Fibonacci backoff strategy: base, 2*base,
+// 3*base, 5*base, 8*base, ... capped at max. It grows more gently than
+// exponential backoff with factor 2.
+// It is safe for concurrent use.
+type FibonacciBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// NewFibonacciBackoff creates a Fibonacci backoff strategy.
+// Base must be > 0 and max must be >= base.
+func NewFibonacciBackoff(base, max time.Duration) *FibonacciBackoff {
+	if base <= 0 {
+		base = 1 * time.Second
+	}
+	if max < base {
+		max = base
+	}
+	return &FibonacciBackoff{
+		base: base,
+		max:  max,
+	}
+}
+
+// NextDelay calculates the delay as: base * fib(attempt + 2), capped at max.
+func (f *FibonacciBackoff) NextDelay(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	delay, next := f.base, 2*f.base
+	for i := 0; i < attempt && delay < f.max; i++ {
+		delay, next = next, delay+next
+	}
+
+	if delay > f.max {
+		delay = f.max
+	}
+	return delay
+}
+
+// Reset is a no-op for Fibonacci backoff as it's stateless.
+func (f *FibonacciBackoff) Reset() {
+	// Stateless strategy, nothing to reset
+}
@@ -0,0 +1,228 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrCircuitOpen is returned without calling the function while a Breaker
+// is open, or half-open with every trial slot taken.
+var ErrCircuitOpen = fault.New(
+	"circuit breaker is open",
+	fault.WithCode(fault.InfraError),
+)
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// StateClosed lets every call through and counts consecutive failures.
+	StateClosed BreakerState = iota
+	// StateOpen rejects every call until the cool-down elapses.
+	StateOpen
+	// StateHalfOpen lets a few trial calls through; a success closes the
+	// circuit and a failure opens it again.
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig holds the circuit breaker configuration.
+type BreakerConfig struct {
+	// Name identifies the protected dependency in errors and callbacks.
+	Name string
+
+	// FailureThreshold is how many consecutive failures open the circuit.
+	// If zero, uses 5.
+	FailureThreshold int
+
+	// CoolDown is how long the circuit stays open before letting trial
+	// calls through. If zero, uses 30s.
+	CoolDown time.Duration
+
+	// HalfOpenMaxCalls is how many trial calls may run at once while
+	// half-open. If zero, uses 1.
+	HalfOpenMaxCalls int
+
+	// IsFailure decides whether an error counts against the dependency. If
+	// nil, every error except those wrapped with Permanent counts: a
+	// validation failure means the dependency answered.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called after every transition, outside the lock.
+	OnStateChange func(name string, from, to BreakerState)
+}
+
+// Breaker is a circuit breaker shared by every caller of one dependency:
+// after FailureThreshold consecutive failures it opens and calls fail fast
+// with ErrCircuitOpen for CoolDown, then trial calls decide whether it
+// closes again. Set it on Config.Breaker to guard every attempt of Do, or
+// use Execute directly. It is safe for concurrent use.
+type Breaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func NewBreaker(config BreakerConfig) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool {
+			return err != nil && !IsPermanent(err)
+		}
+	}
+
+	return &Breaker{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// State returns the current state. An open breaker whose cool-down has
+// elapsed reports half-open.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.CoolDown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Execute runs fn if the breaker allows it and records the outcome. A
+// panic in fn is recorded as a failure, releasing its trial slot, and then
+// propagated.
+func (b *Breaker) Execute(ctx context.Context, fn RetryableFunc) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			b.record(true)
+		}
+	}()
+
+	err := fn(ctx)
+	completed = true
+	b.record(b.config.IsFailure(err))
+	return err
+}
+
+// allow reserves a call, or returns ErrCircuitOpen.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+
+	var change transition
+	if b.state == StateOpen {
+		if wait := b.config.CoolDown - b.now().Sub(b.openedAt); wait > 0 {
+			b.mu.Unlock()
+			return fault.Wrap(ErrCircuitOpen, "dependency is failing",
+				fault.WithContext("breaker", b.config.Name),
+				fault.WithContext("retry_in", wait.String()),
+			)
+		}
+		change = b.setState(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+		if b.trials >= b.config.HalfOpenMaxCalls {
+			b.mu.Unlock()
+			b.notify(change)
+			return fault.Wrap(ErrCircuitOpen, "waiting for trial calls",
+				fault.WithContext("breaker", b.config.Name),
+			)
+		}
+		b.trials++
+	}
+
+	b.mu.Unlock()
+	b.notify(change)
+	return nil
+}
+
+// record counts the outcome of an allowed call.
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+
+	var change transition
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			break
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			change = b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		if failed {
+			change = b.setState(StateOpen)
+		} else {
+			change = b.setState(StateClosed)
+		}
+	case StateOpen:
+		// A call that started before the circuit opened; its outcome no
+		// longer matters.
+	}
+
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+type transition struct {
+	from, to BreakerState
+	changed  bool
+}
+
+// setState must be called with b.mu held.
+func (b *Breaker) setState(to BreakerState) transition {
+	from := b.state
+	if from == to {
+		return transition{}
+	}
+
+	b.state = to
+	b.failures = 0
+	b.trials = 0
+	if to == StateOpen {
+		b.openedAt = b.now()
+	}
+	return transition{from: from, to: to, changed: true}
+}
+
+func (b *Breaker) notify(change transition) {
+	if change.changed && b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.config.Name, change.from, change.to)
+	}
+}
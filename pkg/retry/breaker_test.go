@@ -0,0 +1,194 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	var changes []string
+	breaker := NewBreaker(BreakerConfig{
+		Name:             "viacep",
+		FailureThreshold: 3,
+		CoolDown:         time.Minute,
+		OnStateChange: func(name string, from, to BreakerState) {
+			changes = append(changes, name+":"+from.String()+"->"+to.String())
+		},
+	})
+
+	errUnavailable := errors.New("unavailable")
+	for i := 0; i < 3; i++ {
+		_ = breaker.Execute(context.Background(), func(ctx context.Context) error { return errUnavailable })
+	}
+
+	if breaker.State() != StateOpen {
+		t.Fatalf("expected open, got %s", breaker.State())
+	}
+
+	called := false
+	err := breaker.Execute(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if called {
+		t.Error("expected the function not to run while open")
+	}
+	if len(changes) != 1 || changes[0] != "viacep:closed->open" {
+		t.Errorf("unexpected state changes: %v", changes)
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 2})
+	fail := func(ctx context.Context) error { return errors.New("timeout") }
+	ok := func(ctx context.Context) error { return nil }
+
+	_ = breaker.Execute(context.Background(), fail)
+	_ = breaker.Execute(context.Background(), ok)
+	_ = breaker.Execute(context.Background(), fail)
+
+	if breaker.State() != StateClosed {
+		t.Errorf("expected closed, got %s", breaker.State())
+	}
+}
+
+func TestBreakerPermanentErrorsDoNotCount(t *testing.T) {
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 1})
+
+	_ = breaker.Execute(context.Background(), func(ctx context.Context) error {
+		return Permanent(errors.New("invalid cep"))
+	})
+
+	if breaker.State() != StateClosed {
+		t.Errorf("expected closed, got %s", breaker.State())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 1, CoolDown: time.Second})
+	breaker.now = func() time.Time { return now }
+
+	fail := func(ctx context.Context) error { return errors.New("timeout") }
+	_ = breaker.Execute(context.Background(), fail)
+
+	now = now.Add(time.Second)
+	if breaker.State() != StateHalfOpen {
+		t.Fatalf("expected half-open after the cool-down, got %s", breaker.State())
+	}
+
+	_ = breaker.Execute(context.Background(), fail)
+	if breaker.State() != StateOpen {
+		t.Fatalf("expected a failed trial to reopen, got %s", breaker.State())
+	}
+
+	now = now.Add(time.Second)
+	if err := breaker.Execute(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("expected a successful trial to close, got %s", breaker.State())
+	}
+}
+
+func TestBreakerHalfOpenLimitsTrials(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 1, CoolDown: time.Second})
+	breaker.now = func() time.Time { return now }
+
+	_ = breaker.Execute(context.Background(), func(ctx context.Context) error { return errors.New("timeout") })
+	now = now.Add(time.Second)
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		_ = breaker.Execute(context.Background(), func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		breaker.mu.Lock()
+		trials := breaker.trials
+		breaker.mu.Unlock()
+		if trials == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	err := breaker.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen while the trial runs, got %v", err)
+	}
+
+	close(release)
+	<-done
+}
+
+func TestBreakerHalfOpenPanicReleasesTrial(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 1, CoolDown: time.Second})
+	breaker.now = func() time.Time { return now }
+
+	_ = breaker.Execute(context.Background(), func(ctx context.Context) error { return errors.New("timeout") })
+	now = now.Add(time.Second)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		_ = breaker.Execute(context.Background(), func(ctx context.Context) error { panic("boom") })
+	}()
+
+	if breaker.State() != StateOpen {
+		t.Fatalf("expected the panicked trial to reopen, got %s", breaker.State())
+	}
+
+	now = now.Add(time.Second)
+	if err := breaker.Execute(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected a new trial after the cool-down, got %v", err)
+	}
+	if breaker.State() != StateClosed {
+		t.Errorf("expected closed, got %s", breaker.State())
+	}
+}
+
+func TestDoWithBreaker(t *testing.T) {
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 2, CoolDown: time.Minute})
+	cfg := testConfig()
+	cfg.MaxAttempts = 5
+	cfg.Breaker = breaker
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected Do to stop once the circuit opened, got %d calls", calls)
+	}
+
+	calls = 0
+	err = Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Errorf("expected Do to fail fast without calling, got %v after %d calls", err, calls)
+	}
+}
@@ -2,11 +2,10 @@ package retry
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/marcelofabianov/config"
 	"github.com/spf13/viper"
 )
 
@@ -21,25 +20,61 @@ type BackoffConfig struct {
 }
 
 type RetryConfig struct {
-	MaxAttempts int
-	Backoff     BackoffConfig
+	MaxAttempts    int
+	MaxElapsedTime time.Duration
+	AttemptTimeout time.Duration
+	Backoff        BackoffConfig
 }
 
+// LoadConfig loads the RETRY_* settings with config.Load. Like a missing
+// .env, a config file that cannot be read leaves the defaults.
 func LoadConfig() *RetryConfig {
-	v := viper.New()
-	v.SetEnvPrefix("RETRY")
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	if envFile := findEnvFile(); envFile != "" {
-		v.SetConfigFile(envFile)
-		_ = v.ReadInConfig()
-	}
+	c, _ := config.Load()
+	return LoadConfigFrom(c)
+}
 
-	setDefaults(v)
+// LoadConfigFrom loads the retry section of c: RETRY_* variables over the
+// retry section of the config file.
+func LoadConfigFrom(c *config.Config) *RetryConfig {
+	return loadConfig(c.Section("retry", "RETRY"), setDefaults)
+}
+
+// LoadPolicyConfig loads the configuration of a named policy from
+// RETRY_<NAME>_* variables (e.g. RETRY_REDIS_MAX_ATTEMPTS) and the
+// retry.policies.<name> section of the config file, falling back to the
+// RETRY_* values for anything not set.
+func LoadPolicyConfig(name string) *RetryConfig {
+	c, _ := config.Load()
+	return LoadPolicyConfigFrom(c, name)
+}
+
+// LoadPolicyConfigFrom is LoadPolicyConfig reading c.
+func LoadPolicyConfigFrom(c *config.Config, name string) *RetryConfig {
+	base := LoadConfigFrom(c)
+	prefix := "RETRY_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	section := "retry.policies." + strings.ToLower(strings.NewReplacer(".", "_").Replace(name))
+
+	return loadConfig(c.Section(section, prefix), func(v *viper.Viper) {
+		v.SetDefault("max_attempts", base.MaxAttempts)
+		v.SetDefault("max_elapsed_time", base.MaxElapsedTime)
+		v.SetDefault("attempt_timeout", base.AttemptTimeout)
+		v.SetDefault("backoff.type", base.Backoff.Type)
+		v.SetDefault("backoff.min", base.Backoff.Min)
+		v.SetDefault("backoff.max", base.Backoff.Max)
+		v.SetDefault("backoff.factor", base.Backoff.Factor)
+		v.SetDefault("backoff.jitter", base.Backoff.Jitter)
+		v.SetDefault("backoff.delay", base.Backoff.Delay)
+		v.SetDefault("backoff.increment", base.Backoff.Increment)
+	})
+}
+
+func loadConfig(v *viper.Viper, defaults func(v *viper.Viper)) *RetryConfig {
+	defaults(v)
 
 	return &RetryConfig{
-		MaxAttempts: v.GetInt("max_attempts"),
+		MaxAttempts:    v.GetInt("max_attempts"),
+		MaxElapsedTime: v.GetDuration("max_elapsed_time"),
+		AttemptTimeout: v.GetDuration("attempt_timeout"),
 		Backoff: BackoffConfig{
 			Type:      v.GetString("backoff.type"),
 			Min:       v.GetDuration("backoff.min"),
@@ -54,6 +89,8 @@ func LoadConfig() *RetryConfig {
 
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("max_attempts", 3)
+	v.SetDefault("max_elapsed_time", 0)
+	v.SetDefault("attempt_timeout", 0)
 	v.SetDefault("backoff.type", "exponential")
 	v.SetDefault("backoff.min", 1*time.Second)
 	v.SetDefault("backoff.max", 30*time.Second)
@@ -63,27 +100,6 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("backoff.increment", 1*time.Second)
 }
 
-func findEnvFile() string {
-	dir, err := os.Getwd()
-	if err != nil {
-		return ""
-	}
-
-	for i := 0; i < 5; i++ {
-		envPath := filepath.Join(dir, ".env")
-		if _, err := os.Stat(envPath); err == nil {
-			return envPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-
-	return ""
-}
-
 func (bc *BackoffConfig) CreateStrategy() (Strategy, error) {
 	switch bc.Type {
 	case "exponential":
@@ -100,6 +116,12 @@ func (bc *BackoffConfig) CreateStrategy() (Strategy, error) {
 	case "linear":
 		return NewLinearBackoff(bc.Increment, bc.Max), nil
 
+	case "decorrelated_jitter":
+		return NewDecorrelatedJitterBackoff(bc.Min, bc.Max), nil
+
+	case "fibonacci":
+		return NewFibonacciBackoff(bc.Min, bc.Max), nil
+
 	default:
 		return nil, fmt.Errorf("unknown backoff type: %s", bc.Type)
 	}
@@ -112,7 +134,9 @@ func (rc *RetryConfig) ToConfig() (*Config, error) {
 	}
 
 	return &Config{
-		MaxAttempts: rc.MaxAttempts,
-		Strategy:    strategy,
+		MaxAttempts:    rc.MaxAttempts,
+		MaxElapsedTime: rc.MaxElapsedTime,
+		AttemptTimeout: rc.AttemptTimeout,
+		Strategy:       strategy,
 	}, nil
 }
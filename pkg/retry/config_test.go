@@ -2,6 +2,7 @@ package retry
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -23,6 +24,12 @@ func TestLoadConfig(t *testing.T) {
 		if cfg.MaxAttempts != 3 {
 			t.Errorf("expected max attempts 3, got %d", cfg.MaxAttempts)
 		}
+		if cfg.MaxElapsedTime != 0 {
+			t.Errorf("expected no max elapsed time, got %v", cfg.MaxElapsedTime)
+		}
+		if cfg.AttemptTimeout != 0 {
+			t.Errorf("expected no attempt timeout, got %v", cfg.AttemptTimeout)
+		}
 		if cfg.Backoff.Type != "exponential" {
 			t.Errorf("expected backoff type 'exponential', got %s", cfg.Backoff.Type)
 		}
@@ -124,6 +131,55 @@ func TestBackoffConfig_CreateStrategy(t *testing.T) {
 		}
 	})
 
+	t.Run("creates decorrelated jitter backoff", func(t *testing.T) {
+		bc := BackoffConfig{
+			Type: "decorrelated_jitter",
+			Min:  100 * time.Millisecond,
+			Max:  2 * time.Second,
+		}
+
+		strategy, err := bc.CreateStrategy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		prev := bc.Min
+		for attempt := 0; attempt < 20; attempt++ {
+			delay := strategy.NextDelay(attempt)
+			if delay < bc.Min || delay > bc.Max {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, delay, bc.Min, bc.Max)
+			}
+			if attempt > 0 && delay > 3*prev {
+				t.Fatalf("attempt %d: delay %v exceeds 3x previous %v", attempt, delay, prev)
+			}
+			prev = delay
+		}
+
+		if delay := strategy.NextDelay(0); delay >= 3*bc.Min {
+			t.Errorf("expected attempt 0 to restart from base, got %v", delay)
+		}
+	})
+
+	t.Run("creates fibonacci backoff", func(t *testing.T) {
+		bc := BackoffConfig{
+			Type: "fibonacci",
+			Min:  1 * time.Second,
+			Max:  10 * time.Second,
+		}
+
+		strategy, err := bc.CreateStrategy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []time.Duration{1, 2, 3, 5, 8, 10, 10}
+		for attempt, want := range expected {
+			if delay := strategy.NextDelay(attempt); delay != want*time.Second {
+				t.Errorf("attempt %d: expected %v, got %v", attempt, want*time.Second, delay)
+			}
+		}
+	})
+
 	t.Run("returns error for unknown type", func(t *testing.T) {
 		bc := BackoffConfig{
 			Type: "unknown",
@@ -179,7 +235,51 @@ func TestRetryConfig_ToConfig(t *testing.T) {
 	})
 }
 
-func TestFindEnvFile(t *testing.T) {
-	envFile := findEnvFile()
-	_ = envFile
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+retry:
+  max_attempts: 4
+  backoff:
+    type: linear
+  policies:
+    search:
+      max_attempts: 6
+profiles:
+  production:
+    retry:
+      backoff:
+        increment: 3s
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("CONFIG_PROFILE", "production")
+	t.Setenv("RETRY_MAX_ATTEMPTS", "")
+	t.Setenv("RETRY_BACKOFF_TYPE", "")
+
+	rc := LoadConfig()
+	if rc.MaxAttempts != 4 {
+		t.Errorf("expected MaxAttempts 4 from the file, got %d", rc.MaxAttempts)
+	}
+	if rc.Backoff.Type != "linear" {
+		t.Errorf("expected backoff type linear, got %s", rc.Backoff.Type)
+	}
+	if rc.Backoff.Increment != 3*time.Second {
+		t.Errorf("expected the production profile increment 3s, got %v", rc.Backoff.Increment)
+	}
+
+	policy := LoadPolicyConfig("search")
+	if policy.MaxAttempts != 6 {
+		t.Errorf("expected policy MaxAttempts 6, got %d", policy.MaxAttempts)
+	}
+	if policy.Backoff.Type != "linear" {
+		t.Errorf("expected the policy to inherit backoff type linear, got %s", policy.Backoff.Type)
+	}
+
+	t.Setenv("RETRY_MAX_ATTEMPTS", "9")
+	if rc := LoadConfig(); rc.MaxAttempts != 9 {
+		t.Errorf("expected RETRY_MAX_ATTEMPTS to win over the file, got %d", rc.MaxAttempts)
+	}
 }
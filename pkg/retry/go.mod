@@ -3,6 +3,7 @@ module github.com/marcelofabianov/retry
 go 1.25.1
 
 require (
+	github.com/marcelofabianov/config v0.0.0
 	github.com/marcelofabianov/fault v1.5.0
 	github.com/spf13/viper v1.21.0
 )
@@ -28,3 +29,5 @@ require (
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 )
+
+replace github.com/marcelofabianov/config => ../config
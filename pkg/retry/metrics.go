@@ -0,0 +1,61 @@
+package retry
+
+import "sync/atomic"
+
+// Metrics counts what Do does with one operation or dependency. Set the
+// same *Metrics on every Config for that dependency and export the counters,
+// e.g. with prometheus.NewCounterFunc, to alert on retry storms. It is safe
+// for concurrent use; the zero value is ready to use.
+type Metrics struct {
+	attempts            atomic.Int64
+	retries             atomic.Int64
+	successesAfterRetry atomic.Int64
+	giveUps             atomic.Int64
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics. All counters only
+// grow.
+type MetricsSnapshot struct {
+	// Attempts counts every call to the RetryableFunc, first tries included.
+	Attempts int64
+	// Retries counts the calls after the first one.
+	Retries int64
+	// SuccessesAfterRetry counts Do calls that succeeded after at least one
+	// retry.
+	SuccessesAfterRetry int64
+	// GiveUps counts Do calls that stopped while the error was still
+	// retryable: attempts ran out, the budget was exhausted or the context
+	// ended.
+	GiveUps int64
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Attempts:            m.attempts.Load(),
+		Retries:             m.retries.Load(),
+		SuccessesAfterRetry: m.successesAfterRetry.Load(),
+		GiveUps:             m.giveUps.Load(),
+	}
+}
+
+func (m *Metrics) attempt(retry bool) {
+	if m == nil {
+		return
+	}
+	m.attempts.Add(1)
+	if retry {
+		m.retries.Add(1)
+	}
+}
+
+func (m *Metrics) successAfterRetry() {
+	if m != nil {
+		m.successesAfterRetry.Add(1)
+	}
+}
+
+func (m *Metrics) giveUp() {
+	if m != nil {
+		m.giveUps.Add(1)
+	}
+}
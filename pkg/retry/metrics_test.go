@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDoMetrics(t *testing.T) {
+	metrics := &Metrics{}
+	cfg := testConfig()
+	cfg.Metrics = metrics
+
+	// Succeeds on the first try.
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error { return nil })
+
+	// Succeeds after one retry.
+	calls := 0
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+
+	// Never succeeds.
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	// Not retryable: neither a retry nor a give-up.
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error {
+		return Permanent(errors.New("invalid"))
+	})
+
+	got := metrics.Snapshot()
+	want := MetricsSnapshot{Attempts: 1 + 2 + 4 + 1, Retries: 1 + 3, SuccessesAfterRetry: 1, GiveUps: 1}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDoOnGiveUp(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	var gotErr error
+	gotAttempts := 0
+	cfg := testConfig()
+	cfg.OnGiveUp = func(err error, attempts int) {
+		gotErr = err
+		gotAttempts = attempts
+	}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error { return errDown })
+
+	if gotErr != err {
+		t.Errorf("expected OnGiveUp to receive the returned error, got %v", gotErr)
+	}
+	if !errors.Is(gotErr, ErrMaxAttemptsReached) {
+		t.Errorf("expected ErrMaxAttemptsReached, got %v", gotErr)
+	}
+	if gotAttempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", gotAttempts)
+	}
+}
+
+func TestDoOnGiveUpBudgetExhausted(t *testing.T) {
+	gaveUp := 0
+	cfg := &Config{
+		MaxAttempts:    5,
+		Strategy:       NewConstantBackoff(time.Hour),
+		MaxElapsedTime: time.Second,
+		OnGiveUp:       func(err error, attempts int) { gaveUp = attempts },
+	}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		return errors.New("timeout")
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if gaveUp != 1 {
+		t.Errorf("expected OnGiveUp after 1 attempt, got %d", gaveUp)
+	}
+}
+
+func TestDoOnGiveUpWithoutRetries(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	gaveUp := 0
+	metrics := &Metrics{}
+	cfg := &Config{
+		Strategy: NewConstantBackoff(time.Millisecond),
+		Metrics:  metrics,
+		OnGiveUp: func(err error, attempts int) { gaveUp = attempts },
+	}
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error { return errDown })
+
+	if err != errDown {
+		t.Errorf("expected the original error back, got %v", err)
+	}
+	if gaveUp != 1 {
+		t.Errorf("expected OnGiveUp after 1 attempt, got %d", gaveUp)
+	}
+	if got := metrics.Snapshot().GiveUps; got != 1 {
+		t.Errorf("expected 1 give-up, got %d", got)
+	}
+}
+
+func TestDoOnGiveUpContextCancelled(t *testing.T) {
+	errDown := errors.New("connection refused")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var gotErr error
+	cfg := &Config{
+		MaxAttempts: 5,
+		Strategy:    NewConstantBackoff(time.Hour),
+		OnGiveUp:    func(err error, attempts int) { gotErr = err },
+	}
+
+	err := Do(ctx, cfg, func(ctx context.Context) error {
+		cancel()
+		return errDown
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !errors.Is(err, errDown) {
+		t.Errorf("expected the last attempt's error, got %v", err)
+	}
+	if gotErr != err {
+		t.Errorf("expected OnGiveUp to receive the returned error, got %v", gotErr)
+	}
+}
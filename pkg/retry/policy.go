@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrPolicyNotFound is returned by Policy for a name that was never
+// registered.
+var ErrPolicyNotFound = fault.New(
+	"retry policy not found",
+	fault.WithCode(fault.NotFound),
+)
+
+var policies = struct {
+	sync.RWMutex
+	byName map[string]*Config
+}{byName: make(map[string]*Config)}
+
+// RegisterPolicy stores config under name (case-insensitive), replacing any
+// previous policy with that name. Services register their policies once at
+// startup and packages look them up with Policy, instead of each package
+// reading its own backoff variables.
+func RegisterPolicy(name string, config *Config) error {
+	if config == nil {
+		return fault.Wrap(ErrInvalidConfig, "policy config cannot be nil",
+			fault.WithContext("policy", name),
+		)
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	policy := *config
+	policies.Lock()
+	policies.byName[strings.ToLower(name)] = &policy
+	policies.Unlock()
+	return nil
+}
+
+// RegisterPolicyFromEnv registers the policy loaded by LoadPolicyConfig.
+func RegisterPolicyFromEnv(name string) error {
+	config, err := LoadPolicyConfig(name).ToConfig()
+	if err != nil {
+		return fault.Wrap(ErrInvalidConfig, err.Error(),
+			fault.WithContext("policy", name),
+		)
+	}
+	return RegisterPolicy(name, config)
+}
+
+// Policy returns a copy of the policy registered under name, so callers can
+// set Logger, OnRetry or RetryIf without affecting other users. The Strategy,
+// Breaker and Metrics are shared; Do keeps a separate delay sequence per call
+// for strategies that carry one, such as DecorrelatedJitterBackoff.
+func Policy(name string) (*Config, error) {
+	policies.RLock()
+	policy, ok := policies.byName[strings.ToLower(name)]
+	policies.RUnlock()
+
+	if !ok {
+		return nil, fault.Wrap(ErrPolicyNotFound, "no policy registered under this name",
+			fault.WithContext("policy", name),
+		)
+	}
+
+	config := *policy
+	return &config, nil
+}
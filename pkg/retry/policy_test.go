@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPolicyRegistry(t *testing.T) {
+	cfg := &Config{MaxAttempts: 5, Strategy: NewConstantBackoff(time.Millisecond)}
+	if err := RegisterPolicy("Redis", cfg); err != nil {
+		t.Fatalf("RegisterPolicy() error = %v", err)
+	}
+
+	policy, err := Policy("redis")
+	if err != nil {
+		t.Fatalf("Policy() error = %v", err)
+	}
+	if policy.MaxAttempts != 5 {
+		t.Errorf("expected 5 attempts, got %d", policy.MaxAttempts)
+	}
+
+	// Callers get their own copy.
+	policy.MaxAttempts = 1
+	cfg.MaxAttempts = 1
+	again, _ := Policy("REDIS")
+	if again.MaxAttempts != 5 {
+		t.Errorf("expected registered policy to be unaffected, got %d", again.MaxAttempts)
+	}
+
+	if _, err := Policy("kafka"); !errors.Is(err, ErrPolicyNotFound) {
+		t.Errorf("expected ErrPolicyNotFound, got %v", err)
+	}
+}
+
+func TestRegisterPolicyInvalid(t *testing.T) {
+	if err := RegisterPolicy("broken", &Config{MaxAttempts: 3}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for missing strategy, got %v", err)
+	}
+	if err := RegisterPolicy("broken", nil); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for nil config, got %v", err)
+	}
+}
+
+func TestRegisterPolicyFromEnv(t *testing.T) {
+	os.Setenv("RETRY_MAX_ATTEMPTS", "4")
+	os.Setenv("RETRY_BACKOFF_TYPE", "constant")
+	os.Setenv("RETRY_DB_PRIMARY_MAX_ATTEMPTS", "7")
+	defer os.Unsetenv("RETRY_MAX_ATTEMPTS")
+	defer os.Unsetenv("RETRY_BACKOFF_TYPE")
+	defer os.Unsetenv("RETRY_DB_PRIMARY_MAX_ATTEMPTS")
+
+	rc := LoadPolicyConfig("db-primary")
+	if rc.MaxAttempts != 7 {
+		t.Errorf("expected policy override of 7 attempts, got %d", rc.MaxAttempts)
+	}
+	if rc.Backoff.Type != "constant" {
+		t.Errorf("expected RETRY_* fallback for backoff type, got %s", rc.Backoff.Type)
+	}
+
+	if err := RegisterPolicyFromEnv("db-primary"); err != nil {
+		t.Fatalf("RegisterPolicyFromEnv() error = %v", err)
+	}
+	policy, err := Policy("db-primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := policy.Strategy.(*ConstantBackoff); !ok {
+		t.Errorf("expected constant backoff, got %T", policy.Strategy)
+	}
+}
@@ -2,6 +2,8 @@ package retry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -15,6 +17,13 @@ var (
 		fault.WithCode(fault.Invalid),
 	)
 
+	// ErrBudgetExhausted is returned when the next attempt would start after
+	// the context deadline or Config.MaxElapsedTime.
+	ErrBudgetExhausted = fault.New(
+		"retry budget exhausted",
+		fault.WithCode(fault.InfraError),
+	)
+
 	// ErrInvalidConfig is returned when retry configuration is invalid.
 	ErrInvalidConfig = fault.New(
 		"invalid retry configuration",
@@ -33,6 +42,14 @@ type Strategy interface {
 	Reset()
 }
 
+// sequenceStrategy is a Strategy whose delays depend on the ones it returned
+// before. Do takes a fresh sequence for every call, so concurrent calls that
+// share a Config or a policy do not advance each other's state.
+type sequenceStrategy interface {
+	Strategy
+	newSequence() Strategy
+}
+
 // RetryableFunc represents a function that can be retried.
 // It returns an error to indicate whether the operation should be retried.
 type RetryableFunc func(ctx context.Context) error
@@ -45,14 +62,200 @@ type Config struct {
 	// Strategy defines how retry delays are calculated.
 	Strategy Strategy
 
+	// MaxElapsedTime caps the total time spent in Do, attempts and delays
+	// included (0 means no limit besides the context deadline).
+	MaxElapsedTime time.Duration
+
+	// AttemptTimeout bounds each call to the RetryableFunc with its own
+	// context timeout, so one hung attempt cannot consume the whole budget
+	// (0 means attempts only inherit the parent context).
+	AttemptTimeout time.Duration
+
+	// RetryIf decides whether an error is worth retrying. If nil, every
+	// error except those wrapped with Permanent is retried.
+	RetryIf func(err error) bool
+
+	// DelayFromError extracts a server-provided delay (Retry-After, rate
+	// limit reset) from the last error. When it returns true, that delay
+	// replaces the strategy's for the next retry. Errors wrapped with
+	// RetryAfter are honoured even when it is nil.
+	DelayFromError func(err error) (time.Duration, bool)
+
+	// Breaker guards every attempt. While it is open, attempts fail fast
+	// with ErrCircuitOpen, which is never retried. Share one Breaker per
+	// dependency across calls.
+	Breaker *Breaker
+
 	// OnRetry is called before each retry attempt.
 	// The attempt parameter starts at 0 for the first retry.
 	OnRetry func(attempt int, err error)
 
+	// OnGiveUp is called when Do stops while the error is still retryable
+	// (attempts ran out, the budget was exhausted or the context ended),
+	// with the error Do returns and how many times fn was called.
+	OnGiveUp func(err error, attempts int)
+
+	// Metrics counts attempts, successes after retry and give-ups. Share
+	// one per dependency.
+	Metrics *Metrics
+
 	// Logger for retry operations. If nil, uses slog.Default().
 	Logger *slog.Logger
 }
 
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps err so Do returns it immediately instead of retrying,
+// e.g. for validation failures, 4xx responses or unique violations. Do
+// returns the original err, unwrapped. A nil err stays nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// retryAfterError carries the delay a server asked for.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter wraps err with the delay the server asked for, e.g. from a
+// Retry-After header, so Do waits that long before the next attempt instead
+// of the computed backoff. A nil err stays nil.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// RetryAfterDelay returns the delay attached to err with RetryAfter.
+func RetryAfterDelay(err error) (time.Duration, bool) {
+	var retryAfter *retryAfterError
+	if !errors.As(err, &retryAfter) {
+		return 0, false
+	}
+	return retryAfter.delay, true
+}
+
+// nextDelay returns the strategy's delay for attempt, overridden by a
+// server-provided delay found in err. The strategy is always consulted so
+// stateful strategies keep their sequence.
+func (c *Config) nextDelay(attempt int, err error) time.Duration {
+	delay := c.Strategy.NextDelay(attempt)
+
+	if c.DelayFromError != nil {
+		if hinted, ok := c.DelayFromError(err); ok {
+			return max(0, hinted)
+		}
+	}
+	if hinted, ok := RetryAfterDelay(err); ok {
+		return max(0, hinted)
+	}
+
+	return delay
+}
+
+// retryable reports whether Do should retry after err.
+func (c *Config) retryable(err error) bool {
+	if IsPermanent(err) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if c.RetryIf != nil {
+		return c.RetryIf(err)
+	}
+	return true
+}
+
+// remaining returns how much time is left before the context deadline or
+// the MaxElapsedTime budget, whichever comes first. ok is false when
+// neither applies.
+func (c *Config) remaining(ctx context.Context, start time.Time) (time.Duration, bool) {
+	var left time.Duration
+	ok := false
+
+	if deadline, has := ctx.Deadline(); has {
+		left, ok = time.Until(deadline), true
+	}
+	if c.MaxElapsedTime > 0 {
+		budget := c.MaxElapsedTime - time.Since(start)
+		if !ok || budget < left {
+			left, ok = budget, true
+		}
+	}
+
+	return left, ok
+}
+
+// attempt runs fn once, through the Breaker and under AttemptTimeout when
+// set.
+func (c *Config) attempt(ctx context.Context, fn RetryableFunc) error {
+	if c.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.AttemptTimeout)
+		defer cancel()
+	}
+
+	if c.Breaker != nil {
+		return c.Breaker.Execute(ctx, fn)
+	}
+	return fn(ctx)
+}
+
+// giveUp records that Do stopped retrying and returns err.
+func (c *Config) giveUp(err error, attempts int) error {
+	c.Metrics.giveUp()
+	if c.OnGiveUp != nil {
+		c.OnGiveUp(err, attempts)
+	}
+	return err
+}
+
+// cancelled wraps both the context error and the error of the last attempt,
+// so errors.Is matches either.
+func cancelled(ctx context.Context, lastErr error, message string, attempt, maxAttempts int) error {
+	return fault.Wrap(fmt.Errorf("%w (last error: %w)", ctx.Err(), lastErr), message,
+		fault.WithContext("attempt", attempt),
+		fault.WithContext("max_attempts", maxAttempts),
+	)
+}
+
+// unwrapPermanent strips the Permanent marker so callers see the original
+// error.
+func unwrapPermanent(err error) error {
+	if permanent, ok := err.(*permanentError); ok {
+		return permanent.err
+	}
+	return err
+}
+
 // Validate checks if the retry configuration is valid.
 func (c *Config) Validate() error {
 	if c.MaxAttempts < 0 {
@@ -63,28 +266,59 @@ func (c *Config) Validate() error {
 	if c.Strategy == nil {
 		return fault.Wrap(ErrInvalidConfig, "strategy cannot be nil")
 	}
+	if c.MaxElapsedTime < 0 {
+		return fault.Wrap(ErrInvalidConfig, "max elapsed time must be non-negative",
+			fault.WithContext("max_elapsed_time", c.MaxElapsedTime.String()),
+		)
+	}
+	if c.AttemptTimeout < 0 {
+		return fault.Wrap(ErrInvalidConfig, "attempt timeout must be non-negative",
+			fault.WithContext("attempt_timeout", c.AttemptTimeout.String()),
+		)
+	}
 	return nil
 }
 
 // Do executes the given function with retries according to the configuration.
-// It returns the last error encountered if all attempts fail.
+// It returns the last error encountered if all attempts fail. Errors that are
+// not retryable (see Permanent and Config.RetryIf) are returned immediately,
+// unwrapped. Do never sleeps past the context deadline or MaxElapsedTime:
+// when the next delay would, it stops with ErrBudgetExhausted. An attempt
+// cut off by AttemptTimeout fails with context.DeadlineExceeded and is
+// retried like any other error while the parent context is still live.
+// Server-provided delays (see RetryAfter and Config.DelayFromError) replace
+// the computed backoff but are still bound by the budget.
 func Do(ctx context.Context, config *Config, fn RetryableFunc) error {
 	if err := config.Validate(); err != nil {
 		return err
 	}
 
+	if strategy, ok := config.Strategy.(sequenceStrategy); ok {
+		perCall := *config
+		perCall.Strategy = strategy.newSequence()
+		config = &perCall
+	}
+
 	logger := config.Logger
 	if logger == nil {
 		logger = slog.Default()
 	}
 
-	err := fn(ctx)
+	start := time.Now()
+
+	err := config.attempt(ctx, fn)
+	config.Metrics.attempt(false)
 	if err == nil {
 		return nil
 	}
 
+	if !config.retryable(err) {
+		logger.Debug("Error is not retryable", "error", err.Error())
+		return unwrapPermanent(err)
+	}
+
 	if config.MaxAttempts == 0 {
-		return err
+		return config.giveUp(err, 1)
 	}
 
 	logger.Debug("Starting retry attempts",
@@ -94,18 +328,30 @@ func Do(ctx context.Context, config *Config, fn RetryableFunc) error {
 
 	for attempt := 0; attempt < config.MaxAttempts; attempt++ {
 		if ctx.Err() != nil {
-			return fault.Wrap(ctx.Err(), "context cancelled during retry",
-				fault.WithContext("attempt", attempt),
-				fault.WithContext("max_attempts", config.MaxAttempts),
+			return config.giveUp(cancelled(ctx, err, "context cancelled during retry", attempt, config.MaxAttempts), attempt+1)
+		}
+
+		delay := config.nextDelay(attempt, err)
+
+		if left, ok := config.remaining(ctx, start); ok && delay >= left {
+			logger.Warn("Retry budget exhausted",
+				"attempt", attempt+1,
+				"delay_ms", delay.Milliseconds(),
+				"remaining_ms", left.Milliseconds(),
+				"error", err.Error(),
 			)
+			return config.giveUp(fault.Wrap(ErrBudgetExhausted, "next attempt would exceed the deadline",
+				fault.WithContext("attempt", attempt),
+				fault.WithContext("delay", delay.String()),
+				fault.WithContext("remaining", left.String()),
+				fault.WithContext("last_error", err.Error()),
+			), attempt+1)
 		}
 
 		if config.OnRetry != nil {
 			config.OnRetry(attempt, err)
 		}
 
-		delay := config.Strategy.NextDelay(attempt)
-
 		logger.Debug("Retrying after delay",
 			"attempt", attempt+1,
 			"max_attempts", config.MaxAttempts,
@@ -114,21 +360,28 @@ func Do(ctx context.Context, config *Config, fn RetryableFunc) error {
 
 		select {
 		case <-ctx.Done():
-			return fault.Wrap(ctx.Err(), "context cancelled during retry delay",
-				fault.WithContext("attempt", attempt),
-				fault.WithContext("max_attempts", config.MaxAttempts),
-			)
+			return config.giveUp(cancelled(ctx, err, "context cancelled during retry delay", attempt, config.MaxAttempts), attempt+1)
 		case <-time.After(delay):
 		}
 
-		err = fn(ctx)
+		err = config.attempt(ctx, fn)
+		config.Metrics.attempt(true)
 		if err == nil {
+			config.Metrics.successAfterRetry()
 			logger.Debug("Retry succeeded",
 				"attempt", attempt+1,
 				"total_attempts", attempt+2,
 			)
 			return nil
 		}
+
+		if !config.retryable(err) {
+			logger.Debug("Error is not retryable",
+				"attempt", attempt+1,
+				"error", err.Error(),
+			)
+			return unwrapPermanent(err)
+		}
 	}
 
 	logger.Warn("All retry attempts failed",
@@ -136,8 +389,8 @@ func Do(ctx context.Context, config *Config, fn RetryableFunc) error {
 		"error", err.Error(),
 	)
 
-	return fault.Wrap(ErrMaxAttemptsReached, "all retry attempts failed",
+	return config.giveUp(fault.Wrap(ErrMaxAttemptsReached, "all retry attempts failed",
 		fault.WithContext("attempts", config.MaxAttempts),
 		fault.WithWrappedErr(err),
-	)
+	), config.MaxAttempts+1)
 }
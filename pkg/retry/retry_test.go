@@ -0,0 +1,295 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func testConfig() *Config {
+	return &Config{
+		MaxAttempts: 3,
+		Strategy:    NewConstantBackoff(time.Millisecond),
+	}
+}
+
+func TestDoPermanent(t *testing.T) {
+	errInvalid := errors.New("invalid enrollment")
+	calls := 0
+
+	err := Do(context.Background(), testConfig(), func(ctx context.Context) error {
+		calls++
+		return Permanent(errInvalid)
+	})
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if err != errInvalid {
+		t.Errorf("expected the original error back, got %v", err)
+	}
+}
+
+func TestDoPermanentAfterRetries(t *testing.T) {
+	errConflict := errors.New("duplicate key")
+	calls := 0
+
+	err := Do(context.Background(), testConfig(), func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("connection reset")
+		}
+		return fmt.Errorf("insert: %w", Permanent(errConflict))
+	})
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if !errors.Is(err, errConflict) || !IsPermanent(err) {
+		t.Errorf("expected wrapped permanent error, got %v", err)
+	}
+}
+
+func TestDoRetryIf(t *testing.T) {
+	errTransient := errors.New("timeout")
+	errClient := errors.New("bad request")
+
+	cfg := testConfig()
+	cfg.RetryIf = func(err error) bool {
+		return errors.Is(err, errTransient)
+	}
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return errClient
+	})
+	if calls != 1 || err != errClient {
+		t.Errorf("expected non-retryable error after 1 call, got %d calls and %v", calls, err)
+	}
+
+	calls = 0
+	err = Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return errTransient
+	})
+	if calls != cfg.MaxAttempts+1 {
+		t.Errorf("expected %d calls, got %d", cfg.MaxAttempts+1, calls)
+	}
+	if !errors.Is(err, ErrMaxAttemptsReached) {
+		t.Errorf("expected ErrMaxAttemptsReached, got %v", err)
+	}
+}
+
+func TestPermanentNil(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Error("expected Permanent(nil) to be nil")
+	}
+	if IsPermanent(errors.New("plain")) {
+		t.Error("expected plain error not to be permanent")
+	}
+}
+
+func TestDoStopsBeforeContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	cfg := &Config{
+		MaxAttempts: 3,
+		Strategy:    NewConstantBackoff(time.Second),
+	}
+
+	calls := 0
+	start := time.Now()
+	err := Do(ctx, cfg, func(ctx context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("expected Do to return without sleeping, took %v", elapsed)
+	}
+}
+
+func TestDoMaxElapsedTime(t *testing.T) {
+	cfg := &Config{
+		MaxAttempts:    10,
+		MaxElapsedTime: 35 * time.Millisecond,
+		Strategy:       NewConstantBackoff(10 * time.Millisecond),
+	}
+
+	retries := 0
+	cfg.OnRetry = func(attempt int, err error) { retries++ }
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if calls < 2 || calls > 4 {
+		t.Errorf("expected the budget to allow 2-4 calls, got %d", calls)
+	}
+	if retries != calls-1 {
+		t.Errorf("expected OnRetry only for attempts that ran, got %d retries for %d calls", retries, calls)
+	}
+}
+
+func TestDoAttemptTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.AttemptTimeout = 10 * time.Millisecond
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			<-ctx.Done() // first attempt hangs until its own timeout
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("expected the second attempt to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDoAttemptTimeoutKeepsParentDeadline(t *testing.T) {
+	cfg := testConfig()
+	cfg.AttemptTimeout = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := Do(ctx, cfg, func(attemptCtx context.Context) error {
+		deadline, ok := attemptCtx.Deadline()
+		parent, _ := ctx.Deadline()
+		if !ok || deadline.After(parent) {
+			t.Errorf("expected attempt deadline no later than the parent's")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateNegativeAttemptTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.AttemptTimeout = -time.Second
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	cfg := testConfig()
+	cfg.Strategy = NewConstantBackoff(time.Hour)
+
+	calls := 0
+	start := time.Now()
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls == 1 {
+			return RetryAfter(errors.New("429 too many requests"), 5*time.Millisecond)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the server delay to replace the backoff, took %v", elapsed)
+	}
+}
+
+func TestDoDelayFromError(t *testing.T) {
+	errThrottled := errors.New("throttled")
+
+	cfg := testConfig()
+	cfg.Strategy = NewConstantBackoff(time.Hour)
+	cfg.DelayFromError = func(err error) (time.Duration, bool) {
+		if errors.Is(err, errThrottled) {
+			return 2 * time.Millisecond, true
+		}
+		return 0, false
+	}
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errThrottled
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDoRetryAfterBeyondBudget(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxElapsedTime = 50 * time.Millisecond
+
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		return RetryAfter(errors.New("503 service unavailable"), time.Minute)
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	err := fmt.Errorf("fetch cep: %w", RetryAfter(errors.New("429"), 3*time.Second))
+
+	delay, ok := RetryAfterDelay(err)
+	if !ok || delay != 3*time.Second {
+		t.Errorf("expected 3s, got %v (ok=%v)", delay, ok)
+	}
+	if _, ok := RetryAfterDelay(errors.New("plain")); ok {
+		t.Error("expected no delay on a plain error")
+	}
+	if RetryAfter(nil, time.Second) != nil {
+		t.Error("expected RetryAfter(nil) to be nil")
+	}
+}
+
+func TestDoKeepsSharedStrategyState(t *testing.T) {
+	strategy := NewDecorrelatedJitterBackoff(time.Millisecond, 5*time.Millisecond)
+	strategy.sleep = 4 * time.Millisecond
+
+	cfg := testConfig()
+	cfg.Strategy = strategy
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error {
+		return errors.New("temporary")
+	})
+
+	if strategy.sleep != 4*time.Millisecond {
+		t.Errorf("expected Do to leave the shared strategy alone, got sleep %v", strategy.sleep)
+	}
+	if cfg.Strategy != strategy {
+		t.Error("expected Do to leave the config's strategy in place")
+	}
+}
@@ -1,17 +1,27 @@
 package validation
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+	"github.com/marcelofabianov/fault"
 	"github.com/marcelofabianov/wisp"
 )
 
 func RegisterBrazilianValidators(v Validator) error {
 	validators := map[string]validator.Func{
-		"cpf":   validateCPF,
-		"cnpj":  validateCNPJ,
-		"cep":   validateCEP,
-		"phone": validatePhone,
-		"email": validateEmail,
+		"cpf":            validateDocument(IsCPF),
+		"cnpj":           validateDocument(IsCNPJ),
+		"cep":            validateDocument(IsCEP),
+		"phone":          validateDocument(IsPhone),
+		"email":          validateEmail,
+		"pis":            validateDocument(IsPIS),
+		"cnh":            validateDocument(IsCNH),
+		"renavam":        validateDocument(IsRENAVAM),
+		"placa":          validateDocument(IsPlaca),
+		"titulo_eleitor": validateDocument(IsTituloEleitor),
+		"ie":             validateIE,
 	}
 
 	for tag, fn := range validators {
@@ -23,46 +33,90 @@ func RegisterBrazilianValidators(v Validator) error {
 	return nil
 }
 
-func validateCPF(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	if value == "" {
-		return true
-	}
+// ErrInvalidDocument is wrapped by the Validate* document functions.
+var ErrInvalidDocument = fault.New(
+	"invalid document",
+	fault.WithCode(fault.Invalid),
+)
 
+// IsCPF reports whether value is a valid CPF. The Is* functions check a
+// single value without a Validator, e.g. in CSV imports; unlike the tags,
+// empty values are invalid (wisp accepts them as the zero value).
+func IsCPF(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
+	}
 	_, err := wisp.NewCPF(value)
 	return err == nil
 }
 
-func validateCNPJ(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	if value == "" {
-		return true
+// IsCNPJ reports whether value is a valid CNPJ.
+func IsCNPJ(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
 	}
-
 	_, err := wisp.NewCNPJ(value)
 	return err == nil
 }
 
-func validateCEP(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	if value == "" {
-		return true
+// IsCEP reports whether value is a valid CEP.
+func IsCEP(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
 	}
-
 	_, err := wisp.NewCEP(value)
 	return err == nil
 }
 
-func validatePhone(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	if value == "" {
-		return true
+// IsPhone reports whether value is a valid Brazilian phone number.
+func IsPhone(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
 	}
-
 	_, err := wisp.NewPhone(value)
 	return err == nil
 }
 
+// ValidateCPF is IsCPF returning a fault error that wraps
+// ErrInvalidDocument, with the document tag in its context. The other
+// Validate* functions do the same for their document.
+func ValidateCPF(value string) error { return checkDocument("cpf", value, IsCPF) }
+
+func ValidateCNPJ(value string) error { return checkDocument("cnpj", value, IsCNPJ) }
+
+func ValidateCEP(value string) error { return checkDocument("cep", value, IsCEP) }
+
+func ValidatePhone(value string) error { return checkDocument("phone", value, IsPhone) }
+
+func ValidatePIS(value string) error { return checkDocument("pis", value, IsPIS) }
+
+func ValidateCNH(value string) error { return checkDocument("cnh", value, IsCNH) }
+
+func ValidateRENAVAM(value string) error { return checkDocument("renavam", value, IsRENAVAM) }
+
+func ValidatePlaca(value string) error { return checkDocument("placa", value, IsPlaca) }
+
+func ValidateTituloEleitor(value string) error {
+	return checkDocument("titulo_eleitor", value, IsTituloEleitor)
+}
+
+func ValidateInscricaoEstadual(value, uf string) error {
+	return checkDocument("ie", value, func(v string) bool { return IsInscricaoEstadual(v, uf) },
+		fault.WithContext("uf", uf),
+	)
+}
+
+func checkDocument(tag, value string, valid func(string) bool, opts ...fault.Option) error {
+	if valid(value) {
+		return nil
+	}
+	opts = append([]fault.Option{
+		fault.WithCode(fault.Invalid),
+		fault.WithContext("document", tag),
+	}, opts...)
+	return fault.Wrap(ErrInvalidDocument, defaultTemplatesEN[tag], opts...)
+}
+
 func validateEmail(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	if value == "" {
@@ -72,3 +126,40 @@ func validateEmail(fl validator.FieldLevel) bool {
 	_, err := wisp.NewEmail(value)
 	return err == nil
 }
+
+// validateDocument adapts a check digit function to a validator.Func;
+// empty values are left to required.
+func validateDocument(valid func(string) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if value == "" {
+			return true
+		}
+		return valid(value)
+	}
+}
+
+// validateIE checks an inscrição estadual against the UF in the tag
+// parameter, either a UF ("ie=SP") or the name of a sibling field holding
+// it ("ie=State").
+func validateIE(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+
+	uf := fl.Param()
+	if _, ok := ieValidators[strings.ToUpper(uf)]; !ok {
+		parent := reflect.Indirect(fl.Parent())
+		if parent.Kind() != reflect.Struct {
+			return false
+		}
+		field, kind, _, found := fl.GetStructFieldOKAdvanced2(parent, uf)
+		if !found || kind != reflect.String {
+			return false
+		}
+		uf = field.String()
+	}
+
+	return IsInscricaoEstadual(value, uf)
+}
@@ -0,0 +1,206 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type vehicleOwner struct {
+	PIS      string `json:"pis" validate:"pis"`
+	CNH      string `json:"cnh" validate:"cnh"`
+	RENAVAM  string `json:"renavam" validate:"renavam"`
+	Placa    string `json:"placa" validate:"placa"`
+	Titulo   string `json:"titulo" validate:"titulo_eleitor"`
+	State    string `json:"state"`
+	IE       string `json:"ie" validate:"ie=State"`
+	IEFixoSP string `json:"ie_sp" validate:"ie=SP"`
+}
+
+func newBrazilianValidator(t *testing.T) validation.Validator {
+	t.Helper()
+	v := newTestValidator()
+	if err := validation.RegisterBrazilianValidators(v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestBrazilianDocuments(t *testing.T) {
+	ctx := context.Background()
+	v := newBrazilianValidator(t)
+
+	valid := vehicleOwner{
+		PIS:      "120.5443.342-1",
+		CNH:      "02650306461",
+		RENAVAM:  "639884962",
+		Placa:    "ABC1D23",
+		Titulo:   "1023 8501 0671",
+		State:    "PR",
+		IE:       "123.45678-50",
+		IEFixoSP: "110.042.490.114",
+	}
+	if err := v.Struct(ctx, valid); err != nil {
+		t.Fatalf("expected valid documents, got %v", err)
+	}
+
+	t.Run("empty values are left to required", func(t *testing.T) {
+		if err := v.Struct(ctx, vehicleOwner{}); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("wrong check digits", func(t *testing.T) {
+		err := v.Struct(ctx, vehicleOwner{
+			PIS:      "120.5443.342-6",
+			CNH:      "02650306462",
+			RENAVAM:  "639884961",
+			Placa:    "AB12345",
+			Titulo:   "102385010672",
+			State:    "RJ",
+			IE:       "123.45678-50",
+			IEFixoSP: "110.042.490.115",
+		})
+
+		fields, ok := validation.AsValidationErrors(err)
+		if !ok {
+			t.Fatalf("expected validation errors, got %v", err)
+		}
+		got := map[string]string{}
+		for _, fe := range fields {
+			got[fe.Path] = fe.Message
+		}
+		for _, path := range []string{"pis", "cnh", "renavam", "placa", "titulo", "ie", "ie_sp"} {
+			if got[path] == "" {
+				t.Errorf("expected %s to fail, got %v", path, got)
+			}
+		}
+		if got["placa"] != "Placa inválida" {
+			t.Errorf("unexpected placa message %q", got["placa"])
+		}
+	})
+}
+
+func TestPlaca(t *testing.T) {
+	v := newBrazilianValidator(t)
+	for value, ok := range map[string]bool{
+		"ABC-1234": true,
+		"abc1234":  true,
+		"BRA2E19":  true,
+		"BRA-2E19": true,
+		"BR2E19":   false,
+		"ABCD123":  false,
+		"ABC12345": false,
+	} {
+		err := v.Field(context.Background(), value, "placa")
+		if (err == nil) != ok {
+			t.Errorf("placa %q: expected valid=%v, got %v", value, ok, err)
+		}
+	}
+}
+
+func TestInscricaoEstadual(t *testing.T) {
+	v := newBrazilianValidator(t)
+	samples := map[string]string{
+		"AC": "01.004.823/001-12",
+		"AL": "24000004-8",
+		"AP": "030123459",
+		"BA": "123456-63",
+		"CE": "06000001-5",
+		"DF": "07.300.001.001-09",
+		"ES": "99999999-0",
+		"GO": "10.987.654-7",
+		"MA": "12000038-5",
+		"MG": "062.307.904/0081",
+		"MT": "0013000001-9",
+		"PA": "15-999999-5",
+		"PB": "06000001-5",
+		"PE": "0321418-40",
+		"PR": "123.45678-50",
+		"RJ": "99.999.99-3",
+		"RN": "20.040.040-1",
+		"RO": "0000000062521-3",
+		"RR": "24006628-1",
+		"RS": "224/3658792",
+		"SC": "251.040.852",
+		"SE": "27123456-3",
+		"SP": "110.042.490.114",
+		"TO": "29010227836",
+	}
+
+	for uf, ie := range samples {
+		if err := v.Field(context.Background(), ie, "ie="+uf); err != nil {
+			t.Errorf("%s %s: expected valid, got %v", uf, ie, err)
+		}
+	}
+
+	for _, tc := range []struct{ uf, ie string }{
+		{"SP", "ISENTO"},
+		{"SP", "P-01100424.3/002"},
+		{"BA", "1000003-06"},
+	} {
+		if err := v.Field(context.Background(), tc.ie, "ie="+tc.uf); err != nil {
+			t.Errorf("%s %s: expected valid, got %v", tc.uf, tc.ie, err)
+		}
+	}
+
+	for _, tc := range []struct{ uf, ie string }{
+		{"SP", "110.042.490.115"},
+		{"MG", "062.307.904/0082"},
+		{"RJ", "110.042.490.114"},
+		{"XX", "110.042.490.114"},
+	} {
+		if err := v.Field(context.Background(), tc.ie, "ie="+tc.uf); err == nil {
+			t.Errorf("%s %s: expected invalid", tc.uf, tc.ie)
+		}
+	}
+}
+
+func TestStandaloneDocuments(t *testing.T) {
+	valid := map[string]func(string) bool{
+		"529.982.247-25":     validation.IsCPF,
+		"11.222.333/0001-81": validation.IsCNPJ,
+		"01310-100":          validation.IsCEP,
+		"(11) 98765-4321":    validation.IsPhone,
+		"120.5443.342-1":     validation.IsPIS,
+		"ABC-1234":           validation.IsPlaca,
+	}
+	for value, isValid := range valid {
+		if !isValid(value) {
+			t.Errorf("expected %q to be valid", value)
+		}
+		if isValid("") {
+			t.Errorf("expected empty value to be invalid for %q's check", value)
+		}
+	}
+
+	if validation.IsCPF("529.982.247-26") {
+		t.Error("expected wrong check digit to be invalid")
+	}
+
+	t.Run("error variants", func(t *testing.T) {
+		if err := validation.ValidateCPF("529.982.247-25"); err != nil {
+			t.Errorf("ValidateCPF() error = %v", err)
+		}
+
+		err := validation.ValidateCNPJ("11.222.333/0001-80")
+		if !errors.Is(err, validation.ErrInvalidDocument) {
+			t.Fatalf("expected ErrInvalidDocument, got %v", err)
+		}
+		var fErr *fault.Error
+		if !errors.As(err, &fErr) || fErr.Context["document"] != "cnpj" || fErr.Code != fault.Invalid {
+			t.Errorf("expected invalid cnpj fault, got %+v", fErr)
+		}
+
+		if err := validation.ValidateInscricaoEstadual("110.042.490.114", "SP"); err != nil {
+			t.Errorf("ValidateInscricaoEstadual() error = %v", err)
+		}
+		if err := validation.ValidateInscricaoEstadual("110.042.490.114", "RJ"); err == nil {
+			t.Error("expected SP number to be invalid for RJ")
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// itemsPath is the root of the paths reported by ValidateSlice and
+// ValidateMap, e.g. "items[3].email".
+const itemsPath = "items"
+
+// ValidateSlice validates every struct of a bulk payload and reports the
+// failures of all items at once, each path prefixed with its index
+// ("items[3].email"). The error has the same shape as the one of Struct.
+// Items are sanitized in place.
+func ValidateSlice[T any](ctx context.Context, v Validator, items []T) error {
+	var failures ValidationErrors
+	for i := range items {
+		path := fmt.Sprintf("%s[%d]", itemsPath, i)
+		errs, err := elementErrors(ctx, v, path, &items[i])
+		if err != nil {
+			return err
+		}
+		failures = append(failures, errs...)
+	}
+
+	return collectionError(ctx, v, failures, len(items))
+}
+
+// ValidateMap is ValidateSlice for payloads keyed by id; paths use the key
+// ("items[abc].email") and failures are sorted by it.
+func ValidateMap[K comparable, T any](ctx context.Context, v Validator, items map[K]T) error {
+	keys := make([]K, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	var failures ValidationErrors
+	for _, k := range keys {
+		item := items[k]
+		path := fmt.Sprintf("%s[%v]", itemsPath, k)
+		errs, err := elementErrors(ctx, v, path, &item)
+		if err != nil {
+			return err
+		}
+		items[k] = item
+		failures = append(failures, errs...)
+	}
+
+	return collectionError(ctx, v, failures, len(items))
+}
+
+// elementErrors validates one item and prefixes the failure paths with its
+// position in the collection.
+func elementErrors(ctx context.Context, v Validator, path string, item any) (ValidationErrors, error) {
+	// item points into the collection; slices of pointers are validated
+	// through the element itself.
+	if elem := reflect.ValueOf(item).Elem(); elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return nil, fault.Wrap(ErrInvalidInput, "item cannot be nil",
+				fault.WithContext("path", path),
+			)
+		}
+		item = elem.Interface()
+	}
+
+	var errs ValidationErrors
+	if vi, ok := v.(*validatorImpl); ok {
+		s, err := vi.sanitizer.sanitize(item)
+		if err != nil {
+			return nil, err
+		}
+		if errs, err = vi.collect(ctx, vi.validate, s, SeverityError); err != nil {
+			return nil, err
+		}
+	} else if err := v.Struct(ctx, item); err != nil {
+		var ok bool
+		if errs, ok = AsValidationErrors(err); !ok {
+			return nil, err
+		}
+	}
+
+	for i := range errs {
+		errs[i].Path = path + "." + errs[i].Path
+	}
+	return errs, nil
+}
+
+func collectionError(ctx context.Context, v Validator, failures ValidationErrors, count int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if vi, ok := v.(*validatorImpl); ok && vi.config.EnableLogging {
+		vi.logger.ErrorContext(ctx, "Collection validation failed",
+			"items", count,
+			"errors", len(failures),
+		)
+	}
+
+	return failures.Fault(fault.WithContext("item_count", count))
+}
@@ -0,0 +1,97 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type bulkItem struct {
+	Name  string `json:"name" mod:"trim" validate:"required,min=3"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestValidateSlice(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	t.Run("reports failures with element indexes", func(t *testing.T) {
+		items := []bulkItem{
+			{Name: "Ana Maria", Email: "ana@example.com"},
+			{Name: "Bia", Email: "invalid"},
+			{Name: "  Jo ", Email: "jo@example.com"},
+		}
+
+		err := validation.ValidateSlice(ctx, v, items)
+		if !errors.Is(err, validation.ErrValidationFailed) {
+			t.Fatalf("expected ErrValidationFailed, got %v", err)
+		}
+
+		fields, ok := validation.AsValidationErrors(err)
+		if !ok {
+			t.Fatalf("expected ValidationErrors in %v", err)
+		}
+		paths := make([]string, 0, len(fields))
+		for _, fe := range fields {
+			paths = append(paths, fe.Path)
+		}
+		if got := strings.Join(paths, ","); got != "items[1].email,items[2].name" {
+			t.Errorf("unexpected paths: %s", got)
+		}
+		if items[2].Name != "Jo" {
+			t.Errorf("expected items to be sanitized in place, got %q", items[2].Name)
+		}
+	})
+
+	t.Run("valid items", func(t *testing.T) {
+		items := []*bulkItem{{Name: "Ana", Email: "ana@example.com"}}
+		if err := validation.ValidateSlice(ctx, v, items); err != nil {
+			t.Errorf("ValidateSlice() error = %v", err)
+		}
+	})
+
+	t.Run("nil items are invalid input", func(t *testing.T) {
+		err := validation.ValidateSlice(ctx, v, []*bulkItem{nil})
+		if !errors.Is(err, validation.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestValidateMap(t *testing.T) {
+	items := map[string]bulkItem{
+		"b": {Name: "Bia", Email: "invalid"},
+		"a": {Name: "Jo", Email: "jo@example.com"},
+	}
+
+	err := validation.ValidateMap(context.Background(), newTestValidator(), items)
+	fields, ok := validation.AsValidationErrors(err)
+	if !ok || len(fields) != 2 {
+		t.Fatalf("expected 2 failures, got %v", err)
+	}
+	if fields[0].Path != "items[a].name" || fields[1].Path != "items[b].email" {
+		t.Errorf("unexpected paths: %s, %s", fields[0].Path, fields[1].Path)
+	}
+}
+
+func TestFieldDiveIndexes(t *testing.T) {
+	v := newTestValidator()
+
+	err := v.Field(context.Background(), []string{"ana@example.com", "invalid"}, "dive,email")
+	fields, ok := validation.AsValidationErrors(err)
+	if !ok || len(fields) != 1 {
+		t.Fatalf("expected 1 failure, got %v", err)
+	}
+	if fields[0].Path != "[1]" {
+		t.Errorf("expected path [1], got %q", fields[0].Path)
+	}
+
+	err = v.Field(context.Background(), map[string]bulkItem{"x": {Name: "Ana", Email: "invalid"}}, "dive")
+	fields, ok = validation.AsValidationErrors(err)
+	if !ok || len(fields) != 1 || fields[0].Path != "[x].email" {
+		t.Errorf("expected path [x].email, got %+v", fields)
+	}
+}
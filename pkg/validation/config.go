@@ -1,11 +1,12 @@
 package validation
 
 import (
-"os"
-"path/filepath"
-"strings"
+"time"
 
+"github.com/marcelofabianov/config"
 "github.com/spf13/viper"
+
+"github.com/marcelofabianov/redact"
 )
 
 type Config struct {
@@ -13,18 +14,39 @@ EnableLogging             bool
 SanitizeSensitiveData     bool
 AdditionalSensitiveFields []string
 LogSuccessfulValidations  bool
+Locale                    string
+
+// RemoteTimeout bounds each call of a rule registered with
+// RegisterRemote without its own timeout.
+RemoteTimeout time.Duration
+
+// ResultCacheTTL, when positive, memoizes Struct results of identical
+// payloads for that long; ResultCacheSize bounds the entries.
+ResultCacheTTL  time.Duration
+ResultCacheSize int
+
+// Redactor masks struct and field values in logs and errors. LoadConfig
+// builds it from the REDACT_* rules plus AdditionalSensitiveFields; if
+// nil, redact.DefaultConfig plus AdditionalSensitiveFields is used.
+Redactor *redact.Redactor
 }
 
+// LoadConfig loads the VALIDATION_* settings with config.Load. Services
+// that load several packages should call config.Load once and use
+// LoadConfigFrom.
 func LoadConfig() (*Config, error) {
-v := viper.New()
-v.SetEnvPrefix("VALIDATION")
-v.AutomaticEnv()
-v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-if envFile := findEnvFile(); envFile != "" {
-v.SetConfigFile(envFile)
-_ = v.ReadInConfig()
+c, err := config.Load()
+if err != nil {
+return nil, err
 }
+return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom loads the validation section of c: VALIDATION_* variables
+// over the validation section of the config file, with the redact section
+// for Redactor.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+v := c.Section("validation", "VALIDATION")
 
 setDefaults(v)
 
@@ -33,6 +55,21 @@ EnableLogging:             v.GetBool("enable_logging"),
 SanitizeSensitiveData:     v.GetBool("sanitize_sensitive_data"),
 AdditionalSensitiveFields: v.GetStringSlice("additional_sensitive_fields"),
 LogSuccessfulValidations:  v.GetBool("log_successful_validations"),
+Locale:                    v.GetString("locale"),
+RemoteTimeout:             v.GetDuration("remote_timeout"),
+ResultCacheTTL:            v.GetDuration("result_cache_ttl"),
+ResultCacheSize:           v.GetInt("result_cache_size"),
+}
+
+// Start from the REDACT_* rules the logger uses, so a field added to
+// REDACT_ADDITIONAL_FIELDS is masked in logs and validation errors alike.
+if cfg.SanitizeSensitiveData {
+redactCfg, err := redact.LoadConfigFrom(c)
+if err != nil {
+return nil, err
+}
+redactCfg.Fields = append(redactCfg.Fields, cfg.AdditionalSensitiveFields...)
+cfg.Redactor = redact.New(redactCfg)
 }
 
 return cfg, nil
@@ -43,27 +80,10 @@ v.SetDefault("enable_logging", true)
 v.SetDefault("sanitize_sensitive_data", true)
 v.SetDefault("additional_sensitive_fields", []string{})
 v.SetDefault("log_successful_validations", false)
-}
-
-func findEnvFile() string {
-dir, err := os.Getwd()
-if err != nil {
-return ""
-}
-
-for i := 0; i < 5; i++ {
-envPath := filepath.Join(dir, ".env")
-if _, err := os.Stat(envPath); err == nil {
-return envPath
-}
-parent := filepath.Dir(dir)
-if parent == dir {
-break
-}
-dir = parent
-}
-
-return ""
+v.SetDefault("locale", LocalePTBR)
+v.SetDefault("remote_timeout", DefaultRemoteTimeout)
+v.SetDefault("result_cache_ttl", 0)
+v.SetDefault("result_cache_size", DefaultResultCacheSize)
 }
 
 func DefaultConfig() *Config {
@@ -72,5 +92,8 @@ EnableLogging:             true,
 SanitizeSensitiveData:     true,
 AdditionalSensitiveFields: []string{},
 LogSuccessfulValidations:  false,
+Locale:                    LocalePTBR,
+RemoteTimeout:             DefaultRemoteTimeout,
+ResultCacheSize:           DefaultResultCacheSize,
 }
 }
@@ -2,6 +2,7 @@ package validation_test
 
 import (
 "os"
+"path/filepath"
 "testing"
 
 "github.com/marcelofabianov/validation"
@@ -58,6 +59,60 @@ if !cfg.LogSuccessfulValidations {
 t.Error("expected log successful validations to be true")
 }
 })
+
+t.Run("shares REDACT_ADDITIONAL_FIELDS with the logger", func(t *testing.T) {
+t.Setenv("REDACT_ADDITIONAL_FIELDS", "pix_key")
+t.Setenv("VALIDATION_ADDITIONAL_SENSITIVE_FIELDS", "boleto_barcode")
+os.Unsetenv("VALIDATION_SANITIZE_SENSITIVE_DATA")
+
+cfg, err := validation.LoadConfig()
+if err != nil {
+t.Fatalf("LoadConfig() error = %v", err)
+}
+
+for _, field := range []string{"password", "pix_key", "boleto_barcode"} {
+if cfg.Redactor == nil || !cfg.Redactor.IsSensitive(field) {
+t.Errorf("expected %s to be sensitive", field)
+}
+}
+})
+}
+
+func TestLoadConfigFile(t *testing.T) {
+path := filepath.Join(t.TempDir(), "config.toml")
+content := `
+[validation]
+locale = "en"
+result_cache_size = 50
+
+[profiles.staging.validation]
+log_successful_validations = true
+`
+if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+t.Fatal(err)
+}
+t.Setenv("CONFIG_FILE", path)
+t.Setenv("CONFIG_PROFILE", "staging")
+t.Setenv("VALIDATION_RESULT_CACHE_SIZE", "75")
+
+cfg, err := validation.LoadConfig()
+if err != nil {
+t.Fatalf("LoadConfig() error = %v", err)
+}
+if cfg.Locale != "en" {
+t.Errorf("expected locale en from the file, got %s", cfg.Locale)
+}
+if !cfg.LogSuccessfulValidations {
+t.Error("expected the staging profile to enable successful validation logs")
+}
+if cfg.ResultCacheSize != 75 {
+t.Errorf("expected VALIDATION_RESULT_CACHE_SIZE to win over the file, got %d", cfg.ResultCacheSize)
+}
+
+t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+if _, err := validation.LoadConfig(); err == nil {
+t.Error("expected an error for a missing CONFIG_FILE")
+}
 }
 
 func TestDefaultConfig(t *testing.T) {
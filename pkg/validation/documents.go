@@ -0,0 +1,144 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Check digit algorithms of the Brazilian documents without a wisp type.
+// Every function takes the raw value; punctuation is ignored.
+
+var placaPattern = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z0-9][0-9]{2}$`)
+
+// digits returns the decimal digits of value, or nil if value has any other
+// character than digits and the usual separators.
+func digits(value string) []int {
+	ds := make([]int, 0, len(value))
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			ds = append(ds, int(r-'0'))
+		case r == '.' || r == '-' || r == '/' || r == ' ':
+		default:
+			return nil
+		}
+	}
+	return ds
+}
+
+func allEqual(ds []int) bool {
+	for _, d := range ds[1:] {
+		if d != ds[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// weightedSum multiplies ds by weights, position by position.
+func weightedSum(ds []int, weights ...int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += ds[i] * w
+	}
+	return sum
+}
+
+// mod11 is the most common check digit: 11 minus the remainder of sum,
+// with 10 and 11 becoming 0.
+func mod11(sum int) int {
+	dv := 11 - sum%11
+	if dv >= 10 {
+		return 0
+	}
+	return dv
+}
+
+// IsPIS reports whether value is a valid PIS/PASEP/NIS number.
+func IsPIS(value string) bool {
+	ds := digits(value)
+	if len(ds) != 11 || allEqual(ds) {
+		return false
+	}
+	return ds[10] == mod11(weightedSum(ds, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+}
+
+// IsCNH reports whether value is a valid CNH (driver's license) number.
+func IsCNH(value string) bool {
+	ds := digits(value)
+	if len(ds) != 11 || allEqual(ds) {
+		return false
+	}
+
+	dv1 := weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2, 1) % 11
+	discount := 0
+	if dv1 >= 10 {
+		dv1, discount = 0, 2
+	}
+
+	dv2 := weightedSum(ds, 1, 2, 3, 4, 5, 6, 7, 8, 9) % 11
+	if dv2 >= 10 {
+		dv2 = 0
+	} else {
+		dv2 -= discount
+	}
+
+	return ds[9] == dv1 && ds[10] == dv2
+}
+
+// IsRENAVAM reports whether value is a valid RENAVAM (vehicle registry)
+// number. Old 9-digit numbers are padded with zeros.
+func IsRENAVAM(value string) bool {
+	ds := digits(value)
+	if len(ds) == 9 {
+		ds = append([]int{0, 0}, ds...)
+	}
+	if len(ds) != 11 || allEqual(ds) {
+		return false
+	}
+
+	dv := weightedSum(ds, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return ds[10] == dv
+}
+
+// IsPlaca reports whether value is a vehicle plate, either the old format
+// (ABC-1234) or Mercosul (ABC1D23).
+func IsPlaca(value string) bool {
+	value = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), "-", ""))
+	return placaPattern.MatchString(value)
+}
+
+// IsTituloEleitor reports whether value is a valid título de eleitor (voter
+// registration) number.
+func IsTituloEleitor(value string) bool {
+	ds := digits(value)
+	if len(ds) != 12 || allEqual(ds) {
+		return false
+	}
+
+	uf := ds[8]*10 + ds[9]
+	if uf < 1 || uf > 28 {
+		return false
+	}
+	// SP and MG turn a 0 check digit into 1.
+	spOrMG := uf == 1 || uf == 2
+
+	checkDigit := func(sum int) int {
+		dv := sum % 11
+		switch {
+		case dv == 10:
+			return 0
+		case dv == 0 && spOrMG:
+			return 1
+		default:
+			return dv
+		}
+	}
+
+	dv1 := checkDigit(weightedSum(ds, 2, 3, 4, 5, 6, 7, 8, 9))
+	dv2 := checkDigit(ds[8]*7 + ds[9]*8 + dv1*9)
+	return ds[10] == dv1 && ds[11] == dv2
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// FieldsContextKey is the fault context key holding the ValidationErrors of
+// a failed Struct, Field, ValidateSlice or SchemaValidator.Validate call.
+// web.Error renders it as the "fields" array of the response.
+const FieldsContextKey = "fields"
+
+// ValidationErrors lists the failed rules of a validation, one entry per
+// field, in a form clients can bind to form inputs.
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+	for _, fe := range ve {
+		messages = append(messages, fmt.Sprintf("%s: %s", fe.Path, fe.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fault wraps ErrValidationFailed with the failures: the message joins them,
+// FieldsContextKey holds ve, validation_errors maps path to message and
+// every failure is also a detail of the error.
+func (ve ValidationErrors) Fault(opts ...fault.Option) *fault.Error {
+	contexts := make(map[string]interface{}, len(ve))
+	details := make([]*fault.Error, 0, len(ve))
+	for _, fe := range ve {
+		contexts[fe.Path] = fe.Message
+		details = append(details, fault.New(fe.Message,
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("field", fe.Path),
+			fault.WithContext("tag", fe.Tag),
+		))
+	}
+
+	opts = append([]fault.Option{
+		fault.WithContext(FieldsContextKey, ve),
+		fault.WithContext("validation_errors", contexts),
+		fault.WithContext("error_count", len(ve)),
+		fault.WithDetails(details...),
+		fault.WithCode(fault.Invalid),
+	}, opts...)
+
+	return fault.Wrap(ErrValidationFailed, ve.Error(), opts...)
+}
+
+// AsValidationErrors returns the failures carried by err, an error returned
+// by Struct, Field, ValidateSlice or SchemaValidator.Validate, possibly
+// wrapped since.
+func AsValidationErrors(err error) (ValidationErrors, bool) {
+	for err != nil {
+		var fErr *fault.Error
+		if !errors.As(err, &fErr) {
+			return nil, false
+		}
+		if ve, ok := fErr.Context[FieldsContextKey].(ValidationErrors); ok {
+			return ve, true
+		}
+		err = fErr.Unwrap()
+	}
+	return nil, false
+}
@@ -0,0 +1,70 @@
+package validation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/validation"
+)
+
+func TestValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	err := v.Struct(ctx, enrollmentForm{Name: "Jo", Email: "not-an-email"})
+	if !errors.Is(err, validation.ErrValidationFailed) {
+		t.Fatalf("expected ErrValidationFailed, got %v", err)
+	}
+
+	t.Run("typed failures survive wrapping", func(t *testing.T) {
+		wrapped := fault.Wrap(err, "create enrollment", fault.WithCode(fault.Invalid))
+
+		fields, ok := validation.AsValidationErrors(wrapped)
+		if !ok {
+			t.Fatal("expected validation errors")
+		}
+		if len(fields) != 2 {
+			t.Fatalf("expected 2 field errors, got %+v", fields)
+		}
+
+		byPath := map[string]validation.FieldError{}
+		for _, fe := range fields {
+			byPath[fe.Path] = fe
+		}
+		if fe := byPath["name"]; fe.Tag != "min" || fe.Param != "3" || fe.Message == "" {
+			t.Errorf("unexpected name error %+v", fe)
+		}
+		if fe := byPath["email"]; fe.Tag != "email" {
+			t.Errorf("unexpected email error %+v", fe)
+		}
+	})
+
+	t.Run("fault carries fields and details", func(t *testing.T) {
+		var fErr *fault.Error
+		if !errors.As(err, &fErr) {
+			t.Fatal("expected a fault error")
+		}
+		if len(fErr.Details) != 2 {
+			t.Errorf("expected one detail per field, got %d", len(fErr.Details))
+		}
+
+		body, _ := json.Marshal(fault.ToResponse(err).Context[validation.FieldsContextKey])
+		var fields []map[string]any
+		if jsonErr := json.Unmarshal(body, &fields); jsonErr != nil || len(fields) != 2 {
+			t.Fatalf("expected fields to encode as an array, got %s", body)
+		}
+		if fields[0]["field"] == nil || fields[0]["tag"] == nil || fields[0]["message"] == nil {
+			t.Errorf("unexpected field shape %v", fields[0])
+		}
+	})
+
+	t.Run("other errors have no fields", func(t *testing.T) {
+		if _, ok := validation.AsValidationErrors(errors.New("boom")); ok {
+			t.Error("expected no validation errors")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// dateLayout is the layout of date-only strings ("2026-03-01"), parsed in
+// UTC.
+const dateLayout = time.DateOnly
+
+var (
+	uuid7Pattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	// The first character is at most 7 so the 128-bit value does not overflow.
+	ulidPattern = regexp.MustCompile(`^(?i)[0-7][0-9a-hjkmnp-tv-z]{25}$`)
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+)
+
+// formatValidators are registered by New on every validator. Empty values
+// pass; combine with required.
+var formatValidators = map[string]validator.Func{
+	"uuid7": validatePattern(uuid7Pattern),
+	"ulid":  validatePattern(ulidPattern),
+	"slug":  validatePattern(slugPattern),
+	// date_before=today, date_before=2030-01-01 or date_before=EndDate
+	"date_before": validateDate(func(value, limit time.Time) bool { return value.Before(limit) }),
+	// date_after=now, date_after=2020-01-01 or date_after=StartDate
+	"date_after": validateDate(func(value, limit time.Time) bool { return value.After(limit) }),
+}
+
+func registerFormatValidators(v *validator.Validate) {
+	for tag, fn := range formatValidators {
+		// The tags are constant, so registration can only fail on a bug.
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func validatePattern(pattern *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if value == "" {
+			return true
+		}
+		return pattern.MatchString(value)
+	}
+}
+
+// validateDate compares a time.Time or date string field with the limit in
+// the tag parameter: "now", "today", a date or RFC 3339 timestamp, or the
+// name of a sibling field.
+func validateDate(compare func(value, limit time.Time) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		value, ok := dateValue(fl.Field())
+		if !ok {
+			return false
+		}
+		if value.IsZero() {
+			return true
+		}
+
+		limit, ok := dateLimit(fl)
+		if !ok {
+			return false
+		}
+		if limit.IsZero() {
+			// An empty sibling leaves nothing to compare with.
+			return true
+		}
+		return compare(value, limit)
+	}
+}
+
+func dateLimit(fl validator.FieldLevel) (time.Time, bool) {
+	switch param := fl.Param(); param {
+	case "now":
+		return time.Now(), true
+	case "today":
+		return time.Now().UTC().Truncate(24 * time.Hour), true
+	default:
+		if limit, ok := parseDate(param); ok {
+			return limit, true
+		}
+
+		parent := reflect.Indirect(fl.Parent())
+		if parent.Kind() != reflect.Struct {
+			return time.Time{}, false
+		}
+		field, _, _, found := fl.GetStructFieldOKAdvanced2(parent, param)
+		if !found {
+			return time.Time{}, false
+		}
+		return dateValue(field)
+	}
+}
+
+// dateValue reads a time.Time or a date string; the zero time stands for an
+// empty value.
+func dateValue(field reflect.Value) (time.Time, bool) {
+	switch v := field.Interface().(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if v == "" {
+			return time.Time{}, true
+		}
+		return parseDate(v)
+	default:
+		return time.Time{}, false
+	}
+}
+
+func parseDate(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,84 @@
+package validation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFormatTags(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	tests := []struct {
+		tag   string
+		value string
+		valid bool
+	}{
+		{"uuid7", "01890a5d-ac96-774b-bcce-b302099a8057", true},
+		{"uuid7", "550e8400-e29b-41d4-a716-446655440000", false},
+		{"ulid", "01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"ulid", "01arz3ndektsv4rrffq69g5fav", true},
+		{"ulid", "81ARZ3NDEKTSV4RRFFQ69G5FAV", false},
+		{"ulid", "01ARZ3NDEKTSV4RRFFQ69G5FAU0", false},
+		{"slug", "curso-de-go-2026", true},
+		{"slug", "Curso de Go", false},
+		{"slug", "curso--go", false},
+		{"uuid7", "", true},
+	}
+
+	for _, tt := range tests {
+		err := v.Field(ctx, tt.value, tt.tag)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s(%q): expected valid=%v, got %v", tt.tag, tt.value, tt.valid, err)
+		}
+	}
+}
+
+type periodForm struct {
+	BirthDate string     `json:"birth_date" validate:"required,date_before=today"`
+	StartDate time.Time  `json:"start_date" validate:"date_after=2020-01-01"`
+	EndDate   *time.Time `json:"end_date" validate:"omitempty,date_after=StartDate"`
+}
+
+func TestDateTags(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 6, 0)
+
+	if err := v.Struct(ctx, periodForm{BirthDate: "1990-05-17", StartDate: start, EndDate: &end}); err != nil {
+		t.Fatalf("expected valid period, got %v", err)
+	}
+
+	tooEarly := time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)
+	before := tooEarly.AddDate(0, -1, 0)
+	report, err := v.StructAll(ctx, periodForm{
+		BirthDate: time.Now().AddDate(0, 0, 2).Format(time.DateOnly),
+		StartDate: tooEarly,
+		EndDate:   &before,
+	})
+	if err != nil {
+		t.Fatalf("StructAll() error = %v", err)
+	}
+	if len(report.Errors) != 3 {
+		t.Fatalf("expected 3 errors, got %+v", report.Errors)
+	}
+	want := map[string]string{
+		"birth_date": "date_before",
+		"start_date": "date_after",
+		"end_date":   "date_after",
+	}
+	for _, fe := range report.Errors {
+		if want[fe.Path] != fe.Tag {
+			t.Errorf("unexpected failure %s %s", fe.Path, fe.Tag)
+		}
+	}
+
+	t.Run("rejects unparsable dates", func(t *testing.T) {
+		if err := v.Field(ctx, "17/05/1990", "date_before=today"); err == nil {
+			t.Error("expected error for a non ISO date")
+		}
+	})
+}
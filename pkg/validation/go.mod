@@ -3,17 +3,21 @@ module github.com/marcelofabianov/validation
 go 1.25.1
 
 require (
+	github.com/go-playground/locales v0.14.1
+	github.com/go-playground/universal-translator v0.18.1
 	github.com/go-playground/validator/v10 v10.27.0
+	github.com/marcelofabianov/config v0.0.0
 	github.com/marcelofabianov/fault v1.5.0
+	github.com/marcelofabianov/redact v0.0.0
 	github.com/marcelofabianov/wisp v1.10.8
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
 	github.com/spf13/viper v1.21.0
+	golang.org/x/text v0.29.0
 )
 
 require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
-	github.com/go-playground/locales v0.14.1 // indirect
-	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
@@ -28,5 +32,8 @@ require (
 	golang.org/x/crypto v0.40.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
-	golang.org/x/text v0.29.0 // indirect
 )
+
+replace github.com/marcelofabianov/redact => ../redact
+
+replace github.com/marcelofabianov/config => ../config
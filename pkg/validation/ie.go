@@ -0,0 +1,341 @@
+package validation
+
+import "strings"
+
+// ieExempt is accepted as the inscrição estadual of exempt taxpayers.
+const ieExempt = "ISENTO"
+
+// ieValidators checks the digits of an inscrição estadual per UF, following
+// the SINTEGRA specifications.
+var ieValidators = map[string]func(ds []int) bool{
+	"AC": ieAC,
+	"AL": ieAL,
+	"AP": ieAP,
+	"AM": ieMod11Simple,
+	"BA": ieBA,
+	"CE": ieMod11Simple,
+	"DF": ieDF,
+	"ES": ieMod11Remainder,
+	"GO": ieGO,
+	"MA": ieMA,
+	"MG": ieMG,
+	"MS": ieMS,
+	"MT": ieMT,
+	"PA": iePA,
+	"PB": ieMod11Simple,
+	"PE": iePE,
+	"PI": ieMod11Simple,
+	"PR": iePR,
+	"RJ": ieRJ,
+	"RN": ieRN,
+	"RO": ieRO,
+	"RR": ieRR,
+	"RS": ieRS,
+	"SC": ieMod11Remainder,
+	"SE": ieMod11Simple,
+	"SP": ieSP,
+	"TO": ieTO,
+}
+
+// IsInscricaoEstadual reports whether value is a valid inscrição estadual
+// of uf, or ISENTO.
+func IsInscricaoEstadual(value, uf string) bool {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == ieExempt {
+		return true
+	}
+
+	check, ok := ieValidators[strings.ToUpper(uf)]
+	if !ok {
+		return false
+	}
+
+	// SP rural producers are written P0MMMSSSSD000.
+	if strings.HasPrefix(value, "P") {
+		if strings.ToUpper(uf) != "SP" {
+			return false
+		}
+		ds := digits(value[1:])
+		return len(ds) == 12 && ds[8] == weightedSum(ds, 1, 3, 4, 5, 6, 7, 8, 10)%11%10
+	}
+
+	ds := digits(value)
+	if len(ds) == 0 || allEqual(ds) {
+		return false
+	}
+	return check(ds)
+}
+
+// mod11Remainder is 11 minus the remainder, with remainders 0 and 1 giving 0.
+func mod11Remainder(sum int) int {
+	r := sum % 11
+	if r <= 1 {
+		return 0
+	}
+	return 11 - r
+}
+
+func hasPrefix(ds []int, prefix ...int) bool {
+	if len(ds) < len(prefix) {
+		return false
+	}
+	for i, d := range prefix {
+		if ds[i] != d {
+			return false
+		}
+	}
+	return true
+}
+
+func number(ds []int) int {
+	n := 0
+	for _, d := range ds {
+		n = n*10 + d
+	}
+	return n
+}
+
+// ieMod11Simple: 9 digits, weights 9..2, mod11.
+func ieMod11Simple(ds []int) bool {
+	return len(ds) == 9 && ds[8] == mod11(weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2))
+}
+
+// ieMod11Remainder: 9 digits, weights 9..2, mod11Remainder.
+func ieMod11Remainder(ds []int) bool {
+	return len(ds) == 9 && ds[8] == mod11Remainder(weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2))
+}
+
+func ieAC(ds []int) bool {
+	if len(ds) != 13 || !hasPrefix(ds, 0, 1) {
+		return false
+	}
+	dv1 := mod11(weightedSum(ds, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+	dv2 := mod11(weightedSum(ds, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3) + dv1*2)
+	return ds[11] == dv1 && ds[12] == dv2
+}
+
+func ieAL(ds []int) bool {
+	if len(ds) != 9 || !hasPrefix(ds, 2, 4) {
+		return false
+	}
+	dv := weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2) * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return ds[8] == dv
+}
+
+func ieAP(ds []int) bool {
+	if len(ds) != 9 || !hasPrefix(ds, 0, 3) {
+		return false
+	}
+
+	p, d := 0, 0
+	switch n := number(ds[:8]); {
+	case n >= 3000001 && n <= 3017000:
+		p, d = 5, 0
+	case n >= 3017001 && n <= 3019022:
+		p, d = 9, 1
+	}
+
+	dv := 11 - (p+weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2))%11
+	switch dv {
+	case 10:
+		dv = 0
+	case 11:
+		dv = d
+	}
+	return ds[8] == dv
+}
+
+// ieBA: the last digit is checked first, over the digits before the two
+// check digits, and the other one over those plus the last. Modulo 10 or 11
+// depends on the first digit (the second for 9-digit numbers).
+func ieBA(ds []int) bool {
+	if len(ds) != 8 && len(ds) != 9 {
+		return false
+	}
+	n := len(ds) - 2
+	lead := ds[0]
+	if len(ds) == 9 {
+		lead = ds[1]
+	}
+
+	modulo := 10
+	if lead == 6 || lead == 7 || lead == 9 {
+		modulo = 11
+	}
+	checkDigit := func(sum int) int {
+		r := sum % modulo
+		if modulo == 11 && r <= 1 || modulo == 10 && r == 0 {
+			return 0
+		}
+		return modulo - r
+	}
+
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += ds[i] * (n + 1 - i)
+	}
+	dv2 := checkDigit(sum)
+
+	sum = dv2 * 2
+	for i := 0; i < n; i++ {
+		sum += ds[i] * (n + 2 - i)
+	}
+	dv1 := checkDigit(sum)
+
+	return ds[n] == dv1 && ds[n+1] == dv2
+}
+
+func ieDF(ds []int) bool {
+	if len(ds) != 13 || !hasPrefix(ds, 0, 7) {
+		return false
+	}
+	dv1 := mod11(weightedSum(ds, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+	dv2 := mod11(weightedSum(ds, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3) + dv1*2)
+	return ds[11] == dv1 && ds[12] == dv2
+}
+
+func ieGO(ds []int) bool {
+	if len(ds) != 9 || !(hasPrefix(ds, 1, 0) || hasPrefix(ds, 1, 1) || hasPrefix(ds, 1, 5) || ds[0] == 2) {
+		return false
+	}
+
+	n := number(ds[:8])
+	if n == 11094402 {
+		return ds[8] == 0 || ds[8] == 1
+	}
+
+	dv := 0
+	switch r := weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2) % 11; {
+	case r == 1 && n >= 10103105 && n <= 10119997:
+		dv = 1
+	case r > 1:
+		dv = 11 - r
+	}
+	return ds[8] == dv
+}
+
+func ieMA(ds []int) bool {
+	return len(ds) == 9 && hasPrefix(ds, 1, 2) && ieMod11Remainder(ds)
+}
+
+func ieMS(ds []int) bool {
+	return len(ds) == 9 && (hasPrefix(ds, 2, 8) || hasPrefix(ds, 5, 0)) && ieMod11Remainder(ds)
+}
+
+func ieMT(ds []int) bool {
+	if len(ds) == 9 {
+		ds = append([]int{0, 0}, ds...)
+	}
+	return len(ds) == 11 && ds[10] == mod11Remainder(weightedSum(ds, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+}
+
+// ieMG: the first check digit is a modulo 10 over the number with a 0
+// after the municipality code, weights alternating 1 and 2 and the digits
+// of each product summed.
+func ieMG(ds []int) bool {
+	if len(ds) != 13 {
+		return false
+	}
+
+	padded := append(append(append([]int{}, ds[:3]...), 0), ds[3:11]...)
+	sum := 0
+	for i, d := range padded {
+		product := d * (1 + i%2)
+		sum += product/10 + product%10
+	}
+	dv1 := (10 - sum%10) % 10
+
+	dv2 := mod11Remainder(weightedSum(ds, 3, 2, 11, 10, 9, 8, 7, 6, 5, 4, 3) + dv1*2)
+	return ds[11] == dv1 && ds[12] == dv2
+}
+
+func iePA(ds []int) bool {
+	return len(ds) == 9 && hasPrefix(ds, 1, 5) && ieMod11Remainder(ds)
+}
+
+func iePE(ds []int) bool {
+	if len(ds) != 9 {
+		return false
+	}
+	dv1 := mod11Remainder(weightedSum(ds, 8, 7, 6, 5, 4, 3, 2))
+	dv2 := mod11Remainder(weightedSum(ds, 9, 8, 7, 6, 5, 4, 3) + dv1*2)
+	return ds[7] == dv1 && ds[8] == dv2
+}
+
+func iePR(ds []int) bool {
+	if len(ds) != 10 {
+		return false
+	}
+	dv1 := mod11Remainder(weightedSum(ds, 3, 2, 7, 6, 5, 4, 3, 2))
+	dv2 := mod11Remainder(weightedSum(ds, 4, 3, 2, 7, 6, 5, 4, 3) + dv1*2)
+	return ds[8] == dv1 && ds[9] == dv2
+}
+
+func ieRJ(ds []int) bool {
+	return len(ds) == 8 && ds[7] == mod11Remainder(weightedSum(ds, 2, 7, 6, 5, 4, 3, 2))
+}
+
+func ieRN(ds []int) bool {
+	if !hasPrefix(ds, 2, 0) {
+		return false
+	}
+	var sum int
+	switch len(ds) {
+	case 9:
+		sum = weightedSum(ds, 9, 8, 7, 6, 5, 4, 3, 2)
+	case 10:
+		sum = weightedSum(ds, 10, 9, 8, 7, 6, 5, 4, 3, 2)
+	default:
+		return false
+	}
+	dv := sum * 10 % 11
+	if dv == 10 {
+		dv = 0
+	}
+	return ds[len(ds)-1] == dv
+}
+
+func ieRO(ds []int) bool {
+	if len(ds) != 14 {
+		return false
+	}
+	dv := 11 - weightedSum(ds, 6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2)%11
+	if dv >= 10 {
+		dv -= 10
+	}
+	return ds[13] == dv
+}
+
+func ieRR(ds []int) bool {
+	return len(ds) == 9 && hasPrefix(ds, 2, 4) && ds[8] == weightedSum(ds, 1, 2, 3, 4, 5, 6, 7, 8)%9
+}
+
+func ieRS(ds []int) bool {
+	return len(ds) == 10 && ds[9] == mod11(weightedSum(ds, 2, 9, 8, 7, 6, 5, 4, 3, 2))
+}
+
+func ieSP(ds []int) bool {
+	if len(ds) != 12 {
+		return false
+	}
+	dv1 := weightedSum(ds, 1, 3, 4, 5, 6, 7, 8, 10) % 11 % 10
+	dv2 := weightedSum(ds, 3, 2, 10, 9, 8, 7, 6, 5, 4, 3, 2) % 11 % 10
+	return ds[8] == dv1 && ds[11] == dv2
+}
+
+// ieTO accepts the current 9-digit format and the old 11-digit one, whose
+// third and fourth digits (01, 02, 03 or 99) are not part of the check.
+func ieTO(ds []int) bool {
+	if len(ds) == 11 {
+		switch ds[2]*10 + ds[3] {
+		case 1, 2, 3, 99:
+		default:
+			return false
+		}
+		ds = append(append([]int{}, ds[:2]...), ds[4:]...)
+	}
+	return ieMod11Remainder(ds)
+}
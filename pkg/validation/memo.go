@@ -0,0 +1,176 @@
+package validation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultResultCacheSize is the number of Struct results kept when
+// Config.ResultCacheTTL is set without a size.
+const DefaultResultCacheSize = 1024
+
+// resultCache memoizes Struct results by a hash of the payload, for hot
+// endpoints receiving the same small payloads over and over. Entries live
+// for a short TTL; when full, expired entries are dropped first, then
+// arbitrary ones.
+type resultCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[[sha256.Size]byte]memoEntry
+}
+
+type memoEntry struct {
+	err     error
+	expires time.Time
+}
+
+// newResultCache returns nil, disabling memoization, when ttl is not
+// positive.
+func newResultCache(ttl time.Duration, size int) *resultCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = DefaultResultCacheSize
+	}
+	return &resultCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[[sha256.Size]byte]memoEntry, size),
+	}
+}
+
+func (rc *resultCache) get(key [sha256.Size]byte) (error, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return entry.err, true
+}
+
+func (rc *resultCache) put(key [sha256.Size]byte, err error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := time.Now()
+	if len(rc.entries) >= rc.size {
+		for k, entry := range rc.entries {
+			if now.After(entry.expires) {
+				delete(rc.entries, k)
+			}
+		}
+	}
+	for k := range rc.entries {
+		if len(rc.entries) < rc.size {
+			break
+		}
+		delete(rc.entries, k)
+	}
+
+	rc.entries[key] = memoEntry{err: err, expires: now.Add(rc.ttl)}
+}
+
+// memoKey hashes what a Struct result depends on: the type, the JSON form of
+// the sanitized value, the locale and the Params bag. It reports false when
+// memoization is off or the type cannot be memoized safely.
+func (vi *validatorImpl) memoKey(ctx context.Context, s any) ([sha256.Size]byte, bool) {
+	if vi.memo == nil || !vi.memoizable(reflect.TypeOf(s)) {
+		return [sha256.Size]byte{}, false
+	}
+
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	params, err := json.Marshal(ParamsFromContext(ctx))
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	h := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(reflect.TypeOf(s).String()),
+		[]byte(vi.config.locale(ctx)),
+		params,
+		payload,
+	} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key, true
+}
+
+// memoizable reports, once per type, whether the JSON form of t covers
+// everything its rules read: no validated field hidden from JSON, no
+// interface fields and no contextual or remote rule.
+func (vi *validatorImpl) memoizable(t reflect.Type) bool {
+	if cached, ok := vi.memoTypes.Load(t); ok {
+		return cached.(bool)
+	}
+
+	vi.mu.RLock()
+	ok := typeMemoizable(t, vi.contextualTags, make(map[reflect.Type]bool))
+	vi.mu.RUnlock()
+
+	vi.memoTypes.Store(t, ok)
+	return ok
+}
+
+func typeMemoizable(t reflect.Type, contextual map[string]bool, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return typeMemoizable(t.Elem(), contextual, seen)
+	case reflect.Struct:
+	default:
+		return true
+	}
+
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		rules := field.Tag.Get("validate")
+		if rules != "" && strings.SplitN(field.Tag.Get("json"), ",", 2)[0] == "-" {
+			return false
+		}
+		for _, rule := range strings.FieldsFunc(rules, func(r rune) bool { return r == ',' || r == '|' }) {
+			if contextual[strings.SplitN(rule, "=", 2)[0]] {
+				return false
+			}
+		}
+
+		if !typeMemoizable(field.Type, contextual, seen) {
+			return false
+		}
+	}
+	return true
+}
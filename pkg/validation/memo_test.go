@@ -0,0 +1,109 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type pingPayload struct {
+	Device string `json:"device" validate:"required,counted"`
+}
+
+type scopedPayload struct {
+	Device string `json:"device" validate:"required,counted_ctx"`
+}
+
+func newMemoValidator(t *testing.T, ttl time.Duration) (validation.Validator, *atomic.Int64) {
+	t.Helper()
+
+	cfg := validation.DefaultConfig()
+	cfg.EnableLogging = false
+	cfg.ResultCacheTTL = ttl
+	v := validation.New(cfg, nil)
+
+	calls := &atomic.Int64{}
+	if err := v.RegisterCustom("counted", func(fl validator.FieldLevel) bool {
+		calls.Add(1)
+		return fl.Field().String() != "blocked"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RegisterCustomCtx("counted_ctx", func(_ context.Context, fl validator.FieldLevel) bool {
+		calls.Add(1)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return v, calls
+}
+
+func TestResultCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("identical payloads are validated once", func(t *testing.T) {
+		v, calls := newMemoValidator(t, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			if err := v.Struct(ctx, pingPayload{Device: "sensor-1"}); err != nil {
+				t.Fatalf("Struct() error = %v", err)
+			}
+		}
+		for i := 0; i < 2; i++ {
+			if err := v.Struct(ctx, &pingPayload{Device: "blocked"}); !errors.Is(err, validation.ErrValidationFailed) {
+				t.Fatalf("expected cached ErrValidationFailed, got %v", err)
+			}
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected 2 rule calls, got %d", got)
+		}
+	})
+
+	t.Run("locale and params are part of the key", func(t *testing.T) {
+		v, calls := newMemoValidator(t, time.Minute)
+
+		_ = v.Struct(ctx, pingPayload{Device: "blocked"})
+		_ = v.Struct(validation.WithLocale(ctx, validation.LocaleEN), pingPayload{Device: "blocked"})
+		_ = v.Struct(validation.WithRole(ctx, "admin"), pingPayload{Device: "blocked"})
+		if got := calls.Load(); got != 3 {
+			t.Errorf("expected 3 rule calls, got %d", got)
+		}
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		v, calls := newMemoValidator(t, 10*time.Millisecond)
+
+		_ = v.Struct(ctx, pingPayload{Device: "sensor-1"})
+		time.Sleep(20 * time.Millisecond)
+		_ = v.Struct(ctx, pingPayload{Device: "sensor-1"})
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected 2 rule calls, got %d", got)
+		}
+	})
+
+	t.Run("contextual rules are never memoized", func(t *testing.T) {
+		v, calls := newMemoValidator(t, time.Minute)
+
+		_ = v.Struct(ctx, scopedPayload{Device: "sensor-1"})
+		_ = v.Struct(ctx, scopedPayload{Device: "sensor-1"})
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected 2 rule calls, got %d", got)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		v, calls := newMemoValidator(t, 0)
+
+		_ = v.Struct(ctx, pingPayload{Device: "sensor-1"})
+		_ = v.Struct(ctx, pingPayload{Device: "sensor-1"})
+		if got := calls.Load(); got != 2 {
+			t.Errorf("expected 2 rule calls, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	LocalePTBR = "pt-BR"
+	LocaleEN   = "en"
+
+	// messageKeyPrefix namespaces the i18n keys attached to FieldError so
+	// clients can translate them on their side.
+	messageKeyPrefix = "validation."
+)
+
+// Messages is a registry of message templates used to turn failed rules into
+// human-readable text. Templates are registered per locale, either for a tag
+// or for a (field, tag) pair, and support the placeholders {field}, {path},
+// {tag} and {param}. Field templates match either the full path
+// ("guardians[1].phone") or the bare field name ("phone"). It is safe for
+// concurrent use.
+type Messages struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	tags          map[string]map[string]string
+	fields        map[string]map[string]string
+}
+
+// NewMessages creates an empty registry that falls back to defaultLocale
+// when a template is missing for the requested locale.
+func NewMessages(defaultLocale string) *Messages {
+	if defaultLocale == "" {
+		defaultLocale = LocalePTBR
+	}
+
+	return &Messages{
+		defaultLocale: defaultLocale,
+		tags:          make(map[string]map[string]string),
+		fields:        make(map[string]map[string]string),
+	}
+}
+
+// DefaultMessages returns a registry pre-populated with pt-BR and en
+// templates for the built-in and Brazilian tags.
+func DefaultMessages(defaultLocale string) *Messages {
+	m := NewMessages(defaultLocale)
+
+	for tag, tmpl := range defaultTemplatesPTBR {
+		m.SetTag(LocalePTBR, tag, tmpl)
+	}
+	for tag, tmpl := range defaultTemplatesEN {
+		m.SetTag(LocaleEN, tag, tmpl)
+	}
+
+	return m
+}
+
+// SetTag registers the template used for every field failing tag.
+func (m *Messages) SetTag(locale, tag, template string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tags[locale] == nil {
+		m.tags[locale] = make(map[string]string)
+	}
+	m.tags[locale][tag] = template
+}
+
+// SetField registers a template that overrides the tag template for a
+// single field.
+func (m *Messages) SetField(locale, field, tag, template string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fields[locale] == nil {
+		m.fields[locale] = make(map[string]string)
+	}
+	m.fields[locale][fieldKey(field, tag)] = template
+}
+
+// DefaultLocale returns the locale used when none is requested.
+func (m *Messages) DefaultLocale() string {
+	return m.defaultLocale
+}
+
+// Resolve renders the message for fe in locale, falling back to the default
+// locale and finally to a generic English description.
+func (m *Messages) Resolve(locale string, fe FieldError) string {
+	if msg, ok := m.render(locale, fe); ok {
+		return msg
+	}
+
+	return fallbackMessage(fe)
+}
+
+// render is Resolve without the generic fallback, for callers that have a
+// better default of their own.
+func (m *Messages) render(locale string, fe FieldError) (string, bool) {
+	if tmpl, ok := m.lookup(locale, fe); ok {
+		return interpolate(tmpl, fe), true
+	}
+	if locale != m.defaultLocale {
+		if tmpl, ok := m.lookup(m.defaultLocale, fe); ok {
+			return interpolate(tmpl, fe), true
+		}
+	}
+
+	return "", false
+}
+
+func (m *Messages) lookup(locale string, fe FieldError) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if fe.Path != "" {
+		if tmpl, ok := m.fields[locale][fieldKey(fe.Path, fe.Tag)]; ok {
+			return tmpl, true
+		}
+	}
+	if tmpl, ok := m.fields[locale][fieldKey(fe.Field, fe.Tag)]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := m.tags[locale][fe.Tag]
+	return tmpl, ok
+}
+
+// MessageKey returns the i18n key for a failed tag, e.g. "validation.cpf".
+func MessageKey(tag string) string {
+	return messageKeyPrefix + tag
+}
+
+func fieldKey(field, tag string) string {
+	return field + "." + tag
+}
+
+func interpolate(tmpl string, fe FieldError) string {
+	return strings.NewReplacer(
+		"{field}", fe.Field,
+		"{path}", fe.Path,
+		"{tag}", fe.Tag,
+		"{param}", fe.Param,
+	).Replace(tmpl)
+}
+
+func fallbackMessage(fe FieldError) string {
+	field := fe.Path
+	if field == "" {
+		field = fe.Field
+	}
+
+	msg := fmt.Sprintf("field '%s' failed validation '%s'", field, fe.Tag)
+	if fe.Param != "" {
+		msg += fmt.Sprintf(" (param: %s)", fe.Param)
+	}
+	return msg
+}
+
+var defaultTemplatesPTBR = map[string]string{
+	"required":       "{field} é obrigatório",
+	"email":          "{field} deve ser um e-mail válido",
+	"min":            "{field} deve ter no mínimo {param}",
+	"max":            "{field} deve ter no máximo {param}",
+	"len":            "{field} deve ter exatamente {param}",
+	"gt":             "{field} deve ser maior que {param}",
+	"gte":            "{field} deve ser maior ou igual a {param}",
+	"lt":             "{field} deve ser menor que {param}",
+	"lte":            "{field} deve ser menor ou igual a {param}",
+	"oneof":          "{field} deve ser um dos valores: {param}",
+	"url":            "{field} deve ser uma URL válida",
+	"uuid":           "{field} deve ser um UUID válido",
+	"numeric":        "{field} deve ser numérico",
+	"cpf":            "CPF inválido",
+	"cnpj":           "CNPJ inválido",
+	"cep":            "CEP inválido",
+	"phone":          "Telefone inválido",
+	"pis":            "PIS inválido",
+	"cnh":            "CNH inválida",
+	"renavam":        "RENAVAM inválido",
+	"placa":          "Placa inválida",
+	"titulo_eleitor": "Título de eleitor inválido",
+	"ie":             "Inscrição estadual inválida",
+
+	"uuid7":       "{field} deve ser um UUID v7 válido",
+	"ulid":        "{field} deve ser um ULID válido",
+	"slug":        "{field} deve conter apenas letras minúsculas, números e hífens",
+	"date_before": "{field} deve ser anterior a {param}",
+	"date_after":  "{field} deve ser posterior a {param}",
+
+	"required_if_role":   "{field} é obrigatório para o perfil {param}",
+	"required_if_tenant": "{field} é obrigatório para esta organização",
+	"required_if_param":  "{field} é obrigatório",
+}
+
+var defaultTemplatesEN = map[string]string{
+	"required":       "{field} is required",
+	"email":          "{field} must be a valid email",
+	"min":            "{field} must be at least {param}",
+	"max":            "{field} must be at most {param}",
+	"len":            "{field} must be exactly {param}",
+	"gt":             "{field} must be greater than {param}",
+	"gte":            "{field} must be greater than or equal to {param}",
+	"lt":             "{field} must be less than {param}",
+	"lte":            "{field} must be less than or equal to {param}",
+	"oneof":          "{field} must be one of: {param}",
+	"url":            "{field} must be a valid URL",
+	"uuid":           "{field} must be a valid UUID",
+	"numeric":        "{field} must be numeric",
+	"cpf":            "invalid CPF",
+	"cnpj":           "invalid CNPJ",
+	"cep":            "invalid CEP",
+	"phone":          "invalid phone number",
+	"pis":            "invalid PIS",
+	"cnh":            "invalid CNH",
+	"renavam":        "invalid RENAVAM",
+	"placa":          "invalid license plate",
+	"titulo_eleitor": "invalid voter registration",
+	"ie":             "invalid state registration",
+
+	"uuid7":       "{field} must be a valid UUID v7",
+	"ulid":        "{field} must be a valid ULID",
+	"slug":        "{field} must contain only lowercase letters, digits and hyphens",
+	"date_before": "{field} must be before {param}",
+	"date_after":  "{field} must be after {param}",
+
+	"required_if_role":   "{field} is required for role {param}",
+	"required_if_tenant": "{field} is required for this organization",
+	"required_if_param":  "{field} is required",
+}
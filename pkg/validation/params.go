@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Keys of the request values read by the built-in conditional rules.
+const (
+	ParamRole   = "role"
+	ParamTenant = "tenant"
+)
+
+type paramsKey struct{}
+
+// Params is a bag of request values (role, tenant, plan...) placed in the
+// context so rules can depend on who is submitting the data instead of
+// needing a struct per role. Values are usually strings or []string.
+type Params map[string]any
+
+// WithParams returns a copy of ctx carrying params merged over any bag
+// already in ctx. The bag in ctx is never modified.
+func WithParams(ctx context.Context, params Params) context.Context {
+	merged := make(Params, len(params))
+	for k, v := range ParamsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, paramsKey{}, merged)
+}
+
+// WithRole is WithParams for the ParamRole value.
+func WithRole(ctx context.Context, roles ...string) context.Context {
+	if len(roles) == 1 {
+		return WithParams(ctx, Params{ParamRole: roles[0]})
+	}
+	return WithParams(ctx, Params{ParamRole: roles})
+}
+
+// WithTenant is WithParams for the ParamTenant value.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return WithParams(ctx, Params{ParamTenant: tenant})
+}
+
+// ParamsFromContext returns the bag set by WithParams, or nil.
+func ParamsFromContext(ctx context.Context) Params {
+	if ctx == nil {
+		return nil
+	}
+	params, _ := ctx.Value(paramsKey{}).(Params)
+	return params
+}
+
+// Param returns the value stored under key in the context bag when it has
+// type T, e.g. Param[int](ctx, "max_guardians").
+func Param[T any](ctx context.Context, key string) (T, bool) {
+	value, ok := ParamsFromContext(ctx)[key].(T)
+	return value, ok
+}
+
+// Matches reports whether the value under key equals one of values. A
+// []string value matches when any of its elements does.
+func (p Params) Matches(key string, values ...string) bool {
+	for _, have := range paramStrings(p[key]) {
+		for _, want := range values {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func paramStrings(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case fmt.Stringer:
+		return []string{v.String()}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// contextValidators are the conditional rules registered by New. They run
+// even for nil fields so a missing pointer can still be required.
+var contextValidators = map[string]validator.FuncCtx{
+	// required_if_role=admin manager
+	"required_if_role": requiredIfParam(func(param string) (string, []string) {
+		return ParamRole, strings.Fields(param)
+	}),
+	// required_if_tenant=acme
+	"required_if_tenant": requiredIfParam(func(param string) (string, []string) {
+		return ParamTenant, strings.Fields(param)
+	}),
+	// required_if_param=plan premium enterprise
+	"required_if_param": requiredIfParam(func(param string) (string, []string) {
+		fields := strings.Fields(param)
+		if len(fields) < 2 {
+			panic(fmt.Sprintf("required_if_param needs a key and at least one value, got %q", param))
+		}
+		return fields[0], fields[1:]
+	}),
+}
+
+func requiredIfParam(parse func(param string) (key string, values []string)) validator.FuncCtx {
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		key, values := parse(fl.Param())
+		if !ParamsFromContext(ctx).Matches(key, values...) {
+			return true
+		}
+		return hasValue(fl.Field())
+	}
+}
+
+// hasValue mirrors the go-playground `required` check.
+func hasValue(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return !field.IsNil()
+	default:
+		return field.IsValid() && !field.IsZero()
+	}
+}
+
+func registerContextValidators(v *validator.Validate) {
+	for tag, fn := range contextValidators {
+		// The tags are constant, so registration can only fail on a bug.
+		if err := v.RegisterValidationCtx(tag, fn, true); err != nil {
+			panic(err)
+		}
+	}
+}
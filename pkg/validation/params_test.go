@@ -0,0 +1,115 @@
+package validation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type userForm struct {
+	Name       string  `json:"name" validate:"required"`
+	Department string  `json:"department" validate:"required_if_role=admin manager"`
+	CostCenter *string `json:"cost_center" validate:"required_if_tenant=acme"`
+	SLA        string  `json:"sla" validate:"required_if_param=plan enterprise"`
+}
+
+func TestConditionalRules(t *testing.T) {
+	v := newTestValidator()
+
+	t.Run("rules are skipped without matching params", func(t *testing.T) {
+		ctx := validation.WithRole(context.Background(), "teacher")
+		if err := v.Struct(ctx, userForm{Name: "Ana"}); err != nil {
+			t.Fatalf("Struct() error = %v", err)
+		}
+	})
+
+	t.Run("role makes the field required", func(t *testing.T) {
+		ctx := validation.WithRole(context.Background(), "student", "manager")
+		report, err := v.StructAll(ctx, userForm{Name: "Ana"})
+		if err != nil {
+			t.Fatalf("StructAll() error = %v", err)
+		}
+		if len(report.Errors) != 1 || report.Errors[0].Path != "department" {
+			t.Fatalf("expected department error, got %+v", report.Errors)
+		}
+	})
+
+	t.Run("tenant and generic params", func(t *testing.T) {
+		ctx := validation.WithTenant(context.Background(), "acme")
+		ctx = validation.WithParams(ctx, validation.Params{"plan": "enterprise"})
+
+		report, err := v.StructAll(ctx, userForm{Name: "Ana"})
+		if err != nil {
+			t.Fatalf("StructAll() error = %v", err)
+		}
+		paths := make([]string, 0, len(report.Errors))
+		for _, fe := range report.Errors {
+			paths = append(paths, fe.Path)
+		}
+		if got := strings.Join(paths, ","); got != "cost_center,sla" {
+			t.Errorf("expected cost_center,sla errors, got %s", got)
+		}
+
+		center := "CC-01"
+		if err := v.Struct(ctx, userForm{Name: "Ana", CostCenter: &center, SLA: "24h"}); err != nil {
+			t.Errorf("Struct() error = %v", err)
+		}
+	})
+
+	t.Run("messages mention the role", func(t *testing.T) {
+		ctx := validation.WithLocale(validation.WithRole(context.Background(), "admin"), validation.LocaleEN)
+		report, _ := v.StructAll(ctx, userForm{Name: "Ana"})
+		if len(report.Errors) != 1 {
+			t.Fatalf("expected 1 error, got %+v", report.Errors)
+		}
+		if want := "department is required for role admin manager"; report.Errors[0].Message != want {
+			t.Errorf("expected %q, got %q", want, report.Errors[0].Message)
+		}
+	})
+}
+
+func TestRegisterCustomCtx(t *testing.T) {
+	v := newTestValidator()
+
+	err := v.RegisterCustomCtx("max_guardians", func(ctx context.Context, fl validator.FieldLevel) bool {
+		limit, ok := validation.Param[int](ctx, "max_guardians")
+		return !ok || fl.Field().Len() <= limit
+	})
+	if err != nil {
+		t.Fatalf("RegisterCustomCtx() error = %v", err)
+	}
+
+	guardians := []string{"Ana", "Bia", "Caio"}
+	if err := v.Field(context.Background(), guardians, "max_guardians"); err != nil {
+		t.Errorf("expected no limit without params, got %v", err)
+	}
+
+	ctx := validation.WithParams(context.Background(), validation.Params{"max_guardians": 2})
+	if err := v.Field(ctx, guardians, "max_guardians"); err == nil {
+		t.Error("expected the tenant limit to apply")
+	}
+
+	if err := v.RegisterCustomCtx("", nil); err == nil {
+		t.Error("expected error for empty tag")
+	}
+}
+
+func TestWithParams(t *testing.T) {
+	ctx := validation.WithParams(context.Background(), validation.Params{"plan": "basic"})
+	child := validation.WithParams(ctx, validation.Params{"plan": "premium", validation.ParamRole: "admin"})
+
+	if plan, _ := validation.Param[string](ctx, "plan"); plan != "basic" {
+		t.Errorf("expected parent bag untouched, got %q", plan)
+	}
+	params := validation.ParamsFromContext(child)
+	if !params.Matches("plan", "premium") || !params.Matches(validation.ParamRole, "admin") {
+		t.Errorf("expected merged params, got %v", params)
+	}
+	if _, ok := validation.Param[int](child, "plan"); ok {
+		t.Error("expected type mismatch to report false")
+	}
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/marcelofabianov/fault"
+)
+
+// DefaultRemoteTimeout bounds each RemoteFunc call when neither the
+// registration nor Config.RemoteTimeout set one.
+const DefaultRemoteTimeout = 2 * time.Second
+
+// ErrRemoteValidation is returned instead of a validation failure when a
+// RemoteFunc could not answer (timeout, database down), so callers can tell
+// "the email is taken" from "we could not check the email".
+var ErrRemoteValidation = fault.New(
+	"remote validation unavailable",
+	fault.WithCode(fault.InfraError),
+)
+
+// RemoteFunc checks a value against an external source, usually a
+// repository, e.g. whether an email is still free. ok=false fails the rule;
+// a non-nil err aborts the validation with ErrRemoteValidation.
+type RemoteFunc func(ctx context.Context, value any) (ok bool, err error)
+
+type remoteErrorsKey struct{}
+
+// remoteErrors collects the RemoteFunc errors of one validation call, since
+// go-playground rules can only report a bool.
+type remoteErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func withRemoteErrors(ctx context.Context) (context.Context, *remoteErrors) {
+	re := &remoteErrors{}
+	return context.WithValue(ctx, remoteErrorsKey{}, re), re
+}
+
+func (re *remoteErrors) add(err error) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.errs = append(re.errs, err)
+}
+
+func (re *remoteErrors) err() error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	if len(re.errs) == 0 {
+		return nil
+	}
+	// Joined so both errors.Is(err, ErrRemoteValidation) and checks for the
+	// causes (e.g. context.DeadlineExceeded) hold.
+	causes := append([]error{ErrRemoteValidation}, re.errs...)
+	return fault.Wrap(errors.Join(causes...), "remote validation failed",
+		fault.WithCode(fault.InfraError),
+		fault.WithContext("error_count", len(re.errs)),
+	)
+}
+
+// RegisterRemote registers tag as a rule answered by fn, called with a
+// timeout (Config.RemoteTimeout when timeout is zero). Empty values pass, so
+// combine with required.
+func (vi *validatorImpl) RegisterRemote(tag string, fn RemoteFunc, timeout time.Duration) error {
+	if fn == nil {
+		return fault.Wrap(ErrInvalidInput, "remote validator function cannot be nil")
+	}
+	if timeout <= 0 {
+		timeout = vi.config.RemoteTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+
+	return vi.registerCustom(tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		if !hasValue(fl.Field()) {
+			return true
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		ok, err := fn(callCtx, fl.Field().Interface())
+		if err == nil {
+			return ok
+		}
+
+		err = fault.Wrap(err, "remote validator failed",
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("tag", tag),
+			fault.WithContext("field", fl.FieldName()),
+		)
+		if re, found := ctx.Value(remoteErrorsKey{}).(*remoteErrors); found {
+			re.add(err)
+		}
+		// The rule is inconclusive, not failed; the error is reported
+		// instead of a validation failure.
+		return true
+	}, true)
+}
@@ -0,0 +1,98 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type accountForm struct {
+	Email string `json:"email" validate:"required,email,unique_email"`
+}
+
+func TestRegisterRemote(t *testing.T) {
+	ctx := context.Background()
+	taken := map[string]bool{"ana@example.com": true}
+
+	newValidator := func(t *testing.T, fn validation.RemoteFunc, timeout time.Duration) validation.Validator {
+		t.Helper()
+		v := newTestValidator()
+		if err := v.RegisterRemote("unique_email", fn, timeout); err != nil {
+			t.Fatalf("RegisterRemote() error = %v", err)
+		}
+		return v
+	}
+
+	lookup := func(_ context.Context, value any) (bool, error) {
+		return !taken[value.(string)], nil
+	}
+
+	t.Run("taken values fail validation", func(t *testing.T) {
+		v := newValidator(t, lookup, 0)
+
+		err := v.Struct(ctx, accountForm{Email: "ana@example.com"})
+		if !errors.Is(err, validation.ErrValidationFailed) {
+			t.Fatalf("expected ErrValidationFailed, got %v", err)
+		}
+		fields, _ := validation.AsValidationErrors(err)
+		if len(fields) != 1 || fields[0].Tag != "unique_email" {
+			t.Errorf("expected unique_email failure, got %+v", fields)
+		}
+
+		if err := v.Struct(ctx, accountForm{Email: "bia@example.com"}); err != nil {
+			t.Errorf("Struct() error = %v", err)
+		}
+	})
+
+	t.Run("callback errors are not validation failures", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+		v := newValidator(t, func(context.Context, any) (bool, error) {
+			return false, dbErr
+		}, 0)
+
+		err := v.Struct(ctx, accountForm{Email: "ana@example.com"})
+		if !errors.Is(err, validation.ErrRemoteValidation) || !errors.Is(err, dbErr) {
+			t.Fatalf("expected ErrRemoteValidation wrapping the cause, got %v", err)
+		}
+		if errors.Is(err, validation.ErrValidationFailed) {
+			t.Error("remote errors must not look like validation failures")
+		}
+
+		if _, err := v.StructAll(ctx, accountForm{Email: "ana@example.com"}); !errors.Is(err, validation.ErrRemoteValidation) {
+			t.Errorf("expected StructAll to return ErrRemoteValidation, got %v", err)
+		}
+		if err := v.Field(ctx, "ana@example.com", "unique_email"); !errors.Is(err, validation.ErrRemoteValidation) {
+			t.Errorf("expected Field to return ErrRemoteValidation, got %v", err)
+		}
+	})
+
+	t.Run("calls are bounded by the timeout", func(t *testing.T) {
+		v := newValidator(t, func(ctx context.Context, _ any) (bool, error) {
+			<-ctx.Done()
+			return false, ctx.Err()
+		}, 10*time.Millisecond)
+
+		err := v.Struct(ctx, accountForm{Email: "ana@example.com"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("empty values skip the callback", func(t *testing.T) {
+		v := newValidator(t, func(context.Context, any) (bool, error) {
+			t.Error("callback called for an empty value")
+			return true, nil
+		}, 0)
+
+		_ = v.Field(ctx, "", "unique_email")
+	})
+
+	t.Run("rejects nil callbacks", func(t *testing.T) {
+		if err := newTestValidator().RegisterRemote("unique_email", nil, 0); !errors.Is(err, validation.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// warnTagName is the struct tag holding warning-level rules. Rules declared
+// there are evaluated by StructAll but never block Struct.
+const warnTagName = "warn"
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// FieldError describes a single failed rule. Field is the JSON name of the
+// failing field and Path its full location from the root of the validated
+// struct, e.g. "guardians[1].phone".
+type FieldError struct {
+	Field    string   `json:"field"`
+	Path     string   `json:"path"`
+	Tag      string   `json:"tag"`
+	Param    string   `json:"param,omitempty"`
+	Severity Severity `json:"severity"`
+
+	// Message is the human-readable text resolved from the Messages
+	// registry and MessageKey the matching i18n key.
+	Message    string `json:"message"`
+	MessageKey string `json:"message_key"`
+}
+
+// Report holds every failed rule of a StructAll call, split by severity.
+type Report struct {
+	Errors   []FieldError `json:"errors,omitempty"`
+	Warnings []FieldError `json:"warnings,omitempty"`
+}
+
+// Valid reports whether no blocking rule failed. Warnings do not affect it.
+func (r *Report) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *Report) HasWarnings() bool {
+	return len(r.Warnings) > 0
+}
+
+func (vi *validatorImpl) newFieldErrors(locale string, valErrs validator.ValidationErrors, severity Severity) []FieldError {
+	result := make([]FieldError, 0, len(valErrs))
+	for _, fieldErr := range valErrs {
+		fe := FieldError{
+			Field:      fieldErr.Field(),
+			Path:       fieldPath(fieldErr),
+			Tag:        fieldErr.Tag(),
+			Param:      fieldErr.Param(),
+			Severity:   severity,
+			MessageKey: MessageKey(fieldErr.Tag()),
+		}
+		fe.Message = vi.message(locale, fe, fieldErr)
+		result = append(result, fe)
+	}
+	return result
+}
+
+// message renders fe in locale: a Messages template wins, then the
+// go-playground translation of the tag, each tried in locale and then in the
+// default locale, before the generic English description.
+func (vi *validatorImpl) message(locale string, fe FieldError, fieldErr validator.FieldError) string {
+	locales := []string{locale}
+	if def := vi.messages.DefaultLocale(); def != locale {
+		locales = append(locales, def)
+	}
+
+	for _, l := range locales {
+		if tmpl, ok := vi.messages.lookup(l, fe); ok {
+			return interpolate(tmpl, fe)
+		}
+		if msg, ok := vi.translators.translate(l, fieldErr); ok {
+			return msg
+		}
+	}
+	return fallbackMessage(fe)
+}
+
+// fieldPath strips the root struct type name from the validator namespace,
+// leaving the JSON path of the failing field. Namespaces of a diving Field
+// call have no root and start with the element index ("[3].email").
+func fieldPath(fieldErr validator.FieldError) string {
+	ns := fieldErr.Namespace()
+	if strings.HasPrefix(ns, "[") {
+		return ns
+	}
+	if idx := strings.Index(ns, "."); idx >= 0 {
+		return ns[idx+1:]
+	}
+	if ns == "" {
+		return fieldErr.Field()
+	}
+	return ns
+}
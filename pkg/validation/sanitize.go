@@ -0,0 +1,186 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/marcelofabianov/fault"
+	"golang.org/x/text/unicode/norm"
+)
+
+// modTagName is the struct tag holding the modifiers applied by Struct and
+// StructAll before validation, e.g. `mod:"trim,lower"`.
+const modTagName = "mod"
+
+// Modifier rewrites a string field before it is validated.
+type Modifier func(string) string
+
+// defaultModifiers are available to every validator.
+var defaultModifiers = map[string]Modifier{
+	"trim":   strings.TrimSpace,
+	"ltrim":  func(s string) string { return strings.TrimLeftFunc(s, unicode.IsSpace) },
+	"rtrim":  func(s string) string { return strings.TrimRightFunc(s, unicode.IsSpace) },
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"squash": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+	"digits": func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, s)
+	},
+	// normalize composes accents into single code points (NFC), so "José"
+	// typed on different keyboards compares equal.
+	"normalize": norm.NFC.String,
+}
+
+// sanitizer applies the mod tags of a struct in place.
+type sanitizer struct {
+	mu        sync.RWMutex
+	modifiers map[string]Modifier
+	// tagged caches whether a type has mod tags anywhere inside it.
+	tagged sync.Map
+}
+
+func newSanitizer() *sanitizer {
+	modifiers := make(map[string]Modifier, len(defaultModifiers))
+	for name, fn := range defaultModifiers {
+		modifiers[name] = fn
+	}
+	return &sanitizer{modifiers: modifiers}
+}
+
+func (vi *validatorImpl) RegisterModifier(name string, fn Modifier) error {
+	if name == "" {
+		return fault.Wrap(ErrInvalidInput, "modifier name cannot be empty")
+	}
+	if fn == nil {
+		return fault.Wrap(ErrInvalidInput, "modifier function cannot be nil")
+	}
+
+	vi.sanitizer.mu.Lock()
+	defer vi.sanitizer.mu.Unlock()
+	vi.sanitizer.modifiers[name] = fn
+	return nil
+}
+
+// sanitize applies the mod tags of s and returns the value to validate: s
+// itself when it is a pointer, so the caller sees the sanitized fields, or
+// a sanitized copy otherwise.
+func (sz *sanitizer) sanitize(s any) (any, error) {
+	v := reflect.ValueOf(s)
+	t := v.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !sz.hasTags(t) {
+		return s, nil
+	}
+
+	if v.Kind() != reflect.Pointer {
+		cp := reflect.New(v.Type())
+		cp.Elem().Set(v)
+		v, s = cp, cp.Interface()
+	}
+
+	if err := sz.walk(v, nil); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// walk applies mods to v, a string, or to the tagged fields below it.
+func (sz *sanitizer) walk(v reflect.Value, mods []string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return sz.walk(v.Elem(), mods)
+		}
+	case reflect.String:
+		if len(mods) > 0 && v.CanSet() {
+			value, err := sz.apply(v.String(), mods)
+			if err != nil {
+				return err
+			}
+			v.SetString(value)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := sz.walk(v.Index(i), mods); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			var fieldMods []string
+			if tag := field.Tag.Get(modTagName); tag != "" {
+				fieldMods = strings.Split(tag, ",")
+			}
+			if len(fieldMods) == 0 && !sz.hasTags(field.Type) {
+				continue
+			}
+			if err := sz.walk(v.Field(i), fieldMods); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (sz *sanitizer) apply(value string, mods []string) (string, error) {
+	sz.mu.RLock()
+	defer sz.mu.RUnlock()
+
+	for _, name := range mods {
+		name = strings.TrimSpace(name)
+		fn, ok := sz.modifiers[name]
+		if !ok {
+			return "", fault.Wrap(ErrInvalidInput, "unknown modifier",
+				fault.WithContext("modifier", name),
+			)
+		}
+		value = fn(value)
+	}
+	return value, nil
+}
+
+// hasTags reports whether t, or a type inside it, has mod tags.
+func (sz *sanitizer) hasTags(t reflect.Type) bool {
+	if cached, ok := sz.tagged.Load(t); ok {
+		return cached.(bool)
+	}
+	tagged := typeHasTags(t, modTagName, map[reflect.Type]bool{})
+	sz.tagged.Store(t, tagged)
+	return tagged
+}
+
+// typeHasTags reports whether t, or a type inside it, has a field with the
+// struct tag name; seen stops recursive types.
+func typeHasTags(t reflect.Type, name string, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return typeHasTags(t.Elem(), name, seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.IsExported() && (field.Tag.Get(name) != "" || typeHasTags(field.Type, name, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type guardianInput struct {
+	Name  string `json:"name" mod:"trim,squash" validate:"required"`
+	Phone string `json:"phone" mod:"digits" validate:"required,len=11"`
+}
+
+type studentInput struct {
+	Name      string          `json:"name" mod:"trim,normalize" validate:"required,min=3"`
+	Email     string          `json:"email" mod:"trim,lower" validate:"required,email"`
+	Nickname  *string         `json:"nickname" mod:"trim"`
+	Tags      []string        `json:"tags" mod:"trim,upper"`
+	Guardians []guardianInput `json:"guardians" validate:"dive"`
+}
+
+func TestSanitization(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	t.Run("applies modifiers in place before validation", func(t *testing.T) {
+		nickname := "  jo  "
+		input := &studentInput{
+			Name:      "  José ",
+			Email:     "  Jose@Example.COM ",
+			Nickname:  &nickname,
+			Tags:      []string{" new ", "vip"},
+			Guardians: []guardianInput{{Name: "  Maria   da  Silva ", Phone: "(11) 98765-4321"}},
+		}
+
+		if err := v.Struct(ctx, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if input.Name != "Jos\u00e9" {
+			t.Errorf("expected trimmed NFC name, got %q", input.Name)
+		}
+		if input.Email != "jose@example.com" {
+			t.Errorf("unexpected email %q", input.Email)
+		}
+		if *input.Nickname != "jo" {
+			t.Errorf("unexpected nickname %q", *input.Nickname)
+		}
+		if strings.Join(input.Tags, ",") != "NEW,VIP" {
+			t.Errorf("unexpected tags %v", input.Tags)
+		}
+		if g := input.Guardians[0]; g.Name != "Maria da Silva" || g.Phone != "11987654321" {
+			t.Errorf("unexpected guardian %+v", g)
+		}
+	})
+
+	t.Run("blank values fail required after trimming", func(t *testing.T) {
+		err := v.Struct(ctx, studentInput{Name: "   ", Email: "ana@example.com"})
+		fields, ok := validation.AsValidationErrors(err)
+		if !ok || len(fields) != 1 || fields[0].Path != "name" || fields[0].Tag != "required" {
+			t.Errorf("expected name to be required, got %v", err)
+		}
+	})
+
+	t.Run("custom modifiers", func(t *testing.T) {
+		type couponInput struct {
+			Code string `json:"code" mod:"trim,nodash" validate:"required,len=8"`
+		}
+
+		if err := v.RegisterModifier("nodash", func(s string) string {
+			return strings.ReplaceAll(s, "-", "")
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		input := &couponInput{Code: " ABCD-1234 "}
+		if err := v.Struct(ctx, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if input.Code != "ABCD1234" {
+			t.Errorf("unexpected code %q", input.Code)
+		}
+	})
+
+	t.Run("unknown modifiers are rejected", func(t *testing.T) {
+		type badInput struct {
+			Name string `mod:"shout"`
+		}
+
+		err := v.Struct(ctx, &badInput{Name: "ana"})
+		if !errors.Is(err, validation.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
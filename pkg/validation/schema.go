@@ -0,0 +1,241 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var (
+	ErrSchemaNotFound = fault.New(
+		"json schema not registered",
+		fault.WithCode(fault.Internal),
+	)
+
+	ErrInvalidSchema = fault.New(
+		"invalid json schema",
+		fault.WithCode(fault.Internal),
+	)
+)
+
+// schemaPrinter renders library messages for keywords without a template.
+var schemaPrinter = message.NewPrinter(language.English)
+
+// SchemaValidator validates raw JSON documents against registered JSON
+// Schema files. It is the schema-first alternative to struct tags, for
+// contracts owned by an external standards body. Failures are reported with
+// the same FieldError shape and message registry used by Validator.
+type SchemaValidator struct {
+	mu       sync.RWMutex
+	schemas  map[string]*jsonschema.Schema
+	config   *Config
+	messages *Messages
+	logger   *slog.Logger
+}
+
+func NewSchemaValidator(cfg *Config, logger *slog.Logger) *SchemaValidator {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SchemaValidator{
+		schemas:  make(map[string]*jsonschema.Schema),
+		config:   cfg,
+		messages: DefaultMessages(cfg.Locale),
+		logger:   logger,
+	}
+}
+
+// Messages returns the registry used to render schema failures.
+func (sv *SchemaValidator) Messages() *Messages {
+	return sv.messages
+}
+
+// Register compiles schema and stores it under name, typically the route it
+// validates (e.g. "enrollment.create").
+func (sv *SchemaValidator) Register(name string, schema []byte) error {
+	if name == "" {
+		return fault.Wrap(ErrInvalidInput, "schema name cannot be empty")
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fault.Wrap(ErrInvalidSchema, "schema is not valid JSON",
+			fault.WithWrappedErr(err),
+			fault.WithContext("schema", name),
+		)
+	}
+
+	url := "mem://schemas/" + name + ".json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, doc); err != nil {
+		return fault.Wrap(ErrInvalidSchema, "failed to add schema resource",
+			fault.WithWrappedErr(err),
+			fault.WithContext("schema", name),
+		)
+	}
+
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return fault.Wrap(ErrInvalidSchema, "failed to compile schema",
+			fault.WithWrappedErr(err),
+			fault.WithContext("schema", name),
+		)
+	}
+
+	sv.mu.Lock()
+	sv.schemas[name] = compiled
+	sv.mu.Unlock()
+
+	return nil
+}
+
+// RegisterFS registers every file matching pattern in fsys, usually an
+// embed.FS, naming each schema after its file name without extension.
+func (sv *SchemaValidator) RegisterFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fault.Wrap(ErrInvalidInput, "invalid schema pattern",
+			fault.WithWrappedErr(err),
+			fault.WithContext("pattern", pattern),
+		)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fault.Wrap(ErrInvalidSchema, "failed to read schema file",
+				fault.WithWrappedErr(err),
+				fault.WithContext("file", file),
+			)
+		}
+
+		name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		if err := sv.Register(name, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks body against the schema registered under name.
+func (sv *SchemaValidator) Validate(ctx context.Context, name string, body []byte) error {
+	sv.mu.RLock()
+	schema, ok := sv.schemas[name]
+	sv.mu.RUnlock()
+
+	if !ok {
+		return fault.Wrap(ErrSchemaNotFound, "unknown schema",
+			fault.WithContext("schema", name),
+		)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fault.Wrap(ErrInvalidInput, "body is not valid JSON",
+			fault.WithWrappedErr(err),
+			fault.WithCode(fault.Invalid),
+		)
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+
+	var schemaErr *jsonschema.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return fault.Wrap(err, "unexpected schema validation error",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("schema", name),
+		)
+	}
+
+	fieldErrs := ValidationErrors(sv.fieldErrors(sv.config.locale(ctx), schemaErr, nil))
+
+	if sv.config.EnableLogging {
+		sv.logger.ErrorContext(ctx, "Schema validation failed",
+			"schema", name,
+			"errors", len(fieldErrs),
+		)
+	}
+
+	return fieldErrs.Fault(fault.WithContext("schema", name))
+}
+
+func (sv *SchemaValidator) fieldErrors(locale string, err *jsonschema.ValidationError, acc []FieldError) []FieldError {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			acc = sv.fieldErrors(locale, cause, acc)
+		}
+		return acc
+	}
+
+	if required, ok := err.ErrorKind.(*kind.Required); ok {
+		for _, missing := range required.Missing {
+			location := append(append([]string{}, err.InstanceLocation...), missing)
+			acc = append(acc, sv.newFieldError(locale, location, "required", err))
+		}
+		return acc
+	}
+
+	tag := "schema"
+	if keyword := err.ErrorKind.KeywordPath(); len(keyword) > 0 {
+		tag = keyword[0]
+	}
+
+	return append(acc, sv.newFieldError(locale, err.InstanceLocation, tag, err))
+}
+
+func (sv *SchemaValidator) newFieldError(locale string, location []string, tag string, err *jsonschema.ValidationError) FieldError {
+	fe := FieldError{
+		Path:       instancePath(location),
+		Tag:        tag,
+		Severity:   SeverityError,
+		MessageKey: MessageKey(tag),
+	}
+	if len(location) > 0 {
+		fe.Field = location[len(location)-1]
+	}
+
+	if msg, ok := sv.messages.render(locale, fe); ok {
+		fe.Message = msg
+	} else {
+		fe.Message = err.ErrorKind.LocalizedString(schemaPrinter)
+	}
+
+	return fe
+}
+
+// instancePath renders a JSON pointer token list as "guardians[1].phone".
+func instancePath(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		if _, err := strconv.Atoi(token); err == nil {
+			sb.WriteString("[" + token + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(token)
+	}
+	return sb.String()
+}
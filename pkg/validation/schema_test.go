@@ -0,0 +1,75 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/marcelofabianov/validation"
+)
+
+func newTestSchemaValidator(t *testing.T) *validation.SchemaValidator {
+	t.Helper()
+
+	cfg := validation.DefaultConfig()
+	cfg.EnableLogging = false
+
+	sv := validation.NewSchemaValidator(cfg, nil)
+	if err := sv.RegisterFS(os.DirFS("testdata/schemas"), "*.json"); err != nil {
+		t.Fatalf("RegisterFS() error = %v", err)
+	}
+	return sv
+}
+
+func TestSchemaValidator(t *testing.T) {
+	ctx := context.Background()
+	sv := newTestSchemaValidator(t)
+
+	t.Run("accepts valid document", func(t *testing.T) {
+		body := []byte(`{"name":"Ana Maria","guardians":[{"phone":"11999999999"}]}`)
+		if err := sv.Validate(ctx, "guardian", body); err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	})
+
+	t.Run("reports paths of failed keywords", func(t *testing.T) {
+		body := []byte(`{"name":"Jo","guardians":[{"phone":"11999999999"},{}]}`)
+
+		err := sv.Validate(ctx, "guardian", body)
+		if !errors.Is(err, validation.ErrValidationFailed) {
+			t.Fatalf("expected ErrValidationFailed, got %v", err)
+		}
+
+		fErr, ok := fault.AsFault(err)
+		if !ok {
+			t.Fatalf("expected fault error, got %T", err)
+		}
+		errs, _ := fErr.Context["validation_errors"].(map[string]interface{})
+		if _, ok := errs["guardians[1].phone"]; !ok {
+			t.Errorf("expected guardians[1].phone failure, got %v", errs)
+		}
+		if _, ok := errs["name"]; !ok {
+			t.Errorf("expected name failure, got %v", errs)
+		}
+	})
+
+	t.Run("rejects unknown schema", func(t *testing.T) {
+		if err := sv.Validate(ctx, "missing", []byte(`{}`)); !errors.Is(err, validation.ErrSchemaNotFound) {
+			t.Errorf("expected ErrSchemaNotFound, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed json", func(t *testing.T) {
+		if err := sv.Validate(ctx, "guardian", []byte(`{`)); !fault.IsInvalid(err) {
+			t.Errorf("expected invalid input error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid schema", func(t *testing.T) {
+		if err := sv.Register("broken", []byte(`{"type": 12}`)); !errors.Is(err, validation.ErrInvalidSchema) {
+			t.Errorf("expected ErrInvalidSchema, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	"golang.org/x/text/language"
+)
+
+type localeKey struct{}
+
+// supportedLocales are the locales with translations, in the order
+// Accept-Language ties are broken.
+var supportedLocales = []language.Tag{language.BrazilianPortuguese, language.English}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+// WithLocale returns a copy of ctx asking for messages in locale (LocalePTBR
+// or LocaleEN). Struct, StructAll and SchemaValidator.Validate use it
+// instead of Config.Locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// WithAcceptLanguage is WithLocale with the best supported match of an
+// Accept-Language header. ctx is returned unchanged when nothing matches.
+func WithAcceptLanguage(ctx context.Context, header string) context.Context {
+	if locale := MatchLocale(header); locale != "" {
+		return WithLocale(ctx, locale)
+	}
+	return ctx
+}
+
+// MatchLocale returns the supported locale that best matches an
+// Accept-Language header ("pt-BR,pt;q=0.9,en;q=0.8"), or "" when none does.
+func MatchLocale(header string) string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return ""
+	}
+	if index == 0 {
+		return LocalePTBR
+	}
+	return LocaleEN
+}
+
+// LocaleFromContext returns the locale set by WithLocale, or "".
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+func (cfg *Config) locale(ctx context.Context) string {
+	if locale := LocaleFromContext(ctx); locale != "" {
+		return locale
+	}
+	return cfg.Locale
+}
+
+// translators holds the go-playground translations of the built-in tags,
+// used for tags without a template in Messages.
+type translators struct {
+	byLocale map[string]ut.Translator
+}
+
+func newTranslators(validates ...*validator.Validate) *translators {
+	uni := ut.New(en.New(), en.New(), pt_BR.New())
+
+	t := &translators{byLocale: make(map[string]ut.Translator)}
+	t.byLocale[LocaleEN], _ = uni.GetTranslator("en")
+	t.byLocale[LocalePTBR], _ = uni.GetTranslator("pt_BR")
+
+	for _, v := range validates {
+		// Registration only fails on malformed built-in templates.
+		_ = en_translations.RegisterDefaultTranslations(v, t.byLocale[LocaleEN])
+		_ = pt_BR_translations.RegisterDefaultTranslations(v, t.byLocale[LocalePTBR])
+	}
+	return t
+}
+
+// translate renders fieldErr in locale, if there is a translation for its
+// tag.
+func (t *translators) translate(locale string, fieldErr validator.FieldError) (string, bool) {
+	trans, ok := t.byLocale[locale]
+	if !ok {
+		return "", false
+	}
+	msg := fieldErr.Translate(trans)
+	// Tags without a translation come back as the raw validator error.
+	if msg == "" || msg == fieldErr.Error() {
+		return "", false
+	}
+	return msg, true
+}
@@ -0,0 +1,77 @@
+package validation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type signupForm struct {
+	Name     string `json:"name" validate:"required,min=3"`
+	Nickname string `json:"nickname" validate:"alpha"`
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	v := newTestValidator()
+	form := signupForm{Name: "Jo", Nickname: "jo2"}
+
+	messages := func(ctx context.Context) map[string]string {
+		t.Helper()
+		err := v.Struct(ctx, form)
+		fields, ok := validation.AsValidationErrors(err)
+		if !ok {
+			t.Fatalf("expected validation errors, got %v", err)
+		}
+		byPath := map[string]string{}
+		for _, fe := range fields {
+			byPath[fe.Path] = fe.Message
+		}
+		return byPath
+	}
+
+	t.Run("config locale by default", func(t *testing.T) {
+		msgs := messages(context.Background())
+		if msgs["name"] != "name deve ter no mínimo 3" {
+			t.Errorf("unexpected name message %q", msgs["name"])
+		}
+		if !strings.Contains(msgs["nickname"], "nickname") || strings.Contains(msgs["nickname"], "failed validation") {
+			t.Errorf("expected a pt-BR translation for alpha, got %q", msgs["nickname"])
+		}
+	})
+
+	t.Run("locale from context", func(t *testing.T) {
+		msgs := messages(validation.WithLocale(context.Background(), validation.LocaleEN))
+		if msgs["name"] != "name must be at least 3" {
+			t.Errorf("unexpected name message %q", msgs["name"])
+		}
+		if msgs["nickname"] != "nickname can only contain alphabetic characters" {
+			t.Errorf("unexpected nickname message %q", msgs["nickname"])
+		}
+	})
+
+	t.Run("locale from Accept-Language", func(t *testing.T) {
+		ctx := validation.WithAcceptLanguage(context.Background(), "en-US,en;q=0.9,pt;q=0.5")
+		if msgs := messages(ctx); msgs["name"] != "name must be at least 3" {
+			t.Errorf("unexpected name message %q", msgs["name"])
+		}
+	})
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := map[string]string{
+		"pt-BR,pt;q=0.9":       validation.LocalePTBR,
+		"pt-PT":                validation.LocalePTBR,
+		"en-GB,en;q=0.8":       validation.LocaleEN,
+		"fr-FR;q=0.9,en;q=0.5": validation.LocaleEN,
+		"ja":                   "",
+		"":                     "",
+	}
+
+	for header, expected := range tests {
+		if got := validation.MatchLocale(header); got != expected {
+			t.Errorf("MatchLocale(%q) = %q, want %q", header, got, expected)
+		}
+	}
+}
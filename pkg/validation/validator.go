@@ -2,37 +2,53 @@ package validation
 
 import (
 "context"
+"errors"
 "fmt"
 "log/slog"
 "reflect"
 "strings"
 "sync"
+"time"
 
 "github.com/go-playground/validator/v10"
 "github.com/marcelofabianov/fault"
+"github.com/marcelofabianov/redact"
 )
 
 type Validator interface {
 Struct(ctx context.Context, s any) error
+StructAll(ctx context.Context, s any) (*Report, error)
 Field(ctx context.Context, field any, tag string) error
 RegisterCustom(tag string, fn validator.Func) error
+RegisterCustomCtx(tag string, fn validator.FuncCtx) error
+RegisterRemote(tag string, fn RemoteFunc, timeout time.Duration) error
+RegisterModifier(name string, fn Modifier) error
+Messages() *Messages
 }
 
 type validatorImpl struct {
 validate         *validator.Validate
+warnValidate     *validator.Validate
 logger           *slog.Logger
 config           *Config
+messages         *Messages
+translators      *translators
+sanitizer        *sanitizer
 mu               sync.RWMutex
-sensitiveFields  map[string]bool
-customValidators map[string]validator.Func
-}
+redactor         *redact.Redactor
+customValidators map[string]validator.FuncCtx
+contextualTags   map[string]bool
 
-var (
-defaultSensitiveFields = []string{
-"password", "senha", "token", "secret", "apikey", "api_key",
-"credit_card", "card_number", "cvv", "pin", "private_key",
+// memo caches Struct results when Config.ResultCacheTTL is set and
+// memoTypes whether a struct type can be memoized.
+memo      *resultCache
+memoTypes sync.Map
+
+// warnTypes caches whether a struct type has `warn` rules.
+warnTypes sync.Map
 }
 
+var (
 ErrValidationFailed = fault.New(
 "validation failed",
 fault.WithCode(fault.Invalid),
@@ -53,6 +69,37 @@ if logger == nil {
 logger = slog.Default()
 }
 
+v := newValidate()
+wv := newValidate()
+wv.SetTagName(warnTagName)
+
+redactor := cfg.Redactor
+switch {
+case !cfg.SanitizeSensitiveData:
+// No rules: values are still flattened for logging, just not masked.
+redactor = redact.New(&redact.Config{})
+case redactor == nil:
+redactCfg := redact.DefaultConfig()
+redactCfg.Fields = append(redactCfg.Fields, cfg.AdditionalSensitiveFields...)
+redactor = redact.New(redactCfg)
+}
+
+return &validatorImpl{
+validate:         v,
+warnValidate:     wv,
+logger:           logger,
+config:           cfg,
+messages:         DefaultMessages(cfg.Locale),
+translators:      newTranslators(v, wv),
+sanitizer:        newSanitizer(),
+redactor:         redactor,
+customValidators: make(map[string]validator.FuncCtx),
+contextualTags:   make(map[string]bool),
+memo:             newResultCache(cfg.ResultCacheTTL, cfg.ResultCacheSize),
+}
+}
+
+func newValidate() *validator.Validate {
 v := validator.New()
 
 v.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -65,37 +112,51 @@ return fld.Name
 }
 return name
 })
+registerContextValidators(v)
+registerFormatValidators(v)
+
+return v
+}
 
-sensitiveMap := make(map[string]bool)
-for _, field := range defaultSensitiveFields {
-sensitiveMap[strings.ToLower(field)] = true
+func (vi *validatorImpl) Struct(ctx context.Context, s any) error {
+if s == nil {
+return fault.Wrap(ErrInvalidInput, "struct cannot be nil")
 }
-for _, field := range cfg.AdditionalSensitiveFields {
-sensitiveMap[strings.ToLower(field)] = true
+
+s, err := vi.sanitizer.sanitize(s)
+if err != nil {
+return err
 }
 
-return &validatorImpl{
-validate:         v,
-logger:           logger,
-config:           cfg,
-sensitiveFields:  sensitiveMap,
-customValidators: make(map[string]validator.Func),
+vi.structWarnings(ctx, s)
+
+key, memoize := vi.memoKey(ctx, s)
+if memoize {
+if cached, ok := vi.memo.get(key); ok {
+return cached
 }
 }
 
-func (vi *validatorImpl) Struct(ctx context.Context, s any) error {
-if s == nil {
-return fault.Wrap(ErrInvalidInput, "struct cannot be nil")
+err = vi.validateStruct(ctx, s)
+if memoize && (err == nil || errors.Is(err, ErrValidationFailed)) {
+vi.memo.put(key, err)
+}
+return err
 }
 
+func (vi *validatorImpl) validateStruct(ctx context.Context, s any) error {
+ctx, remote := withRemoteErrors(ctx)
 err := vi.validate.StructCtx(ctx, s)
+if remoteErr := remote.err(); remoteErr != nil {
+return vi.remoteFailure(ctx, remoteErr)
+}
 if err == nil {
 return nil
 }
 
 if valErrs, ok := err.(validator.ValidationErrors); ok {
 sanitized := vi.sanitizeStruct(s)
-faultErr := vi.buildValidationError(valErrs)
+faultErr := vi.buildValidationError(vi.config.locale(ctx), valErrs)
 
 if vi.config.EnableLogging {
 vi.logger.ErrorContext(ctx, "Struct validation failed",
@@ -122,6 +183,66 @@ vi.logger.ErrorContext(ctx, "Unexpected validation error",
 return faultErr
 }
 
+// StructAll runs both the blocking `validate` rules and the warning-level
+// `warn` rules and reports every failure instead of stopping at the first
+// error. The returned error is only set for invalid input or unexpected
+// validator failures; rule failures are carried by the Report.
+func (vi *validatorImpl) StructAll(ctx context.Context, s any) (*Report, error) {
+if s == nil {
+return nil, fault.Wrap(ErrInvalidInput, "struct cannot be nil")
+}
+
+s, err := vi.sanitizer.sanitize(s)
+if err != nil {
+return nil, err
+}
+
+report := &Report{}
+
+errs, err := vi.collect(ctx, vi.validate, s, SeverityError)
+if err != nil {
+return nil, err
+}
+report.Errors = errs
+
+warnings, err := vi.collect(ctx, vi.warnValidate, s, SeverityWarning)
+if err != nil {
+return nil, err
+}
+report.Warnings = warnings
+
+if vi.config.EnableLogging && (!report.Valid() || report.HasWarnings()) {
+vi.logger.WarnContext(ctx, "Struct audit found failed rules",
+"struct_type", fmt.Sprintf("%T", s),
+"struct_data", vi.sanitizeStruct(s),
+"errors", len(report.Errors),
+"warnings", len(report.Warnings),
+)
+}
+
+return report, nil
+}
+
+func (vi *validatorImpl) collect(ctx context.Context, v *validator.Validate, s any, severity Severity) ([]FieldError, error) {
+ctx, remote := withRemoteErrors(ctx)
+err := v.StructCtx(ctx, s)
+if remoteErr := remote.err(); remoteErr != nil {
+return nil, vi.remoteFailure(ctx, remoteErr)
+}
+if err == nil {
+return nil, nil
+}
+
+if valErrs, ok := err.(validator.ValidationErrors); ok {
+return vi.newFieldErrors(vi.config.locale(ctx), valErrs, severity), nil
+}
+
+return nil, fault.Wrap(err, "unexpected validation error",
+fault.WithCode(fault.Internal),
+fault.WithContext("severity", string(severity)),
+)
+}
+
 func (vi *validatorImpl) Field(ctx context.Context, field any, tag string) error {
 if field == nil {
 return fault.Wrap(ErrInvalidInput, "field cannot be nil")
@@ -131,7 +252,11 @@ if tag == "" {
 return fault.Wrap(ErrInvalidInput, "validation tag cannot be empty")
 }
 
+ctx, remote := withRemoteErrors(ctx)
 err := vi.validate.VarCtx(ctx, field, tag)
+if remoteErr := remote.err(); remoteErr != nil {
+return vi.remoteFailure(ctx, remoteErr)
+}
 if err == nil {
 return nil
 }
@@ -144,6 +269,7 @@ fmt.Sprintf("field validation failed for tag '%s'", tag),
 fault.WithCode(fault.Invalid),
 fault.WithContext("tag", tag),
 fault.WithContext("field_value", sanitizedValue),
+fault.WithContext(FieldsContextKey, ValidationErrors(vi.newFieldErrors(vi.config.locale(ctx), valErrs, SeverityError))),
 )
 
 if vi.config.EnableLogging {
@@ -172,6 +298,26 @@ return faultErr
 }
 
 func (vi *validatorImpl) RegisterCustom(tag string, fn validator.Func) error {
+var fnCtx validator.FuncCtx
+if fn != nil {
+fnCtx = func(_ context.Context, fl validator.FieldLevel) bool {
+return fn(fl)
+}
+}
+
+return vi.registerCustom(tag, fnCtx, false)
+}
+
+// RegisterCustomCtx registers a rule that receives the context passed to
+// Struct, StructAll or Field, so it can read request values placed there
+// with WithParams (see Param).
+func (vi *validatorImpl) RegisterCustomCtx(tag string, fn validator.FuncCtx) error {
+return vi.registerCustom(tag, fn, true)
+}
+
+// registerCustom registers fn on both validates. Contextual rules depend on
+// more than the validated value, so structs using them are never memoized.
+func (vi *validatorImpl) registerCustom(tag string, fn validator.FuncCtx, contextual bool) error {
 vi.mu.Lock()
 defer vi.mu.Unlock()
 
@@ -183,99 +329,48 @@ if fn == nil {
 return fault.Wrap(ErrInvalidInput, "custom validator function cannot be nil")
 }
 
-if err := vi.validate.RegisterValidation(tag, fn); err != nil {
+for _, v := range []*validator.Validate{vi.validate, vi.warnValidate} {
+if err := v.RegisterValidationCtx(tag, fn); err != nil {
 return fault.Wrap(err, "failed to register custom validator",
 fault.WithContext("tag", tag),
 )
 }
-
-vi.customValidators[tag] = fn
-return nil
-}
-
-func (vi *validatorImpl) buildValidationError(valErrs validator.ValidationErrors) error {
-var messages []string
-contexts := make(map[string]interface{})
-
-for i, fieldErr := range valErrs {
-msg := fmt.Sprintf("field '%s' failed validation '%s'",
-fieldErr.Field(),
-fieldErr.Tag(),
-)
-
-if fieldErr.Param() != "" {
-msg += fmt.Sprintf(" (param: %s)", fieldErr.Param())
-}
-
-messages = append(messages, msg)
-contexts[fmt.Sprintf("error_%d", i)] = msg
-}
-
-return fault.Wrap(
-ErrValidationFailed,
-strings.Join(messages, "; "),
-fault.WithContext("validation_errors", contexts),
-fault.WithContext("error_count", len(valErrs)),
-fault.WithCode(fault.Invalid),
-)
-}
-
-func (vi *validatorImpl) sanitizeStruct(s any) map[string]interface{} {
-result := make(map[string]interface{})
-
-val := reflect.ValueOf(s)
-if val.Kind() == reflect.Ptr {
-val = val.Elem()
 }
 
-if val.Kind() != reflect.Struct {
-return result
+vi.customValidators[tag] = fn
+if contextual {
+vi.contextualTags[tag] = true
+} else {
+delete(vi.contextualTags, tag)
 }
-
-typ := val.Type()
-for i := 0; i < val.NumField(); i++ {
-field := typ.Field(i)
-fieldValue := val.Field(i)
-
-if !field.IsExported() {
-continue
+vi.memoTypes.Clear()
+return nil
 }
 
-jsonTag := field.Tag.Get("json")
-if jsonTag == "-" {
-continue
+// Messages returns the registry used to render failed rules, so callers can
+// register their own templates per tag or field.
+func (vi *validatorImpl) Messages() *Messages {
+return vi.messages
 }
 
-fieldName := field.Name
-if jsonTag != "" {
-parts := strings.Split(jsonTag, ",")
-if parts[0] != "" {
-fieldName = parts[0]
+func (vi *validatorImpl) remoteFailure(ctx context.Context, err error) error {
+if vi.config.EnableLogging {
+vi.logger.ErrorContext(ctx, "Remote validation failed", "error", err.Error())
 }
+return err
 }
 
-result[fieldName] = vi.sanitizeValue(fieldValue.Interface(), fieldName)
+func (vi *validatorImpl) buildValidationError(locale string, valErrs validator.ValidationErrors) error {
+return ValidationErrors(vi.newFieldErrors(locale, valErrs, SeverityError)).Fault()
 }
 
-return result
+func (vi *validatorImpl) sanitizeStruct(s any) map[string]interface{} {
+return vi.redactor.Struct(s)
 }
 
 func (vi *validatorImpl) sanitizeValue(value any, fieldName string) interface{} {
 if !vi.config.SanitizeSensitiveData {
 return value
 }
-
-if vi.isSensitiveField(fieldName) {
-return "***REDACTED***"
-}
-
-return value
-}
-
-func (vi *validatorImpl) isSensitiveField(fieldName string) bool {
-vi.mu.RLock()
-defer vi.mu.RUnlock()
-
-lowerField := strings.ToLower(fieldName)
-return vi.sensitiveFields[lowerField]
+return vi.redactor.Value(fieldName, value)
 }
@@ -0,0 +1,236 @@
+package validation_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/validation"
+)
+
+type enrollmentForm struct {
+	Name  string `json:"name" validate:"required,min=3"`
+	Email string `json:"email" validate:"required,email"`
+	Phone string `json:"phone" warn:"required"`
+	Notes string `json:"notes" warn:"max=10"`
+}
+
+func newTestValidator() validation.Validator {
+	cfg := validation.DefaultConfig()
+	cfg.EnableLogging = false
+	return validation.New(cfg, nil)
+}
+
+func TestStructAll(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	t.Run("collects errors and warnings", func(t *testing.T) {
+		report, err := v.StructAll(ctx, enrollmentForm{
+			Name:  "Jo",
+			Notes: "provisional data pending review",
+		})
+		if err != nil {
+			t.Fatalf("StructAll() error = %v", err)
+		}
+
+		if report.Valid() {
+			t.Error("expected report to be invalid")
+		}
+		if len(report.Errors) != 2 {
+			t.Fatalf("expected 2 errors, got %d: %+v", len(report.Errors), report.Errors)
+		}
+		if len(report.Warnings) != 2 {
+			t.Fatalf("expected 2 warnings, got %d: %+v", len(report.Warnings), report.Warnings)
+		}
+		for _, w := range report.Warnings {
+			if w.Severity != validation.SeverityWarning {
+				t.Errorf("expected warning severity, got %s", w.Severity)
+			}
+		}
+	})
+
+	t.Run("warnings alone keep the report valid", func(t *testing.T) {
+		report, err := v.StructAll(ctx, enrollmentForm{
+			Name:  "John",
+			Email: "john@example.com",
+		})
+		if err != nil {
+			t.Fatalf("StructAll() error = %v", err)
+		}
+
+		if !report.Valid() {
+			t.Errorf("expected report to be valid, got errors %+v", report.Errors)
+		}
+		if !report.HasWarnings() {
+			t.Error("expected warnings for missing phone")
+		}
+	})
+
+	t.Run("warnings do not block Struct", func(t *testing.T) {
+		err := v.Struct(ctx, enrollmentForm{
+			Name:  "John",
+			Email: "john@example.com",
+		})
+		if err != nil {
+			t.Errorf("Struct() error = %v", err)
+		}
+	})
+
+	t.Run("rejects nil input", func(t *testing.T) {
+		if _, err := v.StructAll(ctx, nil); err == nil {
+			t.Error("expected error for nil input")
+		}
+	})
+}
+
+type documentForm struct {
+	CPF  string `json:"cpf" validate:"required"`
+	Name string `json:"name" validate:"min=3"`
+}
+
+func TestStructWarnings(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := validation.DefaultConfig()
+	v := validation.New(cfg, slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	ctx, warnings := validation.CollectWarnings(context.Background())
+	err := v.Struct(ctx, enrollmentForm{
+		Name:  "John",
+		Email: "john@example.com",
+		Notes: "provisional data pending review",
+	})
+	if err != nil {
+		t.Fatalf("Struct() error = %v", err)
+	}
+
+	list := warnings.List()
+	if len(list) != 2 {
+		t.Fatalf("expected 2 warnings, got %+v", list)
+	}
+	for _, w := range list {
+		if w.Severity != validation.SeverityWarning {
+			t.Errorf("expected warning severity, got %s", w.Severity)
+		}
+	}
+
+	if !strings.Contains(logs.String(), `"msg":"Struct validation warnings"`) ||
+		!strings.Contains(logs.String(), "notes:max") {
+		t.Errorf("expected warnings to be logged, got %s", logs.String())
+	}
+
+	t.Run("without a collector warnings are only logged", func(t *testing.T) {
+		logs.Reset()
+		if err := v.Struct(context.Background(), enrollmentForm{Name: "John", Email: "john@example.com"}); err != nil {
+			t.Errorf("Struct() error = %v", err)
+		}
+		if !strings.Contains(logs.String(), "phone:required") {
+			t.Errorf("expected warnings to be logged, got %s", logs.String())
+		}
+	})
+}
+
+func TestMessages(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses default locale templates", func(t *testing.T) {
+		v := newTestValidator()
+
+		report, err := v.StructAll(ctx, documentForm{Name: "Jo"})
+		if err != nil {
+			t.Fatalf("StructAll() error = %v", err)
+		}
+
+		got := map[string]string{}
+		for _, fe := range report.Errors {
+			got[fe.Field] = fe.Message
+		}
+		if got["cpf"] != "cpf é obrigatório" {
+			t.Errorf("unexpected cpf message %q", got["cpf"])
+		}
+		if got["name"] != "name deve ter no mínimo 3" {
+			t.Errorf("unexpected name message %q", got["name"])
+		}
+	})
+
+	t.Run("field override wins over tag template", func(t *testing.T) {
+		v := newTestValidator()
+		v.Messages().SetField(validation.LocalePTBR, "cpf", "required", "Informe o CPF")
+
+		err := v.Struct(ctx, documentForm{Name: "John"})
+		if err == nil {
+			t.Fatal("expected validation error")
+		}
+		if !strings.Contains(err.Error(), "Informe o CPF") {
+			t.Errorf("expected custom message in %q", err.Error())
+		}
+	})
+
+	t.Run("falls back to generic message for unknown tags", func(t *testing.T) {
+		m := validation.NewMessages(validation.LocaleEN)
+		msg := m.Resolve(validation.LocaleEN, validation.FieldError{Field: "code", Tag: "custom", Param: "x"})
+		if msg != "field 'code' failed validation 'custom' (param: x)" {
+			t.Errorf("unexpected fallback %q", msg)
+		}
+	})
+
+	t.Run("interpolates parameters and keys", func(t *testing.T) {
+		m := validation.NewMessages(validation.LocaleEN)
+		m.SetTag(validation.LocaleEN, "max", "{field} up to {param}")
+		msg := m.Resolve(validation.LocaleEN, validation.FieldError{Field: "notes", Tag: "max", Param: "10"})
+		if msg != "notes up to 10" {
+			t.Errorf("unexpected message %q", msg)
+		}
+		if validation.MessageKey("max") != "validation.max" {
+			t.Errorf("unexpected key %q", validation.MessageKey("max"))
+		}
+	})
+}
+
+type guardian struct {
+	Name  string `json:"name" validate:"required"`
+	Phone string `json:"phone" validate:"required"`
+}
+
+type nestedEnrollment struct {
+	Student struct {
+		Name string `json:"name" validate:"required"`
+	} `json:"student"`
+	Guardians []guardian `json:"guardians" validate:"dive"`
+}
+
+func TestFieldPaths(t *testing.T) {
+	ctx := context.Background()
+	v := newTestValidator()
+
+	form := nestedEnrollment{
+		Guardians: []guardian{
+			{Name: "Ana", Phone: "11999999999"},
+			{Name: "Bruno"},
+		},
+	}
+
+	report, err := v.StructAll(ctx, form)
+	if err != nil {
+		t.Fatalf("StructAll() error = %v", err)
+	}
+
+	paths := map[string]string{}
+	for _, fe := range report.Errors {
+		paths[fe.Path] = fe.Field
+	}
+
+	if paths["student.name"] != "name" {
+		t.Errorf("expected student.name path, got %v", paths)
+	}
+	if paths["guardians[1].phone"] != "phone" {
+		t.Errorf("expected guardians[1].phone path, got %v", paths)
+	}
+
+	err = v.Struct(ctx, form)
+	if err == nil || !strings.Contains(err.Error(), "guardians[1].phone") {
+		t.Errorf("expected path in error message, got %v", err)
+	}
+}
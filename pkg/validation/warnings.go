@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type warningsKey struct{}
+
+// Warnings collects the warning-level failures of the Struct calls made
+// with a context from CollectWarnings. It is safe for concurrent use.
+type Warnings struct {
+	mu   sync.Mutex
+	list []FieldError
+}
+
+// CollectWarnings returns a copy of ctx whose Struct calls record the
+// failed `warn` rules in the returned Warnings. Struct still succeeds when
+// only warnings fail, so contracts can be tightened without breaking
+// existing clients: warn first, then promote the rule to `validate`.
+func CollectWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// List returns the warnings recorded so far.
+func (w *Warnings) List() []FieldError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]FieldError(nil), w.list...)
+}
+
+func (w *Warnings) add(fes []FieldError) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.list = append(w.list, fes...)
+}
+
+// structWarnings evaluates the `warn` rules of s for Struct, logging the
+// failures and recording them in the context's Warnings. Warnings never
+// block, so errors of the warning pass are ignored.
+func (vi *validatorImpl) structWarnings(ctx context.Context, s any) {
+	if !vi.hasWarnRules(reflect.TypeOf(s)) {
+		return
+	}
+
+	warnings, err := vi.collect(ctx, vi.warnValidate, s, SeverityWarning)
+	if err != nil || len(warnings) == 0 {
+		return
+	}
+
+	if vi.config.EnableLogging {
+		paths := make([]string, 0, len(warnings))
+		for _, fe := range warnings {
+			paths = append(paths, fe.Path+":"+fe.Tag)
+		}
+		vi.logger.WarnContext(ctx, "Struct validation warnings",
+			"struct_type", fmt.Sprintf("%T", s),
+			"warnings", paths,
+		)
+	}
+
+	if w, ok := ctx.Value(warningsKey{}).(*Warnings); ok {
+		w.add(warnings)
+	}
+}
+
+// hasWarnRules reports, once per type, whether t has `warn` tags anywhere,
+// so structs without them skip the warning pass.
+func (vi *validatorImpl) hasWarnRules(t reflect.Type) bool {
+	if cached, ok := vi.warnTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	has := typeHasTags(t, warnTagName, map[reflect.Type]bool{})
+	vi.warnTypes.Store(t, has)
+	return has
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelofabianov/fault"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultAdminHost keeps the admin listener on loopback unless
+// WEB_HTTP_ADMIN_HOST says otherwise, since pprof and the log level must
+// not be reachable from outside the pod by accident.
+const DefaultAdminHost = "127.0.0.1"
+
+// AdminRoutes configures the handlers of NewAdminRouter.
+type AdminRoutes struct {
+	// Readiness serves /health/ready and defaults to ReadinessHandler();
+	// wrap it with Drain.Readiness so the pod leaves the endpoints on drain.
+	Readiness http.Handler
+	// Gatherer is served on /metrics and defaults to
+	// prometheus.DefaultGatherer.
+	Gatherer prometheus.Gatherer
+	// LogLevel is mounted on /log/level when set, e.g. the LevelHandler of
+	// the logger package.
+	LogLevel http.Handler
+	// PreStop is mounted on /admin/prestop when set, e.g.
+	// Drain.PreStopHandler.
+	PreStop http.Handler
+	// Startup is mounted on /health/startup when set, e.g.
+	// http.HandlerFunc(StartupHandler).
+	Startup http.Handler
+	// Routes is mounted on /admin/routes when set, e.g.
+	// APIRouter.RoutesHandler.
+	Routes http.Handler
+}
+
+// NewAdminRouter returns the router of the admin listener: /health,
+// /health/ready, /metrics, /debug/pprof/, /debug/vars and /debug/runtime,
+// plus the optional log level, preStop, startup and route table endpoints.
+// None of the public middleware (rate limiting, CSRF, allowed hosts)
+// applies to it.
+func NewAdminRouter(routes AdminRoutes) *chi.Mux {
+	if routes.Readiness == nil {
+		routes.Readiness = ReadinessHandler()
+	}
+	if routes.Gatherer == nil {
+		routes.Gatherer = prometheus.DefaultGatherer
+	}
+
+	r := NewRouter()
+
+	r.Get("/health", LivenessHandler)
+	r.Method(http.MethodGet, "/health/ready", routes.Readiness)
+	r.Method(http.MethodGet, "/metrics", MetricsHandler(routes.Gatherer))
+	r.Mount("/debug", debugRoutes())
+	if routes.LogLevel != nil {
+		r.Handle("/log/level", routes.LogLevel)
+	}
+	if routes.PreStop != nil {
+		r.Handle("/admin/prestop", routes.PreStop)
+	}
+	if routes.Startup != nil {
+		r.Method(http.MethodGet, "/health/startup", routes.Startup)
+	}
+	if routes.Routes != nil {
+		r.Method(http.MethodGet, "/admin/routes", routes.Routes)
+	}
+	return r
+}
+
+// WithAdmin serves handler, usually a NewAdminRouter, on the admin listener
+// configured by WEB_HTTP_ADMIN_PORT. Without it the listener serves
+// NewAdminRouter(AdminRoutes{}). It has no effect while the port is unset.
+func WithAdmin(handler http.Handler) ServerOption {
+	return func(s *Server) {
+		s.adminHandler = handler
+	}
+}
+
+// newAdminServer builds the admin http.Server. It keeps the header and idle
+// timeouts but has no write timeout, which would cut CPU profiles short.
+func newAdminServer(cfg *Config, handler http.Handler) *http.Server {
+	if handler == nil {
+		handler = NewAdminRouter(AdminRoutes{})
+	}
+	host := cfg.HTTP.Admin.Host
+	if host == "" {
+		host = cfg.HTTP.Host
+	}
+
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", host, cfg.HTTP.Admin.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+	}
+}
+
+// startAdmin binds the admin listener and serves it in the background.
+func (s *Server) startAdmin() error {
+	ln, err := net.Listen("tcp", s.admin.Addr)
+	if err != nil {
+		return fault.Wrap(err, "failed to listen on admin address",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("addr", s.admin.Addr),
+		)
+	}
+	addr := ln.Addr().String()
+	s.adminBound.Store(&addr)
+
+	s.logger.Info("Starting admin server", "addr", addr)
+	go func() {
+		if err := s.admin.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Admin server failed", "addr", addr, "error", err)
+		}
+	}()
+	return nil
+}
+
+// AdminAddr returns the address of the admin listener once Start has bound
+// it, the configured one before that, and "" when it is disabled.
+func (s *Server) AdminAddr() string {
+	if s.admin == nil {
+		return ""
+	}
+	if addr := s.adminBound.Load(); addr != nil {
+		return *addr
+	}
+	return s.admin.Addr
+}
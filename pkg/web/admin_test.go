@@ -0,0 +1,99 @@
+package web
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerAdmin(t *testing.T) {
+	freePort := func(t *testing.T) int {
+		t.Helper()
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+		return ln.Addr().(*net.TCPAddr).Port
+	}
+
+	get := func(t *testing.T, url string) int {
+		t.Helper()
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("serves ops endpoints off the public listener", func(t *testing.T) {
+		public := NewRouter()
+		public.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+		srv := NewServer(&Config{HTTP: HTTPConfig{
+			Host:  "127.0.0.1",
+			Admin: AdminConfig{Port: freePort(t)},
+		}}, nil, public, WithAdmin(NewAdminRouter(AdminRoutes{
+			LogLevel: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			}),
+		})))
+		go func() { _ = srv.Start() }()
+		t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+		addr := waitBound(t, srv)
+		admin := "http://" + srv.AdminAddr()
+
+		for path, want := range map[string]int{
+			"/health":        http.StatusOK,
+			"/health/ready":  http.StatusOK,
+			"/metrics":       http.StatusOK,
+			"/debug/pprof/":  http.StatusOK,
+			"/log/level":     http.StatusAccepted,
+			"/admin/prestop": http.StatusNotFound,
+		} {
+			if got := get(t, admin+path); got != want {
+				t.Errorf("admin %s: expected %d, got %d", path, want, got)
+			}
+		}
+		if got := get(t, "http://"+addr+"/metrics"); got != http.StatusNotFound {
+			t.Errorf("expected /metrics off the public listener, got %d", got)
+		}
+	})
+
+	t.Run("disabled without a port", func(t *testing.T) {
+		srv := NewServer(&Config{HTTP: HTTPConfig{Host: "127.0.0.1"}}, nil, http.NotFoundHandler(),
+			WithAdmin(NewAdminRouter(AdminRoutes{})))
+		if srv.AdminAddr() != "" {
+			t.Errorf("expected no admin address, got %q", srv.AdminAddr())
+		}
+	})
+
+	t.Run("fails to start when the admin port is taken", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+
+		srv := NewServer(&Config{HTTP: HTTPConfig{
+			Host:  "127.0.0.1",
+			Admin: AdminConfig{Port: ln.Addr().(*net.TCPAddr).Port},
+		}}, nil, http.NotFoundHandler())
+
+		done := make(chan error, 1)
+		go func() { done <- srv.Start() }()
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "admin") {
+				t.Errorf("expected admin listen error, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Start did not fail")
+		}
+	})
+}
@@ -0,0 +1,330 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelofabianov/fault"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcelofabianov/web/middleware"
+)
+
+// ErrAuthNotConfigured is returned to requests for a route marked with
+// RouteAuth on an APIRouter without WithAuth, so a missing auth middleware
+// fails closed.
+var ErrAuthNotConfigured = fault.New(
+	"route requires authentication but no auth middleware is configured",
+	fault.WithCode(fault.Internal),
+)
+
+// RouteMeta describes a route registered on an APIRouter. Middleware reads
+// it with RouteMetaFrom, and RoutesHandler lists it.
+type RouteMeta struct {
+	Name          string
+	Version       string
+	AuthRequired  bool
+	RateLimitTier string
+	Timeout       time.Duration
+}
+
+// RouteOption sets route metadata, on one route or as the default of a
+// group.
+type RouteOption func(*RouteMeta)
+
+// RouteName names the route in the route table.
+func RouteName(name string) RouteOption {
+	return func(m *RouteMeta) { m.Name = name }
+}
+
+// RouteVersion records the API version of the route; Version sets it for
+// a whole group.
+func RouteVersion(version string) RouteOption {
+	return func(m *RouteMeta) { m.Version = version }
+}
+
+// RouteAuth runs the route behind the WithAuth middleware.
+func RouteAuth() RouteOption {
+	return func(m *RouteMeta) { m.AuthRequired = true }
+}
+
+// RoutePublic lifts RouteAuth inherited from the group, e.g. for a login
+// route.
+func RoutePublic() RouteOption {
+	return func(m *RouteMeta) { m.AuthRequired = false }
+}
+
+// RouteRateLimit runs the route behind the WithRateLimitTier middleware of
+// tier. A tier without middleware is not limited.
+func RouteRateLimit(tier string) RouteOption {
+	return func(m *RouteMeta) { m.RateLimitTier = tier }
+}
+
+// RouteTimeout bounds the handler to d, answering 408 when it runs out.
+func RouteTimeout(d time.Duration) RouteOption {
+	return func(m *RouteMeta) { m.Timeout = d }
+}
+
+type routeMetaKey struct{}
+
+// RouteMetaFrom returns the metadata of the APIRouter route serving ctx.
+func RouteMetaFrom(ctx context.Context) (RouteMeta, bool) {
+	meta, ok := ctx.Value(routeMetaKey{}).(RouteMeta)
+	return meta, ok
+}
+
+// APIRouter is a thin layer over the chi router of NewRouter: groups with a
+// path prefix and default metadata, and per-route metadata (auth, rate
+// limit tier, timeout) applied by the middleware registered with WithAuth
+// and WithRateLimitTier. Routes, including those added to Mux directly,
+// can be listed with RoutesHandler.
+//
+//	api := web.NewAPIRouter(cfg, logger, redisClient).
+//		WithAuth(authMiddleware).
+//		WithRateLimitTier("write", limiter.PerUserLimit(10, time.Minute, 5))
+//
+//	v1 := api.Version("v1", web.RouteAuth())
+//	v1.Get("/courses", listCourses, web.RouteTimeout(2*time.Second))
+//	v1.Post("/courses", createCourse, web.RouteRateLimit("write"))
+type APIRouter struct {
+	root        *apiRoot
+	group       bool
+	prefix      string
+	meta        RouteMeta
+	middlewares chi.Middlewares
+}
+
+type apiRoot struct {
+	mux *chi.Mux
+
+	mu     sync.RWMutex
+	auth   func(http.Handler) http.Handler
+	tiers  map[string]func(http.Handler) http.Handler
+	routes map[string]RouteMeta
+}
+
+// NewAPIRouter returns NewRouter with StandardMiddleware(cfg, logger,
+// redisClient) in place. A nil cfg leaves the middleware out.
+func NewAPIRouter(cfg *Config, logger *slog.Logger, redisClient *redis.Client) *APIRouter {
+	mux := NewRouter()
+	if cfg != nil {
+		mux.Use(StandardMiddleware(cfg, logger, redisClient)...)
+	}
+
+	return &APIRouter{root: &apiRoot{
+		mux:    mux,
+		tiers:  make(map[string]func(http.Handler) http.Handler),
+		routes: make(map[string]RouteMeta),
+	}}
+}
+
+// WithAuth sets the middleware of the routes marked with RouteAuth. Like
+// WithRateLimitTier, it must be set before the first request.
+func (a *APIRouter) WithAuth(mw func(http.Handler) http.Handler) *APIRouter {
+	a.root.mu.Lock()
+	defer a.root.mu.Unlock()
+
+	a.root.auth = mw
+	return a
+}
+
+// WithRateLimitTier sets the middleware of the routes marked with
+// RouteRateLimit(tier), e.g. a RateLimiter.PerUserLimit.
+func (a *APIRouter) WithRateLimitTier(tier string, mw func(http.Handler) http.Handler) *APIRouter {
+	a.root.mu.Lock()
+	defer a.root.mu.Unlock()
+
+	a.root.tiers[tier] = mw
+	return a
+}
+
+// Use adds middleware. On the router returned by NewAPIRouter it wraps
+// every request and, as with chi, must come before the first route; on a
+// group it wraps the routes registered on the group from then on.
+func (a *APIRouter) Use(middlewares ...func(http.Handler) http.Handler) {
+	if !a.group {
+		a.root.mux.Use(middlewares...)
+		return
+	}
+	a.middlewares = append(a.middlewares, middlewares...)
+}
+
+// Group returns a router for the routes under prefix, inheriting the
+// middleware and metadata of a, with opts as the defaults of its routes.
+func (a *APIRouter) Group(prefix string, opts ...RouteOption) *APIRouter {
+	meta := a.meta
+	for _, opt := range opts {
+		opt(&meta)
+	}
+
+	return &APIRouter{
+		root:        a.root,
+		group:       true,
+		prefix:      joinPattern(a.prefix, prefix),
+		meta:        meta,
+		middlewares: append(chi.Middlewares(nil), a.middlewares...),
+	}
+}
+
+// Version returns Group("/api/"+version) with RouteVersion(version).
+func (a *APIRouter) Version(version string, opts ...RouteOption) *APIRouter {
+	return a.Group("/api/"+version, append([]RouteOption{RouteVersion(version)}, opts...)...)
+}
+
+func (a *APIRouter) Get(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	a.Method(http.MethodGet, pattern, handler, opts...)
+}
+
+func (a *APIRouter) Post(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	a.Method(http.MethodPost, pattern, handler, opts...)
+}
+
+func (a *APIRouter) Put(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	a.Method(http.MethodPut, pattern, handler, opts...)
+}
+
+func (a *APIRouter) Patch(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	a.Method(http.MethodPatch, pattern, handler, opts...)
+}
+
+func (a *APIRouter) Delete(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	a.Method(http.MethodDelete, pattern, handler, opts...)
+}
+
+// Method registers handler for method and pattern, relative to the group
+// prefix, with the group metadata overridden by opts.
+func (a *APIRouter) Method(method, pattern string, handler http.Handler, opts ...RouteOption) {
+	meta := a.meta
+	for _, opt := range opts {
+		opt(&meta)
+	}
+	pattern = joinPattern(a.prefix, pattern)
+
+	a.root.mu.Lock()
+	a.root.routes[method+" "+pattern] = meta
+	a.root.mu.Unlock()
+
+	a.root.mux.With(a.middlewares...).Method(method, pattern, a.root.routeHandler(meta, handler))
+}
+
+// Mount attaches handler under pattern, relative to the group prefix,
+// without route metadata.
+func (a *APIRouter) Mount(pattern string, handler http.Handler) {
+	a.root.mux.With(a.middlewares...).Mount(joinPattern(a.prefix, pattern), handler)
+}
+
+// Mux returns the underlying chi router, e.g. for MountDebug.
+func (a *APIRouter) Mux() *chi.Mux {
+	return a.root.mux
+}
+
+func (a *APIRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.root.mux.ServeHTTP(w, r)
+}
+
+// routeHandler puts meta in the request context and runs handler behind
+// the middleware meta asks for, resolved on the first request so WithAuth
+// and WithRateLimitTier may follow the routes.
+func (root *apiRoot) routeHandler(meta RouteMeta, handler http.Handler) http.Handler {
+	var once sync.Once
+	var chained http.Handler
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { chained = root.chain(meta, handler) })
+		chained.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeMetaKey{}, meta)))
+	})
+}
+
+// chain wraps handler as auth, then rate limit, then timeout.
+func (root *apiRoot) chain(meta RouteMeta, handler http.Handler) http.Handler {
+	if meta.Timeout > 0 {
+		handler = middleware.Timeout(meta.Timeout)(handler)
+	}
+
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+
+	if mw, ok := root.tiers[meta.RateLimitTier]; ok && meta.RateLimitTier != "" {
+		handler = mw(handler)
+	}
+	if meta.AuthRequired {
+		if root.auth == nil {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Error(w, r, ErrAuthNotConfigured)
+			})
+		}
+		handler = root.auth(handler)
+	}
+	return handler
+}
+
+// RouteInfo is one entry of the route table.
+type RouteInfo struct {
+	Method        string `json:"method"`
+	Pattern       string `json:"pattern"`
+	Name          string `json:"name,omitempty"`
+	Version       string `json:"version,omitempty"`
+	AuthRequired  bool   `json:"auth_required"`
+	RateLimitTier string `json:"rate_limit_tier,omitempty"`
+	Timeout       string `json:"timeout,omitempty"`
+	Middlewares   int    `json:"middlewares"`
+}
+
+// Routes returns every route of the router, sorted by pattern and method,
+// with the metadata of those registered through the APIRouter.
+func (a *APIRouter) Routes() []RouteInfo {
+	a.root.mu.RLock()
+	defer a.root.mu.RUnlock()
+
+	var routes []RouteInfo
+	_ = chi.Walk(a.root.mux, func(method, pattern string, _ http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		info := RouteInfo{Method: method, Pattern: pattern, Middlewares: len(middlewares)}
+		if meta, ok := a.root.routes[method+" "+pattern]; ok {
+			info.Name = meta.Name
+			info.Version = meta.Version
+			info.AuthRequired = meta.AuthRequired
+			info.RateLimitTier = meta.RateLimitTier
+			if meta.Timeout > 0 {
+				info.Timeout = meta.Timeout.String()
+			}
+		}
+		routes = append(routes, info)
+		return nil
+	})
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// RoutesHandler serves the route table as JSON, for debugging which
+// routes are registered and how they are protected. Mount it on the admin
+// listener (AdminRoutes.Routes) or behind MountDebug's gate, never on the
+// public router.
+func (a *APIRouter) RoutesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, map[string][]RouteInfo{"routes": a.Routes()})
+	})
+}
+
+// joinPattern appends pattern to prefix; "/" alone stands for the prefix.
+func joinPattern(prefix, pattern string) string {
+	if prefix == "" {
+		return pattern
+	}
+	if pattern == "" || pattern == "/" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(pattern, "/")
+}
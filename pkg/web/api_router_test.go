@@ -0,0 +1,137 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/web"
+)
+
+func serve(handler http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIRouter(t *testing.T) {
+	var limited int
+	api := web.NewAPIRouter(&web.Config{}, nil, nil).
+		WithAuth(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer ok" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}).
+		WithRateLimitTier("write", func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				limited++
+				next.ServeHTTP(w, r)
+			})
+		})
+
+	echoMeta := func(w http.ResponseWriter, r *http.Request) {
+		meta, _ := web.RouteMetaFrom(r.Context())
+		_ = json.NewEncoder(w).Encode(meta)
+	}
+
+	v1 := api.Version("v1", web.RouteAuth())
+	v1.Get("/courses", echoMeta, web.RouteName("courses.list"))
+	v1.Post("/courses", echoMeta, web.RouteRateLimit("write"))
+	v1.Get("/status", echoMeta, web.RoutePublic())
+	v1.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, web.RouteTimeout(10*time.Millisecond))
+
+	admin := api.Group("/admin", web.RouteAuth())
+	admin.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Admin", "1")
+			next.ServeHTTP(w, r)
+		})
+	})
+	admin.Get("/", echoMeta)
+	api.Mux().Get("/raw", func(w http.ResponseWriter, r *http.Request) {})
+
+	authorized := http.Header{"Authorization": {"Bearer ok"}}
+
+	t.Run("group auth", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve(api, http.MethodGet, "/api/v1/courses", nil).Code)
+
+		w := serve(api, http.MethodGet, "/api/v1/courses", authorized)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var meta web.RouteMeta
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&meta))
+		assert.Equal(t, web.RouteMeta{Name: "courses.list", Version: "v1", AuthRequired: true}, meta)
+
+		assert.Equal(t, http.StatusOK, serve(api, http.MethodGet, "/api/v1/status", nil).Code)
+	})
+
+	t.Run("rate limit tier", func(t *testing.T) {
+		serve(api, http.MethodGet, "/api/v1/courses", authorized)
+		assert.Equal(t, 0, limited)
+
+		serve(api, http.MethodPost, "/api/v1/courses", authorized)
+		assert.Equal(t, 1, limited)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		assert.Equal(t, http.StatusRequestTimeout, serve(api, http.MethodGet, "/api/v1/slow", authorized).Code)
+	})
+
+	t.Run("group middleware", func(t *testing.T) {
+		w := serve(api, http.MethodGet, "/admin", authorized)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Admin"))
+		assert.Empty(t, serve(api, http.MethodGet, "/api/v1/status", nil).Header().Get("X-Admin"))
+	})
+
+	t.Run("standard middleware and router errors", func(t *testing.T) {
+		w := serve(api, http.MethodGet, "/missing", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("route table", func(t *testing.T) {
+		w := serve(api.RoutesHandler(), http.MethodGet, "/admin/routes", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Routes []web.RouteInfo `json:"routes"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+
+		byRoute := map[string]web.RouteInfo{}
+		for _, route := range body.Routes {
+			byRoute[route.Method+" "+route.Pattern] = route
+		}
+		assert.Equal(t, "courses.list", byRoute["GET /api/v1/courses"].Name)
+		assert.True(t, byRoute["POST /api/v1/courses"].AuthRequired)
+		assert.Equal(t, "write", byRoute["POST /api/v1/courses"].RateLimitTier)
+		assert.False(t, byRoute["GET /api/v1/status"].AuthRequired)
+		assert.Equal(t, "10ms", byRoute["GET /api/v1/slow"].Timeout)
+		assert.Equal(t, "v1", byRoute["GET /api/v1/slow"].Version)
+		assert.True(t, byRoute["GET /admin"].AuthRequired)
+		assert.Contains(t, byRoute, "GET /raw")
+	})
+}
+
+func TestAPIRouterWithoutAuth(t *testing.T) {
+	api := web.NewAPIRouter(nil, nil, nil)
+	api.Get("/private", func(w http.ResponseWriter, r *http.Request) {}, web.RouteAuth())
+
+	assert.Equal(t, http.StatusInternalServerError, serve(api, http.MethodGet, "/private", nil).Code)
+}
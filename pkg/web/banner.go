@@ -0,0 +1,136 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// ServiceInfo describes what a service runs, for LogStartup. Version and
+// Commit are usually injected with -ldflags "-X main.version=...".
+type ServiceInfo struct {
+	Name    string
+	Version string
+	// Commit defaults to the vcs.revision stamped by go build.
+	Commit string
+	// Middlewares defaults to MiddlewareNames(cfg, nil); pass
+	// MiddlewareNames(cfg, redisClient) when rate limiting is backed by Redis.
+	Middlewares []string
+	// Dependencies maps a dependency name to its target, e.g.
+	// "postgres" -> "postgres://app:secret@db:5432/app". Passwords in URLs
+	// are masked before logging.
+	Dependencies map[string]string
+}
+
+// LogStartup emits the first record of a service: build metadata, listen
+// address, enabled middleware, a summary of cfg and dependency targets, so
+// the logs show what a pod is actually running. The config summary is an
+// allow-list: secrets such as the CSRF secret are never included.
+func LogStartup(logger *slog.Logger, info ServiceInfo, cfg *Config) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	commit := info.Commit
+	if commit == "" {
+		commit = buildRevision()
+	}
+
+	middlewares := info.Middlewares
+	if middlewares == nil {
+		middlewares = MiddlewareNames(cfg, nil)
+	}
+
+	logger.Info("service starting",
+		slog.Group("build",
+			slog.String("service", info.Name),
+			slog.String("version", info.Version),
+			slog.String("commit", commit),
+			slog.String("go_version", runtime.Version()),
+		),
+		slog.String("addr", listenSummary(cfg)),
+		slog.Any("middlewares", middlewares),
+		slog.Group("config", configSummary(cfg)...),
+		slog.Group("dependencies", dependencyTargets(info.Dependencies)...),
+	)
+}
+
+func configSummary(cfg *Config) []any {
+	h := cfg.HTTP
+	return []any{
+		slog.String("environment", cfg.Environment),
+		slog.Duration("read_timeout", h.ReadTimeout),
+		slog.Duration("read_header_timeout", h.ReadHeaderTimeout),
+		slog.Duration("write_timeout", h.WriteTimeout),
+		slog.Duration("idle_timeout", h.IdleTimeout),
+		slog.Duration("drain_delay", h.DrainDelay),
+		slog.Duration("shutdown_timeout", h.ShutdownTimeout),
+		slog.Int("max_header_bytes", h.MaxHeaderBytes),
+		slog.Any("allowed_hosts", h.AllowedHosts),
+		slog.Bool("tls", h.TLS.Enabled),
+		slog.Bool("http2", h.HTTP2.Enabled),
+		slog.Bool("h2c", h.HTTP2.H2C),
+		slog.Int("admin_port", h.Admin.Port),
+		slog.Bool("debug_endpoints", h.Debug.Enabled),
+		slog.Bool("https_only", h.HTTPSOnly.Enabled),
+		slog.Bool("hsts", h.SecurityHeaders.Enabled && h.SecurityHeaders.HSTS != ""),
+		slog.Bool("echo_request_body", h.EchoRequestBody),
+		slog.Bool("cors", h.CORS.Enabled),
+		slog.Any("cors_origins", h.CORS.AllowedOrigins),
+		slog.Bool("rate_limit", h.RateLimit.Enabled),
+		slog.Int("rate_limit_rps", h.RateLimit.RequestsPerSecond),
+		slog.Int("rate_limit_burst", h.RateLimit.Burst),
+		slog.Bool("csrf", h.CSRF.Enabled),
+	}
+}
+
+func listenSummary(cfg *Config) string {
+	if len(cfg.HTTP.Listen.Addresses) > 0 {
+		return strings.Join(cfg.HTTP.Listen.Addresses, ",")
+	}
+	return fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port)
+}
+
+func dependencyTargets(deps map[string]string) []any {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, slog.String(name, redactTarget(deps[name])))
+	}
+	return attrs
+}
+
+// redactTarget masks the password of URL targets; host:port targets are
+// returned as is.
+func redactTarget(target string) string {
+	if !strings.Contains(target, "://") {
+		return target
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "<invalid url>"
+	}
+	return u.Redacted()
+}
+
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStartup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	cfg := &Config{}
+	cfg.HTTP.Port = 8080
+	cfg.HTTP.CORS.Enabled = true
+	cfg.HTTP.CSRF.Enabled = true
+	cfg.HTTP.CSRF.Secret = "csrf-secret"
+
+	LogStartup(logger, ServiceInfo{
+		Name:    "course",
+		Version: "1.2.3",
+		Commit:  "abc123",
+		Dependencies: map[string]string{
+			"postgres": "postgres://app:db-secret@db:5432/app",
+			"redis":    "redis:6379",
+		},
+	}, cfg)
+
+	assert.NotContains(t, buf.String(), "csrf-secret")
+	assert.NotContains(t, buf.String(), "db-secret")
+
+	var entry struct {
+		Msg   string `json:"msg"`
+		Addr  string `json:"addr"`
+		Build struct {
+			Service string `json:"service"`
+			Version string `json:"version"`
+			Commit  string `json:"commit"`
+		} `json:"build"`
+		Middlewares  []string          `json:"middlewares"`
+		Config       map[string]any    `json:"config"`
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.Equal(t, "service starting", entry.Msg)
+	assert.Equal(t, ":8080", entry.Addr)
+	assert.Equal(t, "course", entry.Build.Service)
+	assert.Equal(t, "1.2.3", entry.Build.Version)
+	assert.Equal(t, "abc123", entry.Build.Commit)
+	assert.Equal(t, MiddlewareNames(cfg, nil), entry.Middlewares)
+	assert.Equal(t, true, entry.Config["cors"])
+	assert.Equal(t, "redis:6379", entry.Dependencies["redis"])
+	assert.True(t, strings.HasPrefix(entry.Dependencies["postgres"], "postgres://app:xxxxx@db:5432"))
+}
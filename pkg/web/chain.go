@@ -0,0 +1,169 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/marcelofabianov/web/middleware"
+)
+
+// StandardMiddleware returns the middleware chain shared by every service,
+// driven by Config. Request ID, real IP, request framing checks, path
+// canonicalization, recovery, access logging and automatic HEAD handling
+// are always on, as are the HTTP metrics unless WEB_HTTP_METRICS_ENABLED is
+// false; the host allow-list, HTTPS-only, security headers, request body
+// echo, problem details, method override, CORS, rate limiting and CSRF are
+// added when configured through WEB_HTTP_* variables or the WEB_ENVIRONMENT
+// preset. redisClient backs the rate limiter and may be nil, in which case
+// rate limiting is skipped with a warning.
+func StandardMiddleware(cfg *Config, logger *slog.Logger, redisClient *redis.Client) []func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	secLogger := middleware.NewSecurityLogger(logger)
+
+	chain := []func(http.Handler) http.Handler{
+		middleware.RequestID(),
+		middleware.RealIP(),
+	}
+
+	if cfg.HTTP.Metrics.Enabled {
+		metrics, err := middleware.NewHTTPMetrics(prometheus.DefaultRegisterer)
+		if err != nil {
+			logger.Warn("HTTP metrics not registered, skipping", "error", err)
+		} else {
+			chain = append(chain, metrics.Middleware())
+		}
+	}
+
+	chain = append(chain,
+		middleware.RequestFraming(secLogger),
+		middleware.CanonicalPath(cfg.HTTP.Path.middlewareConfig(), secLogger),
+		middleware.Recovery(logger),
+		middleware.Logger(logger),
+		middleware.AutoHead(),
+	)
+
+	if len(cfg.HTTP.AllowedHosts) > 0 {
+		chain = append(chain, middleware.AllowedHosts(cfg.HTTP.AllowedHosts, secLogger))
+	}
+
+	if cfg.HTTP.HTTPSOnly.Enabled {
+		chain = append(chain, middleware.HTTPSOnly(cfg.HTTP.HTTPSOnly.middlewareConfig()))
+	}
+
+	if cfg.HTTP.SecurityHeaders.Enabled {
+		chain = append(chain, middleware.SecurityHeaders(cfg.HTTP.SecurityHeaders.middlewareConfig()))
+	}
+
+	if cfg.HTTP.EchoRequestBody {
+		chain = append(chain, middleware.EchoRequestBody(middleware.DefaultEchoBodyLimit))
+	}
+
+	if cfg.HTTP.ProblemDetails.Enabled {
+		chain = append(chain, ProblemDetails(cfg.HTTP.ProblemDetails))
+	}
+
+	if cfg.HTTP.MethodOverride.Enabled {
+		chain = append(chain, middleware.MethodOverride(cfg.HTTP.MethodOverride.AllowedMethods...))
+	}
+
+	if cfg.HTTP.CORS.Enabled {
+		chain = append(chain, middleware.CORS(cfg.HTTP.CORS.middlewareConfig()))
+	}
+
+	if cfg.HTTP.RateLimit.Enabled {
+		if redisClient == nil {
+			logger.Warn("Rate limiting enabled but no redis client provided, skipping")
+		} else {
+			chain = append(chain, middleware.RateLimitFromConfig(redisClient, cfg.HTTP.RateLimit.middlewareConfig(), secLogger))
+		}
+	}
+
+	if cfg.HTTP.CSRF.Enabled {
+		if cfg.HTTP.CSRF.Secret == "" {
+			// Validate rejects this; a Config built by hand fails closed
+			// instead of signing tokens with an empty key.
+			chain = append(chain, csrfNotConfigured)
+		} else {
+			csrf := middleware.NewCSRFProtectionFromConfig(cfg.HTTP.CSRF.middlewareConfig(), secLogger)
+			chain = append(chain, csrf.Protect())
+		}
+	}
+
+	return chain
+}
+
+// ErrCSRFNotConfigured is returned to unsafe requests when CSRF protection
+// is enabled without a secret, so the misconfiguration fails closed.
+var ErrCSRFNotConfigured = fault.New(
+	"CSRF protection is enabled but no secret is configured",
+	fault.WithCode(fault.Internal),
+)
+
+// csrfNotConfigured rejects every request that could change state and lets
+// GET, HEAD, OPTIONS and TRACE through.
+func csrfNotConfigured(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			next.ServeHTTP(w, r)
+		default:
+			Error(w, r, ErrCSRFNotConfigured)
+		}
+	})
+}
+
+// MiddlewareNames lists, in order, the middleware StandardMiddleware builds
+// for cfg and redisClient, for the startup record.
+func MiddlewareNames(cfg *Config, redisClient *redis.Client) []string {
+	names := []string{
+		"request_id",
+		"real_ip",
+	}
+	if cfg.HTTP.Metrics.Enabled {
+		names = append(names, "metrics")
+	}
+	names = append(names,
+		"request_framing",
+		"canonical_path",
+		"recovery",
+		"logger",
+		"auto_head",
+	)
+
+	if len(cfg.HTTP.AllowedHosts) > 0 {
+		names = append(names, "allowed_hosts")
+	}
+	if cfg.HTTP.HTTPSOnly.Enabled {
+		names = append(names, "https_only")
+	}
+	if cfg.HTTP.SecurityHeaders.Enabled {
+		names = append(names, "security_headers")
+	}
+	if cfg.HTTP.EchoRequestBody {
+		names = append(names, "echo_request_body")
+	}
+	if cfg.HTTP.ProblemDetails.Enabled {
+		names = append(names, "problem_details")
+	}
+	if cfg.HTTP.MethodOverride.Enabled {
+		names = append(names, "method_override")
+	}
+	if cfg.HTTP.CORS.Enabled {
+		names = append(names, "cors")
+	}
+	if cfg.HTTP.RateLimit.Enabled && redisClient != nil {
+		names = append(names, "rate_limit")
+	}
+	if cfg.HTTP.CSRF.Enabled {
+		names = append(names, "csrf")
+	}
+
+	return names
+}
@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/web"
+)
+
+func TestStandardMiddleware(t *testing.T) {
+	t.Run("base chain", func(t *testing.T) {
+		cfg := &web.Config{}
+
+		chain := web.StandardMiddleware(cfg, nil, nil)
+		assert.Len(t, chain, 7)
+	})
+
+	t.Run("rate limit skipped without redis", func(t *testing.T) {
+		cfg := &web.Config{}
+		cfg.HTTP.RateLimit.Enabled = true
+
+		chain := web.StandardMiddleware(cfg, nil, nil)
+		assert.Len(t, chain, 7)
+	})
+
+	t.Run("csrf enabled rejects unsafe requests without token", func(t *testing.T) {
+		cfg := &web.Config{}
+		cfg.HTTP.CSRF.Enabled = true
+		cfg.HTTP.CSRF.Secret = "secret"
+
+		chain := web.StandardMiddleware(cfg, nil, nil)
+		assert.Len(t, chain, 8)
+
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		for i := len(chain) - 1; i >= 0; i-- {
+			handler = chain[i](handler)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/courses", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("csrf without secret fails closed", func(t *testing.T) {
+		cfg := &web.Config{}
+		cfg.HTTP.CSRF.Enabled = true
+
+		chain := web.StandardMiddleware(cfg, nil, nil)
+		assert.Len(t, chain, 8)
+
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		for i := len(chain) - 1; i >= 0; i-- {
+			handler = chain[i](handler)
+		}
+
+		for method, want := range map[string]int{
+			http.MethodGet:  http.StatusOK,
+			http.MethodPost: http.StatusInternalServerError,
+		} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(method, "/courses", nil))
+			assert.Equal(t, want, w.Code, method)
+		}
+	})
+}
+
+func TestMiddlewareNames(t *testing.T) {
+	cfg := &web.Config{}
+	cfg.HTTP.AllowedHosts = []string{"api.example.com"}
+	cfg.HTTP.CORS.Enabled = true
+	cfg.HTTP.RateLimit.Enabled = true
+	cfg.HTTP.CSRF.Enabled = true
+	cfg.HTTP.CSRF.Secret = "secret"
+
+	names := web.MiddlewareNames(cfg, nil)
+	assert.Len(t, names, len(web.StandardMiddleware(cfg, nil, nil)))
+	assert.Equal(t, "request_id", names[0])
+	assert.Contains(t, names, "cors")
+	assert.NotContains(t, names, "rate_limit")
+	assert.Equal(t, "csrf", names[len(names)-1])
+}
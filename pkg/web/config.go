@@ -1,33 +1,129 @@
 package web
 
 import (
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/marcelofabianov/config"
 	"github.com/spf13/viper"
+
+	"github.com/marcelofabianov/web/middleware"
 )
 
 type Config struct {
-	HTTP HTTPConfig
+	// Environment selects the middleware preset: development (default) or
+	// production/staging. See setPresetDefaults.
+	Environment string
+	// LogFormat is "text" or "json", for NewLogger.
+	LogFormat string
+	HTTP      HTTPConfig
 }
 
 type HTTPConfig struct {
-	Host         string
-	Port         int
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
-	TLS          TLSConfig
-	CORS         CORSConfig
-	RateLimit    RateLimitConfig
+	Host              string
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	DrainDelay        time.Duration
+	ShutdownTimeout   time.Duration
+	AllowedHosts      []string
+	Path              PathConfig
+	Listen            ListenConfig
+	MethodOverride    MethodOverrideConfig
+	HTTPSOnly         HTTPSOnlyConfig
+	SecurityHeaders   SecurityHeadersConfig
+	EchoRequestBody   bool
+	ProblemDetails    ProblemDetailsConfig
+	TLS               TLSConfig
+	HTTP2             HTTP2Config
+	Admin             AdminConfig
+	Metrics           MetricsConfig
+	Debug             DebugConfig
+	CORS              CORSConfig
+	RateLimit         RateLimitConfig
+	CSRF              CSRFConfig
+}
+
+type PathConfig struct {
+	KeepTrailingSlash bool
+	Redirect          bool
+}
+
+// ListenConfig controls binding. Addresses, set through WEB_HTTP_LISTEN as a
+// comma-separated list of host:port, tcp://host:port or unix:///path
+// entries, replaces the host and port so one server can listen on several
+// TCP ports and unix sockets at once, e.g. for a sidecar proxy. Retries,
+// RetryDelay, NextFreePort and PortScan decide what Start does when a TCP
+// port is already taken. NextFreePort is meant for local development only:
+// in production a service must listen where its probes and load balancer
+// expect it.
+type ListenConfig struct {
+	Addresses    []string
+	Retries      int
+	RetryDelay   time.Duration
+	NextFreePort bool
+	PortScan     int
+}
+
+type MethodOverrideConfig struct {
+	Enabled        bool
+	AllowedMethods []string
 }
 
+type HTTPSOnlyConfig struct {
+	Enabled             bool
+	TrustForwardedProto bool
+	ExemptPaths         []string
+}
+
+type SecurityHeadersConfig struct {
+	Enabled bool
+	HSTS    string
+}
+
+// TLSConfig locates the certificate served over HTTPS. The files are
+// checked every ReloadInterval and reloaded when they change, so rotated
+// certificates (cert-manager, Vault agent) are picked up without a restart;
+// zero disables the check, leaving Server.ReloadTLS as the only trigger.
+//
+// ClientCAFile enables mTLS: client certificates are verified against the
+// CAs in it, under the ClientAuth policy (none, request, require,
+// verify_if_given or require_and_verify, the default once a CA is set).
 type TLSConfig struct {
-	Enabled  bool
-	CertFile string
-	KeyFile  string
+	Enabled        bool
+	CertFile       string
+	KeyFile        string
+	ReloadInterval time.Duration
+	ClientCAFile   string
+	ClientAuth     string
+}
+
+// HTTP2Config selects the protocols served besides HTTP/1.1: HTTP/2 over
+// TLS, and h2c (HTTP/2 without TLS, with prior knowledge) for plaintext
+// hops behind a trusted load balancer such as Envoy or a GCP backend.
+type HTTP2Config struct {
+	Enabled              bool
+	H2C                  bool
+	MaxConcurrentStreams int
+}
+
+// AdminConfig places the ops endpoints (health, metrics, pprof, log level)
+// on their own listener, away from public traffic and its middleware. A
+// zero Port disables it. LoadConfig defaults Host to DefaultAdminHost; an
+// empty Host uses the public host.
+type AdminConfig struct {
+	Host string
+	Port int
+}
+
+// MetricsConfig controls the HTTP metrics StandardMiddleware records on
+// prometheus.DefaultRegisterer.
+type MetricsConfig struct {
+	Enabled bool
 }
 
 type CORSConfig struct {
@@ -41,35 +137,109 @@ type CORSConfig struct {
 }
 
 type RateLimitConfig struct {
-	Enabled      bool
+	Enabled           bool
 	RequestsPerSecond int
-	Burst        int
+	Burst             int
+	TrustedProxies    []string
+}
+
+type CSRFConfig struct {
+	Enabled     bool
+	Secret      string
+	CookieName  string
+	HeaderName  string
+	TTL         time.Duration
+	ExemptPaths []string
 }
 
+// LoadConfig loads the WEB_* settings with config.Load. Services that load
+// several packages should call config.Load once and use LoadConfigFrom.
 func LoadConfig() (*Config, error) {
-	v := viper.New()
-	v.SetEnvPrefix("WEB")
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	if envFile := findEnvFile(); envFile != "" {
-		v.SetConfigFile(envFile)
-		_ = v.ReadInConfig()
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
 	}
+	return LoadConfigFrom(c)
+}
+
+// LoadConfigFrom loads the web section of c: WEB_* variables over the web
+// section of the config file.
+func LoadConfigFrom(c *config.Config) (*Config, error) {
+	v := c.Section("web", "WEB")
 
+	// Read before setDefaults, so an unset environment is told apart from
+	// an explicit development one.
+	env := v.GetString("environment")
 	setDefaults(v)
+	setPresetDefaults(v, env)
 
 	cfg := &Config{
+		Environment: v.GetString("environment"),
+		LogFormat:   v.GetString("log_format"),
 		HTTP: HTTPConfig{
-			Host:         v.GetString("http.host"),
-			Port:         v.GetInt("http.port"),
-			ReadTimeout:  v.GetDuration("http.read_timeout"),
-			WriteTimeout: v.GetDuration("http.write_timeout"),
-			IdleTimeout:  v.GetDuration("http.idle_timeout"),
+			Host:              v.GetString("http.host"),
+			Port:              v.GetInt("http.port"),
+			ReadTimeout:       v.GetDuration("http.read_timeout"),
+			ReadHeaderTimeout: v.GetDuration("http.read_header_timeout"),
+			WriteTimeout:      v.GetDuration("http.write_timeout"),
+			IdleTimeout:       v.GetDuration("http.idle_timeout"),
+			MaxHeaderBytes:    v.GetInt("http.max_header_bytes"),
+			DrainDelay:        v.GetDuration("http.drain_delay"),
+			ShutdownTimeout:   v.GetDuration("http.shutdown_timeout"),
+			AllowedHosts:      v.GetStringSlice("http.allowed_hosts"),
+			Path: PathConfig{
+				KeepTrailingSlash: v.GetBool("http.path.keep_trailing_slash"),
+				Redirect:          v.GetBool("http.path.redirect"),
+			},
+			Listen: ListenConfig{
+				Addresses:    listenAddresses(v),
+				Retries:      v.GetInt("http.listen.retries"),
+				RetryDelay:   v.GetDuration("http.listen.retry_delay"),
+				NextFreePort: v.GetBool("http.listen.next_free_port"),
+				PortScan:     v.GetInt("http.listen.port_scan"),
+			},
+			MethodOverride: MethodOverrideConfig{
+				Enabled:        v.GetBool("http.method_override.enabled"),
+				AllowedMethods: v.GetStringSlice("http.method_override.allowed_methods"),
+			},
+			HTTPSOnly: HTTPSOnlyConfig{
+				Enabled:             v.GetBool("http.https_only.enabled"),
+				TrustForwardedProto: v.GetBool("http.https_only.trust_forwarded_proto"),
+				ExemptPaths:         v.GetStringSlice("http.https_only.exempt_paths"),
+			},
+			SecurityHeaders: SecurityHeadersConfig{
+				Enabled: v.GetBool("http.security_headers.enabled"),
+				HSTS:    v.GetString("http.security_headers.hsts"),
+			},
+			EchoRequestBody: v.GetBool("http.echo_request_body"),
+			ProblemDetails: ProblemDetailsConfig{
+				Enabled:     v.GetBool("http.problem_details.enabled"),
+				TypeBaseURI: v.GetString("http.problem_details.type_base_uri"),
+			},
 			TLS: TLSConfig{
-				Enabled:  v.GetBool("http.tls.enabled"),
-				CertFile: v.GetString("http.tls.cert_file"),
-				KeyFile:  v.GetString("http.tls.key_file"),
+				Enabled:        v.GetBool("http.tls.enabled"),
+				CertFile:       v.GetString("http.tls.cert_file"),
+				KeyFile:        v.GetString("http.tls.key_file"),
+				ReloadInterval: v.GetDuration("http.tls.reload_interval"),
+				ClientCAFile:   v.GetString("http.tls.client_ca_file"),
+				ClientAuth:     v.GetString("http.tls.client_auth"),
+			},
+			HTTP2: HTTP2Config{
+				Enabled:              v.GetBool("http.http2.enabled"),
+				H2C:                  v.GetBool("http.http2.h2c"),
+				MaxConcurrentStreams: v.GetInt("http.http2.max_concurrent_streams"),
+			},
+			Admin: AdminConfig{
+				Host: v.GetString("http.admin.host"),
+				Port: v.GetInt("http.admin.port"),
+			},
+			Metrics: MetricsConfig{
+				Enabled: v.GetBool("http.metrics.enabled"),
+			},
+			Debug: DebugConfig{
+				Enabled:    v.GetBool("http.debug.enabled"),
+				Token:      v.GetString("http.debug.token"),
+				AllowedIPs: stringList(v, "http.debug.allowed_ips"),
 			},
 			CORS: CORSConfig{
 				Enabled:          v.GetBool("http.cors.enabled"),
@@ -84,54 +254,166 @@ func LoadConfig() (*Config, error) {
 				Enabled:           v.GetBool("http.rate_limit.enabled"),
 				RequestsPerSecond: v.GetInt("http.rate_limit.requests_per_second"),
 				Burst:             v.GetInt("http.rate_limit.burst"),
+				TrustedProxies:    v.GetStringSlice("http.rate_limit.trusted_proxies"),
+			},
+			CSRF: CSRFConfig{
+				Enabled:     v.GetBool("http.csrf.enabled"),
+				Secret:      v.GetString("http.csrf.secret"),
+				CookieName:  v.GetString("http.csrf.cookie_name"),
+				HeaderName:  v.GetString("http.csrf.header_name"),
+				TTL:         v.GetDuration("http.csrf.ttl"),
+				ExemptPaths: v.GetStringSlice("http.csrf.exempt_paths"),
 			},
 		},
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// setDefaults sets the development preset; setPresetDefaults overrides it
+// for production.
 func setDefaults(v *viper.Viper) {
+	v.SetDefault("environment", EnvDevelopment)
+	v.SetDefault("log_format", "text")
+
 	v.SetDefault("http.host", "0.0.0.0")
 	v.SetDefault("http.port", 8080)
 	v.SetDefault("http.read_timeout", 15*time.Second)
 	v.SetDefault("http.write_timeout", 15*time.Second)
 	v.SetDefault("http.idle_timeout", 60*time.Second)
-	
+	v.SetDefault("http.read_header_timeout", 5*time.Second)
+	v.SetDefault("http.max_header_bytes", 64<<10)
+	v.SetDefault("http.drain_delay", DefaultDrainDelay)
+	v.SetDefault("http.shutdown_timeout", DefaultShutdownTimeout)
+	v.SetDefault("http.allowed_hosts", []string{})
+	v.SetDefault("http.path.keep_trailing_slash", false)
+	v.SetDefault("http.path.redirect", false)
+	v.SetDefault("http.listen.retries", 0)
+	v.SetDefault("http.listen.retry_delay", 500*time.Millisecond)
+	v.SetDefault("http.listen.next_free_port", false)
+	v.SetDefault("http.listen.port_scan", 10)
+	v.SetDefault("http.method_override.enabled", false)
+	v.SetDefault("http.method_override.allowed_methods", middleware.DefaultOverrideMethods)
+
+	v.SetDefault("http.https_only.enabled", false)
+	v.SetDefault("http.https_only.trust_forwarded_proto", false)
+	v.SetDefault("http.https_only.exempt_paths", []string{"/health", "/health/ready", "/health/startup"})
+	v.SetDefault("http.security_headers.enabled", false)
+	v.SetDefault("http.security_headers.hsts", "")
+	v.SetDefault("http.echo_request_body", false)
+
 	v.SetDefault("http.tls.enabled", false)
 	v.SetDefault("http.tls.cert_file", "")
 	v.SetDefault("http.tls.key_file", "")
-	
+	v.SetDefault("http.tls.reload_interval", DefaultTLSReloadInterval)
+	v.SetDefault("http.tls.client_ca_file", "")
+	v.SetDefault("http.tls.client_auth", "")
+	v.SetDefault("http.admin.host", DefaultAdminHost)
+	v.SetDefault("http.admin.port", 0)
+	v.SetDefault("http.metrics.enabled", true)
+	v.SetDefault("http.problem_details.enabled", false)
+	v.SetDefault("http.problem_details.type_base_uri", "")
+	v.SetDefault("http.debug.enabled", false)
+	v.SetDefault("http.debug.token", "")
+	v.SetDefault("http.debug.allowed_ips", "")
+
+	v.SetDefault("http.http2.enabled", true)
+	v.SetDefault("http.http2.h2c", false)
+	v.SetDefault("http.http2.max_concurrent_streams", DefaultHTTP2MaxConcurrentStreams)
+
 	v.SetDefault("http.cors.enabled", true)
 	v.SetDefault("http.cors.allowed_origins", []string{"*"})
 	v.SetDefault("http.cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
 	v.SetDefault("http.cors.allowed_headers", []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"})
 	v.SetDefault("http.cors.exposed_headers", []string{"X-Request-ID"})
-	v.SetDefault("http.cors.allow_credentials", true)
+	v.SetDefault("http.cors.allow_credentials", false)
 	v.SetDefault("http.cors.max_age", 300)
-	
+
 	v.SetDefault("http.rate_limit.enabled", false)
 	v.SetDefault("http.rate_limit.requests_per_second", 100)
 	v.SetDefault("http.rate_limit.burst", 50)
+	v.SetDefault("http.rate_limit.trusted_proxies", []string{})
+
+	v.SetDefault("http.csrf.enabled", false)
+	v.SetDefault("http.csrf.secret", "")
+	v.SetDefault("http.csrf.cookie_name", middleware.DefaultCSRFCookieName)
+	v.SetDefault("http.csrf.header_name", middleware.DefaultCSRFHeaderName)
+	v.SetDefault("http.csrf.ttl", middleware.DefaultCSRFTTL)
+	v.SetDefault("http.csrf.exempt_paths", []string{})
 }
 
-func findEnvFile() string {
-	dir, err := os.Getwd()
-	if err != nil {
-		return ""
+func (c PathConfig) middlewareConfig() middleware.CanonicalPathConfig {
+	return middleware.CanonicalPathConfig{
+		KeepTrailingSlash: c.KeepTrailingSlash,
+		Redirect:          c.Redirect,
 	}
+}
 
-	for i := 0; i < 5; i++ {
-		envPath := filepath.Join(dir, ".env")
-		if _, err := os.Stat(envPath); err == nil {
-			return envPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
+func (c CORSConfig) middlewareConfig() middleware.CORSConfig {
+	return middleware.CORSConfig{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
 	}
+}
+
+func (c RateLimitConfig) middlewareConfig() middleware.RateLimitConfig {
+	return middleware.RateLimitConfig{
+		Enabled:           c.Enabled,
+		RequestsPerSecond: c.RequestsPerSecond,
+		Burst:             c.Burst,
+		TrustedProxies:    c.TrustedProxies,
+	}
+}
+
+func (c CSRFConfig) middlewareConfig() middleware.CSRFConfig {
+	return middleware.CSRFConfig{
+		Enabled:     c.Enabled,
+		Secret:      c.Secret,
+		CookieName:  c.CookieName,
+		HeaderName:  c.HeaderName,
+		TTL:         c.TTL,
+		ExemptPaths: c.ExemptPaths,
+	}
+}
+
+// keysWithoutDefault are read by LoadConfigFrom but have no default, since
+// one would shadow the http.listen.* settings; LoadConfigStrict accepts
+// them.
+var keysWithoutDefault = []string{"http.listen", "http.listen.addresses"}
+
+// listenAddresses reads WEB_HTTP_LISTEN. "http.listen" also holds the
+// WEB_HTTP_LISTEN_* settings, so it is only a string when the variable is
+// set; otherwise viper returns that map, and a config file can list the
+// addresses under http.listen.addresses.
+func listenAddresses(v *viper.Viper) []string {
+	raw, ok := v.Get("http.listen").(string)
+	if !ok {
+		return v.GetStringSlice("http.listen.addresses")
+	}
+	return listFields(raw)
+}
+
+// stringList reads key as a comma-separated string from the environment or
+// as a list from a config file.
+func stringList(v *viper.Viper, key string) []string {
+	if s, ok := v.Get(key).(string); ok {
+		return listFields(s)
+	}
+	return v.GetStringSlice(key)
+}
 
-	return ""
+// listFields splits a comma- or space-separated list, dropping empty
+// entries.
+func listFields(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
 }
@@ -1,59 +1,171 @@
 package web_test
 
 import (
-"os"
-"testing"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
 
-"github.com/marcelofabianov/web"
+	"github.com/marcelofabianov/web"
 )
 
 func TestLoadConfig(t *testing.T) {
-origHost := os.Getenv("WEB_HTTP_HOST")
-origPort := os.Getenv("WEB_HTTP_PORT")
-defer func() {
-os.Setenv("WEB_HTTP_HOST", origHost)
-os.Setenv("WEB_HTTP_PORT", origPort)
-}()
-
-t.Run("loads defaults when no env vars set", func(t *testing.T) {
-os.Unsetenv("WEB_HTTP_HOST")
-os.Unsetenv("WEB_HTTP_PORT")
-
-cfg, err := web.LoadConfig()
-if err != nil {
-t.Fatalf("LoadConfig() error = %v", err)
-}
+	origHost := os.Getenv("WEB_HTTP_HOST")
+	origPort := os.Getenv("WEB_HTTP_PORT")
+	defer func() {
+		os.Setenv("WEB_HTTP_HOST", origHost)
+		os.Setenv("WEB_HTTP_PORT", origPort)
+	}()
 
-if cfg.HTTP.Host != "0.0.0.0" {
-t.Errorf("expected host 0.0.0.0, got %s", cfg.HTTP.Host)
-}
-if cfg.HTTP.Port != 8080 {
-t.Errorf("expected port 8080, got %d", cfg.HTTP.Port)
-}
-if !cfg.HTTP.CORS.Enabled {
-t.Error("expected CORS to be enabled by default")
-}
-})
+	t.Run("loads defaults when no env vars set", func(t *testing.T) {
+		os.Unsetenv("WEB_HTTP_HOST")
+		os.Unsetenv("WEB_HTTP_PORT")
 
-t.Run("loads from environment variables", func(t *testing.T) {
-os.Setenv("WEB_HTTP_HOST", "localhost")
-os.Setenv("WEB_HTTP_PORT", "3000")
-os.Setenv("WEB_HTTP_CORS_ENABLED", "false")
-defer os.Unsetenv("WEB_HTTP_CORS_ENABLED")
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
 
-cfg, err := web.LoadConfig()
-if err != nil {
-t.Fatalf("LoadConfig() error = %v", err)
-}
+		if cfg.HTTP.Host != "0.0.0.0" {
+			t.Errorf("expected host 0.0.0.0, got %s", cfg.HTTP.Host)
+		}
+		if cfg.HTTP.Port != 8080 {
+			t.Errorf("expected port 8080, got %d", cfg.HTTP.Port)
+		}
+		if cfg.HTTP.DrainDelay != web.DefaultDrainDelay {
+			t.Errorf("expected drain delay %s, got %s", web.DefaultDrainDelay, cfg.HTTP.DrainDelay)
+		}
+		if cfg.HTTP.Admin.Host != web.DefaultAdminHost {
+			t.Errorf("expected admin host %s, got %q", web.DefaultAdminHost, cfg.HTTP.Admin.Host)
+		}
+		if !cfg.HTTP.CORS.Enabled {
+			t.Error("expected CORS to be enabled by default")
+		}
+		if cfg.HTTP.CSRF.Enabled {
+			t.Error("expected CSRF to be disabled by default")
+		}
+		if cfg.HTTP.CSRF.CookieName != "csrf_token" {
+			t.Errorf("expected CSRF cookie csrf_token, got %s", cfg.HTTP.CSRF.CookieName)
+		}
+		if cfg.HTTP.CSRF.HeaderName != "X-CSRF-Token" {
+			t.Errorf("expected CSRF header X-CSRF-Token, got %s", cfg.HTTP.CSRF.HeaderName)
+		}
+	})
 
-if cfg.HTTP.Host != "localhost" {
-t.Errorf("expected host localhost, got %s", cfg.HTTP.Host)
-}
-if cfg.HTTP.Port != 3000 {
-t.Errorf("expected port 3000, got %d", cfg.HTTP.Port)
-}
-if cfg.HTTP.CORS.Enabled {
-t.Error("expected CORS to be disabled")
+	t.Run("loads from environment variables", func(t *testing.T) {
+		os.Setenv("WEB_HTTP_HOST", "localhost")
+		os.Setenv("WEB_HTTP_PORT", "3000")
+		os.Setenv("WEB_HTTP_CORS_ENABLED", "false")
+		defer os.Unsetenv("WEB_HTTP_CORS_ENABLED")
+
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+
+		if cfg.HTTP.Host != "localhost" {
+			t.Errorf("expected host localhost, got %s", cfg.HTTP.Host)
+		}
+		if cfg.HTTP.Port != 3000 {
+			t.Errorf("expected port 3000, got %d", cfg.HTTP.Port)
+		}
+		if cfg.HTTP.CORS.Enabled {
+			t.Error("expected CORS to be disabled")
+		}
+	})
+
+	t.Run("loads listen addresses", func(t *testing.T) {
+		t.Setenv("WEB_HTTP_LISTEN", "unix:///var/run/app.sock, 127.0.0.1:8081")
+		t.Setenv("WEB_HTTP_LISTEN_RETRIES", "2")
+
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+
+		want := []string{"unix:///var/run/app.sock", "127.0.0.1:8081"}
+		if !slices.Equal(cfg.HTTP.Listen.Addresses, want) {
+			t.Errorf("expected addresses %v, got %v", want, cfg.HTTP.Listen.Addresses)
+		}
+		if cfg.HTTP.Listen.Retries != 2 {
+			t.Errorf("expected 2 retries, got %d", cfg.HTTP.Listen.Retries)
+		}
+	})
 }
-})
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+web:
+  http:
+    port: 9000
+    write_timeout: 30s
+    listen:
+      addresses: ["127.0.0.1:9000", "unix:///tmp/web.sock"]
+    debug:
+      allowed_ips: ["10.0.0.0/8"]
+profiles:
+  production:
+    web:
+      environment: production
+      http:
+        port: 9443
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	t.Run("base section", func(t *testing.T) {
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.HTTP.Port != 9000 || cfg.HTTP.WriteTimeout != 30*time.Second {
+			t.Errorf("expected file values, got port %d write timeout %s", cfg.HTTP.Port, cfg.HTTP.WriteTimeout)
+		}
+		if len(cfg.HTTP.Listen.Addresses) != 2 || !slices.Equal(cfg.HTTP.Debug.AllowedIPs, []string{"10.0.0.0/8"}) {
+			t.Errorf("expected lists from the file, got %v and %v", cfg.HTTP.Listen.Addresses, cfg.HTTP.Debug.AllowedIPs)
+		}
+		if cfg.Environment != web.EnvDevelopment {
+			t.Errorf("expected development profile by default, got %s", cfg.Environment)
+		}
+	})
+
+	t.Run("profile layered over base", func(t *testing.T) {
+		t.Setenv("CONFIG_PROFILE", "production")
+
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.HTTP.Port != 9443 || cfg.HTTP.WriteTimeout != 30*time.Second {
+			t.Errorf("expected profile port over base, got port %d write timeout %s", cfg.HTTP.Port, cfg.HTTP.WriteTimeout)
+		}
+		if !cfg.HTTP.HTTPSOnly.Enabled {
+			t.Error("expected the production preset from the profile environment")
+		}
+	})
+
+	t.Run("environment wins", func(t *testing.T) {
+		t.Setenv("CONFIG_PROFILE", "production")
+		t.Setenv("WEB_HTTP_PORT", "7000")
+
+		cfg, err := web.LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.HTTP.Port != 7000 {
+			t.Errorf("expected WEB_HTTP_PORT to win, got %d", cfg.HTTP.Port)
+		}
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.toml"))
+
+		if _, err := web.LoadConfig(); err == nil {
+			t.Error("expected an error for a missing CONFIG_FILE")
+		}
+	})
 }
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrDebugForbidden is returned to requests for the debug endpoints that
+// carry neither the debug token nor come from an allowed IP.
+var ErrDebugForbidden = fault.New(
+	"debug endpoints require a token or an allowed IP",
+	fault.WithCode(fault.Forbidden),
+)
+
+// processStart approximates the process start for the uptime in
+// /debug/runtime.
+var processStart = time.Now()
+
+// DebugConfig enables the /debug endpoints on the public router via
+// MountDebug. Requests are let through when they carry Token as a bearer
+// token or X-Debug-Token header, or when the TCP peer is in AllowedIPs
+// (addresses or CIDRs). X-Forwarded-For and X-Real-IP are not considered.
+type DebugConfig struct {
+	Enabled    bool
+	Token      string
+	AllowedIPs []string
+}
+
+// MountDebug mounts /debug/pprof/, /debug/vars (expvar) and /debug/runtime
+// on r when WEB_HTTP_DEBUG_ENABLED is set, so profiles can be taken from a
+// running service without an ad-hoc build. It fails when the endpoints
+// would be unprotected, i.e. neither a token nor allowed IPs are set, or
+// when an allowed IP does not parse. On the admin listener the endpoints
+// are always served, without this gate.
+func MountDebug(r chi.Router, cfg *Config) error {
+	debug := cfg.HTTP.Debug
+	if !debug.Enabled {
+		return nil
+	}
+	if debug.Token == "" && len(debug.AllowedIPs) == 0 {
+		return fault.New("debug endpoints enabled without a token or allowed IPs",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("hint", "set WEB_HTTP_DEBUG_TOKEN or WEB_HTTP_DEBUG_ALLOWED_IPS"),
+		)
+	}
+
+	allowed, err := parseIPNets(debug.AllowedIPs)
+	if err != nil {
+		return err
+	}
+
+	r.Route("/debug", func(r chi.Router) {
+		r.Use(debugGate(debug.Token, allowed))
+		r.Mount("/", debugRoutes())
+	})
+	return nil
+}
+
+// debugRoutes serves pprof and expvar under /pprof and /vars, plus a
+// runtime summary under /runtime.
+func debugRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/runtime", debugRuntimeHandler)
+	r.Mount("/", chimiddleware.Profiler())
+	return r
+}
+
+func debugGate(token string, allowed []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if validDebugToken(r, token) || ipAllowed(peerIP(r), allowed) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			Forbidden(w, r, ErrDebugForbidden)
+		})
+	}
+}
+
+func validDebugToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got := r.Header.Get("X-Debug-Token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		got = bearer
+	}
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+type peerAddrKey struct{}
+
+// withPeerAddr records the TCP peer of a connection, which RealIP does not
+// rewrite, for checks that must not trust forwarding headers.
+func withPeerAddr(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, peerAddrKey{}, conn.RemoteAddr().String())
+}
+
+// peerIP returns the IP of the connection peer, falling back to RemoteAddr
+// for requests that did not come through a Server (e.g. httptest).
+func peerIP(r *http.Request) net.IP {
+	addr, ok := r.Context().Value(peerAddrKey{}).(string)
+	if !ok {
+		addr = r.RemoteAddr
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
+
+func ipAllowed(ip net.IP, allowed []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range allowed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPNets parses addresses and CIDRs; a bare address matches only
+// itself.
+func parseIPNets(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fault.New("invalid IP in debug allow-list",
+					fault.WithCode(fault.Invalid),
+					fault.WithContext("ip", entry),
+				)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fault.Wrap(err, "invalid CIDR in debug allow-list",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("ip", entry),
+			)
+		}
+		nets = append(nets, network)
+	}
+	return nets, nil
+}
+
+type runtimeStats struct {
+	GoVersion     string  `json:"go_version"`
+	Uptime        string  `json:"uptime"`
+	Goroutines    int     `json:"goroutines"`
+	GOMAXPROCS    int     `json:"gomaxprocs"`
+	NumCPU        int     `json:"num_cpu"`
+	HeapAlloc     uint64  `json:"heap_alloc_bytes"`
+	HeapInuse     uint64  `json:"heap_inuse_bytes"`
+	Sys           uint64  `json:"sys_bytes"`
+	NumGC         uint32  `json:"num_gc"`
+	GCPauseTotal  string  `json:"gc_pause_total"`
+	GCCPUFraction float64 `json:"gc_cpu_fraction"`
+}
+
+func debugRuntimeHandler(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	writeJSON(w, http.StatusOK, runtimeStats{
+		GoVersion:     runtime.Version(),
+		Uptime:        time.Since(processStart).Round(time.Second).String(),
+		Goroutines:    runtime.NumGoroutine(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumCPU:        runtime.NumCPU(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		GCPauseTotal:  time.Duration(mem.PauseTotalNs).String(),
+		GCCPUFraction: mem.GCCPUFraction,
+	})
+}
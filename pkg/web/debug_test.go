@@ -0,0 +1,116 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+)
+
+func TestMountDebug(t *testing.T) {
+	mount := func(t *testing.T, debug DebugConfig) http.Handler {
+		t.Helper()
+		r := NewRouter()
+		if err := MountDebug(r, &Config{HTTP: HTTPConfig{Debug: debug}}); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	get := func(h http.Handler, path, remoteAddr string, header http.Header) int {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = remoteAddr
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("not mounted when disabled", func(t *testing.T) {
+		h := mount(t, DebugConfig{Token: "secret"})
+		if got := get(h, "/debug/pprof/", "127.0.0.1:1234", http.Header{"X-Debug-Token": {"secret"}}); got != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", got)
+		}
+	})
+
+	t.Run("token", func(t *testing.T) {
+		h := mount(t, DebugConfig{Enabled: true, Token: "secret"})
+
+		if got := get(h, "/debug/pprof/", "203.0.113.9:1234", http.Header{"Authorization": {"Bearer secret"}}); got != http.StatusOK {
+			t.Errorf("expected bearer token to pass, got %d", got)
+		}
+		if got := get(h, "/debug/vars", "203.0.113.9:1234", http.Header{"X-Debug-Token": {"secret"}}); got != http.StatusOK {
+			t.Errorf("expected header token to pass, got %d", got)
+		}
+		if got := get(h, "/debug/pprof/", "203.0.113.9:1234", http.Header{"Authorization": {"Bearer wrong"}}); got != http.StatusForbidden {
+			t.Errorf("expected wrong token to be rejected, got %d", got)
+		}
+	})
+
+	t.Run("allowed IPs", func(t *testing.T) {
+		h := mount(t, DebugConfig{Enabled: true, AllowedIPs: []string{"10.0.0.0/8", "192.0.2.7"}})
+
+		if got := get(h, "/debug/runtime", "10.1.2.3:1234", nil); got != http.StatusOK {
+			t.Errorf("expected CIDR match to pass, got %d", got)
+		}
+		if got := get(h, "/debug/runtime", "192.0.2.7:1234", nil); got != http.StatusOK {
+			t.Errorf("expected address match to pass, got %d", got)
+		}
+		if got := get(h, "/debug/runtime", "192.0.2.8:1234", nil); got != http.StatusForbidden {
+			t.Errorf("expected other IP to be rejected, got %d", got)
+		}
+	})
+
+	t.Run("ignores forwarded addresses", func(t *testing.T) {
+		h := mount(t, DebugConfig{Enabled: true, AllowedIPs: []string{"10.0.0.1"}})
+
+		r := httptest.NewRequest(http.MethodGet, "/debug/runtime", nil)
+		// RealIP rewrote RemoteAddr from a spoofed header; the peer is public.
+		r.RemoteAddr = "10.0.0.1"
+		r = r.WithContext(context.WithValue(r.Context(), peerAddrKey{}, "203.0.113.9:1234"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("runtime stats", func(t *testing.T) {
+		h := mount(t, DebugConfig{Enabled: true, Token: "secret"})
+
+		r := httptest.NewRequest(http.MethodGet, "/debug/runtime", nil)
+		r.Header.Set("X-Debug-Token", "secret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		var stats runtimeStats
+		if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+			t.Fatal(err)
+		}
+		if stats.Goroutines == 0 || stats.GoVersion == "" {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("refuses to mount unprotected", func(t *testing.T) {
+		err := MountDebug(NewRouter(), &Config{HTTP: HTTPConfig{Debug: DebugConfig{Enabled: true}}})
+		var fErr *fault.Error
+		if !errors.As(err, &fErr) || fErr.Code != fault.Invalid {
+			t.Errorf("expected invalid config error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid IPs", func(t *testing.T) {
+		err := MountDebug(NewRouter(), &Config{HTTP: HTTPConfig{Debug: DebugConfig{Enabled: true, AllowedIPs: []string{"10.0.0"}}}})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const (
+	DefaultMaxBodyBytes = 1 << 20
+	DefaultMaxJSONDepth = 32
+)
+
+var (
+	ErrInvalidJSON = fault.New(
+		"invalid JSON body",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrBodyTooLarge = fault.New(
+		"request body too large",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrJSONTooDeep = fault.New(
+		"JSON body nested too deeply",
+		fault.WithCode(fault.Invalid),
+	)
+)
+
+type decodeOptions struct {
+	maxBytes              int64
+	maxDepth              int
+	disallowUnknownFields bool
+}
+
+type DecodeOption func(*decodeOptions)
+
+// WithMaxBodyBytes caps how many bytes are read from the body.
+func WithMaxBodyBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) {
+		if n > 0 {
+			o.maxBytes = n
+		}
+	}
+}
+
+// WithMaxDepth caps how deeply objects and arrays may be nested.
+func WithMaxDepth(depth int) DecodeOption {
+	return func(o *decodeOptions) {
+		if depth > 0 {
+			o.maxDepth = depth
+		}
+	}
+}
+
+// WithDisallowUnknownFields rejects object keys with no matching field in dst.
+func WithDisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// DecodeJSON streams the request body into dst. The body is bounded in size
+// and its nesting depth is checked while it is read, so deeply nested
+// payloads are rejected before the decoder allocates for them.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any, opts ...DecodeOption) error {
+	options := decodeOptions{
+		maxBytes: DefaultMaxBodyBytes,
+		maxDepth: DefaultMaxJSONDepth,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if r.Body == nil {
+		return fault.Wrap(ErrInvalidJSON, "request body is empty")
+	}
+
+	body := http.MaxBytesReader(w, r.Body, options.maxBytes)
+	depth := &depthReader{r: body, max: options.maxDepth}
+
+	dec := json.NewDecoder(depth)
+	if options.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err, options)
+	}
+
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		if depth.err != nil || isMaxBytesError(err) {
+			return decodeError(err, options)
+		}
+		return fault.Wrap(ErrInvalidJSON, "request body must contain a single JSON value")
+	}
+
+	return nil
+}
+
+// StructValidator validates a decoded request body. validation.Validator
+// from pkg/validation satisfies it.
+type StructValidator interface {
+	Struct(ctx context.Context, s any) error
+}
+
+// DecodeAndValidate decodes the request body into a T with DecodeJSON,
+// rejecting unknown fields, and validates it with v (skipped when nil). The
+// error is a fault error ready for Error: 400 for a malformed body, the
+// validator's own fault (with its per-field failures) for invalid input.
+//
+//	input, err := web.DecodeAndValidate[CreateCourseInput](w, r, validator)
+//	if err != nil {
+//		web.Error(w, r, err)
+//		return
+//	}
+func DecodeAndValidate[T any](w http.ResponseWriter, r *http.Request, v StructValidator, opts ...DecodeOption) (T, error) {
+	var dst T
+
+	opts = append([]DecodeOption{WithDisallowUnknownFields()}, opts...)
+	if err := DecodeJSON(w, r, &dst, opts...); err != nil {
+		return dst, err
+	}
+
+	if v != nil {
+		if err := v.Struct(r.Context(), &dst); err != nil {
+			return dst, err
+		}
+	}
+
+	return dst, nil
+}
+
+func decodeError(err error, options decodeOptions) error {
+	var depthErr *depthExceededError
+	switch {
+	case errors.As(err, &depthErr):
+		return fault.Wrap(ErrJSONTooDeep, "JSON body exceeds maximum nesting depth",
+			fault.WithContext("max_depth", options.maxDepth),
+		)
+	case isMaxBytesError(err):
+		return fault.Wrap(ErrBodyTooLarge, "request body exceeds maximum size",
+			fault.WithContext("max_bytes", options.maxBytes),
+		)
+	case errors.Is(err, io.EOF):
+		return fault.Wrap(ErrInvalidJSON, "request body is empty")
+	default:
+		return fault.Wrap(ErrInvalidJSON, "failed to decode JSON body",
+			fault.WithWrappedErr(err),
+		)
+	}
+}
+
+func isMaxBytesError(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+type depthExceededError struct{}
+
+func (*depthExceededError) Error() string {
+	return "json: maximum nesting depth exceeded"
+}
+
+// depthReader tracks object/array nesting on the raw bytes as they are read,
+// skipping brackets inside strings, and fails once max is exceeded.
+type depthReader struct {
+	r        io.Reader
+	max      int
+	depth    int
+	inString bool
+	escaped  bool
+	err      error
+}
+
+func (d *depthReader) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.r.Read(p)
+	for _, b := range p[:n] {
+		if d.inString {
+			switch {
+			case d.escaped:
+				d.escaped = false
+			case b == '\\':
+				d.escaped = true
+			case b == '"':
+				d.inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			d.inString = true
+		case '{', '[':
+			d.depth++
+			if d.depth > d.max {
+				d.err = &depthExceededError{}
+				return 0, d.err
+			}
+		case '}', ']':
+			d.depth--
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	decode := func(body string, opts ...DecodeOption) (payload, error) {
+		var p payload
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		err := DecodeJSON(w, r, &p, opts...)
+		return p, err
+	}
+
+	t.Run("valid body", func(t *testing.T) {
+		p, err := decode(`{"name":"ana"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Name != "ana" {
+			t.Errorf("expected name ana, got %s", p.Name)
+		}
+	})
+
+	t.Run("brackets inside strings do not count", func(t *testing.T) {
+		p, err := decode(`{"name":"[[[{{{\"]]]"}`, WithMaxDepth(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Name != `[[[{{{"]]]` {
+			t.Errorf("unexpected name %q", p.Name)
+		}
+	})
+
+	t.Run("nesting too deep", func(t *testing.T) {
+		body := `{"name":"a","extra":` + strings.Repeat("[", 10) + strings.Repeat("]", 10) + `}`
+		_, err := decode(body, WithMaxDepth(5))
+		if !errors.Is(err, ErrJSONTooDeep) {
+			t.Errorf("expected ErrJSONTooDeep, got %v", err)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		_, err := decode(`{"name":"`+strings.Repeat("a", 100)+`"}`, WithMaxBodyBytes(16))
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		_, err := decode(`{"name":"ana","age":3}`, WithDisallowUnknownFields())
+		if !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("expected ErrInvalidJSON, got %v", err)
+		}
+	})
+
+	t.Run("multiple values", func(t *testing.T) {
+		_, err := decode(`{"name":"ana"}{"name":"bia"}`)
+		if !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("expected ErrInvalidJSON, got %v", err)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		_, err := decode(``)
+		if !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("expected ErrInvalidJSON, got %v", err)
+		}
+	})
+}
+
+// requiredName is a StructValidator rejecting an empty name.
+type requiredName struct{}
+
+func (requiredName) Struct(_ context.Context, s any) error {
+	if s.(*courseInput).Name == "" {
+		return fault.New("name: required", fault.WithCode(fault.Invalid))
+	}
+	return nil
+}
+
+type courseInput struct {
+	Name string `json:"name"`
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	decode := func(body string, v StructValidator) (courseInput, error) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		return DecodeAndValidate[courseInput](w, r, v)
+	}
+
+	t.Run("decodes and validates", func(t *testing.T) {
+		input, err := decode(`{"name":"Go 101"}`, requiredName{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if input.Name != "Go 101" {
+			t.Errorf("expected name Go 101, got %q", input.Name)
+		}
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := decode(`{"name":"Go 101","price":10}`, requiredName{})
+		if !errors.Is(err, ErrInvalidJSON) {
+			t.Errorf("expected ErrInvalidJSON, got %v", err)
+		}
+	})
+
+	t.Run("returns the validation fault", func(t *testing.T) {
+		_, err := decode(`{"name":""}`, requiredName{})
+		if fault.ToResponse(err).StatusCode != http.StatusBadRequest {
+			t.Errorf("expected a 400 fault, got %v", err)
+		}
+	})
+
+	t.Run("nil validator only decodes", func(t *testing.T) {
+		if _, err := decode(`{"name":""}`, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
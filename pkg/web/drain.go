@@ -0,0 +1,138 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDrainDelay is how long a drain keeps serving after readiness
+// flips, giving the load balancer time to stop routing to the pod.
+const DefaultDrainDelay = 5 * time.Second
+
+// Drain coordinates a graceful rollout. Kubernetes sends SIGTERM and removes
+// the pod from its Service endpoints at the same time, so requests keep
+// arriving for a few seconds after shutdown starts and fail with 502s.
+// A preStop hook calling PreStopHandler closes that gap: readiness flips to
+// 503, keep-alives are disabled so clients reconnect elsewhere, and the hook
+// holds SIGTERM back until the delay has passed. It is safe for concurrent
+// use.
+type Drain struct {
+	delay    time.Duration
+	logger   *slog.Logger
+	draining atomic.Bool
+	done     chan struct{}
+	once     sync.Once
+
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func NewDrain(delay time.Duration, logger *slog.Logger) *Drain {
+	if delay < 0 {
+		delay = 0
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Drain{
+		delay:  delay,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+// Begin starts draining. Only the first call has any effect.
+func (d *Drain) Begin() {
+	d.once.Do(func() {
+		d.draining.Store(true)
+		d.logger.Info("Draining HTTP server", "delay", d.delay.String())
+
+		d.mu.Lock()
+		hooks := d.hooks
+		d.mu.Unlock()
+		for _, hook := range hooks {
+			hook()
+		}
+
+		time.AfterFunc(d.delay, func() { close(d.done) })
+	})
+}
+
+// Draining reports whether Begin has been called.
+func (d *Drain) Draining() bool {
+	return d.draining.Load()
+}
+
+// Done is closed once the drain delay has passed.
+func (d *Drain) Done() <-chan struct{} {
+	return d.done
+}
+
+// Wait blocks until the drain delay has passed or ctx is done. It does not
+// begin the drain.
+func (d *Drain) Wait(ctx context.Context) error {
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PreStopHandler begins the drain and responds once the delay has passed, so
+// a preStop httpGet hook delays SIGTERM until the pod is out of rotation.
+// Serve it only on the admin listener, through AdminRoutes.PreStop, never on
+// the public router: any caller can take the pod out of rotation. The pod's terminationGracePeriodSeconds must cover the delay
+// plus Server.Shutdown.
+func (d *Drain) PreStopHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.Begin()
+		_ = d.Wait(r.Context())
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(HealthResponse{
+			Status:    HealthStatusDraining,
+			Timestamp: time.Now(),
+			Uptime:    time.Since(startTime).String(),
+		})
+	}
+}
+
+// Readiness wraps a readiness handler (usually ReadinessHandler) so it
+// answers 503 "draining" without running checks once the drain has begun.
+func (d *Drain) Readiness(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !d.Draining() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(HealthResponse{
+			Status:    HealthStatusDraining,
+			Timestamp: time.Now(),
+			Uptime:    time.Since(startTime).String(),
+		})
+	})
+}
+
+// onBegin registers fn to run when the drain begins, or runs it now if it
+// already has.
+func (d *Drain) onBegin(fn func()) {
+	d.mu.Lock()
+	if !d.Draining() {
+		d.hooks = append(d.hooks, fn)
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+	fn()
+}
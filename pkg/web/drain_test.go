@@ -0,0 +1,103 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainReadiness(t *testing.T) {
+	drain := NewDrain(time.Hour, nil)
+	handler := drain.Readiness(ReadinessHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 before drain, got %d", rec.Code)
+	}
+
+	drain.Begin()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %d", rec.Code)
+	}
+
+	var body HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != HealthStatusDraining {
+		t.Errorf("expected status draining, got %s", body.Status)
+	}
+}
+
+func TestDrainPreStopWaitsForDelay(t *testing.T) {
+	delay := 50 * time.Millisecond
+	drain := NewDrain(delay, nil)
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	drain.PreStopHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/prestop", nil))
+
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("expected prestop to wait %s, returned after %s", delay, elapsed)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+	if !drain.Draining() {
+		t.Error("expected drain to have begun")
+	}
+
+	// A second call (the kubelet retrying) returns at once.
+	start = time.Now()
+	drain.PreStopHandler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/prestop", nil))
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("expected repeated prestop to return immediately, took %s", elapsed)
+	}
+}
+
+func TestDrainPreStopHonoursRequestContext(t *testing.T) {
+	drain := NewDrain(time.Hour, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	rec := httptest.NewRecorder()
+	drain.PreStopHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/prestop", nil).WithContext(ctx))
+
+	if !drain.Draining() {
+		t.Error("expected drain to have begun")
+	}
+}
+
+func TestServerShutdownDrains(t *testing.T) {
+	delay := 50 * time.Millisecond
+	drain := NewDrain(delay, nil)
+
+	srv := NewServer(&Config{HTTP: HTTPConfig{Host: "127.0.0.1"}}, nil, http.NotFoundHandler(), WithDrain(drain))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.httpServer.Serve(ln) }()
+
+	start := time.Now()
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !drain.Draining() {
+		t.Error("expected Shutdown to begin the drain")
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("expected Shutdown to wait %s, returned after %s", delay, elapsed)
+	}
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ETag returns an entity tag for body: a quoted, truncated SHA-256, prefixed
+// with W/ when weak. Use a weak tag when equivalent representations may
+// differ byte-wise, e.g. when map ordering or whitespace is not stable.
+func ETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// NotModified evaluates If-None-Match and, when absent, If-Modified-Since
+// against the current validators of the resource (RFC 9110 13.2.2). Only
+// GET and HEAD requests are considered; a zero lastModified skips the date
+// check.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && matchesETag(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// matchesETag applies the weak comparison If-None-Match calls for.
+func matchesETag(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// SuccessConditional writes data as JSON with a strong ETag and, when
+// lastModified is not zero, a Last-Modified header, answering 304 Not
+// Modified when the client's copy is current, so polling clients do not
+// download unchanged payloads again.
+func SuccessConditional(w http.ResponseWriter, r *http.Request, data any, lastModified time.Time) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		Error(w, r, fault.Wrap(err, "failed to encode response", fault.WithCode(fault.Internal)))
+		return
+	}
+	// Match the trailing newline of the json.Encoder used by Success.
+	body = append(body, '\n')
+
+	etag := ETag(body, false)
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if NotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// ConditionalGET buffers 200 responses to GET and HEAD requests, tags them
+// with a strong ETag unless the handler set one, and replaces them with 304
+// Not Modified when If-None-Match matches. The handler still runs, so it
+// saves bandwidth, not work; it suits static-ish endpoints such as
+// configuration or reference data, not streaming responses.
+func ConditionalGET() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buf := &conditionalWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(buf, r)
+
+			if buf.status != http.StatusOK {
+				w.WriteHeader(buf.status)
+				_, _ = w.Write(buf.body.Bytes())
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				etag = ETag(buf.body.Bytes(), false)
+				w.Header().Set("ETag", etag)
+			}
+
+			var lastModified time.Time
+			if lm := w.Header().Get("Last-Modified"); lm != "" {
+				lastModified, _ = http.ParseTime(lm)
+			}
+			if NotModified(r, etag, lastModified) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(buf.body.Bytes())
+		})
+	}
+}
+
+type conditionalWriter struct {
+	http.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (c *conditionalWriter) WriteHeader(status int) {
+	if !c.wroteHeader {
+		c.status = status
+		c.wroteHeader = true
+	}
+}
+
+func (c *conditionalWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	return c.body.Write(p)
+}
@@ -0,0 +1,125 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+func TestETag(t *testing.T) {
+	strong := ETag([]byte(`{"id":1}`), false)
+	if !strings.HasPrefix(strong, `"`) || strong != ETag([]byte(`{"id":1}`), false) {
+		t.Errorf("expected stable strong tag, got %s", strong)
+	}
+	if weak := ETag([]byte(`{"id":1}`), true); weak != "W/"+strong {
+		t.Errorf("expected weak tag of the same digest, got %s", weak)
+	}
+	if strong == ETag([]byte(`{"id":2}`), false) {
+		t.Error("expected different tags for different bodies")
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	etag := `"abc"`
+
+	tests := []struct {
+		name   string
+		method string
+		header map[string]string
+		want   bool
+	}{
+		{name: "no validators", method: http.MethodGet, want: false},
+		{name: "matching etag", method: http.MethodGet, header: map[string]string{"If-None-Match": `"x", "abc"`}, want: true},
+		{name: "weak comparison", method: http.MethodGet, header: map[string]string{"If-None-Match": `W/"abc"`}, want: true},
+		{name: "wildcard", method: http.MethodHead, header: map[string]string{"If-None-Match": "*"}, want: true},
+		{name: "stale etag", method: http.MethodGet, header: map[string]string{"If-None-Match": `"old"`}, want: false},
+		{
+			name:   "etag takes precedence over date",
+			method: http.MethodGet,
+			header: map[string]string{"If-None-Match": `"old"`, "If-Modified-Since": modified.Format(http.TimeFormat)},
+			want:   false,
+		},
+		{name: "not modified since", method: http.MethodGet, header: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, want: true},
+		{name: "modified since", method: http.MethodGet, header: map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, want: false},
+		{name: "unsafe method", method: http.MethodPut, header: map[string]string{"If-None-Match": "*"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			if got := NotModified(r, etag, modified.Add(500*time.Millisecond)); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSuccessConditional(t *testing.T) {
+	data := map[string]string{"name": "Go"}
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	SuccessConditional(w, httptest.NewRequest(http.MethodGet, "/", nil), data, modified)
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", w.Code, etag)
+	}
+	if got := w.Header().Get("Last-Modified"); got != "Sun, 01 Mar 2026 12:00:00 GMT" {
+		t.Errorf("unexpected Last-Modified %q", got)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	SuccessConditional(w, r, data, modified)
+
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected empty 304, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+}
+
+func TestConditionalGET(t *testing.T) {
+	calls := 0
+	handler := ConditionalGET()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/missing" {
+			NotFound(w, r, fault.New("setting not found", fault.WithCode(fault.NotFound)))
+			return
+		}
+		Success(w, r, http.StatusOK, map[string]string{"currency": "BRL"})
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/settings", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || !strings.Contains(w.Body.String(), "BRL") {
+		t.Fatalf("expected tagged 200, got %d %q %s", w.Code, etag, w.Body.String())
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/settings", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+		t.Errorf("expected bare 304, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("expected untagged error, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	if calls != 3 {
+		t.Errorf("expected the handler to run every time, ran %d", calls)
+	}
+}
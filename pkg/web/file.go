@@ -0,0 +1,54 @@
+package web
+
+import (
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// File streams content as a download named filename. When content is an
+// io.ReadSeeker, such as an *os.File, it is served with http.ServeContent:
+// Range and If-Range requests get 206 partial content, and the modification
+// time of files backs If-Modified-Since. Other readers are copied as they
+// are, with Accept-Ranges: none. An empty contentType is derived from the
+// filename extension.
+//
+// As with SSE streams, the server WriteTimeout is lifted for the response,
+// so large downloads and slow clients are not cut off mid-transfer.
+func File(w http.ResponseWriter, r *http.Request, content io.Reader, filename, contentType string) {
+	// Writers without deadlines, such as test recorders, have none to lift.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	if filename != "" {
+		if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); disposition != "" {
+			w.Header().Set("Content-Disposition", disposition)
+		}
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		var modtime time.Time
+		if stater, ok := content.(interface{ Stat() (fs.FileInfo, error) }); ok {
+			if info, err := stater.Stat(); err == nil {
+				modtime = info.ModTime()
+			}
+		}
+		http.ServeContent(w, r, filename, modtime, seeker)
+		return
+	}
+
+	w.Header().Set("Accept-Ranges", "none")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = io.Copy(w, content)
+	}
+}
@@ -0,0 +1,119 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileServesRanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syllabus.txt")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(header http.Header) *httptest.ResponseRecorder {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+
+		r := httptest.NewRequest(http.MethodGet, "/files/1", nil)
+		r.Header = header
+		w := httptest.NewRecorder()
+		File(w, r, f, "Syllabus 2026.txt", "")
+		return w
+	}
+
+	w := serve(http.Header{})
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" {
+		t.Fatalf("expected full body, got %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="Syllabus 2026.txt"` {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("expected type from extension, got %q", got)
+	}
+	if got := w.Header().Get("Accept-Ranges"); got != "bytes" {
+		t.Errorf("expected byte ranges, got %q", got)
+	}
+
+	w = serve(http.Header{"Range": {"bytes=2-5"}})
+	if w.Code != http.StatusPartialContent || w.Body.String() != "2345" {
+		t.Errorf("expected 206 with 2345, got %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 2-5/10" {
+		t.Errorf("unexpected Content-Range %q", got)
+	}
+
+	w = serve(http.Header{"If-Modified-Since": {modified.Format(http.TimeFormat)}})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 from the file mtime, got %d", w.Code)
+	}
+}
+
+func TestFileStreamsPlainReader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	r.Header.Set("Range", "bytes=0-1")
+	w := httptest.NewRecorder()
+
+	File(w, r, io.NopCloser(strings.NewReader("id,name\n")), "export.csv", "text/csv")
+
+	if w.Code != http.StatusOK || w.Body.String() != "id,name\n" {
+		t.Errorf("expected full body without range support, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Accept-Ranges") != "none" || w.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("unexpected headers %v", w.Header())
+	}
+}
+
+// slowReader yields chunks one delay apart.
+type slowReader struct {
+	chunks []string
+	delay  time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if len(s.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(s.delay)
+	n := copy(p, s.chunks[0])
+	s.chunks = s.chunks[1:]
+	return n, nil
+}
+
+func TestFileOutlivesWriteTimeout(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content := &slowReader{chunks: []string{"lesson-1;", "lesson-2;", "lesson-3"}, delay: 60 * time.Millisecond}
+		File(w, r, content, "lessons.txt", "")
+	}))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected the whole body past the write timeout, got %v", err)
+	}
+	if string(body) != "lesson-1;lesson-2;lesson-3" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
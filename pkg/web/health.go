@@ -19,12 +19,18 @@ const (
 	HealthStatusHealthy   HealthStatus = "healthy"
 	HealthStatusDegraded  HealthStatus = "degraded"
 	HealthStatusUnhealthy HealthStatus = "unhealthy"
+	HealthStatusDraining  HealthStatus = "draining"
+	HealthStatusStarting  HealthStatus = "starting"
 )
 
 type CheckResult struct {
-	Status  string `json:"status"`
-	Latency string `json:"latency,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Status      string `json:"status"`
+	Latency     string `json:"latency,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Criticality string `json:"criticality,omitempty"`
+
+	// duration is Latency before formatting, for HealthMetrics.
+	duration time.Duration
 }
 
 type HealthResponse struct {
@@ -33,6 +39,10 @@ type HealthResponse struct {
 	Version   string                 `json:"version,omitempty"`
 	Uptime    string                 `json:"uptime,omitempty"`
 	Checks    map[string]CheckResult `json:"checks,omitempty"`
+	// CheckedAt is when the checks ran, older than Timestamp when cached.
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+	// Pending lists the dependencies a starting service waits for.
+	Pending []string `json:"pending,omitempty"`
 }
 
 type RootResponse struct {
@@ -67,71 +77,143 @@ func LivenessHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// ReadinessHandler serves NewReadiness(checkers...) with its defaults:
+// results cached for DefaultReadinessCacheTTL and the check detail behind
+// ?verbose=1.
 func ReadinessHandler(checkers ...HealthChecker) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
-
-		checks := make(map[string]CheckResult)
-		var mu sync.Mutex
-		var wg sync.WaitGroup
-
-		for _, checker := range checkers {
-			wg.Add(1)
-			go func(c HealthChecker) {
-				defer wg.Done()
-
-				start := time.Now()
-				err := c.Check(ctx)
-				latency := time.Since(start)
-
-				result := CheckResult{
-					Status:  "healthy",
-					Latency: latency.String(),
-				}
-
-				if err != nil {
-					result.Status = "unhealthy"
-					result.Error = err.Error()
-				}
-
-				mu.Lock()
-				checks[c.Name()] = result
-				mu.Unlock()
-			}(checker)
-		}
+	return NewReadiness(checkers...).ServeHTTP
+}
+
+// Criticality of a check, set with Critical or Informational.
+const (
+	CheckCritical      = "critical"
+	CheckInformational = "informational"
+)
+
+// checkPolicy carries the per-check settings of a wrapped checker.
+type checkPolicy struct {
+	HealthChecker
+	timeout     time.Duration
+	criticality string
+}
+
+func policyOf(checker HealthChecker) checkPolicy {
+	if p, ok := checker.(*checkPolicy); ok {
+		return *p
+	}
+	return checkPolicy{HealthChecker: checker}
+}
+
+// Critical marks checker as critical: its failure alone makes readiness
+// unhealthy (503). Checkers left unmarked only do so when every check
+// fails.
+func Critical(checker HealthChecker) HealthChecker {
+	p := policyOf(checker)
+	p.criticality = CheckCritical
+	return &p
+}
+
+// Informational marks checker as informational: it is reported, and its
+// failure degrades readiness, but never makes it unhealthy. Use it for
+// dependencies the service can run without, such as a cache.
+func Informational(checker HealthChecker) HealthChecker {
+	p := policyOf(checker)
+	p.criticality = CheckInformational
+	return &p
+}
 
-		wg.Wait()
+// CheckTimeout bounds checker to d, within the timeout of the whole run,
+// so one slow dependency is reported as failed instead of holding up the
+// probe. It combines with Critical and Informational.
+func CheckTimeout(checker HealthChecker, d time.Duration) HealthChecker {
+	p := policyOf(checker)
+	p.timeout = d
+	return &p
+}
+
+// runChecks runs every checker concurrently and collects the results by
+// checker name. A check still running when its timeout, or ctx, expires is
+// reported as failed without waiting for it.
+func runChecks(ctx context.Context, checkers []HealthChecker) map[string]CheckResult {
+	checks := make(map[string]CheckResult)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(c checkPolicy) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if c.timeout > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
 
-		status := HealthStatusHealthy
-		statusCode := http.StatusOK
-		unhealthyCount := 0
+			start := time.Now()
+			done := make(chan error, 1)
+			go func() { done <- c.Check(checkCtx) }()
 
-		for _, check := range checks {
-			if check.Status == "unhealthy" {
-				unhealthyCount++
+			var err error
+			select {
+			case err = <-done:
+			case <-checkCtx.Done():
+				err = checkCtx.Err()
 			}
-		}
+			latency := time.Since(start)
 
-		if unhealthyCount > 0 {
-			if unhealthyCount == len(checks) {
-				status = HealthStatusUnhealthy
-				statusCode = http.StatusServiceUnavailable
-			} else {
-				status = HealthStatusDegraded
-				statusCode = http.StatusOK
+			result := CheckResult{
+				Status:      "healthy",
+				Latency:     latency.String(),
+				Criticality: c.criticality,
+				duration:    latency,
+			}
+
+			if err != nil {
+				result.Status = "unhealthy"
+				result.Error = err.Error()
 			}
-		}
 
-		response := HealthResponse{
-			Status:    status,
-			Timestamp: time.Now(),
-			Uptime:    time.Since(startTime).String(),
-			Checks:    checks,
+			mu.Lock()
+			checks[c.Name()] = result
+			mu.Unlock()
+		}(policyOf(checker))
+	}
+
+	wg.Wait()
+	return checks
+}
+
+// overallStatus is unhealthy when a critical check failed or every check
+// that is not informational did, and degraded when any other check failed.
+func overallStatus(checks map[string]CheckResult) HealthStatus {
+	failed, counted, countedFailed := 0, 0, 0
+	for _, check := range checks {
+		unhealthy := check.Status == "unhealthy"
+		if unhealthy {
+			failed++
 		}
+		switch check.Criticality {
+		case CheckCritical:
+			if unhealthy {
+				return HealthStatusUnhealthy
+			}
+		case CheckInformational:
+			continue
+		}
+		counted++
+		if unhealthy {
+			countedFailed++
+		}
+	}
 
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		w.WriteHeader(statusCode)
-		_ = json.NewEncoder(w).Encode(response)
+	switch {
+	case failed == 0:
+		return HealthStatusHealthy
+	case countedFailed > 0 && countedFailed == counted:
+		return HealthStatusUnhealthy
+	default:
+		return HealthStatusDegraded
 	}
 }
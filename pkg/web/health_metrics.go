@@ -0,0 +1,103 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HealthMetrics exports the results of the readiness checks, so alerting
+// can page on a degraded dependency without scraping the JSON readiness
+// endpoint. It records each run of a Readiness (see WithMetrics) rather
+// than running the checkers itself, so the metrics agree with the probe and
+// the dependencies are not checked twice:
+//
+//	health_check_status{check="db"}            1 healthy, 0 unhealthy
+//	health_check_latency_seconds{check="db"}   duration of the last check
+//	health_check_duration_seconds{check="db"}  histogram of check durations
+//	health_status                              1 healthy, 0.5 degraded, 0 unhealthy
+//	health_check_last_run_timestamp_seconds    unix time of the last run
+type HealthMetrics struct {
+	status   *prometheus.GaugeVec
+	latency  *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	overall  prometheus.Gauge
+	lastRun  prometheus.Gauge
+}
+
+// NewHealthMetrics registers the health metrics on reg. A nil reg uses
+// prometheus.DefaultRegisterer, served by MetricsHandler(nil) next to the
+// Go runtime and HTTP metrics.
+func NewHealthMetrics(reg prometheus.Registerer) (*HealthMetrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	hm := &HealthMetrics{
+		status: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "health_check_status",
+			Help: "Result of the last health check: 1 healthy, 0 unhealthy.",
+		}, []string{"check"}),
+		latency: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "health_check_latency_seconds",
+			Help: "Duration of the last health check.",
+		}, []string{"check"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "health_check_duration_seconds",
+			Help:    "Duration of the health checks.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"check"}),
+		overall: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "health_status",
+			Help: "Overall health: 1 healthy, 0.5 degraded, 0 unhealthy.",
+		}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "health_check_last_run_timestamp_seconds",
+			Help: "Unix time of the last health check run.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{hm.status, hm.latency, hm.duration, hm.overall, hm.lastRun} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return hm, nil
+}
+
+// Record refreshes the metrics from one run of the checks.
+func (hm *HealthMetrics) Record(checks map[string]CheckResult) {
+	for name, check := range checks {
+		value := 0.0
+		if check.Status == "healthy" {
+			value = 1
+		}
+		hm.status.WithLabelValues(name).Set(value)
+		hm.latency.WithLabelValues(name).Set(check.duration.Seconds())
+		hm.duration.WithLabelValues(name).Observe(check.duration.Seconds())
+	}
+
+	switch overallStatus(checks) {
+	case HealthStatusHealthy:
+		hm.overall.Set(1)
+	case HealthStatusDegraded:
+		hm.overall.Set(0.5)
+	default:
+		hm.overall.Set(0)
+	}
+
+	hm.lastRun.SetToCurrentTime()
+}
+
+// MetricsHandler serves the metrics of gatherer, negotiating the OpenMetrics
+// format when the scraper asks for it. A nil gatherer serves
+// prometheus.DefaultGatherer, where StandardMiddleware records the HTTP
+// metrics.
+func MetricsHandler(gatherer prometheus.Gatherer) http.Handler {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHealthMetricsRecord(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	hm, err := NewHealthMetrics(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hm.Record(map[string]CheckResult{
+		"db":    {Status: "healthy", duration: 20 * time.Millisecond},
+		"redis": {Status: "unhealthy", Error: "connection refused", duration: time.Second},
+	})
+
+	if got := testutil.ToFloat64(hm.status.WithLabelValues("db")); got != 1 {
+		t.Errorf("expected db status 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(hm.status.WithLabelValues("redis")); got != 0 {
+		t.Errorf("expected redis status 0, got %v", got)
+	}
+	if got := testutil.ToFloat64(hm.latency.WithLabelValues("redis")); got != 1 {
+		t.Errorf("expected redis latency 1s, got %v", got)
+	}
+	if got := testutil.ToFloat64(hm.overall); got != 0.5 {
+		t.Errorf("expected overall status 0.5 (degraded), got %v", got)
+	}
+	if got := testutil.ToFloat64(hm.lastRun); got == 0 {
+		t.Error("expected last run timestamp to be set")
+	}
+}
+
+func TestReadinessWithMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	hm, err := NewHealthMetrics(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checker := &countingChecker{name: "db"}
+	readiness := NewReadiness(checker).WithMetrics(hm)
+
+	// Cached probes neither run the checker again nor record a new sample.
+	probe(t, readiness, "/health/ready")
+	probe(t, readiness, "/health/ready")
+
+	if got := checker.calls.Load(); got != 1 {
+		t.Errorf("expected the checker to run once, ran %d times", got)
+	}
+	if got := testutil.CollectAndCount(hm.duration); got != 1 {
+		t.Errorf("expected one duration series, got %d", got)
+	}
+	if got := testutil.ToFloat64(hm.status.WithLabelValues("db")); got != 1 {
+		t.Errorf("expected db status 1, got %v", got)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "health_check_duration_seconds" {
+			if got := family.GetMetric()[0].GetHistogram().GetSampleCount(); got != 1 {
+				t.Errorf("expected one duration observation, got %d", got)
+			}
+		}
+	}
+}
+
+func TestHealthMetricsDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := NewHealthMetrics(reg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewHealthMetrics(reg); err == nil {
+		t.Error("expected error registering health metrics twice")
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	hm, err := NewHealthMetrics(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hm.Record(map[string]CheckResult{"db": {Status: "healthy"}})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	r.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+
+	MetricsHandler(reg).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("expected OpenMetrics content type, got %s", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `health_check_status{check="db"} 1`) {
+		t.Errorf("expected db status in output, got:\n%s", body)
+	}
+}
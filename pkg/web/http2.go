@@ -0,0 +1,29 @@
+package web
+
+import "net/http"
+
+// DefaultHTTP2MaxConcurrentStreams caps the streams a single HTTP/2
+// connection may have open, so one client multiplexing requests cannot
+// occupy the whole server. It matches the Go default and is well above what
+// browsers and gRPC-web clients open.
+const DefaultHTTP2MaxConcurrentStreams = 250
+
+// protocols returns the protocols and HTTP/2 settings for the http.Server.
+// HTTP/1.1 is always served.
+func (c HTTP2Config) protocols() (*http.Protocols, *http.HTTP2Config) {
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+
+	if !c.Enabled && !c.H2C {
+		return protocols, nil
+	}
+
+	protocols.SetHTTP2(c.Enabled)
+	protocols.SetUnencryptedHTTP2(c.H2C)
+
+	streams := c.MaxConcurrentStreams
+	if streams <= 0 {
+		streams = DefaultHTTP2MaxConcurrentStreams
+	}
+	return protocols, &http.HTTP2Config{MaxConcurrentStreams: streams}
+}
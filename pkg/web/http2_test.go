@@ -0,0 +1,77 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestServerH2C(t *testing.T) {
+	h2cClient := &http.Client{Transport: func() *http.Transport {
+		tr := &http.Transport{Protocols: &http.Protocols{}}
+		tr.Protocols.SetUnencryptedHTTP2(true)
+		return tr
+	}()}
+
+	serve := func(t *testing.T, cfg HTTP2Config) string {
+		t.Helper()
+		srv := NewServer(&Config{HTTP: HTTPConfig{HTTP2: cfg}}, nil,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.Proto))
+			}))
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() { _ = srv.httpServer.Serve(ln) }()
+		t.Cleanup(func() { _ = srv.httpServer.Close() })
+		return "http://" + ln.Addr().String()
+	}
+
+	t.Run("serves HTTP/2 with prior knowledge", func(t *testing.T) {
+		url := serve(t, HTTP2Config{Enabled: true, H2C: true})
+
+		resp, err := h2cClient.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.ProtoMajor != 2 {
+			t.Errorf("expected HTTP/2, got %s", resp.Proto)
+		}
+
+		resp, err = http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.ProtoMajor != 1 {
+			t.Errorf("expected HTTP/1.1 clients to keep working, got %s", resp.Proto)
+		}
+	})
+
+	t.Run("h2c is off by default", func(t *testing.T) {
+		url := serve(t, HTTP2Config{Enabled: true})
+
+		if resp, err := h2cClient.Get(url); err == nil {
+			resp.Body.Close()
+			t.Errorf("expected h2c to be refused, got %s", resp.Proto)
+		}
+	})
+}
+
+func TestHTTP2ConfigProtocols(t *testing.T) {
+	protocols, h2 := HTTP2Config{Enabled: true}.protocols()
+	if !protocols.HTTP1() || !protocols.HTTP2() || protocols.UnencryptedHTTP2() {
+		t.Errorf("unexpected protocols %s", protocols)
+	}
+	if h2 == nil || h2.MaxConcurrentStreams != DefaultHTTP2MaxConcurrentStreams {
+		t.Errorf("expected default max concurrent streams, got %+v", h2)
+	}
+
+	protocols, h2 = HTTP2Config{}.protocols()
+	if protocols.HTTP2() || h2 != nil {
+		t.Errorf("expected HTTP/1.1 only, got %s", protocols)
+	}
+}
@@ -0,0 +1,179 @@
+package web
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLatencyWindow is how far back LatencyTracker keeps samples.
+	DefaultLatencyWindow = 5 * time.Minute
+
+	// maxLatencySamples bounds memory per dependency; past it the oldest
+	// samples are dropped even if they are still inside the window.
+	maxLatencySamples = 10000
+
+	// maxErrorSamples is how many recent errors are kept per dependency.
+	maxErrorSamples = 10
+)
+
+// ErrorSample is a recent failure of a dependency call.
+type ErrorSample struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// LatencySummary reports a dependency's calls over the tracker window.
+type LatencySummary struct {
+	Window       string        `json:"window"`
+	Count        int           `json:"count"`
+	Errors       int           `json:"errors"`
+	P50          string        `json:"p50"`
+	P95          string        `json:"p95"`
+	P99          string        `json:"p99"`
+	RecentErrors []ErrorSample `json:"recent_errors,omitempty"`
+}
+
+type latencySample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+type dependencyLatency struct {
+	samples []latencySample
+	errors  []ErrorSample
+}
+
+// LatencyTracker records per-dependency call latencies over a sliding
+// window and reports p50/p95/p99 and recent errors. It is safe for
+// concurrent use.
+type LatencyTracker struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	deps map[string]*dependencyLatency
+}
+
+func NewLatencyTracker(window time.Duration) *LatencyTracker {
+	if window <= 0 {
+		window = DefaultLatencyWindow
+	}
+
+	return &LatencyTracker{
+		window: window,
+		now:    time.Now,
+		deps:   make(map[string]*dependencyLatency),
+	}
+}
+
+// Observe records one call to dependency.
+func (lt *LatencyTracker) Observe(dependency string, latency time.Duration, err error) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	now := lt.now()
+
+	dep, ok := lt.deps[dependency]
+	if !ok {
+		dep = &dependencyLatency{}
+		lt.deps[dependency] = dep
+	}
+
+	dep.samples = append(dep.samples, latencySample{at: now, latency: latency, failed: err != nil})
+	if len(dep.samples) > maxLatencySamples {
+		dep.samples = dep.samples[len(dep.samples)-maxLatencySamples:]
+	}
+	lt.prune(dep, now)
+
+	if err != nil {
+		dep.errors = append(dep.errors, ErrorSample{Time: now, Error: err.Error()})
+		if len(dep.errors) > maxErrorSamples {
+			dep.errors = dep.errors[len(dep.errors)-maxErrorSamples:]
+		}
+	}
+}
+
+// Track runs fn and records its latency and error under dependency.
+func (lt *LatencyTracker) Track(dependency string, fn func() error) error {
+	start := lt.now()
+	err := fn()
+	lt.Observe(dependency, lt.now().Sub(start), err)
+	return err
+}
+
+// Dependencies returns the names of every tracked dependency, sorted.
+func (lt *LatencyTracker) Dependencies() []string {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	names := make([]string, 0, len(lt.deps))
+	for name := range lt.deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Summary returns the latency summary of dependency over the window.
+func (lt *LatencyTracker) Summary(dependency string) LatencySummary {
+	now := lt.now()
+	summary := LatencySummary{Window: lt.window.String()}
+
+	lt.mu.Lock()
+	dep, ok := lt.deps[dependency]
+	if !ok {
+		lt.mu.Unlock()
+		return summary
+	}
+	lt.prune(dep, now)
+
+	latencies := make([]time.Duration, len(dep.samples))
+	for i, sample := range dep.samples {
+		latencies[i] = sample.latency
+		if sample.failed {
+			summary.Errors++
+		}
+	}
+	for _, sample := range dep.errors {
+		if now.Sub(sample.Time) <= lt.window {
+			summary.RecentErrors = append(summary.RecentErrors, sample)
+		}
+	}
+	lt.mu.Unlock()
+
+	slices.Sort(latencies)
+	summary.Count = len(latencies)
+	summary.P50 = percentile(latencies, 0.50).String()
+	summary.P95 = percentile(latencies, 0.95).String()
+	summary.P99 = percentile(latencies, 0.99).String()
+
+	return summary
+}
+
+// prune drops samples older than the window. Samples are appended in time
+// order, so the stale ones are a prefix.
+func (lt *LatencyTracker) prune(dep *dependencyLatency, now time.Time) {
+	cutoff := now.Add(-lt.window)
+	i := sort.Search(len(dep.samples), func(i int) bool {
+		return dep.samples[i].at.After(cutoff)
+	})
+	if i > 0 {
+		dep.samples = append(dep.samples[:0], dep.samples[i:]...)
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
@@ -0,0 +1,74 @@
+package web
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyTrackerPercentiles(t *testing.T) {
+	tracker := NewLatencyTracker(time.Minute)
+
+	for i := 1; i <= 100; i++ {
+		tracker.Observe("postgres", time.Duration(i)*time.Millisecond, nil)
+	}
+
+	summary := tracker.Summary("postgres")
+	if summary.Count != 100 {
+		t.Errorf("expected 100 samples, got %d", summary.Count)
+	}
+	if summary.P50 != "50ms" || summary.P95 != "95ms" || summary.P99 != "99ms" {
+		t.Errorf("unexpected percentiles p50=%s p95=%s p99=%s", summary.P50, summary.P95, summary.P99)
+	}
+	if summary.Window != "1m0s" {
+		t.Errorf("expected window 1m0s, got %s", summary.Window)
+	}
+}
+
+func TestLatencyTrackerWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewLatencyTracker(5 * time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Observe("redis", time.Second, errors.New("i/o timeout"))
+	now = now.Add(6 * time.Minute)
+	tracker.Observe("redis", 2*time.Millisecond, nil)
+
+	summary := tracker.Summary("redis")
+	if summary.Count != 1 || summary.Errors != 0 {
+		t.Errorf("expected only the recent sample, got count=%d errors=%d", summary.Count, summary.Errors)
+	}
+	if len(summary.RecentErrors) != 0 {
+		t.Errorf("expected expired error samples to be hidden, got %v", summary.RecentErrors)
+	}
+	if summary.P99 != "2ms" {
+		t.Errorf("expected p99 2ms, got %s", summary.P99)
+	}
+}
+
+func TestLatencyTrackerTrack(t *testing.T) {
+	tracker := NewLatencyTracker(0)
+	failure := errors.New("connection refused")
+
+	for i := 0; i < maxErrorSamples+5; i++ {
+		_ = tracker.Track("payments", func() error { return failure })
+	}
+	if err := tracker.Track("payments", func() error { return nil }); err != nil {
+		t.Errorf("expected Track to return fn's error, got %v", err)
+	}
+
+	summary := tracker.Summary("payments")
+	if summary.Errors != maxErrorSamples+5 {
+		t.Errorf("expected %d errors, got %d", maxErrorSamples+5, summary.Errors)
+	}
+	if len(summary.RecentErrors) != maxErrorSamples {
+		t.Errorf("expected %d error samples, got %d", maxErrorSamples, len(summary.RecentErrors))
+	}
+
+	if deps := tracker.Dependencies(); len(deps) != 1 || deps[0] != "payments" {
+		t.Errorf("unexpected dependencies %v", deps)
+	}
+	if empty := tracker.Summary("unknown"); empty.Count != 0 {
+		t.Errorf("expected empty summary for unknown dependency, got %+v", empty)
+	}
+}
@@ -0,0 +1,190 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// Query parameters read by clients of list endpoints and written into the
+// ListPage links.
+const (
+	PageParam    = "page"
+	PerPageParam = "per_page"
+	CursorParam  = "cursor"
+)
+
+// ListQuota caps how much a list response may cost to serialize. Zero values
+// disable the corresponding limit.
+type ListQuota struct {
+	MaxBytes    int
+	MaxDuration time.Duration
+}
+
+// ListMeta describes the page of a list response. Page and PerPage are for
+// offset pagination, NextCursor for cursor pagination; Total is optional
+// because counting is not always affordable.
+type ListMeta struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Truncated is set by SuccessList when a ListQuota cut the page short.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ListLinks are the navigation links of a list response, relative to the
+// request URL so they survive proxies rewriting the host.
+type ListLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// ListPage is the envelope written by SuccessList.
+type ListPage struct {
+	Data  json.RawMessage `json:"data"`
+	Meta  ListMeta        `json:"meta"`
+	Links ListLinks       `json:"links"`
+}
+
+// CursorFunc returns the continuation cursor that resumes a list right after
+// item.
+type CursorFunc[T any] func(item T) string
+
+type listOptions[T any] struct {
+	quota  ListQuota
+	cursor CursorFunc[T]
+}
+
+// ListOption configures SuccessList.
+type ListOption[T any] func(*listOptions[T])
+
+// WithListQuota caps the serialization of the page. When the quota is
+// exhausted the page is cut short after the last item that fit, cursor of
+// that item is returned in next_cursor and a Warning header is set, so
+// clients get a partial page instead of a timeout. At least one item is
+// always written.
+func WithListQuota[T any](quota ListQuota, cursor CursorFunc[T]) ListOption[T] {
+	return func(o *listOptions[T]) {
+		o.quota = quota
+		o.cursor = cursor
+	}
+}
+
+// SuccessList writes items in the standard list envelope: data, meta and
+// links built from meta and the request URL. Items are encoded one at a
+// time so a quota can cut the page short; nil items are written as []. The
+// envelope is always JSON, whatever the Accept header.
+func SuccessList[T any](w http.ResponseWriter, r *http.Request, items []T, meta ListMeta, opts ...ListOption[T]) {
+	var o listOptions[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	start := time.Now()
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+
+	truncated := ""
+	written := 0
+	for i, item := range items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			Error(w, r, fault.Wrap(err, "failed to encode list item",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("index", i),
+			))
+			return
+		}
+
+		if written > 0 {
+			if o.quota.MaxBytes > 0 && buf.Len()+len(encoded)+2 > o.quota.MaxBytes {
+				truncated = "size"
+				break
+			}
+			if o.quota.MaxDuration > 0 && time.Since(start) > o.quota.MaxDuration {
+				truncated = "time"
+				break
+			}
+			buf.WriteByte(',')
+		}
+
+		buf.Write(encoded)
+		written++
+	}
+	buf.WriteByte(']')
+
+	if truncated != "" {
+		meta.Truncated = true
+		meta.NextCursor = ""
+		if o.cursor != nil {
+			meta.NextCursor = o.cursor(items[written-1])
+		}
+		w.Header().Set("Warning", `199 - "response truncated: `+truncated+` quota exceeded"`)
+	}
+
+	// The envelope embeds JSON-encoded items, so it is always JSON.
+	writeJSON(w, http.StatusOK, ListPage{
+		Data:  buf.Bytes(),
+		Meta:  meta,
+		Links: listLinks(r.URL, meta, written),
+	})
+}
+
+// listLinks builds the links from meta: a cursor link when there is a next
+// cursor, page links otherwise. Without Total, a full page is assumed to
+// have a next one.
+func listLinks(u *url.URL, meta ListMeta, count int) ListLinks {
+	links := ListLinks{Self: u.RequestURI()}
+
+	if meta.NextCursor != "" {
+		links.Next = withQuery(u, map[string]string{CursorParam: meta.NextCursor}, PageParam)
+		return links
+	}
+	if meta.Page <= 0 || meta.PerPage <= 0 || meta.Truncated {
+		return links
+	}
+
+	page := func(n int) string {
+		return withQuery(u, map[string]string{
+			PageParam:    strconv.Itoa(n),
+			PerPageParam: strconv.Itoa(meta.PerPage),
+		}, CursorParam)
+	}
+
+	links.First = page(1)
+	if meta.Page > 1 {
+		links.Prev = page(meta.Page - 1)
+	}
+	if meta.Total != nil {
+		last := max(1, int((*meta.Total+int64(meta.PerPage)-1)/int64(meta.PerPage)))
+		links.Last = page(last)
+		if meta.Page < last {
+			links.Next = page(meta.Page + 1)
+		}
+	} else if count >= meta.PerPage {
+		links.Next = page(meta.Page + 1)
+	}
+	return links
+}
+
+// withQuery returns the path and query of u with set applied and drop
+// removed.
+func withQuery(u *url.URL, set map[string]string, drop string) string {
+	query := u.Query()
+	query.Del(drop)
+	for key, value := range set {
+		query.Set(key, value)
+	}
+	next := url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: query.Encode()}
+	return next.RequestURI()
+}
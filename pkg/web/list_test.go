@@ -0,0 +1,118 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSuccessList(t *testing.T) {
+	type course struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	items := make([]course, 10)
+	for i := range items {
+		items[i] = course{ID: i + 1, Name: "course"}
+	}
+	cursor := func(c course) string { return strconv.Itoa(c.ID) }
+
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) ([]course, ListPage) {
+		t.Helper()
+		var page ListPage
+		if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+			t.Fatalf("failed to decode page: %v", err)
+		}
+		var data []course
+		if err := json.Unmarshal(page.Data, &data); err != nil {
+			t.Fatalf("failed to decode data: %v", err)
+		}
+		return data, page
+	}
+
+	t.Run("within quota", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SuccessList(w, httptest.NewRequest(http.MethodGet, "/", nil), items, ListMeta{}, WithListQuota(ListQuota{}, cursor))
+
+		data, page := decode(t, w)
+		if len(data) != 10 || page.Meta.Truncated || page.Meta.NextCursor != "" {
+			t.Errorf("expected full page, got %d items (truncated=%v)", len(data), page.Meta.Truncated)
+		}
+		if w.Header().Get("Warning") != "" {
+			t.Error("expected no Warning header")
+		}
+	})
+
+	t.Run("size quota truncates", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SuccessList(w, httptest.NewRequest(http.MethodGet, "/", nil), items, ListMeta{}, WithListQuota(ListQuota{MaxBytes: 100}, cursor))
+
+		data, page := decode(t, w)
+		if len(data) == 0 || len(data) >= 10 {
+			t.Fatalf("expected partial page, got %d items", len(data))
+		}
+		if !page.Meta.Truncated || page.Meta.NextCursor != strconv.Itoa(data[len(data)-1].ID) {
+			t.Errorf("unexpected cursor %q for %d items", page.Meta.NextCursor, len(data))
+		}
+		if w.Header().Get("Warning") == "" {
+			t.Error("expected Warning header")
+		}
+	})
+
+	t.Run("always writes one item", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SuccessList(w, httptest.NewRequest(http.MethodGet, "/", nil), items, ListMeta{}, WithListQuota(ListQuota{MaxBytes: 1}, cursor))
+
+		data, page := decode(t, w)
+		if len(data) != 1 || page.Meta.NextCursor != "1" {
+			t.Errorf("expected one item with cursor 1, got %d items and cursor %q", len(data), page.Meta.NextCursor)
+		}
+		if page.Links.Next != "/?cursor=1" {
+			t.Errorf("expected cursor link, got %q", page.Links.Next)
+		}
+	})
+
+	t.Run("page links from total", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/courses?page=2&per_page=10&sort=name", nil)
+		total := int64(35)
+		SuccessList(w, r, items, ListMeta{Page: 2, PerPage: 10, Total: &total})
+
+		_, page := decode(t, w)
+		if page.Meta.Page != 2 || page.Meta.PerPage != 10 || page.Meta.Total == nil || *page.Meta.Total != 35 {
+			t.Errorf("unexpected meta %+v", page.Meta)
+		}
+		want := ListLinks{
+			Self:  "/courses?page=2&per_page=10&sort=name",
+			First: "/courses?page=1&per_page=10&sort=name",
+			Prev:  "/courses?page=1&per_page=10&sort=name",
+			Next:  "/courses?page=3&per_page=10&sort=name",
+			Last:  "/courses?page=4&per_page=10&sort=name",
+		}
+		if page.Links != want {
+			t.Errorf("expected links %+v, got %+v", want, page.Links)
+		}
+	})
+
+	t.Run("last page without total", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SuccessList(w, httptest.NewRequest(http.MethodGet, "/courses?page=3", nil), items[:4], ListMeta{Page: 3, PerPage: 10})
+
+		_, page := decode(t, w)
+		if page.Links.Next != "" || page.Links.Last != "" {
+			t.Errorf("expected no next or last link, got %+v", page.Links)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		SuccessList[course](w, httptest.NewRequest(http.MethodGet, "/courses", nil), nil, ListMeta{})
+
+		if got := w.Body.String(); got != `{"data":[],"meta":{},"links":{"self":"/courses"}}`+"\n" {
+			t.Errorf("unexpected body %s", got)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrPortInUse is returned by Start when the address stays taken after the
+// configured retries and port scan. Its context carries a remediation hint.
+var ErrPortInUse = fault.New(
+	"address already in use",
+	fault.WithCode(fault.Conflict),
+)
+
+// listeners binds every address of Listen.Addresses, or the configured
+// host and port when there are none. On failure the listeners already
+// opened are closed.
+func (s *Server) listeners() ([]net.Listener, error) {
+	if len(s.listen.Addresses) == 0 {
+		ln, err := s.bind()
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{ln}, nil
+	}
+
+	lns := make([]net.Listener, 0, len(s.listen.Addresses))
+	for _, raw := range s.listen.Addresses {
+		ln, err := s.listenOn(raw)
+		if err != nil {
+			for _, opened := range lns {
+				_ = opened.Close()
+			}
+			return nil, err
+		}
+		lns = append(lns, ln)
+	}
+	return lns, nil
+}
+
+// listenOn binds one Listen.Addresses entry: unix:///path, tcp://host:port
+// or a bare host:port.
+func (s *Server) listenOn(raw string) (net.Listener, error) {
+	network, addr, err := parseListenAddress(raw)
+	if err != nil {
+		return nil, err
+	}
+	if network == "tcp" {
+		return s.bindTCP(addr)
+	}
+
+	if err := removeStaleSocket(addr); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to listen",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("addr", raw),
+		)
+	}
+	return ln, nil
+}
+
+func parseListenAddress(raw string) (network, addr string, err error) {
+	switch {
+	case strings.HasPrefix(raw, "unix://"):
+		network, addr = "unix", strings.TrimPrefix(raw, "unix://")
+	case strings.HasPrefix(raw, "tcp://"):
+		network, addr = "tcp", strings.TrimPrefix(raw, "tcp://")
+	case strings.Contains(raw, "://"):
+		return "", "", fault.New("unsupported listen scheme, use unix:// or tcp://",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("addr", raw),
+		)
+	default:
+		network, addr = "tcp", raw
+	}
+	if addr == "" {
+		return "", "", fault.New("empty listen address",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("addr", raw),
+		)
+	}
+	return network, addr, nil
+}
+
+// removeStaleSocket deletes a socket file left behind by a process that
+// did not shut down cleanly. A socket something still accepts on is kept,
+// so the Listen that follows fails with address in use.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fault.Wrap(err, "failed to remove stale socket",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("addr", path),
+		)
+	}
+	return nil
+}
+
+// bind listens on the configured host and port.
+func (s *Server) bind() (net.Listener, error) {
+	return s.bindTCP(s.addr)
+}
+
+// bindTCP listens on addr. While the port is taken it retries
+// Listen.Retries times with exponential backoff, then, with
+// Listen.NextFreePort, tries the following Listen.PortScan ports.
+func (s *Server) bindTCP(addr string) (net.Listener, error) {
+	delay := s.listen.RetryDelay
+	for attempt := 0; ; attempt++ {
+		ln, err := net.Listen("tcp", addr)
+		if err == nil {
+			return ln, nil
+		}
+		if !errors.Is(err, syscall.EADDRINUSE) {
+			return nil, fault.Wrap(err, "failed to listen",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("addr", addr),
+			)
+		}
+		if attempt >= s.listen.Retries {
+			break
+		}
+
+		s.logger.Warn("Port in use, retrying",
+			"addr", addr,
+			"attempt", attempt+1,
+			"retries", s.listen.Retries,
+			"delay", delay,
+		)
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fault.Wrap(err, "invalid listen address",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("addr", addr),
+		)
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	if s.listen.NextFreePort && port > 0 {
+		for next := port + 1; next <= port+s.listen.PortScan && next <= 65535; next++ {
+			nextAddr := net.JoinHostPort(host, strconv.Itoa(next))
+			ln, err := net.Listen("tcp", nextAddr)
+			if err != nil {
+				continue
+			}
+
+			s.logger.Warn("Port in use, listening on the next free port",
+				"requested_addr", addr,
+				"addr", nextAddr,
+			)
+			return ln, nil
+		}
+	}
+
+	return nil, fault.Wrap(ErrPortInUse, "another process is listening on "+addr,
+		fault.WithContext("addr", addr),
+		fault.WithContext("hint", portInUseHint(port)),
+	)
+}
+
+func portInUseHint(port int) string {
+	return fmt.Sprintf(
+		"find the process with `lsof -i :%d` and stop it, or set WEB_HTTP_PORT to another port; "+
+			"in development WEB_HTTP_LISTEN_NEXT_FREE_PORT=true picks the next free port",
+		port,
+	)
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func takenPort(t *testing.T) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func listenTestServer(port int, listen ListenConfig) *Server {
+	cfg := &Config{HTTP: HTTPConfig{Host: "127.0.0.1", Port: port, Listen: listen}}
+	return NewServer(cfg, nil, http.NotFoundHandler())
+}
+
+func TestServerBind(t *testing.T) {
+	t.Run("port in use fails with hint", func(t *testing.T) {
+		port := takenPort(t)
+		srv := listenTestServer(port, ListenConfig{Retries: 1, RetryDelay: time.Millisecond})
+
+		err := srv.Start()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrPortInUse))
+		assert.Contains(t, err.Error(), strconv.Itoa(port))
+	})
+
+	t.Run("retry succeeds once the port is released", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := ln.Addr().(*net.TCPAddr).Port
+
+		time.AfterFunc(30*time.Millisecond, func() { _ = ln.Close() })
+
+		srv := listenTestServer(port, ListenConfig{Retries: 5, RetryDelay: 20 * time.Millisecond})
+		bound, err := srv.bind()
+		require.NoError(t, err)
+		defer bound.Close()
+
+		assert.Equal(t, port, bound.Addr().(*net.TCPAddr).Port)
+	})
+
+	t.Run("next free port", func(t *testing.T) {
+		port := takenPort(t)
+		srv := listenTestServer(port, ListenConfig{NextFreePort: true, PortScan: 20})
+
+		go func() { _ = srv.Start() }()
+		defer srv.httpServer.Close()
+
+		require.Eventually(t, func() bool {
+			return srv.Addr() != net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+		}, time.Second, 5*time.Millisecond)
+
+		_, chosen, err := net.SplitHostPort(srv.Addr())
+		require.NoError(t, err)
+		chosenPort, _ := strconv.Atoi(chosen)
+		assert.Greater(t, chosenPort, port)
+	})
+}
+
+func TestServerListenAddresses(t *testing.T) {
+	socketDir := func(t *testing.T) string {
+		t.Helper()
+		// Socket paths are limited to ~100 bytes, too short for t.TempDir.
+		dir, err := os.MkdirTemp("", "web")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		return dir
+	}
+
+	unixClient := func(path string) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		}}
+	}
+
+	t.Run("serves a unix socket and a TCP port together", func(t *testing.T) {
+		sock := filepath.Join(socketDir(t), "app.sock")
+		srv := NewServer(&Config{HTTP: HTTPConfig{Listen: ListenConfig{
+			Addresses: []string{"unix://" + sock, "tcp://127.0.0.1:0"},
+		}}}, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		go func() { _ = srv.Start() }()
+
+		waitBound(t, srv)
+		addrs := srv.Addrs()
+		require.Len(t, addrs, 2)
+		assert.Equal(t, "unix://"+sock, addrs[0])
+
+		resp, err := unixClient(sock).Get("http://app/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err = http.Get("http://" + addrs[1])
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		require.NoError(t, srv.Shutdown(context.Background()))
+		_, err = os.Stat(sock)
+		assert.True(t, os.IsNotExist(err), "socket file should be removed on shutdown")
+	})
+
+	t.Run("replaces a stale socket", func(t *testing.T) {
+		sock := filepath.Join(socketDir(t), "app.sock")
+		ln, err := net.Listen("unix", sock)
+		require.NoError(t, err)
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, ln.Close())
+
+		srv := NewServer(&Config{}, nil, http.NotFoundHandler())
+		stale, err := srv.listenOn("unix://" + sock)
+		require.NoError(t, err)
+		_ = stale.Close()
+	})
+
+	t.Run("keeps a socket in use", func(t *testing.T) {
+		sock := filepath.Join(socketDir(t), "app.sock")
+		ln, err := net.Listen("unix", sock)
+		require.NoError(t, err)
+		defer ln.Close()
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.Close()
+			}
+		}()
+
+		srv := NewServer(&Config{}, nil, http.NotFoundHandler())
+		_, err = srv.listenOn("unix://" + sock)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects unknown schemes", func(t *testing.T) {
+		srv := NewServer(&Config{HTTP: HTTPConfig{Listen: ListenConfig{
+			Addresses: []string{"udp://127.0.0.1:0"},
+		}}}, nil, http.NotFoundHandler())
+		assert.Error(t, srv.Start())
+	})
+}
@@ -6,6 +6,16 @@ import (
 )
 
 func AcceptJSON() func(http.Handler) http.Handler {
+	return Accept("application/json")
+}
+
+// Accept rejects with 406 requests whose Accept header matches none of
+// mediaTypes, directly or through a type/* or */* range. Requests without
+// an Accept header pass.
+func Accept(mediaTypes ...string) func(http.Handler) http.Handler {
+	body := []byte(`{"code":"NOT_ACCEPTABLE","message":"This API only returns ` +
+		strings.Join(mediaTypes, ", ") + `","status_code":406}`)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			accept := r.Header.Get("Accept")
@@ -15,29 +25,29 @@ func AcceptJSON() func(http.Handler) http.Handler {
 				return
 			}
 
-			if acceptsJSON(accept) {
+			if acceptsAny(accept, mediaTypes) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			w.WriteHeader(http.StatusNotAcceptable)
-			_, _ = w.Write([]byte(`{"code":"NOT_ACCEPTABLE","message":"This API only returns application/json","status_code":406}`))
+			_, _ = w.Write(body)
 		})
 	}
 }
 
-func acceptsJSON(accept string) bool {
+func acceptsAny(accept string, mediaTypes []string) bool {
 	accept = strings.ToLower(accept)
 
-	acceptableTypes := []string{
-		"application/json",
-		"application/*",
-		"*/*",
+	if strings.Contains(accept, "*/*") {
+		return true
 	}
 
-	for _, acceptable := range acceptableTypes {
-		if strings.Contains(accept, acceptable) {
+	for _, mediaType := range mediaTypes {
+		mediaType = strings.ToLower(mediaType)
+		major, _, _ := strings.Cut(mediaType, "/")
+		if strings.Contains(accept, mediaType) || strings.Contains(accept, major+"/*") {
 			return true
 		}
 	}
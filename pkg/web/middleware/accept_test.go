@@ -42,3 +42,37 @@ func TestAcceptJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestAccept(t *testing.T) {
+	middleware := Accept("application/json", "application/xml", "application/msgpack")
+
+	tests := []struct {
+		name           string
+		acceptHeader   string
+		expectedStatus int
+	}{
+		{"xml", "application/xml", http.StatusOK},
+		{"msgpack with quality", "text/html, application/msgpack;q=0.9", http.StatusOK},
+		{"application range", "application/*", http.StatusOK},
+		{"html only", "text/html", http.StatusNotAcceptable},
+		{"yaml", "application/yaml", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.acceptHeader)
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowedHosts(t *testing.T) {
+	handler := AllowedHosts([]string{"api.example.com", "*.tenants.example.com"}, &SecurityLogger{})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	tests := []struct {
+		host       string
+		wantStatus int
+	}{
+		{"api.example.com", http.StatusOK},
+		{"API.example.com:8443", http.StatusOK},
+		{"acme.tenants.example.com", http.StatusOK},
+		{"tenants.example.com", http.StatusBadRequest},
+		{"evil.com", http.StatusBadRequest},
+		{"api.example.com.evil.com", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestHostRouter(t *testing.T) {
+	respond := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+	}
+
+	router := NewHostRouter()
+	router.Handle("*.example.com", respond("wildcard"))
+	router.Handle("admin.example.com", respond("admin"))
+
+	tests := []struct {
+		host       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"admin.example.com", http.StatusOK, "admin"},
+		{"www.example.com:8080", http.StatusOK, "wildcard"},
+		{"other.org", http.StatusNotFound, `{"error":"unknown host"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = tt.host
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type CanonicalPathConfig struct {
+	// KeepTrailingSlash leaves "/courses/" distinct from "/courses".
+	KeepTrailingSlash bool
+	// Redirect answers 308 with the canonical URL instead of rewriting the
+	// request path in place.
+	Redirect bool
+}
+
+// CanonicalPath normalizes the request path before routing so route
+// matching, rate-limit keys and logs all see one spelling of each URL:
+// repeated slashes are collapsed and the trailing slash is stripped. Paths
+// that are not valid UTF-8, contain control characters or carry "." / ".."
+// segments (including percent-encoded ones) are rejected with 400.
+func CanonicalPath(cfg CanonicalPathConfig, secLogger *SecurityLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if reason := unsafePath(r.URL.Path); reason != "" {
+				secLogger.LogEvent(EventSuspiciousActivity, SeverityMedium, r, map[string]string{
+					"reason": reason,
+				})
+
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid request path"}`))
+				return
+			}
+
+			path := canonicalPath(r.URL.Path, cfg.KeepTrailingSlash)
+			if path == r.URL.Path {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rawPath := ""
+			if r.URL.RawPath != "" {
+				rawPath = canonicalPath(r.URL.RawPath, cfg.KeepTrailingSlash)
+			}
+
+			if cfg.Redirect {
+				target := *r.URL
+				target.Path = path
+				target.RawPath = rawPath
+				http.Redirect(w, r, target.RequestURI(), http.StatusPermanentRedirect)
+				return
+			}
+
+			r.URL.Path = path
+			r.URL.RawPath = rawPath
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func canonicalPath(path string, keepTrailingSlash bool) string {
+	if strings.Contains(path, "//") {
+		var sb strings.Builder
+		sb.Grow(len(path))
+		for i := 0; i < len(path); i++ {
+			if path[i] == '/' && i > 0 && path[i-1] == '/' {
+				continue
+			}
+			sb.WriteByte(path[i])
+		}
+		path = sb.String()
+	}
+
+	if !keepTrailingSlash && len(path) > 1 && strings.HasSuffix(path, "/") {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	return path
+}
+
+func unsafePath(path string) string {
+	if !utf8.ValidString(path) {
+		return "invalid_utf8_path"
+	}
+
+	for _, r := range path {
+		if unicode.IsControl(r) {
+			return "control_character_in_path"
+		}
+	}
+
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == "." || segment == ".." {
+			return "path_traversal"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCanonicalPath(t *testing.T) {
+	var gotPath string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name         string
+		cfg          CanonicalPathConfig
+		target       string
+		wantStatus   int
+		wantPath     string
+		wantLocation string
+	}{
+		{"already canonical", CanonicalPathConfig{}, "/courses/1", http.StatusOK, "/courses/1", ""},
+		{"root untouched", CanonicalPathConfig{}, "/", http.StatusOK, "/", ""},
+		{"collapse slashes", CanonicalPathConfig{}, "//courses///1", http.StatusOK, "/courses/1", ""},
+		{"strip trailing slash", CanonicalPathConfig{}, "/courses/", http.StatusOK, "/courses", ""},
+		{"keep trailing slash", CanonicalPathConfig{KeepTrailingSlash: true}, "/courses/", http.StatusOK, "/courses/", ""},
+		{"redirect", CanonicalPathConfig{Redirect: true}, "/courses//1/?page=2", http.StatusPermanentRedirect, "", "/courses/1?page=2"},
+		{"traversal", CanonicalPathConfig{}, "/static/../etc/passwd", http.StatusBadRequest, "", ""},
+		{"encoded traversal", CanonicalPathConfig{}, "/static/%2e%2e/etc/passwd", http.StatusBadRequest, "", ""},
+		{"backslash traversal", CanonicalPathConfig{}, `/static/..%5cetc`, http.StatusBadRequest, "", ""},
+		{"control character", CanonicalPathConfig{}, "/courses/%00", http.StatusBadRequest, "", ""},
+		{"invalid utf8", CanonicalPathConfig{}, "/courses/%ff", http.StatusBadRequest, "", ""},
+		{"unicode allowed", CanonicalPathConfig{}, "/cursos/matem%C3%A1tica", http.StatusOK, "/cursos/matemática", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath = ""
+			handler := CanonicalPath(tt.cfg, &SecurityLogger{})(next)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("expected path %q, got %q", tt.wantPath, gotPath)
+			}
+			if loc := w.Header().Get("Location"); loc != tt.wantLocation {
+				t.Errorf("expected location %q, got %q", tt.wantLocation, loc)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+type clientIdentityKey struct{}
+
+// ClientIdentity is the verified client certificate of an mTLS request.
+type ClientIdentity struct {
+	// Subject is the distinguished name, e.g. "CN=billing,O=platform".
+	Subject    string
+	CommonName string
+	// DNSNames and URIs are the SANs; SPIFFE IDs show up in URIs.
+	DNSNames     []string
+	URIs         []string
+	Issuer       string
+	SerialNumber string
+}
+
+// ClientCert stores the identity of the verified client certificate in the
+// context, read back with ClientIdentityFromContext. Only chains the TLS
+// handshake verified against WEB_HTTP_TLS_CLIENT_CA_FILE are trusted; with
+// required set, requests without one are rejected with 401, which covers
+// routes behind a "request" or "verify_if_given" client auth policy.
+func ClientCert(required bool, secLogger *SecurityLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+				if !required {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				secLogger.LogEvent(EventInvalidAuth, SeverityMedium, r, map[string]string{
+					"reason": "client_certificate_missing",
+				})
+
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"client certificate required"}`))
+				return
+			}
+
+			leaf := r.TLS.VerifiedChains[0][0]
+			identity := ClientIdentity{
+				Subject:      leaf.Subject.String(),
+				CommonName:   leaf.Subject.CommonName,
+				DNSNames:     leaf.DNSNames,
+				Issuer:       leaf.Issuer.String(),
+				SerialNumber: leaf.SerialNumber.String(),
+			}
+			for _, uri := range leaf.URIs {
+				identity.URIs = append(identity.URIs, uri.String())
+			}
+
+			ctx := context.WithValue(r.Context(), clientIdentityKey{}, identity)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIdentityFromContext returns the identity stored by ClientCert.
+func ClientIdentityFromContext(ctx context.Context) (ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityKey{}).(ClientIdentity)
+	return identity, ok
+}
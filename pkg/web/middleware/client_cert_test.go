@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientCert(t *testing.T) {
+	secLogger := NewSecurityLogger(slog.Default())
+
+	spiffe, _ := url.Parse("spiffe://platform/billing")
+	leaf := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "billing", Organization: []string{"platform"}},
+		Issuer:       pkix.Name{CommonName: "internal-ca"},
+		SerialNumber: big.NewInt(42),
+		DNSNames:     []string{"billing.internal"},
+		URIs:         []*url.URL{spiffe},
+	}
+
+	var got ClientIdentity
+	var found bool
+	handler := func(required bool) http.Handler {
+		return ClientCert(required, secLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, found = ClientIdentityFromContext(r.Context())
+		}))
+	}
+
+	t.Run("stores the verified identity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf}}}
+		w := httptest.NewRecorder()
+
+		handler(true).ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || !found {
+			t.Fatalf("expected identity, got status %d", w.Code)
+		}
+		if got.CommonName != "billing" || got.Subject != "CN=billing,O=platform" {
+			t.Errorf("unexpected subject %+v", got)
+		}
+		if got.Issuer != "CN=internal-ca" || got.SerialNumber != "42" {
+			t.Errorf("unexpected issuer or serial %+v", got)
+		}
+		if len(got.URIs) != 1 || got.URIs[0] != "spiffe://platform/billing" {
+			t.Errorf("unexpected URIs %v", got.URIs)
+		}
+	})
+
+	t.Run("ignores unverified certificates", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}
+		w := httptest.NewRecorder()
+
+		handler(false).ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if found {
+			t.Error("expected no identity for an unverified certificate")
+		}
+	})
+
+	t.Run("rejects requests without a certificate when required", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler(true).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	TenantHeader = "X-Tenant-ID"
+	APIKeyHeader = "X-API-Key"
+
+	DefaultConcurrencyClass = "interactive"
+)
+
+// ConcurrencyClass bounds the in-flight requests of a single tenant within
+// one traffic class. Requests over MaxInFlight wait in a queue of up to
+// MaxQueue for at most QueueTimeout before being rejected with 429.
+type ConcurrencyClass struct {
+	MaxInFlight  int
+	MaxQueue     int
+	QueueTimeout time.Duration
+}
+
+// TenantConcurrencyConfig configures TenantConcurrency. Tenant identifies
+// the caller (default: X-Tenant-ID, then X-API-Key, then the client IP) and
+// Classify picks the class of a request (default: DefaultConcurrencyClass).
+// Requests whose class is not in Classes pass through unlimited.
+type TenantConcurrencyConfig struct {
+	Tenant   func(r *http.Request) string
+	Classify func(r *http.Request) string
+	Classes  map[string]ConcurrencyClass
+}
+
+// TenantConcurrency isolates tenants from each other by limiting how many
+// requests each one may have in flight per class, so one institution's batch
+// sync cannot take every worker from the others' interactive traffic.
+func TenantConcurrency(cfg TenantConcurrencyConfig) func(http.Handler) http.Handler {
+	if cfg.Tenant == nil {
+		cfg.Tenant = defaultTenant
+	}
+	if cfg.Classify == nil {
+		cfg.Classify = func(*http.Request) string { return DefaultConcurrencyClass }
+	}
+
+	limiter := &tenantLimiter{slots: make(map[string]*tenantSlot)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			className := cfg.Classify(r)
+			class, ok := cfg.Classes[className]
+			if !ok || class.MaxInFlight <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := className + "\x00" + cfg.Tenant(r)
+			slot, ok := limiter.acquire(r, key, class)
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(class.QueueTimeout)))
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":"too many concurrent requests"}`))
+				return
+			}
+			defer limiter.release(key, slot)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func defaultTenant(r *http.Request) string {
+	if tenant := r.Header.Get(TenantHeader); tenant != "" {
+		return "tenant:" + tenant
+	}
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return "key:" + key
+	}
+	return "ip:" + parseIP(r.RemoteAddr)
+}
+
+func retryAfterSeconds(timeout time.Duration) int {
+	return max(1, int(timeout.Seconds()))
+}
+
+type tenantSlot struct {
+	sem     chan struct{}
+	refs    int
+	waiting int
+}
+
+// tenantLimiter keeps one semaphore per tenant and class. Slots are created
+// on first use and dropped once no request holds or waits on them, so idle
+// tenants cost nothing.
+type tenantLimiter struct {
+	mu    sync.Mutex
+	slots map[string]*tenantSlot
+}
+
+func (l *tenantLimiter) acquire(r *http.Request, key string, class ConcurrencyClass) (*tenantSlot, bool) {
+	l.mu.Lock()
+	slot, ok := l.slots[key]
+	if !ok {
+		slot = &tenantSlot{sem: make(chan struct{}, class.MaxInFlight)}
+		l.slots[key] = slot
+	}
+	slot.refs++
+	l.mu.Unlock()
+
+	select {
+	case slot.sem <- struct{}{}:
+		return slot, true
+	default:
+	}
+
+	l.mu.Lock()
+	if slot.waiting >= class.MaxQueue || class.QueueTimeout <= 0 {
+		l.unref(key, slot)
+		l.mu.Unlock()
+		return nil, false
+	}
+	slot.waiting++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(class.QueueTimeout)
+	defer timer.Stop()
+
+	acquired := false
+	select {
+	case slot.sem <- struct{}{}:
+		acquired = true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	l.mu.Lock()
+	slot.waiting--
+	if !acquired {
+		l.unref(key, slot)
+	}
+	l.mu.Unlock()
+
+	return slot, acquired
+}
+
+func (l *tenantLimiter) release(key string, slot *tenantSlot) {
+	<-slot.sem
+
+	l.mu.Lock()
+	l.unref(key, slot)
+	l.mu.Unlock()
+}
+
+// unref must be called with l.mu held.
+func (l *tenantLimiter) unref(key string, slot *tenantSlot) {
+	slot.refs--
+	if slot.refs == 0 {
+		delete(l.slots, key)
+	}
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler holds every request until release is closed and signals
+// started once per request.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func tenantRequest(tenant string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/sync", nil)
+	r.Header.Set(TenantHeader, tenant)
+	return r
+}
+
+func TestTenantConcurrencyIsolatesTenants(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	handler := TenantConcurrency(TenantConcurrencyConfig{
+		Classes: map[string]ConcurrencyClass{
+			DefaultConcurrencyClass: {MaxInFlight: 1},
+		},
+	})(blockingHandler(started, release))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), tenantRequest("school-a"))
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, tenantRequest("school-a"))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected second request of the same tenant to get 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected Retry-After 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	wg.Add(1)
+	other := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(other, tenantRequest("school-b"))
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected another tenant not to be blocked")
+	}
+
+	close(release)
+	wg.Wait()
+
+	if other.Code != http.StatusOK {
+		t.Errorf("expected other tenant to get 200, got %d", other.Code)
+	}
+}
+
+func TestTenantConcurrencyQueue(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	handler := TenantConcurrency(TenantConcurrencyConfig{
+		Classes: map[string]ConcurrencyClass{
+			DefaultConcurrencyClass: {MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+		},
+	})(blockingHandler(started, release))
+
+	var wg sync.WaitGroup
+	first := httptest.NewRecorder()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(first, tenantRequest("school-a"))
+	}()
+	<-started
+
+	queued := httptest.NewRecorder()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(queued, tenantRequest("school-a"))
+	}()
+
+	// Give the second request time to enter the queue, then overflow it.
+	time.Sleep(50 * time.Millisecond)
+	overflow := httptest.NewRecorder()
+	handler.ServeHTTP(overflow, tenantRequest("school-a"))
+	if overflow.Code != http.StatusTooManyRequests {
+		t.Errorf("expected request over the queue limit to get 429, got %d", overflow.Code)
+	}
+
+	release <- struct{}{}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected queued request to run once a slot was freed")
+	}
+	close(release)
+	wg.Wait()
+
+	if first.Code != http.StatusOK || queued.Code != http.StatusOK {
+		t.Errorf("expected both admitted requests to get 200, got %d and %d", first.Code, queued.Code)
+	}
+}
+
+func TestTenantConcurrencyQueueTimeout(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	defer close(release)
+
+	handler := TenantConcurrency(TenantConcurrencyConfig{
+		Classes: map[string]ConcurrencyClass{
+			DefaultConcurrencyClass: {MaxInFlight: 1, MaxQueue: 5, QueueTimeout: 20 * time.Millisecond},
+		},
+	})(blockingHandler(started, release))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), tenantRequest("school-a"))
+	<-started
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(w, tenantRequest("school-a"))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected queued request to time out with 429, got %d", w.Code)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected request to wait for the queue timeout, waited %v", elapsed)
+	}
+}
+
+func TestTenantConcurrencyClasses(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	handler := TenantConcurrency(TenantConcurrencyConfig{
+		Classify: func(r *http.Request) string {
+			if r.URL.Path == "/sync" {
+				return "batch"
+			}
+			return "interactive"
+		},
+		Classes: map[string]ConcurrencyClass{
+			"batch": {MaxInFlight: 1},
+		},
+	})(blockingHandler(started, release))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), tenantRequest("school-a"))
+	<-started
+
+	w := httptest.NewRecorder()
+	go func() {
+		r := httptest.NewRequest(http.MethodGet, "/courses", nil)
+		r.Header.Set(TenantHeader, "school-a")
+		handler.ServeHTTP(w, r)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected unlimited class not to be blocked by the batch class")
+	}
+	close(release)
+}
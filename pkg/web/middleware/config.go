@@ -1,15 +1,17 @@
 package middleware
 
+import "time"
+
 type SecurityHeadersConfig struct {
-	XContentTypeOptions      string
-	XFrameOptions            string
-	ContentSecurityPolicy    string
-	ReferrerPolicy           string
-	StrictTransportSecurity  string
-	CacheControl             string
-	PermissionsPolicy        string
-	XDNSPrefetchControl      string
-	XDownloadOptions         string
+	XContentTypeOptions     string
+	XFrameOptions           string
+	ContentSecurityPolicy   string
+	ReferrerPolicy          string
+	StrictTransportSecurity string
+	CacheControl            string
+	PermissionsPolicy       string
+	XDNSPrefetchControl     string
+	XDownloadOptions        string
 }
 
 type CORSConfig struct {
@@ -20,3 +22,19 @@ type CORSConfig struct {
 	AllowCredentials bool
 	MaxAge           int
 }
+
+type RateLimitConfig struct {
+	Enabled           bool
+	RequestsPerSecond int
+	Burst             int
+	TrustedProxies    []string
+}
+
+type CSRFConfig struct {
+	Enabled     bool
+	Secret      string
+	CookieName  string
+	HeaderName  string
+	TTL         time.Duration
+	ExemptPaths []string
+}
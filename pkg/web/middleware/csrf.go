@@ -45,6 +45,28 @@ func NewCSRFProtection(secret, cookieName, headerName string, ttl time.Duration,
 	}
 }
 
+const (
+	DefaultCSRFCookieName = "csrf_token"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	DefaultCSRFTTL        = 12 * time.Hour
+)
+
+// NewCSRFProtectionFromConfig builds a CSRFProtection from CSRFConfig,
+// applying default cookie name, header name and TTL when they are empty.
+func NewCSRFProtectionFromConfig(cfg CSRFConfig, secLogger *SecurityLogger) *CSRFProtection {
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultCSRFCookieName
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = DefaultCSRFHeaderName
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultCSRFTTL
+	}
+
+	return NewCSRFProtection(cfg.Secret, cfg.CookieName, cfg.HeaderName, cfg.TTL, cfg.ExemptPaths, cfg.Enabled, secLogger)
+}
+
 func (c *CSRFProtection) Protect() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
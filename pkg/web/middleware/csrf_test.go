@@ -238,3 +238,44 @@ func BenchmarkCSRFProtection_ValidToken(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+func TestNewCSRFProtectionFromConfig(t *testing.T) {
+	csrf := middleware.NewCSRFProtectionFromConfig(middleware.CSRFConfig{
+		Enabled:     true,
+		Secret:      "secret",
+		ExemptPaths: []string{"/webhooks"},
+	}, &middleware.SecurityLogger{})
+
+	handler := csrf.Protect()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("exempt path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("default cookie and header names", func(t *testing.T) {
+		token, err := csrf.GenerateToken("test-session")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/test", nil)
+		req.AddCookie(&http.Cookie{Name: middleware.DefaultCSRFCookieName, Value: token})
+		req.Header.Set(middleware.DefaultCSRFHeaderName, token)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+}
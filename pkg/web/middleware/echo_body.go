@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// DefaultEchoBodyLimit is how much of the request body EchoRequestBody
+// keeps.
+const DefaultEchoBodyLimit = 4 << 10
+
+type echoBodyKey struct{}
+
+type echoBody struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (e *echoBody) Write(p []byte) (int, error) {
+	if room := e.limit - e.buf.Len(); room > 0 {
+		if len(p) > room {
+			e.buf.Write(p[:room])
+		} else {
+			e.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// EchoRequestBody keeps the first limit bytes of the request body as the
+// handler reads it, so error responses can echo what the client sent (see
+// EchoedRequestBody). Meant for development only: the body may hold
+// credentials and personal data.
+func EchoRequestBody(limit int) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		limit = DefaultEchoBodyLimit
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			echo := &echoBody{limit: limit}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, echo), r.Body}
+
+			ctx := context.WithValue(r.Context(), echoBodyKey{}, echo)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// EchoedRequestBody returns the part of the body the handler read, when
+// EchoRequestBody is enabled.
+func EchoedRequestBody(r *http.Request) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	echo, ok := r.Context().Value(echoBodyKey{}).(*echoBody)
+	if !ok || echo.buf.Len() == 0 {
+		return "", false
+	}
+	return echo.buf.String(), true
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEchoRequestBody(t *testing.T) {
+	var echoed string
+	var read []byte
+	handler := EchoRequestBody(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read, _ = io.ReadAll(r.Body)
+		echoed, _ = EchoedRequestBody(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/courses", strings.NewReader(`{"title":"Go"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, `{"title":"Go"}`, string(read))
+	assert.Equal(t, `{"title"`, echoed)
+
+	t.Run("nothing echoed without the middleware", func(t *testing.T) {
+		_, ok := EchoedRequestBody(httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.False(t, ok)
+	})
+}
@@ -3,12 +3,18 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // HTTPSOnlyConfig holds configuration for HTTPS enforcement
 type HTTPSOnlyConfig struct {
 	Enabled     bool
 	RedirectURL string
+	// TrustForwardedProto accepts requests whose X-Forwarded-Proto is
+	// "https", for servers behind a TLS-terminating load balancer.
+	TrustForwardedProto bool
+	// ExemptPaths are served over plain HTTP, e.g. kubelet health probes.
+	ExemptPaths []string
 }
 
 // HTTPSOnly is a middleware that ensures all requests are made over HTTPS.
@@ -28,6 +34,11 @@ type HTTPSOnlyConfig struct {
 //	r := chi.NewRouter()
 //	r.Use(middleware.HTTPSOnly(config))
 func HTTPSOnly(cfg HTTPSOnlyConfig) func(http.Handler) http.Handler {
+	exempt := make(map[string]bool, len(cfg.ExemptPaths))
+	for _, path := range cfg.ExemptPaths {
+		exempt[path] = true
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Skip middleware if not enabled
@@ -36,8 +47,11 @@ func HTTPSOnly(cfg HTTPSOnlyConfig) func(http.Handler) http.Handler {
 				return
 			}
 
+			secure := r.TLS != nil ||
+				(cfg.TrustForwardedProto && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https"))
+
 			// Check if the connection is using TLS
-			if r.TLS == nil {
+			if !secure && !exempt[r.URL.Path] {
 				// Connection is not encrypted (HTTP)
 				w.Header().Set("Content-Type", "application/json; charset=utf-8")
 				w.WriteHeader(http.StatusBadRequest)
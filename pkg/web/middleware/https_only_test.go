@@ -197,3 +197,50 @@ func TestHTTPSOnly_JSONResponse(t *testing.T) {
 	assert.Contains(t, body, `"details"`)
 	assert.Contains(t, body, `"https_url"`)
 }
+
+func TestHTTPSOnly_ForwardedProtoAndExemptPaths(t *testing.T) {
+	cfg := HTTPSOnlyConfig{
+		Enabled:             true,
+		TrustForwardedProto: true,
+		ExemptPaths:         []string{"/health"},
+	}
+	handler := HTTPSOnly(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		path   string
+		proto  string
+		status int
+	}{
+		{"forwarded https", "/courses", "https", http.StatusOK},
+		{"forwarded http", "/courses", "http", http.StatusBadRequest},
+		{"exempt path", "/health", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+
+	t.Run("forwarded proto ignored unless trusted", func(t *testing.T) {
+		strict := HTTPSOnly(HTTPSOnlyConfig{Enabled: true})(http.NotFoundHandler())
+		req := httptest.NewRequest(http.MethodGet, "/courses", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		rec := httptest.NewRecorder()
+
+		strict.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -6,29 +6,30 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/marcelofabianov/logger"
 )
 
-func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
+// Logger writes one access log record per request, in the schema shared by
+// every service (see logger.AccessEntry).
+func Logger(log *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
-			requestID := middleware.GetReqID(r.Context())
 
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
 			next.ServeHTTP(ww, r)
 
-			if logger != nil {
-				logger.Info("request completed",
-					"request_id", requestID,
-					"method", r.Method,
-					"path", r.URL.Path,
-					"remote_addr", r.RemoteAddr,
-					"status", ww.Status(),
-					"duration", time.Since(start).String(),
-					"bytes_written", ww.BytesWritten(),
-				)
-			}
+			logger.AccessLog(r.Context(), log, logger.AccessEntry{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    ww.Status(),
+				Bytes:     int64(ww.BytesWritten()),
+				Duration:  time.Since(start),
+				RequestID: middleware.GetReqID(r.Context()),
+				ClientIP:  r.RemoteAddr,
+			})
 		})
 	}
 }
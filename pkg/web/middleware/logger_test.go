@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := middleware.RequestID(Logger(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/courses", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"msg":       "request completed",
+		"method":    http.MethodPost,
+		"path":      "/courses",
+		"status":    float64(http.StatusCreated),
+		"bytes":     float64(10),
+		"client_ip": "203.0.113.7",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if entry["request_id"] == nil || entry["request_id"] == "" {
+		t.Error("expected request_id")
+	}
+	if _, ok := entry["duration_ms"].(float64); !ok {
+		t.Errorf("expected numeric duration_ms, got %v", entry["duration_ms"])
+	}
+}
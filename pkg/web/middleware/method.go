@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// DefaultOverrideMethods are the methods a POST may be turned into when no
+// allow-list is given.
+var DefaultOverrideMethods = []string{http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// MethodOverride lets legacy clients that can only send GET and POST tunnel
+// other methods through the X-HTTP-Method-Override header. Only POST
+// requests are overridden and only to a method in allowed; other overrides
+// are rejected with 400. It must run before routing.
+func MethodOverride(allowed ...string) func(http.Handler) http.Handler {
+	if len(allowed) == 0 {
+		allowed = DefaultOverrideMethods
+	}
+
+	methods := make([]string, 0, len(allowed))
+	for _, method := range allowed {
+		methods = append(methods, strings.ToUpper(strings.TrimSpace(method)))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			override := strings.ToUpper(strings.TrimSpace(r.Header.Get(MethodOverrideHeader)))
+			if override == "" || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !slices.Contains(methods, override) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"method override not allowed"}`))
+				return
+			}
+
+			r.Method = override
+			r.Header.Del(MethodOverrideHeader)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AutoHead answers HEAD requests with the matching GET route when no HEAD
+// route is registered. The GET handler runs as usual; its body is discarded
+// and its length is reported in Content-Length. It must be installed with
+// chi's Use so the route tree is available.
+func AutoHead() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rctx := chi.RouteContext(r.Context())
+			if r.Method != http.MethodHead || rctx == nil || rctx.Routes == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			routePath := rctx.RoutePath
+			if routePath == "" {
+				routePath = r.URL.RawPath
+				if routePath == "" {
+					routePath = r.URL.Path
+				}
+			}
+
+			if rctx.Routes.Match(chi.NewRouteContext(), http.MethodHead, routePath) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rctx.RouteMethod = http.MethodGet
+			hw := &headResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(hw, r)
+			hw.finish()
+		})
+	}
+}
+
+// headResponseWriter holds the status until the handler returns so the
+// body length can be reported in Content-Length.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	written     int64
+	wroteHeader bool
+}
+
+func (h *headResponseWriter) WriteHeader(status int) {
+	if !h.wroteHeader {
+		h.status = status
+		h.wroteHeader = true
+	}
+}
+
+func (h *headResponseWriter) Write(p []byte) (int, error) {
+	h.wroteHeader = true
+	h.written += int64(len(p))
+	return len(p), nil
+}
+
+func (h *headResponseWriter) finish() {
+	if h.ResponseWriter.Header().Get("Content-Length") == "" && h.written > 0 {
+		h.ResponseWriter.Header().Set("Content-Length", strconv.FormatInt(h.written, 10))
+	}
+	h.ResponseWriter.WriteHeader(h.status)
+}
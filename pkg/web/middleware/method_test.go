@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMethodOverride(t *testing.T) {
+	var gotMethod string
+	handler := MethodOverride()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		override   string
+		wantStatus int
+		wantMethod string
+	}{
+		{"no override", http.MethodPost, "", http.StatusOK, http.MethodPost},
+		{"post to delete", http.MethodPost, "delete", http.StatusOK, http.MethodDelete},
+		{"get is never overridden", http.MethodGet, "DELETE", http.StatusOK, http.MethodGet},
+		{"method not allowed", http.MethodPost, "CONNECT", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMethod = ""
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.override != "" {
+				r.Header.Set(MethodOverrideHeader, tt.override)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("expected method %q, got %q", tt.wantMethod, gotMethod)
+			}
+		})
+	}
+}
+
+func TestAutoHead(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(AutoHead())
+	r.Get("/courses", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "2")
+		_, _ = w.Write([]byte(`[{"id":1},{"id":2}]`))
+	})
+	r.Head("/explicit", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Explicit", "true")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Get("/explicit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("head served by get route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/courses", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("expected empty body, got %q", w.Body.String())
+		}
+		if w.Header().Get("Content-Length") != "19" {
+			t.Errorf("expected Content-Length 19, got %q", w.Header().Get("Content-Length"))
+		}
+		if w.Header().Get("X-Total-Count") != "2" {
+			t.Error("expected GET headers to be preserved")
+		}
+	})
+
+	t.Run("explicit head route wins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/explicit", nil))
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected status 204, got %d", w.Code)
+		}
+		if w.Header().Get("X-Explicit") != "true" {
+			t.Error("expected explicit HEAD handler to run")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := NewHTTPMetrics(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(metrics.Middleware())
+	r.Get("/courses/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("course"))
+	})
+	r.Post("/courses", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/courses/1", nil),
+		httptest.NewRequest(http.MethodGet, "/courses/2", nil),
+		httptest.NewRequest(http.MethodPost, "/courses", nil),
+		httptest.NewRequest(http.MethodGet, "/wp-login.php", nil),
+		httptest.NewRequest("PROPFIND", "/courses/1", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("counts requests by route pattern", func(t *testing.T) {
+		want := `
+# HELP http_requests_total Number of HTTP requests handled.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/courses/{id}",status="200"} 2
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+http_requests_total{method="OTHER",route="unmatched",status="405"} 1
+http_requests_total{method="POST",route="/courses",status="201"} 1
+`
+		if err := testutil.CollectAndCompare(metrics.requests, strings.NewReader(want)); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("observes duration and size", func(t *testing.T) {
+		if got := testutil.CollectAndCount(metrics.duration); got != 4 {
+			t.Errorf("expected 4 duration series, got %d", got)
+		}
+		if got := testutil.CollectAndCount(metrics.size); got != 4 {
+			t.Errorf("expected 4 size series, got %d", got)
+		}
+		if got := testutil.ToFloat64(metrics.inFlight); got != 0 {
+			t.Errorf("expected no requests in flight, got %v", got)
+		}
+	})
+
+	t.Run("reuses collectors on the same registry", func(t *testing.T) {
+		again, err := NewHTTPMetrics(reg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.requests != metrics.requests {
+			t.Error("expected the registered counter to be reused")
+		}
+	})
+}
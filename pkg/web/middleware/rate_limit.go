@@ -55,6 +55,25 @@ func NewRateLimiter(redisClient *redis.Client, enabled bool, trustedProxyCIDRs [
 	}
 }
 
+// BreakerState reports the Redis circuit breaker state ("closed",
+// "half-open" or "open") for status pages.
+func (rl *RateLimiter) BreakerState() string {
+	return rl.circuitBreaker.State().String()
+}
+
+// NewRateLimiterFromConfig builds a RateLimiter from RateLimitConfig. The
+// limiter stays a pass-through when disabled or when redisClient is nil.
+func NewRateLimiterFromConfig(redisClient *redis.Client, cfg RateLimitConfig, secLogger *SecurityLogger) *RateLimiter {
+	return NewRateLimiter(redisClient, cfg.Enabled, cfg.TrustedProxies, secLogger)
+}
+
+// RateLimitFromConfig returns the global per-IP limit described by cfg,
+// allowing RequestsPerSecond requests per second with the configured burst.
+func RateLimitFromConfig(redisClient *redis.Client, cfg RateLimitConfig, secLogger *SecurityLogger) func(next http.Handler) http.Handler {
+	rl := NewRateLimiterFromConfig(redisClient, cfg, secLogger)
+	return rl.GlobalLimit(cfg.RequestsPerSecond, time.Second, cfg.Burst)
+}
+
 func parseTrustedProxies(cidrs []string) []net.IPNet {
 	var proxies []net.IPNet
 	for _, cidr := range cidrs {
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RequestFraming rejects requests whose body framing is ambiguous, the
+// ingredient of request smuggling between a proxy and this server:
+// repeated Content-Length headers, Transfer-Encoding on HTTP/1.0, any
+// Transfer-Encoding other than a single "chunked", and Content-Length sent
+// together with Transfer-Encoding. net/http already answers most of these
+// by ignoring one of the headers; rejecting them makes the disagreement with
+// an upstream proxy visible instead of silently picking a side.
+func RequestFraming(secLogger *SecurityLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if reason := ambiguousFraming(r); reason != "" {
+				secLogger.LogRequestSmuggling(r, reason)
+
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.Header().Set("Connection", "close")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"ambiguous request framing"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ambiguousFraming(r *http.Request) string {
+	contentLengths := r.Header.Values("Content-Length")
+	if len(contentLengths) > 1 || (len(contentLengths) == 1 && strings.Contains(contentLengths[0], ",")) {
+		return "multiple_content_length"
+	}
+
+	transferEncodings := slices.Concat(r.TransferEncoding, r.Header.Values("Transfer-Encoding"))
+	if len(transferEncodings) == 0 {
+		return ""
+	}
+
+	if r.ProtoMajor == 1 && r.ProtoMinor == 0 {
+		return "transfer_encoding_http10"
+	}
+
+	if len(contentLengths) > 0 {
+		return "content_length_with_transfer_encoding"
+	}
+
+	for _, te := range transferEncodings {
+		if !strings.EqualFold(strings.TrimSpace(te), "chunked") {
+			return "unsupported_transfer_encoding"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestFraming(t *testing.T) {
+	handler := RequestFraming(&SecurityLogger{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		setup      func(r *http.Request)
+		wantStatus int
+	}{
+		{
+			name:       "plain request",
+			setup:      func(r *http.Request) {},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "chunked request",
+			setup: func(r *http.Request) {
+				r.TransferEncoding = []string{"chunked"}
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "repeated content length",
+			setup: func(r *http.Request) {
+				r.Header["Content-Length"] = []string{"5", "5"}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "content length with transfer encoding",
+			setup: func(r *http.Request) {
+				r.Header.Set("Content-Length", "5")
+				r.TransferEncoding = []string{"chunked"}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "transfer encoding on http 1.0",
+			setup: func(r *http.Request) {
+				r.Proto, r.ProtoMajor, r.ProtoMinor = "HTTP/1.0", 1, 0
+				r.TransferEncoding = []string{"chunked"}
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported transfer encoding",
+			setup: func(r *http.Request) {
+				r.Header.Set("Transfer-Encoding", "gzip, chunked")
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			tt.setup(r)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
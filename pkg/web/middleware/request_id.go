@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
 )
 
+// RequestID ensures every request has an X-Request-ID, echoes it in the
+// response and stores it in the context under chi's middleware.RequestIDKey,
+// so loggers can correlate records with the request.
 func RequestID() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -17,7 +22,8 @@ func RequestID() func(http.Handler) http.Handler {
 			w.Header().Set("X-Request-ID", requestID)
 			r.Header.Set("X-Request-ID", requestID)
 
-			next.ServeHTTP(w, r)
+			ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
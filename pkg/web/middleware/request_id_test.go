@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 func TestRequestID(t *testing.T) {
@@ -51,3 +53,15 @@ func TestRequestIDWithExistingHeader(t *testing.T) {
 		t.Errorf("expected X-Request-ID to be %s, got %s", existingID, responseRequestID)
 	}
 }
+
+func TestRequestIDInContext(t *testing.T) {
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := middleware.GetReqID(r.Context()); got != "ctx-request-id" {
+			t.Errorf("expected request ID in context, got %q", got)
+		}
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "ctx-request-id")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+}
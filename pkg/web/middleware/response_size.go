@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+var errResponseTooLarge = errors.New("response exceeds maximum size")
+
+// MaxResponseSize buffers the response of the wrapped route and replaces it
+// with a 500 when it grows beyond maxBytes. Writes past the limit fail, which
+// stops encoders from serializing the rest of a runaway payload. Meant to be
+// applied per route with chi's With; it does not support streaming responses.
+func MaxResponseSize(maxBytes int64, logger *slog.Logger) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			guard := &sizeGuardWriter{ResponseWriter: w, max: maxBytes, status: http.StatusOK}
+			next.ServeHTTP(guard, r)
+
+			if guard.exceeded {
+				logger.Error("Response size limit exceeded",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"max_bytes", maxBytes,
+				)
+
+				w.Header().Del("Content-Length")
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":"response too large"}`))
+				return
+			}
+
+			w.WriteHeader(guard.status)
+			_, _ = w.Write(guard.buf.Bytes())
+		})
+	}
+}
+
+type sizeGuardWriter struct {
+	http.ResponseWriter
+	buf      bytes.Buffer
+	max      int64
+	status   int
+	exceeded bool
+}
+
+func (g *sizeGuardWriter) WriteHeader(status int) {
+	g.status = status
+}
+
+func (g *sizeGuardWriter) Write(p []byte) (int, error) {
+	if g.exceeded {
+		return 0, errResponseTooLarge
+	}
+
+	if int64(g.buf.Len()+len(p)) > g.max {
+		g.exceeded = true
+		g.buf.Reset()
+		return 0, errResponseTooLarge
+	}
+
+	return g.buf.Write(p)
+}
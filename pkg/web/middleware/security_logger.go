@@ -20,6 +20,7 @@ const (
 	EventPasswordChanged    SecurityEventType = "password_changed"
 	EventTokenRefreshed     SecurityEventType = "token_refreshed"
 	EventTokenRevoked       SecurityEventType = "token_revoked"
+	EventRequestSmuggling   SecurityEventType = "request_smuggling"
 )
 
 type SecuritySeverity string
@@ -87,6 +88,12 @@ func (s *SecurityLogger) LogIPSpoofing(r *http.Request, suspectedIP string) {
 	})
 }
 
+func (s *SecurityLogger) LogRequestSmuggling(r *http.Request, reason string) {
+	s.LogEvent(EventRequestSmuggling, SeverityHigh, r, map[string]string{
+		"reason": reason,
+	})
+}
+
 func (s *SecurityLogger) LogAuthEvent(eventType SecurityEventType, email string, r *http.Request, success bool, reason string) {
 	if s == nil || s.logger == nil {
 		return
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// clientAuthPolicies maps WEB_HTTP_TLS_CLIENT_AUTH values to the tls
+// policies. Only the verifying ones check the chain against the client CAs;
+// with request and require any certificate is accepted, so identities from
+// middleware.ClientCert are only set under verify_if_given and
+// require_and_verify.
+var clientAuthPolicies = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// clientAuth resolves the client auth policy, defaulting to
+// require_and_verify when a client CA is configured.
+func (c TLSConfig) clientAuth() (tls.ClientAuthType, error) {
+	policy := strings.ToLower(strings.TrimSpace(c.ClientAuth))
+	if policy == "" {
+		if c.ClientCAFile == "" {
+			return tls.NoClientCert, nil
+		}
+		policy = "require_and_verify"
+	}
+
+	auth, ok := clientAuthPolicies[policy]
+	if !ok {
+		return 0, fault.New("unknown TLS client auth policy",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("client_auth", c.ClientAuth),
+			fault.WithContext("hint", "use none, request, require, verify_if_given or require_and_verify"),
+		)
+	}
+	if auth >= tls.VerifyClientCertIfGiven && c.ClientCAFile == "" {
+		return 0, fault.New("TLS client auth policy needs WEB_HTTP_TLS_CLIENT_CA_FILE",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("client_auth", policy),
+		)
+	}
+	return auth, nil
+}
+
+// configureClientAuth applies the client certificate policy and CA pool to
+// the TLS config, before the server accepts connections.
+func (s *Server) configureClientAuth() error {
+	auth, err := s.tlsConfig.clientAuth()
+	if err != nil {
+		return err
+	}
+	s.httpServer.TLSConfig.ClientAuth = auth
+	if s.tlsConfig.ClientCAFile == "" {
+		return nil
+	}
+
+	pem, err := os.ReadFile(s.tlsConfig.ClientCAFile)
+	if err != nil {
+		return fault.Wrap(err, "failed to read TLS client CA file",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("client_ca_file", s.tlsConfig.ClientCAFile),
+		)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fault.New("no certificates found in TLS client CA file",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("client_ca_file", s.tlsConfig.ClientCAFile),
+		)
+	}
+	s.httpServer.TLSConfig.ClientCAs = pool
+	return nil
+}
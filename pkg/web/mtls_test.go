@@ -0,0 +1,159 @@
+package web
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/web/middleware"
+)
+
+func TestServerMTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, "server")
+
+	ca, caKey := newTestCA(t)
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	router.Use(middleware.ClientCert(true, middleware.NewSecurityLogger(slog.Default())))
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		identity, _ := middleware.ClientIdentityFromContext(r.Context())
+		_, _ = w.Write([]byte(identity.CommonName))
+	})
+
+	srv := NewServer(&Config{HTTP: HTTPConfig{
+		Host: "127.0.0.1",
+		TLS:  TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+	}}, nil, router)
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+	url := "https://" + waitBound(t, srv)
+
+	client := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			Certificates:       certs,
+		}}}
+	}
+
+	t.Run("accepts a certificate signed by the client CA", func(t *testing.T) {
+		resp, err := client(issueClientCert(t, ca, caKey, "billing")).Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || string(body) != "billing" {
+			t.Errorf("expected billing identity, got %d %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("rejects clients without a certificate", func(t *testing.T) {
+		if resp, err := client().Get(url); err == nil {
+			resp.Body.Close()
+			t.Error("expected the handshake to fail")
+		}
+	})
+
+	t.Run("rejects certificates from another CA", func(t *testing.T) {
+		other, otherKey := newTestCA(t)
+		if resp, err := client(issueClientCert(t, other, otherKey, "intruder")).Get(url); err == nil {
+			resp.Body.Close()
+			t.Error("expected the handshake to fail")
+		}
+	})
+}
+
+func TestTLSConfigClientAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     TLSConfig
+		want    tls.ClientAuthType
+		wantErr bool
+	}{
+		{name: "disabled by default", cfg: TLSConfig{}, want: tls.NoClientCert},
+		{name: "verifies once a CA is set", cfg: TLSConfig{ClientCAFile: "ca.crt"}, want: tls.RequireAndVerifyClientCert},
+		{name: "explicit policy", cfg: TLSConfig{ClientCAFile: "ca.crt", ClientAuth: "verify_if_given"}, want: tls.VerifyClientCertIfGiven},
+		{name: "request without CA", cfg: TLSConfig{ClientAuth: "request"}, want: tls.RequestClientCert},
+		{name: "verification without CA", cfg: TLSConfig{ClientAuth: "require_and_verify"}, wantErr: true},
+		{name: "unknown policy", cfg: TLSConfig{ClientAuth: "always"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.clientAuth()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, key
+}
+
+func issueClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, cn string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
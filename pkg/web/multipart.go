@@ -0,0 +1,282 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const (
+	DefaultMaxUploadBytes = 32 << 20
+	DefaultMaxUploadParts = 32
+	DefaultMaxFieldBytes  = 64 << 10
+)
+
+// multipartSniffLength is how much of a file part http.DetectContentType
+// looks at.
+const multipartSniffLength = 512
+
+var (
+	ErrInvalidMultipart = fault.New(
+		"invalid multipart body",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrUploadTooLarge = fault.New(
+		"upload too large",
+		fault.WithCode(fault.Invalid),
+	)
+
+	ErrUploadTypeNotAllowed = fault.New(
+		"upload content type not allowed",
+		fault.WithCode(fault.Invalid),
+	)
+)
+
+// MultipartLimits bounds ParseMultipart. Zero values use the defaults;
+// MaxFileBytes defaults to MaxBytes. AllowedTypes lists the media types
+// accepted for file parts, with type/* wildcards, and is checked against
+// the type sniffed from the content, not the one the client declared; empty
+// accepts any.
+type MultipartLimits struct {
+	MaxBytes      int64
+	MaxParts      int
+	MaxFileBytes  int64
+	MaxFieldBytes int64
+	AllowedTypes  []string
+	// TempDir holds the uploaded files; empty uses os.TempDir.
+	TempDir string
+}
+
+func (l MultipartLimits) withDefaults() MultipartLimits {
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = DefaultMaxUploadBytes
+	}
+	if l.MaxParts <= 0 {
+		l.MaxParts = DefaultMaxUploadParts
+	}
+	if l.MaxFileBytes <= 0 || l.MaxFileBytes > l.MaxBytes {
+		l.MaxFileBytes = l.MaxBytes
+	}
+	if l.MaxFieldBytes <= 0 {
+		l.MaxFieldBytes = DefaultMaxFieldBytes
+	}
+	return l
+}
+
+func (l MultipartLimits) allows(contentType string) bool {
+	if len(l.AllowedTypes) == 0 {
+		return true
+	}
+	major, _, _ := strings.Cut(contentType, "/")
+	for _, allowed := range l.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == contentType || allowed == major+"/*" || allowed == "*/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadedFile is a file part spooled to a temporary file.
+type UploadedFile struct {
+	Field    string
+	Filename string
+	// ContentType is sniffed from the content, without parameters.
+	ContentType string
+	Size        int64
+
+	path string
+}
+
+// Open opens the uploaded content for reading.
+func (f *UploadedFile) Open() (*os.File, error) {
+	return os.Open(f.path)
+}
+
+// MultipartForm is a parsed multipart/form-data body.
+type MultipartForm struct {
+	Values url.Values
+	Files  map[string][]*UploadedFile
+}
+
+// File returns the first file uploaded under field.
+func (f *MultipartForm) File(field string) (*UploadedFile, bool) {
+	files := f.Files[field]
+	if len(files) == 0 {
+		return nil, false
+	}
+	return files[0], true
+}
+
+// RemoveAll deletes the temporary files; defer it right after a successful
+// ParseMultipart.
+func (f *MultipartForm) RemoveAll() error {
+	var errs []error
+	for _, files := range f.Files {
+		for _, file := range files {
+			if err := os.Remove(file.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseMultipart streams a multipart/form-data body part by part: fields are
+// read into Values up to MaxFieldBytes, files are written to temporary files
+// with their size and sniffed type checked on the way, so no part is held
+// in memory whole. Errors are fault errors ready for Error; the files of a
+// failed parse are removed.
+//
+//	form, err := web.ParseMultipart(r, web.MultipartLimits{
+//		MaxFileBytes: 5 << 20,
+//		AllowedTypes: []string{"image/png", "image/jpeg"},
+//	})
+//	if err != nil {
+//		web.Error(w, r, err)
+//		return
+//	}
+//	defer form.RemoveAll()
+func ParseMultipart(r *http.Request, limits MultipartLimits) (*MultipartForm, error) {
+	limits = limits.withDefaults()
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		return nil, fault.Wrap(ErrInvalidMultipart, "expected a multipart/form-data body",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("content_type", r.Header.Get("Content-Type")),
+		)
+	}
+	if r.Body == nil {
+		return nil, fault.Wrap(ErrInvalidMultipart, "request body is empty", fault.WithCode(fault.Invalid))
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, limits.MaxBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fault.Wrap(ErrInvalidMultipart, err.Error(), fault.WithCode(fault.Invalid))
+	}
+
+	form := &MultipartForm{
+		Values: url.Values{},
+		Files:  map[string][]*UploadedFile{},
+	}
+
+	for parts := 0; ; parts++ {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form, nil
+		}
+		if err != nil {
+			_ = form.RemoveAll()
+			return nil, multipartError(err, limits)
+		}
+		if parts == limits.MaxParts {
+			_ = form.RemoveAll()
+			return nil, fault.Wrap(ErrUploadTooLarge, "too many parts",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("max_parts", limits.MaxParts),
+			)
+		}
+
+		if part.FileName() == "" {
+			err = readField(form, part, limits)
+		} else {
+			err = spoolFile(form, part, limits)
+		}
+		_ = part.Close()
+		if err != nil {
+			_ = form.RemoveAll()
+			return nil, err
+		}
+	}
+}
+
+func readField(form *MultipartForm, part *multipart.Part, limits MultipartLimits) error {
+	value, err := io.ReadAll(io.LimitReader(part, limits.MaxFieldBytes+1))
+	if err != nil {
+		return multipartError(err, limits)
+	}
+	name := part.FormName()
+	if int64(len(value)) > limits.MaxFieldBytes {
+		return fault.Wrap(ErrUploadTooLarge, "form field too large",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("field", name),
+			fault.WithContext("max_bytes", limits.MaxFieldBytes),
+		)
+	}
+	form.Values.Add(name, string(value))
+	return nil
+}
+
+func spoolFile(form *MultipartForm, part *multipart.Part, limits MultipartLimits) error {
+	field := part.FormName()
+
+	head := make([]byte, multipartSniffLength)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return multipartError(err, limits)
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !limits.allows(contentType) {
+		return fault.Wrap(ErrUploadTypeNotAllowed, "file type not allowed",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("field", field),
+			fault.WithContext("content_type", contentType),
+			fault.WithContext("allowed_types", limits.AllowedTypes),
+		)
+	}
+
+	tmp, err := os.CreateTemp(limits.TempDir, "upload-*")
+	if err != nil {
+		return fault.Wrap(err, "failed to store upload", fault.WithCode(fault.Internal))
+	}
+	file := &UploadedFile{
+		Field:       field,
+		Filename:    part.FileName(),
+		ContentType: contentType,
+		path:        tmp.Name(),
+	}
+	// Registered first so RemoveAll cleans it up on any failure below.
+	form.Files[field] = append(form.Files[field], file)
+
+	written, err := io.Copy(tmp, io.LimitReader(io.MultiReader(bytes.NewReader(head), part), limits.MaxFileBytes+1))
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		return fault.Wrap(closeErr, "failed to store upload", fault.WithCode(fault.Internal))
+	}
+	if err != nil {
+		return multipartError(err, limits)
+	}
+	if written > limits.MaxFileBytes {
+		return fault.Wrap(ErrUploadTooLarge, "file too large",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("field", field),
+			fault.WithContext("max_bytes", limits.MaxFileBytes),
+		)
+	}
+	file.Size = written
+	return nil
+}
+
+// multipartError maps a read failure to ErrUploadTooLarge when the body
+// limit was hit and ErrInvalidMultipart otherwise.
+func multipartError(err error, limits MultipartLimits) error {
+	if isMaxBytesError(err) {
+		return fault.Wrap(ErrUploadTooLarge, "request body too large",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("max_bytes", limits.MaxBytes),
+		)
+	}
+	return fault.Wrap(ErrInvalidMultipart, err.Error(), fault.WithCode(fault.Invalid))
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type multipartFile struct {
+	field, name string
+	content     []byte
+}
+
+func newMultipartRequest(t *testing.T, fields map[string]string, files ...multipartFile) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		part, err := mw.CreateFormFile(f.field, f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = part.Write(f.content)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/uploads", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestParseMultipart(t *testing.T) {
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1024)...)
+	r := newMultipartRequest(t, map[string]string{"title": "Cover"},
+		multipartFile{field: "image", name: "../../cover.png", content: content},
+	)
+
+	form, err := ParseMultipart(r, MultipartLimits{
+		TempDir:      t.TempDir(),
+		AllowedTypes: []string{"image/*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if form.Values.Get("title") != "Cover" {
+		t.Errorf("expected field value, got %v", form.Values)
+	}
+	file, ok := form.File("image")
+	if !ok {
+		t.Fatal("expected uploaded file")
+	}
+	if file.Filename != "cover.png" || file.ContentType != "image/png" || file.Size != int64(len(content)) {
+		t.Errorf("unexpected file %+v", file)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	stored, _ := io.ReadAll(f)
+	_ = f.Close()
+	if !bytes.Equal(stored, content) {
+		t.Error("stored content differs from upload")
+	}
+
+	if err := form.RemoveAll(); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := os.Stat(file.path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected temp file removed, got %v", err)
+	}
+}
+
+func TestParseMultipartLimits(t *testing.T) {
+	png := multipartFile{field: "image", name: "a.png", content: pngHeader}
+
+	tests := []struct {
+		name   string
+		req    func(t *testing.T) *http.Request
+		limits MultipartLimits
+		want   error
+	}{
+		{
+			name:   "disallowed type",
+			req:    func(t *testing.T) *http.Request { return newMultipartRequest(t, nil, png) },
+			limits: MultipartLimits{AllowedTypes: []string{"application/pdf"}},
+			want:   ErrUploadTypeNotAllowed,
+		},
+		{
+			name: "file too large",
+			req: func(t *testing.T) *http.Request {
+				return newMultipartRequest(t, nil, multipartFile{field: "doc", name: "a.txt", content: bytes.Repeat([]byte("a"), 2048)})
+			},
+			limits: MultipartLimits{MaxFileBytes: 1024},
+			want:   ErrUploadTooLarge,
+		},
+		{
+			name: "body too large",
+			req: func(t *testing.T) *http.Request {
+				return newMultipartRequest(t, nil, multipartFile{field: "doc", name: "a.txt", content: bytes.Repeat([]byte("a"), 4096)})
+			},
+			limits: MultipartLimits{MaxBytes: 1024},
+			want:   ErrUploadTooLarge,
+		},
+		{
+			name: "field too large",
+			req: func(t *testing.T) *http.Request {
+				return newMultipartRequest(t, map[string]string{"bio": strings.Repeat("a", 100)})
+			},
+			limits: MultipartLimits{MaxFieldBytes: 10},
+			want:   ErrUploadTooLarge,
+		},
+		{
+			name:   "too many parts",
+			req:    func(t *testing.T) *http.Request { return newMultipartRequest(t, nil, png, png, png) },
+			limits: MultipartLimits{MaxParts: 2},
+			want:   ErrUploadTooLarge,
+		},
+		{
+			name: "not multipart",
+			req: func(t *testing.T) *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/uploads", strings.NewReader("{}"))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			want: ErrInvalidMultipart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.limits.TempDir = dir
+
+			_, err := ParseMultipart(tt.req(t), tt.limits)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+
+			w := httptest.NewRecorder()
+			Error(w, httptest.NewRequest(http.MethodPost, "/uploads", nil), err)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", w.Code)
+			}
+
+			if leftovers, _ := os.ReadDir(dir); len(leftovers) != 0 {
+				t.Errorf("expected temp files removed, found %d", len(leftovers))
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package web
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/marcelofabianov/web/middleware"
+)
+
+// Encoder writes response bodies in one format for Success and the helpers
+// built on it.
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+type encoderFunc struct {
+	contentType string
+	encode      func(w io.Writer, v any) error
+}
+
+func (e encoderFunc) ContentType() string             { return e.contentType }
+func (e encoderFunc) Encode(w io.Writer, v any) error { return e.encode(w, v) }
+
+// NewEncoder returns an Encoder writing contentType with encode.
+func NewEncoder(contentType string, encode func(w io.Writer, v any) error) Encoder {
+	return encoderFunc{contentType: contentType, encode: encode}
+}
+
+// Built-in encoders. JSON is the default for requests without an Accept
+// header or accepting nothing registered. MessagePack uses the json struct
+// tags, so response types need no extra tags; XML needs xml tags and cannot
+// encode maps.
+var (
+	JSONEncoder = NewEncoder("application/json; charset=utf-8", func(w io.Writer, v any) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+	XMLEncoder = NewEncoder("application/xml; charset=utf-8", func(w io.Writer, v any) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(v)
+	})
+	MsgpackEncoder = NewEncoder("application/msgpack", func(w io.Writer, v any) error {
+		enc := msgpack.NewEncoder(w)
+		enc.SetCustomStructTag("json")
+		return enc.Encode(v)
+	})
+)
+
+type registeredEncoder struct {
+	mediaType string
+	encoder   Encoder
+}
+
+var encoders = struct {
+	sync.RWMutex
+	list []registeredEncoder
+}{
+	list: []registeredEncoder{
+		{"application/json", JSONEncoder},
+		{"application/xml", XMLEncoder},
+		{"text/xml", XMLEncoder},
+		{"application/msgpack", MsgpackEncoder},
+		{"application/x-msgpack", MsgpackEncoder},
+		{"application/vnd.msgpack", MsgpackEncoder},
+	},
+}
+
+// RegisterEncoder makes Success answer requests accepting any of
+// mediaTypes, by default the media type of enc.ContentType(), with enc. A
+// media type registered again is replaced. Register encoders at startup,
+// before serving.
+func RegisterEncoder(enc Encoder, mediaTypes ...string) {
+	if len(mediaTypes) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(enc.ContentType())
+		mediaTypes = []string{mediaType}
+	}
+
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	for _, mediaType := range mediaTypes {
+		mediaType = strings.ToLower(mediaType)
+		replaced := false
+		for i := range encoders.list {
+			if encoders.list[i].mediaType == mediaType {
+				encoders.list[i].encoder = enc
+				replaced = true
+			}
+		}
+		if !replaced {
+			encoders.list = append(encoders.list, registeredEncoder{mediaType, enc})
+		}
+	}
+}
+
+// MediaTypes returns the media types Success can produce, in registration
+// order.
+func MediaTypes() []string {
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	types := make([]string, 0, len(encoders.list))
+	for _, registered := range encoders.list {
+		types = append(types, registered.mediaType)
+	}
+	return types
+}
+
+// AcceptNegotiated is middleware.AcceptJSON for negotiated APIs: it answers
+// 406 to requests accepting none of the registered media types, instead of
+// letting Success fall back to JSON.
+func AcceptNegotiated() func(http.Handler) http.Handler {
+	return middleware.Accept(MediaTypes()...)
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate picks the encoder for the Accept header of r: the most
+// preferred range by quality, then by order; type/* and */* ranges pick the
+// first registered match not refused with q=0. It falls back to JSON.
+func negotiate(r *http.Request) Encoder {
+	if r == nil {
+		return JSONEncoder
+	}
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return JSONEncoder
+	}
+
+	var ranges []acceptRange
+	refused := map[string]bool{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			refused[mediaType] = true
+			continue
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	for _, ar := range ranges {
+		major, minor, _ := strings.Cut(ar.mediaType, "/")
+		for _, registered := range encoders.list {
+			if registered.mediaType == ar.mediaType {
+				return registered.encoder
+			}
+			if refused[registered.mediaType] {
+				continue
+			}
+			if major == "*" || (minor == "*" && strings.HasPrefix(registered.mediaType, major+"/")) {
+				return registered.encoder
+			}
+		}
+	}
+	return JSONEncoder
+}
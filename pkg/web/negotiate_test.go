@@ -0,0 +1,133 @@
+package web
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type negotiatedCourse struct {
+	XMLName xml.Name `json:"-" xml:"course"`
+	ID      string   `json:"id" xml:"id"`
+	Title   string   `json:"title" xml:"title"`
+}
+
+func TestSuccessNegotiatesEncoder(t *testing.T) {
+	course := negotiatedCourse{ID: "c-1", Title: "Go"}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "no accept", accept: "", contentType: "application/json; charset=utf-8"},
+		{name: "json", accept: "application/json", contentType: "application/json; charset=utf-8"},
+		{name: "xml", accept: "application/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "text xml", accept: "text/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "msgpack", accept: "application/x-msgpack", contentType: "application/msgpack"},
+		{name: "quality order", accept: "application/json;q=0.5, application/xml", contentType: "application/xml; charset=utf-8"},
+		{name: "browser", accept: "text/html,application/xhtml+xml,*/*;q=0.8", contentType: "application/json; charset=utf-8"},
+		{name: "unsupported falls back", accept: "application/yaml", contentType: "application/json; charset=utf-8"},
+		{name: "refused json", accept: "application/json;q=0, application/*", contentType: "application/xml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/courses/c-1", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			Success(w, r, http.StatusOK, course)
+
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("expected %q, got %q", tt.contentType, got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", got)
+			}
+		})
+	}
+}
+
+func TestSuccessEncodings(t *testing.T) {
+	course := negotiatedCourse{ID: "c-1", Title: "Go"}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	Success(w, r, http.StatusOK, course)
+	if want := xml.Header + "<course><id>c-1</id><title>Go</title></course>"; w.Body.String() != want {
+		t.Errorf("unexpected XML %q", w.Body.String())
+	}
+
+	r.Header.Set("Accept", "application/msgpack")
+	w = httptest.NewRecorder()
+	Success(w, r, http.StatusOK, course)
+	var decoded map[string]string
+	if err := msgpack.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode msgpack: %v", err)
+	}
+	if decoded["id"] != "c-1" || decoded["title"] != "Go" {
+		t.Errorf("expected json tag names in msgpack, got %v", decoded)
+	}
+}
+
+func TestSuccessEncodingFailure(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+
+	Success(w, r, http.StatusOK, map[string]string{"id": "c-1"})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 for an XML-incompatible payload, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("expected JSON error, got %q", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	csv := NewEncoder("text/csv; charset=utf-8", func(w io.Writer, v any) error {
+		course := v.(negotiatedCourse)
+		_, err := io.WriteString(w, course.ID+","+course.Title+"\n")
+		return err
+	})
+	RegisterEncoder(csv)
+	t.Cleanup(func() {
+		encoders.Lock()
+		encoders.list = encoders.list[:len(encoders.list)-1]
+		encoders.Unlock()
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	Success(w, r, http.StatusOK, negotiatedCourse{ID: "c-1", Title: "Go"})
+
+	if w.Body.String() != "c-1,Go\n" {
+		t.Errorf("expected CSV body, got %q", w.Body.String())
+	}
+
+	handler := AcceptNegotiated()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected registered type to be acceptable, got %d", w.Code)
+	}
+
+	r.Header.Set("Accept", "image/png")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotAcceptable || !bytes.Contains(w.Body.Bytes(), []byte("text/csv")) {
+		t.Errorf("expected 406 listing the registered types, got %d %s", w.Code, w.Body.String())
+	}
+}
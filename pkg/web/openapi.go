@@ -0,0 +1,288 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelofabianov/fault"
+)
+
+// SwaggerUIVersion is the swagger-ui-dist release loaded by
+// SwaggerUIHandler. Bump it together with SwaggerUICSSIntegrity and
+// SwaggerUIBundleIntegrity, printed by scripts/swagger-ui-sri.sh.
+const SwaggerUIVersion = "5.17.14"
+
+// Subresource Integrity hashes of the SwaggerUIVersion assets. The browser
+// refuses an asset that does not match, so a tampered CDN copy cannot run
+// on the page. The integrity attribute is left out while a hash is empty.
+const (
+	SwaggerUICSSIntegrity    = ""
+	SwaggerUIBundleIntegrity = ""
+)
+
+var (
+	// ErrInvalidOpenAPI is returned by LoadOpenAPI for a document that does
+	// not parse or is not valid OpenAPI 3.
+	ErrInvalidOpenAPI = fault.New(
+		"invalid OpenAPI document",
+		fault.WithCode(fault.Invalid),
+	)
+
+	// ErrSpecViolation is returned to requests rejected by
+	// OpenAPI.ValidateRequests, with a SpecViolation per mismatch in the
+	// fields of the response.
+	ErrSpecViolation = fault.New(
+		"request does not match the API specification",
+		fault.WithCode(fault.Invalid),
+	)
+)
+
+// SpecViolation is one mismatch between a request and the OpenAPI
+// document.
+type SpecViolation struct {
+	// In is where the mismatch is: path, query, header, cookie or body.
+	In string `json:"in"`
+	// Field is the parameter name, or the JSON pointer into the body.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// OpenAPI is a parsed OpenAPI 3 document, served with SpecHandler and
+// SwaggerUIHandler and enforced with ValidateRequests. Load it once at
+// startup, usually from an embedded file:
+//
+//	//go:embed openapi.yaml
+//	var spec []byte
+//
+//	api, err := web.LoadOpenAPI(spec)
+type OpenAPI struct {
+	doc    *openapi3.T
+	spec   []byte
+	router routers.Router
+}
+
+// LoadOpenAPI parses and validates a JSON or YAML document. External
+// references are not followed.
+func LoadOpenAPI(data []byte) (*OpenAPI, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fault.Wrap(ErrInvalidOpenAPI, err.Error(), fault.WithCode(fault.Invalid))
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fault.Wrap(ErrInvalidOpenAPI, err.Error(), fault.WithCode(fault.Invalid))
+	}
+
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fault.Wrap(ErrInvalidOpenAPI, err.Error(), fault.WithCode(fault.Invalid))
+	}
+
+	router, err := gorillamux.NewRouter(routingDoc(doc))
+	if err != nil {
+		return nil, fault.Wrap(ErrInvalidOpenAPI, err.Error(), fault.WithCode(fault.Invalid))
+	}
+
+	return &OpenAPI{doc: doc, spec: spec, router: router}, nil
+}
+
+// routingDoc returns doc with its servers reduced to their paths, so
+// requests match whatever host and scheme the service is reached through.
+func routingDoc(doc *openapi3.T) *openapi3.T {
+	routing := *doc
+	routing.Servers = nil
+	for _, server := range doc.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil || strings.Contains(server.URL, "{") {
+			continue
+		}
+		routing.Servers = append(routing.Servers, &openapi3.Server{URL: strings.TrimSuffix(u.Path, "/")})
+	}
+	return &routing
+}
+
+// Mount serves the document on GET /openapi.json and Swagger UI on GET
+// /docs. Both describe the whole API: mount them on the public router only
+// when the API is public, otherwise on the admin router.
+func (o *OpenAPI) Mount(r chi.Router) {
+	r.Method(http.MethodGet, "/openapi.json", o.SpecHandler())
+	r.Method(http.MethodGet, "/docs", o.SwaggerUIHandler("/openapi.json"))
+}
+
+// SpecHandler serves the document as JSON, whether it was loaded from
+// JSON or YAML.
+func (o *OpenAPI) SpecHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(o.spec)
+	})
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css"{{with .CSSIntegrity}} integrity="{{.}}"{{end}} crossorigin="anonymous">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js"{{with .BundleIntegrity}} integrity="{{.}}"{{end}} crossorigin="anonymous"></script>
+<script nonce="{{.Nonce}}">
+window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler serves a Swagger UI page for the document at specURL.
+// The UI is loaded from unpkg.com, checked against the SwaggerUI*Integrity
+// hashes when they are set; the page sets its own
+// Content-Security-Policy allowing it, replacing the one of
+// SecurityHeaders.
+func (o *OpenAPI) SwaggerUIHandler(specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce := make([]byte, 16)
+		_, _ = rand.Read(nonce)
+		encodedNonce := base64.StdEncoding.EncodeToString(nonce)
+
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; "+
+			"script-src https://unpkg.com 'nonce-"+encodedNonce+"'; "+
+			"style-src https://unpkg.com 'unsafe-inline'; "+
+			"img-src 'self' data: https://unpkg.com; "+
+			"connect-src 'self'")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		title := "API"
+		if o.doc.Info != nil && o.doc.Info.Title != "" {
+			title = o.doc.Info.Title
+		}
+		_ = swaggerUITemplate.Execute(w, map[string]string{
+			"Title":           title,
+			"Version":         SwaggerUIVersion,
+			"CSSIntegrity":    SwaggerUICSSIntegrity,
+			"BundleIntegrity": SwaggerUIBundleIntegrity,
+			"Nonce":           encodedNonce,
+			"SpecURL":         specURL,
+		})
+	})
+}
+
+// ValidateRequests checks the parameters and body of every request for an
+// operation of the document, answering 400 with ErrSpecViolation and the
+// list of mismatches instead of calling next. Requests for paths or
+// methods the document does not describe pass through, to be answered by
+// the router. Security requirements are left to the auth middleware.
+func (o *OpenAPI) ValidateRequests() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := o.router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:         true,
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			})
+			if err != nil {
+				violations := specViolations(err)
+				Error(w, r, fault.Wrap(ErrSpecViolation, violationsMessage(violations),
+					fault.WithCode(fault.Invalid),
+					fault.WithContext(FieldsContextKey, violations),
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// specViolations flattens the errors of openapi3filter into one entry per
+// parameter or body field.
+func specViolations(err error) []SpecViolation {
+	var violations []SpecViolation
+
+	var walk func(err error, in, field string)
+	walk = func(err error, in, field string) {
+		if multi, ok := err.(openapi3.MultiError); ok {
+			for _, e := range multi {
+				walk(e, in, field)
+			}
+			return
+		}
+
+		var requestErr *openapi3filter.RequestError
+		if errors.As(err, &requestErr) {
+			switch {
+			case requestErr.Parameter != nil:
+				in, field = requestErr.Parameter.In, requestErr.Parameter.Name
+			case requestErr.RequestBody != nil:
+				in = "body"
+			}
+			if requestErr.Err != nil {
+				if _, ok := requestErr.Err.(openapi3.MultiError); ok || isSchemaError(requestErr.Err) {
+					walk(requestErr.Err, in, field)
+					return
+				}
+			}
+			message := requestErr.Reason
+			if message == "" && requestErr.Err != nil {
+				message = requestErr.Err.Error()
+			}
+			violations = append(violations, SpecViolation{In: in, Field: field, Message: message})
+			return
+		}
+
+		var schemaErr *openapi3.SchemaError
+		if errors.As(err, &schemaErr) {
+			if pointer := schemaErr.JSONPointer(); in == "body" && len(pointer) > 0 {
+				field = "/" + strings.Join(pointer, "/")
+			}
+			violations = append(violations, SpecViolation{In: in, Field: field, Message: schemaErr.Reason})
+			return
+		}
+
+		violations = append(violations, SpecViolation{In: in, Field: field, Message: err.Error()})
+	}
+	walk(err, "", "")
+
+	return violations
+}
+
+func isSchemaError(err error) bool {
+	var schemaErr *openapi3.SchemaError
+	return errors.As(err, &schemaErr)
+}
+
+func violationsMessage(violations []SpecViolation) string {
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		name := v.In
+		if v.Field != "" {
+			name += " " + v.Field
+		}
+		messages = append(messages, name+": "+v.Message)
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,192 @@
+package web_test
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/web"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: Course API
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/api/v1
+paths:
+  /courses:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, minimum: 1, maximum: 100}
+      responses:
+        "200": {description: ok}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [title, hours]
+              properties:
+                title: {type: string, minLength: 3}
+                hours: {type: integer, minimum: 1}
+      responses:
+        "201": {description: created}
+  /courses/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer, minimum: 1}
+      responses:
+        "200": {description: ok}
+`
+
+func TestLoadOpenAPI(t *testing.T) {
+	_, err := web.LoadOpenAPI([]byte(testSpec))
+	require.NoError(t, err)
+
+	_, err = web.LoadOpenAPI([]byte("openapi: 3.0.3\ninfo: [broken"))
+	assert.True(t, errors.Is(err, web.ErrInvalidOpenAPI))
+
+	_, err = web.LoadOpenAPI([]byte("openapi: 3.0.3\npaths: {}\n"))
+	assert.True(t, errors.Is(err, web.ErrInvalidOpenAPI), "info is required")
+}
+
+func TestOpenAPIMount(t *testing.T) {
+	spec, err := web.LoadOpenAPI([]byte(testSpec))
+	require.NoError(t, err)
+
+	r := web.NewRouter()
+	spec.Mount(r)
+
+	w := serve(r, http.MethodGet, "/openapi.json", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	var doc map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	w = serve(r, http.MethodGet, "/docs", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "<title>Course API</title>")
+	assert.Contains(t, body, `url: "/openapi.json"`)
+	assert.Contains(t, body, "swagger-ui-dist@"+web.SwaggerUIVersion)
+	// html/template escapes the "+" of base64 hashes in attributes.
+	unescaped := html.UnescapeString(body)
+	assert.Contains(t, unescaped, `swagger-ui.css"`+integrityAttr(web.SwaggerUICSSIntegrity)+` crossorigin="anonymous"`)
+	assert.Contains(t, unescaped, `swagger-ui-bundle.js"`+integrityAttr(web.SwaggerUIBundleIntegrity)+` crossorigin="anonymous"`)
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "script-src https://unpkg.com 'nonce-")
+}
+
+func integrityAttr(hash string) string {
+	if hash == "" {
+		return ""
+	}
+	return ` integrity="` + hash + `"`
+}
+
+func TestOpenAPIValidateRequests(t *testing.T) {
+	spec, err := web.LoadOpenAPI([]byte(testSpec))
+	require.NoError(t, err)
+
+	r := web.NewRouter()
+	r.Use(spec.ValidateRequests())
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/courses", ok)
+		r.Post("/courses", ok)
+		r.Get("/courses/{id}", ok)
+		r.Get("/undocumented", ok)
+	})
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/courses?limit=10", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/courses", `{"title":"Go 101","hours":2}`).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/courses/7", "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/undocumented", "").Code)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   []web.SpecViolation
+	}{
+		{
+			name:   "query parameter",
+			method: http.MethodGet,
+			target: "/api/v1/courses?limit=500",
+			want:   []web.SpecViolation{{In: "query", Field: "limit"}},
+		},
+		{
+			name:   "path parameter",
+			method: http.MethodGet,
+			target: "/api/v1/courses/abc",
+			want:   []web.SpecViolation{{In: "path", Field: "id"}},
+		},
+		{
+			name:   "body fields",
+			method: http.MethodPost,
+			target: "/api/v1/courses",
+			body:   `{"title":"Go","hours":0}`,
+			want:   []web.SpecViolation{{In: "body", Field: "/title"}, {In: "body", Field: "/hours"}},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPost,
+			target: "/api/v1/courses",
+			want:   []web.SpecViolation{{In: "body"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.method, tt.target, tt.body)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp struct {
+				Code   string              `json:"code"`
+				Fields []web.SpecViolation `json:"fields"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, "invalid_input", resp.Code)
+			assert.ElementsMatch(t, fieldKeys(tt.want), fieldKeys(resp.Fields))
+			for _, field := range resp.Fields {
+				assert.NotEmpty(t, field.Message)
+			}
+		})
+	}
+}
+
+func fieldKeys(violations []web.SpecViolation) []string {
+	keys := make([]string, len(violations))
+	for i, v := range violations {
+		keys[i] = v.In + " " + v.Field
+	}
+	return keys
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/marcelofabianov/web/middleware"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// DefaultHSTS is the Strict-Transport-Security value of the production
+// preset: two years, subdomains included.
+const DefaultHSTS = "max-age=63072000; includeSubDomains"
+
+// IsProduction reports whether env selects the production preset. Staging
+// runs the production preset so it catches what production would reject.
+func IsProduction(env string) bool {
+	switch strings.ToLower(env) {
+	case EnvProduction, "prod", EnvStaging:
+		return true
+	default:
+		return false
+	}
+}
+
+// setPresetDefaults overrides the development defaults from setDefaults
+// with the strict set when env is production or staging: JSON logs, no
+// CORS origins unless listed and HTTPS-only with HSTS. Only an explicit
+// development env echoes request bodies; an unset one keeps the other
+// development defaults without it. They stay defaults, so any WEB_*
+// variable set explicitly still wins.
+func setPresetDefaults(v *viper.Viper, env string) {
+	if strings.EqualFold(env, EnvDevelopment) {
+		v.SetDefault("http.echo_request_body", true)
+		return
+	}
+	if !IsProduction(env) {
+		return
+	}
+
+	v.SetDefault("log_format", "json")
+	v.SetDefault("http.cors.allowed_origins", []string{})
+	v.SetDefault("http.https_only.enabled", true)
+	v.SetDefault("http.https_only.trust_forwarded_proto", true)
+	v.SetDefault("http.security_headers.enabled", true)
+	v.SetDefault("http.security_headers.hsts", DefaultHSTS)
+}
+
+// NewLogger returns the service logger for cfg: text in development, JSON
+// in production, as selected by WEB_LOG_FORMAT or the environment preset.
+func NewLogger(cfg *Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if cfg.LogFormat == "text" {
+		return slog.New(slog.NewTextHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+}
+
+func (c SecurityHeadersConfig) middlewareConfig() middleware.SecurityHeadersConfig {
+	return middleware.SecurityHeadersConfig{
+		XContentTypeOptions:     "nosniff",
+		XFrameOptions:           "DENY",
+		ReferrerPolicy:          "no-referrer",
+		StrictTransportSecurity: c.HSTS,
+	}
+}
+
+func (c HTTPSOnlyConfig) middlewareConfig() middleware.HTTPSOnlyConfig {
+	return middleware.HTTPSOnlyConfig{
+		Enabled:             c.Enabled,
+		TrustForwardedProto: c.TrustForwardedProto,
+		ExemptPaths:         c.ExemptPaths,
+	}
+}
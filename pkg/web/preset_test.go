@@ -0,0 +1,155 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/web"
+)
+
+func TestEnvironmentPresets(t *testing.T) {
+	t.Run("development", func(t *testing.T) {
+		t.Setenv("WEB_ENVIRONMENT", "development")
+
+		cfg, err := web.LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, "text", cfg.LogFormat)
+		assert.Equal(t, []string{"*"}, cfg.HTTP.CORS.AllowedOrigins)
+		assert.True(t, cfg.HTTP.EchoRequestBody)
+		assert.False(t, cfg.HTTP.HTTPSOnly.Enabled)
+		assert.False(t, cfg.HTTP.SecurityHeaders.Enabled)
+		assert.Empty(t, cfg.HTTP.SecurityHeaders.HSTS)
+	})
+
+	t.Run("unset environment does not echo bodies", func(t *testing.T) {
+		cfg, err := web.LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, web.EnvDevelopment, cfg.Environment)
+		assert.Equal(t, "text", cfg.LogFormat)
+		assert.False(t, cfg.HTTP.EchoRequestBody)
+	})
+
+	t.Run("production", func(t *testing.T) {
+		t.Setenv("WEB_ENVIRONMENT", "production")
+
+		cfg, err := web.LoadConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, "json", cfg.LogFormat)
+		assert.Empty(t, cfg.HTTP.CORS.AllowedOrigins)
+		assert.False(t, cfg.HTTP.EchoRequestBody)
+		assert.True(t, cfg.HTTP.HTTPSOnly.Enabled)
+		assert.True(t, cfg.HTTP.HTTPSOnly.TrustForwardedProto)
+		assert.Contains(t, cfg.HTTP.HTTPSOnly.ExemptPaths, "/health/ready")
+		assert.True(t, cfg.HTTP.SecurityHeaders.Enabled)
+		assert.Equal(t, web.DefaultHSTS, cfg.HTTP.SecurityHeaders.HSTS)
+	})
+
+	t.Run("explicit variables override the preset", func(t *testing.T) {
+		t.Setenv("WEB_ENVIRONMENT", "production")
+		t.Setenv("WEB_HTTP_HTTPS_ONLY_ENABLED", "false")
+		t.Setenv("WEB_LOG_FORMAT", "text")
+
+		cfg, err := web.LoadConfig()
+		require.NoError(t, err)
+
+		assert.False(t, cfg.HTTP.HTTPSOnly.Enabled)
+		assert.Equal(t, "text", cfg.LogFormat)
+		assert.True(t, cfg.HTTP.SecurityHeaders.Enabled)
+	})
+}
+
+func TestProductionChain(t *testing.T) {
+	t.Setenv("WEB_ENVIRONMENT", "production")
+	cfg, err := web.LoadConfig()
+	require.NoError(t, err)
+
+	r := web.NewRouter()
+	r.Use(web.StandardMiddleware(cfg, nil, nil)...)
+	r.Get("/health", web.LivenessHandler)
+	r.Get("/courses", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("plain HTTP rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("forwarded HTTPS gets HSTS", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/courses", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, web.DefaultHSTS, w.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("probes stay on plain HTTP", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestErrorEchoesRequestBody(t *testing.T) {
+	cfg := &web.Config{}
+	cfg.HTTP.EchoRequestBody = true
+
+	r := web.NewRouter()
+	r.Use(web.StandardMiddleware(cfg, nil, nil)...)
+	r.Post("/courses", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := web.DecodeJSON(w, r, &body); err == nil {
+			err = fault.New("title is required", fault.WithCode(fault.Invalid))
+			web.Error(w, r, err)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/courses", strings.NewReader(`{"name":"Go"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"request_body":"{\"name\":\"Go\"}"`)
+}
+
+func TestErrorEchoRedactsRequestBody(t *testing.T) {
+	cfg := &web.Config{}
+	cfg.HTTP.EchoRequestBody = true
+
+	r := web.NewRouter()
+	r.Use(web.StandardMiddleware(cfg, nil, nil)...)
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := web.DecodeJSON(w, r, &body); err == nil {
+			web.Error(w, r, fault.New("email is required", fault.WithCode(fault.Invalid)))
+		}
+	})
+
+	for _, accept := range []string{"application/json", web.ProblemContentType} {
+		t.Run(accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users",
+				strings.NewReader(`{"name":"Ana","password":"s3cret","cpf":"123.456.789-09"}`))
+			req.Header.Set("Accept", accept)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			body := w.Body.String()
+			assert.Contains(t, body, `"request_body"`)
+			assert.Contains(t, body, `\"name\":\"Ana\"`)
+			assert.NotContains(t, body, "s3cret")
+			assert.NotContains(t, body, "123.456.789-09")
+		})
+	}
+}
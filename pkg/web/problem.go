@@ -0,0 +1,112 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/marcelofabianov/fault"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// problemMembers are the members defined by RFC 7807 and by Problem itself;
+// fault context keys with these names are not copied as extensions.
+var problemMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true,
+	"code": true, "request_id": true, "details": true, "request_body": true,
+}
+
+// ProblemDetailsConfig switches Error to RFC 7807 responses for every
+// request. TypeBaseURI prefixes the fault code to build the problem type,
+// e.g. "https://errors.example.com/" gives
+// "https://errors.example.com/invalid_input"; empty uses "about:blank".
+type ProblemDetailsConfig struct {
+	Enabled     bool
+	TypeBaseURI string
+}
+
+type problemKey struct{}
+
+// ProblemDetails makes Error write problem details for the requests it
+// wraps. StandardMiddleware adds it when WEB_HTTP_PROBLEM_DETAILS_ENABLED is
+// set; use it directly to switch a route group only.
+func ProblemDetails(cfg ProblemDetailsConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), problemKey{}, cfg)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// wantsProblem reports whether Error should answer r with problem details:
+// ProblemDetails is on for the request, or the client accepts
+// application/problem+json.
+func wantsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	if _, ok := r.Context().Value(problemKey{}).(ProblemDetailsConfig); ok {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+// Problem writes err as RFC 7807 problem details regardless of the
+// configured mode. The fault code becomes the "code" extension and the
+// problem type, the message the detail, the request path the instance, and
+// each fault context entry an extension member; per-field failures are
+// rendered as "fields" as in Error.
+func Problem(w http.ResponseWriter, r *http.Request, err error) {
+	fields, _ := errorFields(err)
+	writeProblem(w, r, fault.ToResponse(err), fields)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, response fault.ErrorResponse, fields any) {
+	problem := make(map[string]any, len(response.Context)+8)
+	for key, value := range response.Context {
+		if !problemMembers[key] {
+			problem[key] = value
+		}
+	}
+	if fields != nil {
+		problem[FieldsContextKey] = fields
+	}
+
+	problem["type"] = problemType(r, response.Code)
+	problem["title"] = http.StatusText(response.StatusCode)
+	problem["status"] = response.StatusCode
+	problem["detail"] = response.Message
+	problem["code"] = response.Code
+	if len(response.Details) > 0 {
+		problem["details"] = response.Details
+	}
+	if r != nil {
+		problem["instance"] = r.URL.Path
+		if requestID := chimiddleware.GetReqID(r.Context()); requestID != "" {
+			problem["request_id"] = requestID
+		}
+	}
+	if body, ok := echoedRequestBody(r); ok {
+		problem["request_body"] = body
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(response.StatusCode)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func problemType(r *http.Request, code string) string {
+	if r == nil {
+		return "about:blank"
+	}
+	cfg, _ := r.Context().Value(problemKey{}).(ProblemDetailsConfig)
+	if cfg.TypeBaseURI == "" || code == "" {
+		return "about:blank"
+	}
+	return cfg.TypeBaseURI + code
+}
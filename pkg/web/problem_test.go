@@ -0,0 +1,115 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+)
+
+func TestProblem(t *testing.T) {
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+		t.Helper()
+		if got := w.Header().Get("Content-Type"); got != ProblemContentType {
+			t.Fatalf("expected %s, got %s", ProblemContentType, got)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	notFound := fault.New("course not found",
+		fault.WithCode(fault.NotFound),
+		fault.WithContext("course_id", "42"),
+		fault.WithContext("status", "shadowed"),
+	)
+
+	t.Run("maps fault code and context to members", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/courses/42", nil)
+
+		Problem(w, r, notFound)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+		body := decode(t, w)
+		want := map[string]any{
+			"type":      "about:blank",
+			"title":     "Not Found",
+			"status":    float64(404),
+			"detail":    "course not found",
+			"instance":  "/courses/42",
+			"code":      "not_found",
+			"course_id": "42",
+		}
+		for key, value := range want {
+			if body[key] != value {
+				t.Errorf("%s: expected %v, got %v", key, value, body[key])
+			}
+		}
+	})
+
+	t.Run("config switches Error and sets the type", func(t *testing.T) {
+		handler := ProblemDetails(ProblemDetailsConfig{Enabled: true, TypeBaseURI: "https://errors.example.com/"})(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Error(w, r, notFound)
+			}))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/42", nil))
+
+		if got := decode(t, w)["type"]; got != "https://errors.example.com/not_found" {
+			t.Errorf("unexpected type %v", got)
+		}
+	})
+
+	t.Run("negotiated through Accept", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", "application/problem+json, application/json")
+
+		Error(w, r, notFound)
+
+		decode(t, w)
+	})
+
+	t.Run("keeps per-field failures", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		fields := []map[string]string{{"path": "email", "tag": "email"}}
+
+		Problem(w, r, fault.New("invalid", fault.WithCode(fault.Invalid), fault.WithContext(FieldsContextKey, fields)))
+
+		if got, ok := decode(t, w)[FieldsContextKey].([]any); !ok || len(got) != 1 {
+			t.Errorf("expected one field failure, got %v", got)
+		}
+	})
+
+	t.Run("router 404", func(t *testing.T) {
+		r := NewRouter()
+		r.Use(ProblemDetails(ProblemDetailsConfig{Enabled: true}))
+		r.Get("/courses", func(w http.ResponseWriter, r *http.Request) {})
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		if got := decode(t, w)["code"]; got != "not_found" {
+			t.Errorf("unexpected code %v", got)
+		}
+	})
+
+	t.Run("plain JSON by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		Error(w, httptest.NewRequest(http.MethodGet, "/", nil), notFound)
+
+		if got := w.Header().Get("Content-Type"); got == ProblemContentType {
+			t.Error("expected the default error shape")
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReadinessCacheTTL is how long readiness results are reused.
+	// Kubelets, load balancers and monitors probing every second would
+	// otherwise each cost a round trip to every dependency.
+	DefaultReadinessCacheTTL = 5 * time.Second
+	// DefaultReadinessTimeout bounds one run of the checks.
+	DefaultReadinessTimeout = 5 * time.Second
+)
+
+// Readiness serves the readiness probe. It answers 200 when healthy or
+// degraded and 503 when unhealthy (see Critical and Informational), with a
+// minimal body; ?verbose=1 adds the result of every check. Results are
+// cached, and concurrent probes share one run of the checks.
+type Readiness struct {
+	checkers []HealthChecker
+	cacheTTL time.Duration
+	timeout  time.Duration
+	metrics  *HealthMetrics
+
+	mu        sync.Mutex
+	checks    map[string]CheckResult
+	checkedAt time.Time
+}
+
+func NewReadiness(checkers ...HealthChecker) *Readiness {
+	return &Readiness{
+		checkers: checkers,
+		cacheTTL: DefaultReadinessCacheTTL,
+		timeout:  DefaultReadinessTimeout,
+	}
+}
+
+// WithCacheTTL sets how long results are reused; zero runs the checks on
+// every probe.
+func (rd *Readiness) WithCacheTTL(ttl time.Duration) *Readiness {
+	rd.cacheTTL = ttl
+	return rd
+}
+
+// WithTimeout bounds one run of the checks; per-check timeouts are set
+// with CheckTimeout.
+func (rd *Readiness) WithTimeout(timeout time.Duration) *Readiness {
+	rd.timeout = timeout
+	return rd
+}
+
+// WithMetrics records every run of the checks in metrics, so the exported
+// health matches what the probe answered.
+func (rd *Readiness) WithMetrics(metrics *HealthMetrics) *Readiness {
+	rd.metrics = metrics
+	return rd
+}
+
+// Check returns the results of the checks, cached or fresh, and when they
+// were taken.
+func (rd *Readiness) Check(ctx context.Context) (map[string]CheckResult, time.Time) {
+	if rd.cacheTTL <= 0 {
+		return rd.run(ctx), time.Now()
+	}
+
+	// Holding the lock through the run makes concurrent probes wait for it
+	// and reuse its results.
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+
+	if rd.checks == nil || time.Since(rd.checkedAt) >= rd.cacheTTL {
+		// The results outlive the request, so its cancellation must not
+		// fail them.
+		rd.checks = rd.run(context.WithoutCancel(ctx))
+		rd.checkedAt = time.Now()
+	}
+	return rd.checks, rd.checkedAt
+}
+
+func (rd *Readiness) run(ctx context.Context) map[string]CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, rd.timeout)
+	defer cancel()
+
+	checks := runChecks(ctx, rd.checkers)
+	if rd.metrics != nil {
+		rd.metrics.Record(checks)
+	}
+	return checks
+}
+
+func (rd *Readiness) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	checks, checkedAt := rd.Check(r.Context())
+
+	status := overallStatus(checks)
+	statusCode := http.StatusOK
+	if status == HealthStatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	response := HealthResponse{
+		Status:    status,
+		Timestamp: time.Now(),
+		Uptime:    time.Since(startTime).String(),
+	}
+	if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); verbose {
+		response.Checks = checks
+		response.CheckedAt = checkedAt
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,183 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingChecker counts its runs and waits for delay, ignoring the
+// context like a driver without deadline support.
+type countingChecker struct {
+	name  string
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (c *countingChecker) Name() string { return c.name }
+
+func (c *countingChecker) Check(context.Context) error {
+	c.calls.Add(1)
+	time.Sleep(c.delay)
+	return nil
+}
+
+func probe(t *testing.T, handler http.Handler, target string) (int, HealthResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var response HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	return w.Code, response
+}
+
+func TestReadinessCache(t *testing.T) {
+	t.Run("reuses results within the TTL", func(t *testing.T) {
+		checker := &countingChecker{name: "db"}
+		rd := NewReadiness(checker)
+
+		probe(t, rd, "/health/ready")
+		probe(t, rd, "/health/ready")
+
+		if got := checker.calls.Load(); got != 1 {
+			t.Errorf("expected one run, got %d", got)
+		}
+	})
+
+	t.Run("runs again after the TTL", func(t *testing.T) {
+		checker := &countingChecker{name: "db"}
+		rd := NewReadiness(checker).WithCacheTTL(10 * time.Millisecond)
+
+		probe(t, rd, "/health/ready")
+		time.Sleep(20 * time.Millisecond)
+		probe(t, rd, "/health/ready")
+
+		if got := checker.calls.Load(); got != 2 {
+			t.Errorf("expected two runs, got %d", got)
+		}
+	})
+
+	t.Run("concurrent probes share one run", func(t *testing.T) {
+		checker := &countingChecker{name: "db", delay: 20 * time.Millisecond}
+		rd := NewReadiness(checker)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rd.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			}()
+		}
+		wg.Wait()
+
+		if got := checker.calls.Load(); got != 1 {
+			t.Errorf("expected one run, got %d", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		checker := &countingChecker{name: "db"}
+		rd := NewReadiness(checker).WithCacheTTL(0)
+
+		probe(t, rd, "/health/ready")
+		probe(t, rd, "/health/ready")
+
+		if got := checker.calls.Load(); got != 2 {
+			t.Errorf("expected two runs, got %d", got)
+		}
+	})
+}
+
+func TestReadinessVerbose(t *testing.T) {
+	rd := NewReadiness(stubChecker{name: "db", err: errors.New("connection refused")})
+
+	_, minimal := probe(t, rd, "/health/ready")
+	if minimal.Checks != nil || !minimal.CheckedAt.IsZero() {
+		t.Errorf("expected a minimal body, got %+v", minimal)
+	}
+
+	_, verbose := probe(t, rd, "/health/ready?verbose=1")
+	if verbose.Checks["db"].Error != "connection refused" {
+		t.Errorf("expected the check detail, got %+v", verbose.Checks)
+	}
+	if verbose.CheckedAt.IsZero() {
+		t.Error("expected checked_at to be set")
+	}
+}
+
+func TestReadinessCriticality(t *testing.T) {
+	failing := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		checkers   []HealthChecker
+		wantCode   int
+		wantStatus HealthStatus
+	}{
+		{
+			name:       "unmarked checks fail readiness only together",
+			checkers:   []HealthChecker{stubChecker{name: "db"}, stubChecker{name: "redis", err: failing}},
+			wantCode:   http.StatusOK,
+			wantStatus: HealthStatusDegraded,
+		},
+		{
+			name:       "critical check fails readiness alone",
+			checkers:   []HealthChecker{stubChecker{name: "db"}, Critical(stubChecker{name: "redis", err: failing})},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusUnhealthy,
+		},
+		{
+			name:       "informational check only degrades",
+			checkers:   []HealthChecker{Informational(stubChecker{name: "redis", err: failing})},
+			wantCode:   http.StatusOK,
+			wantStatus: HealthStatusDegraded,
+		},
+		{
+			name: "informational checks are not counted",
+			checkers: []HealthChecker{
+				stubChecker{name: "db", err: failing},
+				Informational(stubChecker{name: "redis"}),
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := probe(t, NewReadiness(tt.checkers...), "/health/ready?verbose=true")
+			if code != tt.wantCode || response.Status != tt.wantStatus {
+				t.Errorf("expected %d %s, got %d %s", tt.wantCode, tt.wantStatus, code, response.Status)
+			}
+		})
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	slow := &countingChecker{name: "db", delay: time.Second}
+	rd := NewReadiness(Critical(CheckTimeout(slow, 10*time.Millisecond)), stubChecker{name: "redis"})
+
+	start := time.Now()
+	code, response := probe(t, rd, "/health/ready?verbose=1")
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the timeout to cut the check short, took %s", elapsed)
+	}
+
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", code)
+	}
+	db := response.Checks["db"]
+	if db.Status != "unhealthy" || db.Criticality != CheckCritical || db.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("unexpected db result %+v", db)
+	}
+}
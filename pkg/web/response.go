@@ -1,10 +1,16 @@
 package web
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"maps"
 	"net/http"
 
 	"github.com/marcelofabianov/fault"
+	"github.com/marcelofabianov/redact"
+
+	"github.com/marcelofabianov/web/middleware"
 )
 
 type ErrorResponse struct {
@@ -17,15 +23,105 @@ type MessageResponse struct {
 	Message string `json:"message" example:"Operation completed successfully"`
 }
 
+// Success writes data with the encoder negotiated from the Accept header,
+// JSON unless the client prefers another registered format; see
+// RegisterEncoder. The body is encoded before the status is written, so an
+// encoding failure becomes a 500.
 func Success(w http.ResponseWriter, r *http.Request, status int, data any) {
-	writeJSON(w, status, data)
+	enc := negotiate(r)
+	w.Header().Add("Vary", "Accept")
+	if data == nil {
+		w.Header().Set("Content-Type", enc.ContentType())
+		w.WriteHeader(status)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, data); err != nil {
+		Error(w, r, fault.Wrap(err, "failed to encode response",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("content_type", enc.ContentType()),
+		))
+		return
+	}
+
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// FieldsContextKey is the fault context key of per-field failures, such as
+// the ValidationErrors of pkg/validation. Error renders them as a top-level
+// "fields" array instead of inside the context.
+const FieldsContextKey = "fields"
+
+// errorResponse is the fault response plus the per-field failures.
+type errorResponse struct {
+	fault.ErrorResponse
+	Fields any `json:"fields,omitempty"`
+}
+
+// debugErrorResponse is an error response echoing the request body, written
+// when the EchoRequestBody middleware is enabled (development preset).
+type debugErrorResponse struct {
+	errorResponse
+	RequestBody string `json:"request_body"`
 }
 
+// Error writes err as JSON with the status of its fault code. Requests under
+// ProblemDetails, or accepting application/problem+json, get RFC 7807
+// problem details instead; see Problem.
 func Error(w http.ResponseWriter, r *http.Request, err error) {
-	response := fault.ToResponse(err)
+	if wantsProblem(r) {
+		Problem(w, r, err)
+		return
+	}
+
+	response := errorResponse{ErrorResponse: fault.ToResponse(err)}
+	if fields, ok := errorFields(err); ok {
+		response.Fields = fields
+		response.Context = maps.Clone(response.Context)
+		delete(response.Context, FieldsContextKey)
+	}
+
+	if body, ok := echoedRequestBody(r); ok {
+		writeJSON(w, response.StatusCode, debugErrorResponse{errorResponse: response, RequestBody: body})
+		return
+	}
 	writeJSON(w, response.StatusCode, response)
 }
 
+// echoRedactor masks the echoed request bodies with the rules the logger
+// applies.
+var echoRedactor = redact.Default()
+
+// echoedRequestBody returns the body kept by EchoRequestBody with sensitive
+// fields, documents and card numbers masked, since it goes back to the
+// client and into whatever records the response.
+func echoedRequestBody(r *http.Request) (string, bool) {
+	body, ok := middleware.EchoedRequestBody(r)
+	if !ok {
+		return "", false
+	}
+	return string(echoRedactor.JSON([]byte(body))), true
+}
+
+// errorFields finds FieldsContextKey in the fault chain of err, so fields
+// survive callers wrapping the validation error.
+func errorFields(err error) (any, bool) {
+	for err != nil {
+		var fErr *fault.Error
+		if !errors.As(err, &fErr) {
+			return nil, false
+		}
+		if fields, ok := fErr.Context[FieldsContextKey]; ok {
+			return fields, true
+		}
+		err = fErr.Unwrap()
+	}
+	return nil, false
+}
+
 func Created(w http.ResponseWriter, r *http.Request, data any) {
 	Success(w, r, http.StatusCreated, data)
 }
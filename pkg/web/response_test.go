@@ -1,6 +1,7 @@
 package web
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -38,6 +39,36 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestErrorFields(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	fields := []map[string]string{{"path": "email", "tag": "email", "message": "invalid email"}}
+	validationErr := fault.New("email: invalid email",
+		fault.WithCode(fault.Invalid),
+		fault.WithContext(FieldsContextKey, fields),
+		fault.WithContext("error_count", 1),
+	)
+	Error(w, r, fault.Wrap(validationErr, "create enrollment", fault.WithCode(fault.Invalid)))
+
+	var body struct {
+		Message string              `json:"message"`
+		Fields  []map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if len(body.Fields) != 1 || body.Fields[0]["path"] != "email" {
+		t.Errorf("expected fields array, got %s", w.Body.String())
+	}
+	if _, ok := validationErr.Context[FieldsContextKey]; !ok {
+		t.Error("expected the error context to be left untouched")
+	}
+}
+
 func TestCreated(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodPost, "/", nil)
@@ -1,7 +1,96 @@
 package web
 
-import "github.com/go-chi/chi/v5"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marcelofabianov/fault"
+)
 
 type Router interface {
 	RegisterRoutes(r chi.Router)
 }
+
+// routeMethods are the methods probed when building an Allow header.
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// NewRouter returns a chi router whose 404 and 405 responses use the
+// ErrorResponse JSON shape, or problem details when Error would use them. A
+// 405 carries an Allow header listing the methods actually registered for
+// the path, and OPTIONS requests to a path without an explicit OPTIONS route
+// are answered with 204 and the same Allow header. HEAD is listed whenever
+// GET is, matching middleware.AutoHead.
+func NewRouter() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		if wantsProblem(req) {
+			writeProblem(w, req, fault.ErrorResponse{
+				Code:       "not_found",
+				Message:    "resource not found",
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, ErrorResponse{
+			Code:       "not_found",
+			Message:    "resource not found",
+			StatusCode: http.StatusNotFound,
+		})
+	})
+
+	r.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Allow", strings.Join(allowedMethods(r, req), ", "))
+
+		if req.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if wantsProblem(req) {
+			writeProblem(w, req, fault.ErrorResponse{
+				Code:       "method_not_allowed",
+				Message:    "method " + req.Method + " not allowed",
+				StatusCode: http.StatusMethodNotAllowed,
+			}, nil)
+			return
+		}
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{
+			Code:       "method_not_allowed",
+			Message:    "method " + req.Method + " not allowed",
+			StatusCode: http.StatusMethodNotAllowed,
+		})
+	})
+
+	return r
+}
+
+func allowedMethods(mux *chi.Mux, r *http.Request) []string {
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	var allowed []string
+	hasGet := false
+	for _, method := range routeMethods {
+		if method == http.MethodHead && hasGet {
+			allowed = append(allowed, method)
+			continue
+		}
+		if mux.Match(chi.NewRouteContext(), method, path) {
+			allowed = append(allowed, method)
+			hasGet = hasGet || method == http.MethodGet
+		}
+	}
+
+	return append(allowed, http.MethodOptions)
+}
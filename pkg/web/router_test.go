@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestNewRouter(t *testing.T) {
+	r := NewRouter()
+	r.Get("/courses", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/courses", func(w http.ResponseWriter, r *http.Request) {})
+	r.Route("/lessons", func(sub chi.Router) {
+		sub.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	t.Run("405 with allow header and json body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/courses", nil))
+
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("expected status 405, got %d", w.Code)
+		}
+		if allow := w.Header().Get("Allow"); allow != "GET, HEAD, POST, OPTIONS" {
+			t.Errorf("unexpected Allow header %q", allow)
+		}
+
+		var resp ErrorResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if resp.Code != "method_not_allowed" || resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("unexpected response %+v", resp)
+		}
+	})
+
+	t.Run("options answered with allow header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/lessons/42", nil))
+
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("expected status 204, got %d", w.Code)
+		}
+		if allow := w.Header().Get("Allow"); allow != "DELETE, OPTIONS" {
+			t.Errorf("unexpected Allow header %q", allow)
+		}
+	})
+
+	t.Run("404 json", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status 404, got %d", w.Code)
+		}
+
+		var resp ErrorResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if resp.Code != "not_found" {
+			t.Errorf("unexpected response %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DefaultShutdownTimeout bounds Shutdown, and separately the shutdown hooks
+// run by Run, when WEB_HTTP_SHUTDOWN_TIMEOUT is not set.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// ShutdownHook releases a resource (database pool, cache client, tracer
+// provider) once the server has stopped serving requests.
+type ShutdownHook struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// WithShutdownHook registers a hook run by Run after the HTTP server has
+// shut down. Hooks run in registration order.
+func WithShutdownHook(name string, fn func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.OnShutdown(name, fn)
+	}
+}
+
+// OnShutdown is WithShutdownHook for resources created after the server.
+// It must not be called once Run is shutting down.
+func (s *Server) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.hooks = append(s.hooks, ShutdownHook{Name: name, Fn: fn})
+}
+
+// Run starts the server and blocks until ctx is done or the process gets
+// SIGINT or SIGTERM. It then shuts the server down, draining in-flight
+// requests (and waiting out the Drain, if any), and runs the shutdown hooks
+// in order. A second signal kills the process. Hooks also run when the
+// server fails to start, so resources opened before Run are released.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	started := make(chan error, 1)
+	go func() { started <- s.Start() }()
+
+	select {
+	case err := <-started:
+		return errors.Join(err, s.runShutdownHooks())
+	case <-ctx.Done():
+	}
+	// Restore the default handlers so a second signal kills the process.
+	stop()
+	s.logger.Info("Shutdown requested", "cause", context.Cause(ctx).Error())
+
+	err := s.Shutdown(context.Background())
+	if startErr := <-started; startErr != nil {
+		err = errors.Join(err, startErr)
+	}
+	return errors.Join(err, s.runShutdownHooks())
+}
+
+// runShutdownHooks runs every hook within the shutdown timeout, logging
+// failures and carrying on with the next hook.
+func (s *Server) runShutdownHooks() error {
+	if len(s.hooks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, hook := range s.hooks {
+		start := time.Now()
+		if err := hook.Fn(ctx); err != nil {
+			s.logger.Error("Shutdown hook failed", "hook", hook.Name, "error", err)
+			errs = append(errs, fault.Wrap(err, "shutdown hook failed",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("hook", hook.Name),
+			))
+			continue
+		}
+		s.logger.Info("Shutdown hook done", "hook", hook.Name, "duration", time.Since(start).String())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerRun(t *testing.T) {
+	t.Run("drains requests then runs hooks in order", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := NewServer(&Config{HTTP: HTTPConfig{Host: "127.0.0.1"}}, nil,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+		var order []string
+		srv.OnShutdown("db", func(context.Context) error {
+			order = append(order, "db")
+			return nil
+		})
+		srv.OnShutdown("cache", func(context.Context) error {
+			order = append(order, "cache")
+			return errors.New("cache already closed")
+		})
+		srv.OnShutdown("tracer", func(context.Context) error {
+			order = append(order, "tracer")
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- srv.Run(ctx) }()
+
+		addr := waitBound(t, srv)
+		status := make(chan int, 1)
+		go func() {
+			resp, err := http.Get("http://" + addr)
+			if err != nil {
+				status <- 0
+				return
+			}
+			resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+
+		if got := <-status; got != http.StatusNoContent {
+			t.Errorf("expected in-flight request to complete, got status %d", got)
+		}
+
+		err := <-done
+		if err == nil || !strings.Contains(err.Error(), "cache already closed") {
+			t.Errorf("expected hook error, got %v", err)
+		}
+		if got := strings.Join(order, ","); got != "db,cache,tracer" {
+			t.Errorf("expected hooks in order, got %s", got)
+		}
+	})
+
+	t.Run("runs hooks when the server cannot start", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+		port := ln.Addr().(*net.TCPAddr).Port
+
+		closed := false
+		srv := NewServer(&Config{HTTP: HTTPConfig{Host: "127.0.0.1", Port: port}}, nil, http.NotFoundHandler(),
+			WithShutdownHook("db", func(context.Context) error {
+				closed = true
+				return nil
+			}))
+
+		if err := srv.Run(context.Background()); !errors.Is(err, ErrPortInUse) {
+			t.Errorf("expected ErrPortInUse, got %v", err)
+		}
+		if !closed {
+			t.Error("expected hook to run")
+		}
+	})
+}
+
+func waitBound(t *testing.T, srv *Server) string {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if addr := srv.bound.Load(); addr != nil {
+			return *addr
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("server did not bind")
+	return ""
+}
@@ -6,7 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/marcelofabianov/fault"
@@ -17,10 +21,43 @@ type Server struct {
 	logger     *slog.Logger
 	router     http.Handler
 	addr       string
+	bound      atomic.Pointer[string]
+	addrs      atomic.Pointer[[]string]
+	listen     ListenConfig
 	tlsConfig  *TLSConfig
+	certs      *certReloader
+	conns      connTracker
+	drain      *Drain
+
+	admin        *http.Server
+	adminHandler http.Handler
+	adminBound   atomic.Pointer[string]
+
+	shutdownTimeout time.Duration
+	hooks           []ShutdownHook
 }
 
-func NewServer(cfg *Config, logger *slog.Logger, router http.Handler) *Server {
+type ServerOption func(*Server)
+
+// WithDrain ties the server to drain: keep-alives are disabled once it
+// begins, and Shutdown begins it (if no preStop hook did) and waits out the
+// delay before closing listeners.
+func WithDrain(drain *Drain) ServerOption {
+	return func(s *Server) {
+		s.drain = drain
+	}
+}
+
+// ServerStats reports connection counters. SlowClientDisconnects counts
+// connections closed before a complete request header arrived, typically
+// clients cut off by ReadHeaderTimeout (slowloris) or probes that never
+// send a request.
+type ServerStats struct {
+	OpenConnections       int64
+	SlowClientDisconnects int64
+}
+
+func NewServer(cfg *Config, logger *slog.Logger, router http.Handler, opts ...ServerOption) *Server {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -29,19 +66,35 @@ func NewServer(cfg *Config, logger *slog.Logger, router http.Handler) *Server {
 
 	server := &Server{
 		httpServer: &http.Server{
-			Addr:         addr,
-			Handler:      router,
-			ReadTimeout:  cfg.HTTP.ReadTimeout,
-			WriteTimeout: cfg.HTTP.WriteTimeout,
-			IdleTimeout:  cfg.HTTP.IdleTimeout,
+			Addr:              addr,
+			Handler:           router,
+			ReadTimeout:       cfg.HTTP.ReadTimeout,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+			WriteTimeout:      cfg.HTTP.WriteTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+			MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
 		},
 		logger:    logger,
 		router:    router,
 		addr:      addr,
+		listen:    cfg.HTTP.Listen,
 		tlsConfig: &cfg.HTTP.TLS,
+
+		shutdownTimeout: cfg.HTTP.ShutdownTimeout,
 	}
+	if server.shutdownTimeout <= 0 {
+		server.shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	server.httpServer.Protocols, server.httpServer.HTTP2 = cfg.HTTP.HTTP2.protocols()
+	server.httpServer.Handler = server.conns.handler(router)
+	server.httpServer.ConnContext = func(ctx context.Context, conn net.Conn) context.Context {
+		return withPeerAddr(server.conns.connContext(ctx, conn), conn)
+	}
+	server.httpServer.ConnState = server.conns.track
 
 	if cfg.HTTP.TLS.Enabled {
+		server.certs = newCertReloader(cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile)
 		server.httpServer.TLSConfig = &tls.Config{
 			MinVersion: tls.VersionTLS12,
 			MaxVersion: tls.VersionTLS13,
@@ -59,48 +112,227 @@ func NewServer(cfg *Config, logger *slog.Logger, router http.Handler) *Server {
 				tls.X25519,
 				tls.CurveP256,
 			},
+			GetCertificate: server.certs.getCertificate,
 		}
 	}
 
+	for _, opt := range opts {
+		opt(server)
+	}
+	if cfg.HTTP.Admin.Port > 0 {
+		server.admin = newAdminServer(cfg, server.adminHandler)
+	}
+	if server.drain != nil {
+		server.drain.onBegin(func() {
+			server.httpServer.SetKeepAlivesEnabled(false)
+		})
+	}
+
 	return server
 }
 
 func (s *Server) Start() error {
+	if s.certs != nil {
+		if _, err := s.certs.load(); err != nil {
+			return err
+		}
+		if err := s.configureClientAuth(); err != nil {
+			return err
+		}
+	}
+
+	lns, err := s.listeners()
+	if err != nil {
+		return err
+	}
+	if s.admin != nil {
+		if err := s.startAdmin(); err != nil {
+			for _, ln := range lns {
+				_ = ln.Close()
+			}
+			return err
+		}
+	}
+	addrs := make([]string, len(lns))
+	for i, ln := range lns {
+		addrs[i] = listenerAddr(ln)
+	}
+	s.bound.Store(&addrs[0])
+	s.addrs.Store(&addrs)
+
+	if s.tlsConfig.Enabled && s.tlsConfig.ReloadInterval > 0 {
+		go s.certs.watch(s.tlsConfig.ReloadInterval, s.logger)
+	}
+
+	// Every listener is served by the same http.Server; if one fails the
+	// server is closed so Start returns instead of serving partially.
+	errs := make(chan error, len(lns))
+	for i, ln := range lns {
+		go func() { errs <- s.serve(ln, addrs[i]) }()
+	}
+	var serveErr error
+	for range lns {
+		if err := <-errs; err != nil && serveErr == nil {
+			serveErr = err
+			_ = s.httpServer.Close()
+		}
+	}
+	return serveErr
+}
+
+func (s *Server) serve(ln net.Listener, addr string) error {
 	if s.tlsConfig.Enabled {
 		s.logger.Info("Starting HTTPS server with TLS 1.2/1.3",
-			"addr", s.addr,
+			"addr", addr,
 			"cert_file", s.tlsConfig.CertFile,
 			"key_file", s.tlsConfig.KeyFile,
+			"http2", s.httpServer.Protocols.HTTP2(),
+			"reload_interval", s.tlsConfig.ReloadInterval.String(),
+			"client_auth", s.httpServer.TLSConfig.ClientAuth.String(),
 		)
 
-		if err := s.httpServer.ListenAndServeTLS(s.tlsConfig.CertFile, s.tlsConfig.KeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			return fault.Wrap(err, "failed to start HTTPS server", fault.WithCode(fault.Internal))
-		}
-	} else {
-		s.logger.Info("Starting HTTP server", "addr", s.addr)
-
-		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			return fault.Wrap(err, "failed to start HTTP server", fault.WithCode(fault.Internal))
+		// The certificate comes from GetCertificate, so no files are passed.
+		if err := s.httpServer.ServeTLS(ln, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fault.Wrap(err, "failed to start HTTPS server",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("addr", addr),
+			)
 		}
+		return nil
 	}
 
+	s.logger.Info("Starting HTTP server", "addr", addr, "h2c", s.httpServer.Protocols.UnencryptedHTTP2())
+
+	if err := s.httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fault.Wrap(err, "failed to start HTTP server",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("addr", addr),
+		)
+	}
 	return nil
 }
 
+// listenerAddr formats the bound address, with a unix:// scheme for sockets
+// so Addrs round-trips through WEB_HTTP_LISTEN.
+func listenerAddr(ln net.Listener) string {
+	addr := ln.Addr()
+	if addr.Network() == "unix" {
+		return "unix://" + addr.String()
+	}
+	return addr.String()
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
-	s.logger.Info("Shutting down HTTP server", "addr", s.addr)
+	s.logger.Info("Shutting down HTTP server", "addr", s.Addr())
+	if s.certs != nil {
+		s.certs.close()
+	}
 
-	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(ctx, s.shutdownTimeout)
 	defer cancel()
 
+	if s.drain != nil {
+		s.drain.Begin()
+		if err := s.drain.Wait(shutdownCtx); err != nil {
+			s.logger.Warn("Drain interrupted", "error", err)
+		}
+	}
+
 	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 		return fault.Wrap(err, "failed to shutdown HTTP server", fault.WithCode(fault.Internal))
 	}
+	// The admin listener goes last so probes and metrics stay up while
+	// requests drain.
+	if s.admin != nil {
+		if err := s.admin.Shutdown(shutdownCtx); err != nil {
+			return fault.Wrap(err, "failed to shutdown admin server", fault.WithCode(fault.Internal))
+		}
+	}
 
 	s.logger.Info("HTTP server shutdown complete")
 	return nil
 }
 
+// Addr returns the address the server listens on once Start has bound it,
+// which differs from the configured one when a free port was picked, and
+// the configured address before that. With several listeners it is the
+// first; see Addrs.
 func (s *Server) Addr() string {
-	return s.addr
+	if addr := s.bound.Load(); addr != nil {
+		return *addr
+	}
+	return s.Addrs()[0]
+}
+
+// Addrs returns every address the server listens on once Start has bound
+// them, and the configured ones before that.
+func (s *Server) Addrs() []string {
+	if addrs := s.addrs.Load(); addrs != nil {
+		return slices.Clone(*addrs)
+	}
+	if len(s.listen.Addresses) > 0 {
+		return slices.Clone(s.listen.Addresses)
+	}
+	return []string{s.addr}
+}
+
+func (s *Server) Stats() ServerStats {
+	return ServerStats{
+		OpenConnections:       s.conns.open.Load(),
+		SlowClientDisconnects: s.conns.slow.Load(),
+	}
+}
+
+type connTrackerKey struct{}
+
+// connTracker counts open connections and those closed before any request
+// reached the handler. Go marks a connection active on its first byte, so a
+// request served flag is tracked through the connection context instead.
+type connTracker struct {
+	mu     sync.Mutex
+	served map[net.Conn]*atomic.Bool
+	open   atomic.Int64
+	slow   atomic.Int64
+}
+
+func (t *connTracker) connContext(ctx context.Context, conn net.Conn) context.Context {
+	served := &atomic.Bool{}
+
+	t.mu.Lock()
+	if t.served == nil {
+		t.served = make(map[net.Conn]*atomic.Bool)
+	}
+	t.served[conn] = served
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, connTrackerKey{}, served)
+}
+
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		t.open.Add(1)
+	case http.StateHijacked, http.StateClosed:
+		t.mu.Lock()
+		served, ok := t.served[conn]
+		delete(t.served, conn)
+		t.mu.Unlock()
+
+		if !ok {
+			return
+		}
+		t.open.Add(-1)
+		if state == http.StateClosed && !served.Load() {
+			t.slow.Add(1)
+		}
+	}
+}
+
+func (t *connTracker) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if served, ok := r.Context().Value(connTrackerKey{}).(*atomic.Bool); ok {
+			served.Store(true)
+		}
+		next.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerSlowClientDisconnects(t *testing.T) {
+	cfg := &Config{HTTP: HTTPConfig{
+		Host:              "127.0.0.1",
+		ReadHeaderTimeout: 50 * time.Millisecond,
+		MaxHeaderBytes:    1 << 10,
+	}}
+
+	srv := NewServer(cfg, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.httpServer.Serve(ln) }()
+	defer srv.httpServer.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Send an incomplete header and stall until ReadHeaderTimeout fires.
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: x\r\n"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if srv.Stats().SlowClientDisconnects == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stats := srv.Stats()
+	if stats.SlowClientDisconnects != 1 {
+		t.Errorf("expected 1 slow client disconnect, got %d", stats.SlowClientDisconnects)
+	}
+	if stats.OpenConnections != 0 {
+		t.Errorf("expected 0 open connections, got %d", stats.OpenConnections)
+	}
+}
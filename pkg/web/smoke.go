@@ -0,0 +1,77 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrSmokeFailed is returned by Smoke when any check fails. Its context
+// lists every failed check.
+var ErrSmokeFailed = fault.New(
+	"smoke test failed",
+	fault.WithCode(fault.Internal),
+)
+
+// SmokeCheck is one request Smoke sends through the router.
+type SmokeCheck struct {
+	// Method defaults to GET.
+	Method string
+	Path   string
+	// Status is the expected status code; zero accepts any 2xx.
+	Status int
+}
+
+// DefaultSmokeChecks hit the liveness and readiness probes, followed by
+// extra, typically one representative endpoint per router group.
+func DefaultSmokeChecks(extra ...SmokeCheck) []SmokeCheck {
+	return append([]SmokeCheck{
+		{Path: "/health"},
+		{Path: "/health/ready"},
+	}, extra...)
+}
+
+// Smoke runs checks against handler in-process, through the full middleware
+// chain but without opening a port, so a service binary can verify its
+// wiring and configuration as a container health gate or post-deploy
+// check. Requests are sent as HTTPS to the "localhost" host; include it in
+// WEB_HTTP_ALLOWED_HOSTS when the allow-list is on.
+func Smoke(ctx context.Context, handler http.Handler, checks ...SmokeCheck) error {
+	var failures []string
+	for _, check := range checks {
+		method := check.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		req := httptest.NewRequestWithContext(ctx, method, check.Path, nil)
+		req.Host = "localhost"
+		req.RemoteAddr = "127.0.0.1:0"
+		// Pose as an HTTPS client so the production preset's HTTPS-only
+		// check passes.
+		req.TLS = &tls.ConnectionState{HandshakeComplete: true}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		ok := w.Code >= 200 && w.Code < 300
+		if check.Status != 0 {
+			ok = w.Code == check.Status
+		}
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s %s: %d", method, check.Path, w.Code))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fault.Wrap(ErrSmokeFailed, strings.Join(failures, "; "),
+			fault.WithContext("failures", failures),
+		)
+	}
+	return nil
+}
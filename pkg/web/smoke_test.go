@@ -0,0 +1,39 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/web"
+)
+
+func TestSmoke(t *testing.T) {
+	r := web.NewRouter()
+	r.Get("/health", web.LivenessHandler)
+	r.Get("/health/ready", web.ReadinessHandler())
+	r.Post("/courses", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	t.Run("passes", func(t *testing.T) {
+		checks := web.DefaultSmokeChecks(
+			web.SmokeCheck{Method: http.MethodPost, Path: "/courses", Status: http.StatusCreated},
+		)
+		assert.NoError(t, web.Smoke(context.Background(), r, checks...))
+	})
+
+	t.Run("reports every failure", func(t *testing.T) {
+		err := web.Smoke(context.Background(), r,
+			web.SmokeCheck{Path: "/missing"},
+			web.SmokeCheck{Method: http.MethodPost, Path: "/courses", Status: http.StatusOK},
+		)
+
+		assert.True(t, errors.Is(err, web.ErrSmokeFailed))
+		assert.Contains(t, err.Error(), "GET /missing: 404")
+		assert.Contains(t, err.Error(), "POST /courses: 201")
+	})
+}
@@ -0,0 +1,223 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DefaultSSEHeartbeat is how often an idle SSEStream sends a keep-alive
+// comment, below the idle timeouts of common proxies and load balancers.
+const DefaultSSEHeartbeat = 15 * time.Second
+
+var (
+	// ErrStreamingUnsupported is returned by NewSSEStream when the response
+	// writer cannot flush, e.g. behind a middleware that buffers responses.
+	ErrStreamingUnsupported = fault.New(
+		"response writer does not support streaming",
+		fault.WithCode(fault.Internal),
+	)
+
+	// ErrStreamClosed is returned by Send once the client disconnected or
+	// the stream was closed.
+	ErrStreamClosed = fault.New(
+		"event stream closed",
+		fault.WithCode(fault.Internal),
+	)
+)
+
+// SSEEvent is a Server-Sent Event. Data that is not a string or []byte is
+// encoded as JSON; multi-line data is split into several data fields.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  any
+}
+
+// SSEOption configures an SSEStream.
+type SSEOption func(*SSEStream)
+
+// WithSSEHeartbeat sets the keep-alive interval; zero or less disables it.
+func WithSSEHeartbeat(interval time.Duration) SSEOption {
+	return func(s *SSEStream) {
+		s.heartbeat = interval
+	}
+}
+
+// WithSSERetry tells the client how long to wait before reconnecting.
+func WithSSERetry(retry time.Duration) SSEOption {
+	return func(s *SSEStream) {
+		s.retry = retry
+	}
+}
+
+// SSEStream writes Server-Sent Events to one client. Send is safe for
+// concurrent use; Close must be called before the handler returns.
+type SSEStream struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	ctx       context.Context
+	cancel    context.CancelFunc
+	heartbeat time.Duration
+	retry     time.Duration
+
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewSSEStream starts an event stream on w: it writes the text/event-stream
+// headers, lifts the server WriteTimeout for this response only, flushes
+// the headers and starts the heartbeat. The stream ends when the client
+// disconnects, see Done, or when Close is called.
+func NewSSEStream(w http.ResponseWriter, r *http.Request, opts ...SSEOption) (*SSEStream, error) {
+	ctx, cancel := context.WithCancel(r.Context())
+	s := &SSEStream{
+		w:         w,
+		rc:        http.NewResponseController(w),
+		ctx:       ctx,
+		cancel:    cancel,
+		heartbeat: DefaultSSEHeartbeat,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		cancel()
+		return nil, fault.Wrap(err, "failed to lift write deadline", fault.WithCode(fault.Internal))
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	if r.ProtoMajor == 1 {
+		header.Set("Connection", "keep-alive")
+	}
+	header.Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+
+	if s.retry > 0 {
+		_, _ = w.Write([]byte("retry: " + strconv.FormatInt(s.retry.Milliseconds(), 10) + "\n\n"))
+	}
+	if err := s.rc.Flush(); err != nil {
+		cancel()
+		return nil, ErrStreamingUnsupported
+	}
+
+	if s.heartbeat > 0 {
+		s.wg.Add(1)
+		go s.keepAlive()
+	}
+	return s, nil
+}
+
+// Send writes an event named event; an empty name uses the client's default
+// "message" event.
+func (s *SSEStream) Send(event string, data any) error {
+	return s.SendEvent(SSEEvent{Event: event, Data: data})
+}
+
+// SendEvent writes ev and flushes it to the client.
+func (s *SSEStream) SendEvent(ev SSEEvent) error {
+	payload, err := sseData(ev.Data)
+	if err != nil {
+		return fault.Wrap(err, "failed to encode event",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("event", ev.Event),
+		)
+	}
+
+	var buf bytes.Buffer
+	if ev.ID != "" {
+		buf.WriteString("id: " + sseLine(ev.ID) + "\n")
+	}
+	if ev.Event != "" {
+		buf.WriteString("event: " + sseLine(ev.Event) + "\n")
+	}
+	for _, line := range strings.Split(payload, "\n") {
+		buf.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	buf.WriteByte('\n')
+
+	return s.write(buf.Bytes())
+}
+
+// Done is closed when the client disconnects, a write fails or Close is
+// called.
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Close stops the heartbeat and waits for it, so nothing writes to the
+// response after the handler returns.
+func (s *SSEStream) Close() {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+	})
+}
+
+func (s *SSEStream) keepAlive() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if s.write([]byte(": keep-alive\n\n")) != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *SSEStream) write(p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return ErrStreamClosed
+	}
+	if _, err := s.w.Write(p); err != nil {
+		s.cancel()
+		return ErrStreamClosed
+	}
+	if err := s.rc.Flush(); err != nil {
+		s.cancel()
+		return ErrStreamClosed
+	}
+	return nil
+}
+
+func sseData(data any) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	encoded, err := json.Marshal(data)
+	return string(encoded), err
+}
+
+// sseLine keeps id and event fields on one line so they cannot inject
+// fields of their own.
+func sseLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,125 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEStreamSend(t *testing.T) {
+	w := httptest.NewRecorder()
+	stream, err := NewSSEStream(w, httptest.NewRequest(http.MethodGet, "/events", nil),
+		WithSSEHeartbeat(0), WithSSERetry(3*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := stream.Send("enrolled", map[string]string{"course": "go"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := stream.SendEvent(SSEEvent{ID: "7\nevent: spoof", Data: "line one\nline two"}); err != nil {
+		t.Fatalf("send event: %v", err)
+	}
+	stream.Close()
+
+	if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("unexpected Cache-Control %q", got)
+	}
+
+	want := "retry: 3000\n\n" +
+		"event: enrolled\ndata: {\"course\":\"go\"}\n\n" +
+		"id: 7event: spoof\ndata: line one\ndata: line two\n\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("unexpected stream:\n%q\nwant:\n%q", got, want)
+	}
+
+	if err := stream.Send("late", "x"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("expected ErrStreamClosed after Close, got %v", err)
+	}
+}
+
+func TestSSEStreamOutlivesWriteTimeout(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := NewSSEStream(w, r, WithSSEHeartbeat(20*time.Millisecond))
+		if err != nil {
+			Error(w, r, err)
+			return
+		}
+		defer stream.Close()
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(60 * time.Millisecond)
+			if err := stream.Send("tick", "x"); err != nil {
+				return
+			}
+		}
+	}))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	ticks, heartbeats := 0, 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		switch {
+		case scanner.Text() == "event: tick":
+			ticks++
+		case strings.HasPrefix(scanner.Text(), ": keep-alive"):
+			heartbeats++
+		}
+	}
+
+	if ticks != 3 {
+		t.Errorf("expected 3 events past the write timeout, got %d (%v)", ticks, scanner.Err())
+	}
+	if heartbeats == 0 {
+		t.Error("expected keep-alive comments")
+	}
+}
+
+func TestSSEStreamDetectsDisconnect(t *testing.T) {
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := NewSSEStream(w, r, WithSSEHeartbeat(10*time.Millisecond))
+		if err != nil {
+			done <- err
+			return
+		}
+		defer stream.Close()
+
+		select {
+		case <-stream.Done():
+			done <- stream.Send("late", "x")
+		case <-time.After(2 * time.Second):
+			done <- errors.New("disconnect not detected")
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	cancel()
+	_ = resp.Body.Close()
+
+	if err := <-done; !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("expected ErrStreamClosed, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Startup tracks the initialization of a service, so the startup and
+// readiness probes fail until migrations have run, caches are warm and
+// whatever else main does has finished, instead of answering 200 as soon
+// as the port opens. The service is ready once SetReady(true) has been
+// called and every expected dependency is marked ready. It is safe for
+// concurrent use.
+type Startup struct {
+	mu      sync.Mutex
+	ready   bool
+	started bool
+	pending []string
+}
+
+// NewStartup returns a Startup waiting for SetReady(true) and for
+// dependencies to be marked ready.
+func NewStartup(dependencies ...string) *Startup {
+	s := &Startup{}
+	s.ExpectDependencies(dependencies...)
+	return s
+}
+
+// DefaultStartup is the Startup behind SetReady, MarkDependencyReady,
+// ExpectDependencies and StartupHandler.
+var DefaultStartup = NewStartup()
+
+// ExpectDependencies adds dependencies that must be marked ready before
+// the service is. Call it before the service can become ready; it has no
+// effect on a started service.
+func (s *Startup) ExpectDependencies(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	for _, name := range names {
+		if !slices.Contains(s.pending, name) {
+			s.pending = append(s.pending, name)
+		}
+	}
+}
+
+// MarkDependencyReady records that name has finished initializing, e.g.
+// "db" once its migrations have run.
+func (s *Startup) MarkDependencyReady(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = slices.DeleteFunc(s.pending, func(pending string) bool { return pending == name })
+	s.update()
+}
+
+// SetReady marks initialization as finished, or the service as unable to
+// take traffic again with false, e.g. while a cache is rebuilt. The startup
+// probe keeps passing once it has.
+func (s *Startup) SetReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ready = ready
+	s.update()
+}
+
+func (s *Startup) update() {
+	if s.ready && len(s.pending) == 0 {
+		s.started = true
+	}
+}
+
+// Ready reports whether the service can take traffic.
+func (s *Startup) Ready() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ready && len(s.pending) == 0
+}
+
+// Started reports whether the service has been ready at least once.
+func (s *Startup) Started() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.started
+}
+
+// Pending returns the expected dependencies not marked ready yet.
+func (s *Startup) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.pending)
+}
+
+// Handler serves the startup probe: 503 "starting" with the pending
+// dependencies until the service has been ready once, 200 from then on, so
+// the kubelet starts the liveness and readiness probes only after a slow
+// initialization.
+func (s *Startup) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Started() {
+			writeStartup(w, http.StatusOK, HealthStatusHealthy, nil)
+			return
+		}
+		writeStartup(w, http.StatusServiceUnavailable, HealthStatusStarting, s.Pending())
+	}
+}
+
+// Readiness wraps a readiness handler (usually ReadinessHandler) so it
+// answers 503 without running checks while the service is not ready:
+// "starting" before it has been ready once, "unhealthy" after SetReady
+// (false).
+func (s *Startup) Readiness(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Ready() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		status := HealthStatusStarting
+		if s.Started() {
+			status = HealthStatusUnhealthy
+		}
+		writeStartup(w, http.StatusServiceUnavailable, status, s.Pending())
+	})
+}
+
+func writeStartup(w http.ResponseWriter, statusCode int, status HealthStatus, pending []string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(HealthResponse{
+		Status:    status,
+		Timestamp: time.Now(),
+		Uptime:    time.Since(startTime).String(),
+		Pending:   pending,
+	})
+}
+
+// SetReady calls SetReady on DefaultStartup.
+func SetReady(ready bool) {
+	DefaultStartup.SetReady(ready)
+}
+
+// MarkDependencyReady calls MarkDependencyReady on DefaultStartup.
+func MarkDependencyReady(name string) {
+	DefaultStartup.MarkDependencyReady(name)
+}
+
+// ExpectDependencies calls ExpectDependencies on DefaultStartup.
+func ExpectDependencies(names ...string) {
+	DefaultStartup.ExpectDependencies(names...)
+}
+
+// StartupHandler serves the startup probe of DefaultStartup, e.g. on
+// /health/startup.
+func StartupHandler(w http.ResponseWriter, r *http.Request) {
+	DefaultStartup.Handler()(w, r)
+}
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestStartup(t *testing.T) {
+	startup := NewStartup("db", "cache")
+	startupProbe := startup.Handler()
+	readiness := startup.Readiness(ReadinessHandler())
+
+	code, body := probe(t, startupProbe, "/health/startup")
+	if code != http.StatusServiceUnavailable || body.Status != HealthStatusStarting {
+		t.Fatalf("expected 503 starting, got %d %s", code, body.Status)
+	}
+	if !slices.Equal(body.Pending, []string{"db", "cache"}) {
+		t.Errorf("expected both dependencies pending, got %v", body.Pending)
+	}
+
+	startup.MarkDependencyReady("db")
+	startup.SetReady(true)
+	if startup.Ready() {
+		t.Fatal("expected the pending cache to hold readiness back")
+	}
+	code, body = probe(t, readiness, "/health/ready")
+	if code != http.StatusServiceUnavailable || !slices.Equal(body.Pending, []string{"cache"}) {
+		t.Errorf("expected 503 waiting for cache, got %d %v", code, body.Pending)
+	}
+
+	startup.MarkDependencyReady("cache")
+	if code, _ := probe(t, startupProbe, "/health/startup"); code != http.StatusOK {
+		t.Errorf("expected the startup probe to pass, got %d", code)
+	}
+	if code, body := probe(t, readiness, "/health/ready"); code != http.StatusOK || body.Status != HealthStatusHealthy {
+		t.Errorf("expected readiness to run the checks, got %d %s", code, body.Status)
+	}
+
+	startup.SetReady(false)
+	code, body = probe(t, readiness, "/health/ready")
+	if code != http.StatusServiceUnavailable || body.Status != HealthStatusUnhealthy {
+		t.Errorf("expected 503 unhealthy after SetReady(false), got %d %s", code, body.Status)
+	}
+	if code, _ := probe(t, startupProbe, "/health/startup"); code != http.StatusOK {
+		t.Errorf("expected the startup probe to keep passing, got %d", code)
+	}
+
+	// Expecting more once started changes nothing.
+	startup.SetReady(true)
+	startup.ExpectDependencies("search")
+	if !startup.Ready() {
+		t.Error("expected a started service to stay ready")
+	}
+}
+
+func TestStartupWithoutDependencies(t *testing.T) {
+	startup := NewStartup()
+	if startup.Ready() || startup.Started() {
+		t.Fatal("expected a new Startup to wait for SetReady")
+	}
+
+	startup.SetReady(true)
+	if !startup.Ready() || !startup.Started() {
+		t.Error("expected SetReady(true) to finish the startup")
+	}
+}
@@ -0,0 +1,191 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DependencyStatus is everything the status page knows about one
+// dependency. Sections that were not registered are omitted.
+type DependencyStatus struct {
+	Health  *CheckResult    `json:"health,omitempty"`
+	Breaker string          `json:"breaker,omitempty"`
+	Stats   any             `json:"stats,omitempty"`
+	Latency *LatencySummary `json:"latency,omitempty"`
+}
+
+type StatusReport struct {
+	Status       HealthStatus                `json:"status"`
+	Timestamp    time.Time                   `json:"timestamp"`
+	Uptime       string                      `json:"uptime"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// StatusPage serves an internal incident dashboard at e.g. /admin/status:
+// health, circuit breaker state, pool stats and recent latency percentiles
+// and errors per dependency. It renders HTML for browsers and JSON when the
+// client accepts application/json or passes ?format=json. It exposes
+// internals and must only be mounted on an internal listener or behind
+// authentication.
+type StatusPage struct {
+	tracker  *LatencyTracker
+	checkers []HealthChecker
+	breakers map[string]func() string
+	stats    map[string]func() any
+}
+
+func NewStatusPage(tracker *LatencyTracker) *StatusPage {
+	return &StatusPage{
+		tracker:  tracker,
+		breakers: make(map[string]func() string),
+		stats:    make(map[string]func() any),
+	}
+}
+
+// WithCheckers adds health checks, keyed by checker name.
+func (sp *StatusPage) WithCheckers(checkers ...HealthChecker) *StatusPage {
+	sp.checkers = append(sp.checkers, checkers...)
+	return sp
+}
+
+// WithBreaker reports the circuit breaker state of dependency.
+func (sp *StatusPage) WithBreaker(dependency string, state func() string) *StatusPage {
+	sp.breakers[dependency] = state
+	return sp
+}
+
+// WithStats reports pool or client statistics of dependency, e.g.
+// db.Stats or redisClient.PoolStats.
+func (sp *StatusPage) WithStats(dependency string, stats func() any) *StatusPage {
+	sp.stats[dependency] = stats
+	return sp
+}
+
+// Report collects the current status of every dependency.
+func (sp *StatusPage) Report(ctx context.Context) StatusReport {
+	checks := runChecks(ctx, sp.checkers)
+	dependencies := make(map[string]DependencyStatus)
+
+	for name, check := range checks {
+		dep := dependencies[name]
+		dep.Health = &check
+		dependencies[name] = dep
+	}
+	for name, state := range sp.breakers {
+		dep := dependencies[name]
+		dep.Breaker = state()
+		dependencies[name] = dep
+	}
+	for name, stats := range sp.stats {
+		dep := dependencies[name]
+		dep.Stats = stats()
+		dependencies[name] = dep
+	}
+	if sp.tracker != nil {
+		for _, name := range sp.tracker.Dependencies() {
+			summary := sp.tracker.Summary(name)
+			dep := dependencies[name]
+			dep.Latency = &summary
+			dependencies[name] = dep
+		}
+	}
+
+	return StatusReport{
+		Status:       overallStatus(checks),
+		Timestamp:    time.Now(),
+		Uptime:       time.Since(startTime).String(),
+		Dependencies: dependencies,
+	}
+}
+
+func (sp *StatusPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	report := sp.Report(ctx)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = statusTemplate.Execute(w, newStatusView(report))
+}
+
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+type statusView struct {
+	StatusReport
+	Names []string
+}
+
+func newStatusView(report StatusReport) statusView {
+	names := make([]string, 0, len(report.Dependencies))
+	for name := range report.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return statusView{StatusReport: report, Names: names}
+}
+
+func (v statusView) Dependency(name string) DependencyStatus {
+	return v.Dependencies[name]
+}
+
+func (v statusView) StatsJSON(stats any) string {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Status</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.healthy, .closed { color: #080; }
+.degraded, .half-open { color: #a60; }
+.unhealthy, .open { color: #c00; }
+pre { margin: 0; }
+</style>
+</head>
+<body>
+<h1>Status: <span class="{{.Status}}">{{.Status}}</span></h1>
+<p>{{.Timestamp.Format "2006-01-02T15:04:05Z07:00"}} &middot; uptime {{.Uptime}}</p>
+{{range .Names}}{{$dep := $.Dependency .}}
+<h2>{{.}}</h2>
+<table>
+{{with $dep.Health}}<tr><th>Health</th><td class="{{.Status}}">{{.Status}} ({{.Latency}}){{if .Error}}: {{.Error}}{{end}}</td></tr>{{end}}
+{{with $dep.Breaker}}<tr><th>Breaker</th><td class="{{.}}">{{.}}</td></tr>{{end}}
+{{with $dep.Latency}}<tr><th>Latency ({{.Window}})</th><td>{{.Count}} calls, {{.Errors}} errors &middot; p50 {{.P50}} &middot; p95 {{.P95}} &middot; p99 {{.P99}}</td></tr>
+{{if .RecentErrors}}<tr><th>Recent errors</th><td>{{range .RecentErrors}}{{.Time.Format "15:04:05"}} {{.Error}}<br>{{end}}</td></tr>{{end}}{{end}}
+{{with $dep.Stats}}<tr><th>Stats</th><td><pre>{{$.StatsJSON .}}</pre></td></tr>{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
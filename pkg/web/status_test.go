@@ -0,0 +1,108 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (c stubChecker) Name() string                    { return c.name }
+func (c stubChecker) Check(ctx context.Context) error { return c.err }
+
+func newTestStatusPage() *StatusPage {
+	tracker := NewLatencyTracker(time.Minute)
+	tracker.Observe("postgres", 3*time.Millisecond, nil)
+	tracker.Observe("redis", time.Second, errors.New("i/o timeout"))
+
+	return NewStatusPage(tracker).
+		WithCheckers(
+			stubChecker{name: "postgres"},
+			stubChecker{name: "redis", err: errors.New("connection refused")},
+		).
+		WithBreaker("redis", func() string { return "open" }).
+		WithStats("postgres", func() any { return map[string]int{"open": 4} })
+}
+
+func TestStatusPageJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/admin/status", nil)
+	r.Header.Set("Accept", "application/json")
+
+	newTestStatusPage().ServeHTTP(w, r)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON content type, got %s", ct)
+	}
+
+	var report StatusReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if report.Status != HealthStatusDegraded {
+		t.Errorf("expected degraded status, got %s", report.Status)
+	}
+
+	redis := report.Dependencies["redis"]
+	if redis.Health == nil || redis.Health.Status != "unhealthy" {
+		t.Errorf("expected redis to be unhealthy, got %+v", redis.Health)
+	}
+	if redis.Breaker != "open" {
+		t.Errorf("expected redis breaker open, got %q", redis.Breaker)
+	}
+	if redis.Latency == nil || redis.Latency.Errors != 1 || len(redis.Latency.RecentErrors) != 1 {
+		t.Errorf("expected redis latency with one error, got %+v", redis.Latency)
+	}
+
+	postgres := report.Dependencies["postgres"]
+	if postgres.Stats == nil {
+		t.Error("expected postgres stats")
+	}
+	if postgres.Latency == nil || postgres.Latency.P50 != "3ms" {
+		t.Errorf("expected postgres p50 3ms, got %+v", postgres.Latency)
+	}
+}
+
+func TestStatusPageHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/admin/status", nil)
+	r.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	newTestStatusPage().ServeHTTP(w, r)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("expected HTML content type, got %s", ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("expected Cache-Control no-store, got %s", cc)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{"<h2>postgres</h2>", "<h2>redis</h2>", "connection refused", "p99", `&#34;open&#34;: 4`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+}
+
+func TestStatusPageFormatQuery(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/admin/status?format=json", nil)
+	r.Header.Set("Accept", "text/html")
+
+	NewStatusPage(nil).ServeHTTP(w, r)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON content type, got %s", ct)
+	}
+}
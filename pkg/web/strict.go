@@ -0,0 +1,27 @@
+package web
+
+import (
+	"github.com/marcelofabianov/config"
+	"github.com/spf13/viper"
+)
+
+// ErrUnknownEnv is returned by LoadConfigStrict for WEB_* variables that
+// match no setting.
+var ErrUnknownEnv = config.ErrUnknownEnv
+
+// LoadConfigStrict is LoadConfig, but fails with ErrUnknownEnv when the
+// environment or the .env file holds a WEB_* variable that no setting
+// reads (e.g. WEB_HTTP_PORTT), instead of silently falling back to the default.
+func LoadConfigStrict() (*Config, error) {
+	c, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	// config.Load has exported the .env file, so the environment holds it.
+	defaults := viper.New()
+	setDefaults(defaults)
+	if err := config.CheckUnknownEnv("WEB", defaults, keysWithoutDefault...); err != nil {
+		return nil, err
+	}
+	return LoadConfigFrom(c)
+}
@@ -0,0 +1,43 @@
+package web_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marcelofabianov/web"
+)
+
+func TestLoadConfigStrict(t *testing.T) {
+	t.Run("accepts known variables", func(t *testing.T) {
+		t.Setenv("WEB_HTTP_PORT", "8081")
+
+		if _, err := web.LoadConfigStrict(); err != nil {
+			t.Fatalf("LoadConfigStrict() error = %v", err)
+		}
+	})
+
+	t.Run("accepts listen addresses", func(t *testing.T) {
+		t.Setenv("WEB_HTTP_LISTEN", ":8080,unix:/tmp/x.sock")
+
+		cfg, err := web.LoadConfigStrict()
+		if err != nil {
+			t.Fatalf("LoadConfigStrict() error = %v", err)
+		}
+		if len(cfg.HTTP.Listen.Addresses) != 2 {
+			t.Errorf("expected 2 listen addresses, got %v", cfg.HTTP.Listen.Addresses)
+		}
+	})
+
+	t.Run("rejects misspelled variables", func(t *testing.T) {
+		t.Setenv("WEB_HTTP_PORTT", "8081")
+
+		_, err := web.LoadConfigStrict()
+		if !errors.Is(err, web.ErrUnknownEnv) {
+			t.Fatalf("expected ErrUnknownEnv, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "did you mean WEB_HTTP_PORT?") {
+			t.Errorf("expected a suggestion in %q", err.Error())
+		}
+	})
+}
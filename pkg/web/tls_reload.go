@@ -0,0 +1,151 @@
+package web
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DefaultTLSReloadInterval is how often the certificate files are checked
+// for changes. Kubernetes propagates updated secrets to mounted volumes in
+// about a minute, so checking more often gains little.
+const DefaultTLSReloadInterval = time.Minute
+
+// ErrTLSDisabled is returned by ReloadTLS when the server does not serve
+// HTTPS.
+var ErrTLSDisabled = fault.New("TLS is not enabled", fault.WithCode(fault.Invalid))
+
+// certReloader serves the certificate through tls.Config.GetCertificate and
+// swaps it when the files change. A failed load keeps the previous
+// certificate, so a half-written rotation never takes the server down.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+
+	mu    sync.Mutex
+	stamp [2]fileStamp
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// fileStamp identifies a version of a file. Stat follows symlinks, so the
+// atomic ..data swap done by the kubelet shows up as a change too.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newCertReloader(certFile, keyFile string) *certReloader {
+	return &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+func (r *certReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to load TLS certificate",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("cert_file", r.certFile),
+			fault.WithContext("key_file", r.keyFile),
+		)
+	}
+
+	r.cert.Store(&cert)
+	r.stamp = stamp
+	return &cert, nil
+}
+
+func (r *certReloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := r.stat()
+	return err == nil && stamp != r.stamp
+}
+
+func (r *certReloader) stat() ([2]fileStamp, error) {
+	var stamp [2]fileStamp
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return stamp, fault.Wrap(err, "failed to read TLS certificate",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("file", name),
+			)
+		}
+		stamp[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamp, nil
+}
+
+// watch reloads the certificate whenever the files change, until close.
+func (r *certReloader) watch(interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if cert, err := r.load(); err != nil {
+			logger.Error("TLS certificate reload failed, keeping the current one", "error", err)
+		} else {
+			logReloaded(logger, cert)
+		}
+	}
+}
+
+func (r *certReloader) close() {
+	r.stopOnce.Do(func() { close(r.stop) })
+}
+
+func logReloaded(logger *slog.Logger, cert *tls.Certificate) {
+	attrs := []any{}
+	if cert.Leaf != nil {
+		attrs = append(attrs, "subject", cert.Leaf.Subject.String(), "not_after", cert.Leaf.NotAfter)
+	}
+	logger.Info("TLS certificate reloaded", attrs...)
+}
+
+// ReloadTLS reads the certificate files again and serves the new
+// certificate to subsequent handshakes; established connections keep the
+// old one. On error the current certificate stays in use. Call it from a
+// SIGHUP handler or an admin endpoint when polling is disabled.
+func (s *Server) ReloadTLS() error {
+	if s.certs == nil {
+		return ErrTLSDisabled
+	}
+	cert, err := s.certs.load()
+	if err != nil {
+		s.logger.Error("TLS certificate reload failed, keeping the current one", "error", err)
+		return err
+	}
+	logReloaded(s.logger, cert)
+	return nil
+}
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServerTLSReload(t *testing.T) {
+	start := func(t *testing.T, interval time.Duration) (*Server, string, string) {
+		t.Helper()
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+		writeCert(t, certFile, keyFile, "first")
+
+		srv := NewServer(&Config{HTTP: HTTPConfig{
+			Host: "127.0.0.1",
+			TLS:  TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile, ReloadInterval: interval},
+		}}, nil, http.NotFoundHandler())
+		go func() { _ = srv.Start() }()
+		t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+		return srv, waitBound(t, srv), certFile
+	}
+
+	t.Run("picks up rotated files", func(t *testing.T) {
+		_, addr, certFile := start(t, 10*time.Millisecond)
+		if got := servedCN(t, addr); got != "first" {
+			t.Fatalf("expected first certificate, got %q", got)
+		}
+
+		// Make sure the modification time moves on coarse filesystems.
+		time.Sleep(20 * time.Millisecond)
+		writeCert(t, certFile, filepath.Join(filepath.Dir(certFile), "tls.key"), "second")
+
+		deadline := time.Now().Add(2 * time.Second)
+		for servedCN(t, addr) != "second" {
+			if time.Now().After(deadline) {
+				t.Fatal("rotated certificate was not served")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("ReloadTLS keeps the current certificate on error", func(t *testing.T) {
+		srv, addr, certFile := start(t, 0)
+
+		if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := srv.ReloadTLS(); err == nil {
+			t.Error("expected reload error")
+		}
+		if got := servedCN(t, addr); got != "first" {
+			t.Errorf("expected first certificate, got %q", got)
+		}
+
+		writeCert(t, certFile, filepath.Join(filepath.Dir(certFile), "tls.key"), "second")
+		if err := srv.ReloadTLS(); err != nil {
+			t.Fatal(err)
+		}
+		if got := servedCN(t, addr); got != "second" {
+			t.Errorf("expected second certificate, got %q", got)
+		}
+	})
+
+	t.Run("ReloadTLS without TLS", func(t *testing.T) {
+		srv := NewServer(&Config{}, nil, http.NotFoundHandler())
+		if err := srv.ReloadTLS(); !errors.Is(err, ErrTLSDisabled) {
+			t.Errorf("expected ErrTLSDisabled, got %v", err)
+		}
+	})
+}
+
+func servedCN(t *testing.T, addr string) string {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func writeCert(t *testing.T, certFile, keyFile, cn string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
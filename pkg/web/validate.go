@@ -0,0 +1,125 @@
+package web
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// ErrInvalidConfig is returned by LoadConfig when the settings contradict
+// each other or cannot work, so a service fails at startup instead of on
+// its first request or its first TLS handshake.
+var ErrInvalidConfig = fault.New(
+	"invalid web configuration",
+	fault.WithCode(fault.Invalid),
+)
+
+// Validate checks cfg and reports every problem at once, in the "problems"
+// context of an ErrInvalidConfig. LoadConfig calls it; call it again after
+// changing a loaded Config by hand.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(problem string) {
+		problems = append(problems, problem)
+	}
+
+	h := c.HTTP
+	if !validPort(h.Port) {
+		add("WEB_HTTP_PORT must be between 0 and 65535, got " + strconv.Itoa(h.Port))
+	}
+	for _, raw := range h.Listen.Addresses {
+		if _, _, err := parseListenAddress(raw); err != nil {
+			add("WEB_HTTP_LISTEN entry " + strconv.Quote(raw) + " is not a host:port, tcp:// or unix:// address")
+		}
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"WEB_HTTP_READ_TIMEOUT", h.ReadTimeout},
+		{"WEB_HTTP_READ_HEADER_TIMEOUT", h.ReadHeaderTimeout},
+		{"WEB_HTTP_WRITE_TIMEOUT", h.WriteTimeout},
+		{"WEB_HTTP_IDLE_TIMEOUT", h.IdleTimeout},
+		{"WEB_HTTP_SHUTDOWN_TIMEOUT", h.ShutdownTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			add(timeout.name + " must be positive")
+		}
+	}
+	if h.DrainDelay < 0 {
+		add("WEB_HTTP_DRAIN_DELAY must not be negative")
+	}
+
+	if h.TLS.Enabled {
+		if h.TLS.CertFile == "" {
+			add("WEB_HTTP_TLS_CERT_FILE is required when TLS is enabled")
+		}
+		if h.TLS.KeyFile == "" {
+			add("WEB_HTTP_TLS_KEY_FILE is required when TLS is enabled")
+		}
+		if _, err := h.TLS.clientAuth(); err != nil {
+			add("WEB_HTTP_TLS_CLIENT_AUTH: " + fault.ToResponse(err).Message)
+		}
+	}
+
+	if !validPort(h.Admin.Port) {
+		add("WEB_HTTP_ADMIN_PORT must be between 0 and 65535, got " + strconv.Itoa(h.Admin.Port))
+	} else if h.Admin.Port != 0 && h.Admin.Port == h.Port &&
+		(h.Admin.Host == "" || hostsOverlap(h.Admin.Host, h.Host)) && len(h.Listen.Addresses) == 0 {
+		add("WEB_HTTP_ADMIN_PORT must differ from WEB_HTTP_PORT")
+	}
+
+	if h.RateLimit.Enabled {
+		if h.RateLimit.RequestsPerSecond <= 0 {
+			add("WEB_HTTP_RATE_LIMIT_REQUESTS_PER_SECOND must be positive when rate limiting is enabled")
+		}
+		if h.RateLimit.Burst <= 0 {
+			add("WEB_HTTP_RATE_LIMIT_BURST must be positive when rate limiting is enabled")
+		}
+	}
+
+	if h.CSRF.Enabled && h.CSRF.Secret == "" {
+		add("WEB_HTTP_CSRF_SECRET is required when CSRF protection is enabled")
+	}
+
+	if h.CORS.Enabled && h.CORS.AllowCredentials {
+		for _, origin := range h.CORS.AllowedOrigins {
+			if origin == "*" {
+				add("WEB_HTTP_CORS_ALLOW_CREDENTIALS cannot be combined with the wildcard origin; list the origins explicitly")
+				break
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fault.Wrap(ErrInvalidConfig, strings.Join(problems, "; "),
+		fault.WithCode(fault.Invalid),
+		fault.WithContext("problems", problems),
+	)
+}
+
+// hostsOverlap reports whether listeners on hosts a and b contend for the
+// same ports. An empty or unspecified host binds every interface.
+func hostsOverlap(a, b string) bool {
+	return a == b || unspecifiedHost(a) || unspecifiedHost(b)
+}
+
+func unspecifiedHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// validPort accepts 0, which lets the OS pick a free port.
+func validPort(port int) bool {
+	return port >= 0 && port <= 65535
+}
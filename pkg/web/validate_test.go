@@ -0,0 +1,133 @@
+package web
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	valid := func(t *testing.T) *Config {
+		t.Helper()
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("expected defaults to validate, got %v", err)
+		}
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(cfg *Config)
+		problem string
+	}{
+		{
+			name:    "port out of range",
+			mutate:  func(cfg *Config) { cfg.HTTP.Port = 70000 },
+			problem: "WEB_HTTP_PORT must be between 0 and 65535",
+		},
+		{
+			name:    "bad listen address",
+			mutate:  func(cfg *Config) { cfg.HTTP.Listen.Addresses = []string{"udp://:53"} },
+			problem: `WEB_HTTP_LISTEN entry "udp://:53"`,
+		},
+		{
+			name:    "zero write timeout",
+			mutate:  func(cfg *Config) { cfg.HTTP.WriteTimeout = 0 },
+			problem: "WEB_HTTP_WRITE_TIMEOUT must be positive",
+		},
+		{
+			name:    "TLS without key",
+			mutate:  func(cfg *Config) { cfg.HTTP.TLS = TLSConfig{Enabled: true, CertFile: "cert.pem"} },
+			problem: "WEB_HTTP_TLS_KEY_FILE is required",
+		},
+		{
+			name: "unknown client auth",
+			mutate: func(cfg *Config) {
+				cfg.HTTP.TLS = TLSConfig{Enabled: true, CertFile: "cert.pem", KeyFile: "key.pem", ClientAuth: "maybe"}
+			},
+			problem: "WEB_HTTP_TLS_CLIENT_AUTH",
+		},
+		{
+			name:    "admin on the public port",
+			mutate:  func(cfg *Config) { cfg.HTTP.Admin.Port = cfg.HTTP.Port },
+			problem: "WEB_HTTP_ADMIN_PORT must differ",
+		},
+		{
+			name: "admin on the public port of a specific host",
+			mutate: func(cfg *Config) {
+				cfg.HTTP.Host = "10.0.0.5"
+				cfg.HTTP.Admin = AdminConfig{Host: "0.0.0.0", Port: cfg.HTTP.Port}
+			},
+			problem: "WEB_HTTP_ADMIN_PORT must differ",
+		},
+		{
+			name: "rate limit without requests per second",
+			mutate: func(cfg *Config) {
+				cfg.HTTP.RateLimit.Enabled = true
+				cfg.HTTP.RateLimit.RequestsPerSecond = 0
+			},
+			problem: "WEB_HTTP_RATE_LIMIT_REQUESTS_PER_SECOND must be positive",
+		},
+		{
+			name: "rate limit with negative burst",
+			mutate: func(cfg *Config) {
+				cfg.HTTP.RateLimit.Enabled = true
+				cfg.HTTP.RateLimit.Burst = -1
+			},
+			problem: "WEB_HTTP_RATE_LIMIT_BURST must be positive",
+		},
+		{
+			name:    "CSRF without secret",
+			mutate:  func(cfg *Config) { cfg.HTTP.CSRF.Enabled = true },
+			problem: "WEB_HTTP_CSRF_SECRET is required",
+		},
+		{
+			name: "credentials with wildcard origin",
+			mutate: func(cfg *Config) {
+				cfg.HTTP.CORS.AllowedOrigins = []string{"*"}
+				cfg.HTTP.CORS.AllowCredentials = true
+			},
+			problem: "WEB_HTTP_CORS_ALLOW_CREDENTIALS cannot be combined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid(t)
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("expected ErrInvalidConfig, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("expected %q in %q", tt.problem, err.Error())
+			}
+		})
+	}
+
+	t.Run("credentials with listed origins", func(t *testing.T) {
+		cfg := valid(t)
+		cfg.HTTP.CORS.AllowedOrigins = []string{"https://app.example.com", "https://*.example.com"}
+		cfg.HTTP.CORS.AllowCredentials = true
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestLoadConfigFailsFast(t *testing.T) {
+	t.Setenv("WEB_HTTP_TLS_ENABLED", "true")
+	t.Setenv("WEB_HTTP_READ_TIMEOUT", "0s")
+
+	_, err := LoadConfig()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	for _, problem := range []string{"WEB_HTTP_TLS_CERT_FILE", "WEB_HTTP_TLS_KEY_FILE", "WEB_HTTP_READ_TIMEOUT"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected every problem reported, missing %s in %q", problem, err.Error())
+		}
+	}
+}
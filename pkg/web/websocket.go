@@ -0,0 +1,492 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/marcelofabianov/fault"
+)
+
+// WebSocket defaults applied by UpgradeWebSocket to zero options. Pings go
+// out well within PongWait so an idle but healthy peer is never dropped.
+const (
+	DefaultWebSocketReadLimit    = 64 << 10
+	DefaultWebSocketSendQueue    = 64
+	DefaultWebSocketPingInterval = 30 * time.Second
+	DefaultWebSocketPongWait     = 60 * time.Second
+	DefaultWebSocketWriteWait    = 10 * time.Second
+)
+
+var (
+	// ErrWebSocketClosed is returned when sending to a closed connection or
+	// adding one to a hub that is shutting down.
+	ErrWebSocketClosed = fault.New(
+		"websocket connection closed",
+		fault.WithCode(fault.Internal),
+	)
+
+	// ErrWebSocketQueueFull is returned by Send when the peer does not keep
+	// up with its send queue; the connection is closed, so one slow client
+	// cannot hold back a broadcast.
+	ErrWebSocketQueueFull = fault.New(
+		"websocket send queue full",
+		fault.WithCode(fault.Internal),
+	)
+)
+
+// WebSocketOptions configures UpgradeWebSocket. AllowedOrigins lists the
+// accepted Origin headers ("*" accepts any); empty accepts only the
+// request's own host, which is what browsers on the same site send.
+type WebSocketOptions struct {
+	AllowedOrigins []string
+	Subprotocols   []string
+	ReadLimit      int64
+	SendQueue      int
+	PingInterval   time.Duration
+	PongWait       time.Duration
+	WriteWait      time.Duration
+}
+
+func (o WebSocketOptions) withDefaults() WebSocketOptions {
+	if o.ReadLimit <= 0 {
+		o.ReadLimit = DefaultWebSocketReadLimit
+	}
+	if o.SendQueue <= 0 {
+		o.SendQueue = DefaultWebSocketSendQueue
+	}
+	if o.PingInterval <= 0 {
+		o.PingInterval = DefaultWebSocketPingInterval
+	}
+	if o.PongWait <= 0 {
+		o.PongWait = DefaultWebSocketPongWait
+	}
+	if o.WriteWait <= 0 {
+		o.WriteWait = DefaultWebSocketWriteWait
+	}
+	return o
+}
+
+func (o WebSocketOptions) checkOrigin() func(r *http.Request) bool {
+	if len(o.AllowedOrigins) == 0 {
+		// gorilla/websocket compares Origin with Host.
+		return nil
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, allowed := range o.AllowedOrigins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type wsFrame struct {
+	kind int
+	data []byte
+}
+
+// WebSocketConn is an upgraded connection. Writes go through a buffered
+// queue drained by a single goroutine, so Send is safe for concurrent use
+// and never blocks on the network.
+type WebSocketConn struct {
+	conn *websocket.Conn
+	opts WebSocketOptions
+	send chan wsFrame
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+
+	mu      sync.Mutex
+	onClose []func()
+}
+
+// UpgradeWebSocket upgrades the request and starts the connection's write
+// loop, which also sends the pings. On failure the error response has
+// already been written. The handler must then call Listen, which processes
+// incoming messages and control frames until the connection ends; the
+// connection is closed when the handler returns.
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request, opts WebSocketOptions) (*WebSocketConn, error) {
+	opts = opts.withDefaults()
+
+	upgrader := websocket.Upgrader{
+		Subprotocols: opts.Subprotocols,
+		CheckOrigin:  opts.checkOrigin(),
+		Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+			code := fault.Invalid
+			if status == http.StatusForbidden {
+				code = fault.Forbidden
+			}
+			Error(w, r, fault.Wrap(reason, "websocket upgrade failed", fault.WithCode(code)))
+		},
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, fault.Wrap(err, "websocket upgrade failed", fault.WithCode(fault.Invalid))
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	c := &WebSocketConn{
+		conn:      conn,
+		opts:      opts,
+		send:      make(chan wsFrame, opts.SendQueue),
+		ctx:       ctx,
+		cancel:    cancel,
+		closed:    make(chan struct{}),
+		closeCode: websocket.CloseNormalClosure,
+	}
+	go c.writeLoop()
+	return c, nil
+}
+
+// Subprotocol returns the negotiated subprotocol.
+func (c *WebSocketConn) Subprotocol() string {
+	return c.conn.Subprotocol()
+}
+
+// Context is cancelled when the connection starts closing.
+func (c *WebSocketConn) Context() context.Context {
+	return c.ctx
+}
+
+// Done is closed once the connection is closed.
+func (c *WebSocketConn) Done() <-chan struct{} {
+	return c.closed
+}
+
+// Send queues v: strings as text frames, []byte as binary frames, anything
+// else as JSON text frames.
+func (c *WebSocketConn) Send(v any) error {
+	frame, err := wsEncode(v)
+	if err != nil {
+		return err
+	}
+	return c.enqueue(frame)
+}
+
+func (c *WebSocketConn) enqueue(frame wsFrame) error {
+	if c.ctx.Err() != nil {
+		return ErrWebSocketClosed
+	}
+	select {
+	case c.send <- frame:
+		return nil
+	case <-c.ctx.Done():
+		return ErrWebSocketClosed
+	default:
+		c.Close(websocket.ClosePolicyViolation, "send queue full")
+		return ErrWebSocketQueueFull
+	}
+}
+
+// Listen reads messages and passes them to fn until the peer closes the
+// connection, the pong deadline passes or the connection is closed. An
+// error from fn closes the connection with 1011 and is returned; a nil fn
+// discards messages, for push-only connections. Listen must be called from
+// one goroutine only.
+func (c *WebSocketConn) Listen(fn func(ctx context.Context, data []byte) error) error {
+	c.conn.SetReadLimit(c.opts.ReadLimit)
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.opts.PongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(c.opts.PongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.Close(websocket.CloseNormalClosure, "")
+			<-c.closed
+			return nil
+		}
+		if fn == nil {
+			continue
+		}
+		if err := fn(c.ctx, data); err != nil {
+			c.Close(websocket.CloseInternalServerErr, "")
+			<-c.closed
+			return err
+		}
+	}
+}
+
+// Close sends a close frame with code and reason, after the messages
+// already queued, and closes the connection. It returns without waiting;
+// see Done.
+func (c *WebSocketConn) Close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = reason
+		c.cancel()
+	})
+}
+
+// onClosed registers fn to run once the connection is closed, immediately
+// if it already is.
+func (c *WebSocketConn) onClosed(fn func()) {
+	c.mu.Lock()
+	select {
+	case <-c.closed:
+		c.mu.Unlock()
+		fn()
+		return
+	default:
+	}
+	c.onClose = append(c.onClose, fn)
+	c.mu.Unlock()
+}
+
+func (c *WebSocketConn) writeLoop() {
+	ticker := time.NewTicker(c.opts.PingInterval)
+	defer ticker.Stop()
+	defer c.finish()
+
+	for {
+		select {
+		case frame := <-c.send:
+			if !c.write(frame.kind, frame.data) {
+				return
+			}
+		case <-ticker.C:
+			if !c.write(websocket.PingMessage, nil) {
+				return
+			}
+		case <-c.ctx.Done():
+			// Claim closeOnce so the close code cannot change under us when
+			// the request context ended first.
+			c.closeOnce.Do(func() {})
+			c.flush()
+			message := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+			_ = c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(c.opts.WriteWait))
+			return
+		}
+	}
+}
+
+// flush writes the frames queued before Close.
+func (c *WebSocketConn) flush() {
+	for {
+		select {
+		case frame := <-c.send:
+			if !c.write(frame.kind, frame.data) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *WebSocketConn) write(kind int, data []byte) bool {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.opts.WriteWait))
+	if err := c.conn.WriteMessage(kind, data); err != nil {
+		c.Close(websocket.CloseAbnormalClosure, "")
+		return false
+	}
+	return true
+}
+
+func (c *WebSocketConn) finish() {
+	c.cancel()
+	_ = c.conn.Close()
+
+	c.mu.Lock()
+	close(c.closed)
+	callbacks := c.onClose
+	c.onClose = nil
+	c.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+func wsEncode(v any) (wsFrame, error) {
+	switch data := v.(type) {
+	case string:
+		return wsFrame{kind: websocket.TextMessage, data: []byte(data)}, nil
+	case []byte:
+		return wsFrame{kind: websocket.BinaryMessage, data: data}, nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return wsFrame{}, fault.Wrap(err, "failed to encode websocket message", fault.WithCode(fault.Internal))
+	}
+	return wsFrame{kind: websocket.TextMessage, data: encoded}, nil
+}
+
+// WebSocketHub tracks connections and the rooms they joined, for
+// broadcasts. Closed connections leave the hub on their own.
+type WebSocketHub struct {
+	mu      sync.RWMutex
+	conns   map[*WebSocketConn]map[string]struct{}
+	rooms   map[string]map[*WebSocketConn]struct{}
+	closing bool
+}
+
+// NewWebSocketHub returns an empty hub. Register hub.Shutdown with
+// WithShutdownHook so clients get a going-away close frame on shutdown;
+// http.Server.Shutdown does not wait for upgraded connections.
+func NewWebSocketHub() *WebSocketHub {
+	return &WebSocketHub{
+		conns: make(map[*WebSocketConn]map[string]struct{}),
+		rooms: make(map[string]map[*WebSocketConn]struct{}),
+	}
+}
+
+// Add registers c with the hub. During Shutdown, c is closed instead and
+// ErrWebSocketClosed returned.
+func (h *WebSocketHub) Add(c *WebSocketConn) error {
+	h.mu.Lock()
+	if h.closing {
+		h.mu.Unlock()
+		c.Close(websocket.CloseGoingAway, "server shutting down")
+		return ErrWebSocketClosed
+	}
+	if _, ok := h.conns[c]; !ok {
+		h.conns[c] = make(map[string]struct{})
+	}
+	h.mu.Unlock()
+
+	c.onClosed(func() { h.remove(c) })
+	return nil
+}
+
+// Join adds c to room; c must have been added to the hub.
+func (h *WebSocketHub) Join(c *WebSocketConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	joined, ok := h.conns[c]
+	if !ok {
+		return
+	}
+	joined[room] = struct{}{}
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*WebSocketConn]struct{})
+	}
+	h.rooms[room][c] = struct{}{}
+}
+
+// Leave removes c from room.
+func (h *WebSocketHub) Leave(c *WebSocketConn, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if joined, ok := h.conns[c]; ok {
+		delete(joined, room)
+	}
+	h.leave(c, room)
+}
+
+func (h *WebSocketHub) leave(c *WebSocketConn, room string) {
+	members := h.rooms[room]
+	delete(members, c)
+	if len(members) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+func (h *WebSocketHub) remove(c *WebSocketConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for room := range h.conns[c] {
+		h.leave(c, room)
+	}
+	delete(h.conns, c)
+}
+
+// Broadcast sends v to every connection and returns how many accepted it;
+// connections whose queue is full are closed.
+func (h *WebSocketHub) Broadcast(v any) (int, error) {
+	h.mu.RLock()
+	targets := make([]*WebSocketConn, 0, len(h.conns))
+	for c := range h.conns {
+		targets = append(targets, c)
+	}
+	h.mu.RUnlock()
+
+	return broadcast(targets, v)
+}
+
+// BroadcastRoom sends v to the connections in room.
+func (h *WebSocketHub) BroadcastRoom(room string, v any) (int, error) {
+	h.mu.RLock()
+	targets := make([]*WebSocketConn, 0, len(h.rooms[room]))
+	for c := range h.rooms[room] {
+		targets = append(targets, c)
+	}
+	h.mu.RUnlock()
+
+	return broadcast(targets, v)
+}
+
+func broadcast(targets []*WebSocketConn, v any) (int, error) {
+	frame, err := wsEncode(v)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, c := range targets {
+		if c.enqueue(frame) == nil {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// Len returns the number of connections in the hub.
+func (h *WebSocketHub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// RoomLen returns the number of connections in room.
+func (h *WebSocketHub) RoomLen(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room])
+}
+
+// Shutdown closes every connection with 1001 (going away) after its queued
+// messages and waits for them to close or ctx to end. Connections added
+// afterwards are rejected.
+func (h *WebSocketHub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	conns := make([]*WebSocketConn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close(websocket.CloseGoingAway, "server shutting down")
+	}
+	for _, c := range conns {
+		select {
+		case <-c.Done():
+		case <-ctx.Done():
+			return fault.Wrap(ctx.Err(), "websocket connections did not close in time",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("open", h.Len()),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newWebSocketServer(t *testing.T, hub *WebSocketHub, opts WebSocketOptions) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := UpgradeWebSocket(w, r, opts)
+		if err != nil {
+			return
+		}
+		if err := hub.Add(conn); err != nil {
+			return
+		}
+		if room := r.URL.Query().Get("room"); room != "" {
+			hub.Join(conn, room)
+		}
+		_ = conn.Listen(func(ctx context.Context, data []byte) error {
+			return conn.Send("echo: " + string(data))
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dialWebSocket(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/?"+query, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func waitHubLen(t *testing.T, hub *WebSocketHub, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.Len() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d connections, have %d", want, hub.Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocketEchoAndRooms(t *testing.T) {
+	hub := NewWebSocketHub()
+	srv := newWebSocketServer(t, hub, WebSocketOptions{})
+
+	golang := dialWebSocket(t, srv, "room=go")
+	rust := dialWebSocket(t, srv, "room=rust")
+	waitHubLen(t, hub, 2)
+
+	if err := golang.WriteMessage(websocket.TextMessage, []byte("hi")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, msg, err := golang.ReadMessage(); err != nil || string(msg) != "echo: hi" {
+		t.Fatalf("expected echo, got %q %v", msg, err)
+	}
+
+	if sent, err := hub.BroadcastRoom("go", map[string]string{"lesson": "generics"}); err != nil || sent != 1 {
+		t.Fatalf("expected one recipient, got %d %v", sent, err)
+	}
+	if _, msg, err := golang.ReadMessage(); err != nil || string(msg) != `{"lesson":"generics"}` {
+		t.Fatalf("expected room message, got %q %v", msg, err)
+	}
+
+	if sent, _ := hub.Broadcast("all"); sent != 2 {
+		t.Fatalf("expected two recipients, got %d", sent)
+	}
+	if _, msg, err := rust.ReadMessage(); err != nil || string(msg) != "all" {
+		t.Fatalf("expected broadcast, got %q %v", msg, err)
+	}
+
+	_ = rust.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	waitHubLen(t, hub, 1)
+	if hub.RoomLen("rust") != 0 {
+		t.Error("expected closed connection to leave its room")
+	}
+}
+
+func TestWebSocketHubShutdown(t *testing.T) {
+	hub := NewWebSocketHub()
+	srv := newWebSocketServer(t, hub, WebSocketOptions{})
+
+	client := dialWebSocket(t, srv, "")
+	waitHubLen(t, hub, 1)
+
+	if _, err := hub.Broadcast("bye soon"); err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := hub.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if _, msg, err := client.ReadMessage(); err != nil || string(msg) != "bye soon" {
+		t.Fatalf("expected queued message before close, got %q %v", msg, err)
+	}
+	_, _, err := client.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("expected going-away close, got %v", err)
+	}
+
+	late, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = late.Close() }()
+	_ = late.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := late.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("expected connections after shutdown to be turned away, got %v", err)
+	}
+}
+
+func TestWebSocketPing(t *testing.T) {
+	hub := NewWebSocketHub()
+	srv := newWebSocketServer(t, hub, WebSocketOptions{PingInterval: 20 * time.Millisecond})
+
+	client := dialWebSocket(t, srv, "")
+	pinged := make(chan struct{}, 1)
+	client.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("expected a ping")
+	}
+}
+
+func TestWebSocketRejectsForeignOrigin(t *testing.T) {
+	hub := NewWebSocketHub()
+	srv := newWebSocketServer(t, hub, WebSocketOptions{AllowedOrigins: []string{"https://app.example.com"}})
+
+	header := http.Header{"Origin": {"https://evil.example.com"}}
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header)
+	if err == nil {
+		t.Fatal("expected handshake to fail")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %v", resp)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestWebSocketSendAfterClose(t *testing.T) {
+	closed := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := UpgradeWebSocket(w, r, WebSocketOptions{})
+		if err != nil {
+			closed <- err
+			return
+		}
+		conn.Close(websocket.CloseNormalClosure, "")
+		<-conn.Done()
+		closed <- conn.Send("late")
+	}))
+	defer srv.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err := <-closed; !errors.Is(err, ErrWebSocketClosed) {
+		t.Errorf("expected ErrWebSocketClosed, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+#!/bin/bash
+# Prints the Subresource Integrity hashes of the Swagger UI assets loaded by
+# pkg/web, for SwaggerUICSSIntegrity and SwaggerUIBundleIntegrity in
+# pkg/web/openapi.go. Run it after bumping SwaggerUIVersion.
+set -euo pipefail
+cd "$(dirname "$0")/.."
+
+VERSION=$(sed -n 's/^const SwaggerUIVersion = "\(.*\)"$/\1/p' pkg/web/openapi.go)
+if [ -z "$VERSION" ]; then
+    echo "SwaggerUIVersion not found in pkg/web/openapi.go" >&2
+    exit 1
+fi
+
+sri() {
+    echo "sha384-$(curl -fsSL "https://unpkg.com/swagger-ui-dist@${VERSION}/$1" | openssl dgst -sha384 -binary | openssl base64 -A)"
+}
+
+echo "swagger-ui-dist ${VERSION}"
+echo "SwaggerUICSSIntegrity    = \"$(sri swagger-ui.css)\""
+echo "SwaggerUIBundleIntegrity = \"$(sri swagger-ui-bundle.js)\""
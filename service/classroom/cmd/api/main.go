@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 
-	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 
+	"github.com/marcelofabianov/config"
+	"github.com/marcelofabianov/devsecrets"
 	"github.com/marcelofabianov/web"
-	"github.com/marcelofabianov/web/middleware"
+)
+
+// version and commit are set at build time with
+// -ldflags "-X main.version=... -X main.commit=...".
+var (
+	version = "1.0.0"
+	commit  = ""
 )
 
 func main() {
@@ -17,39 +26,108 @@ func main() {
 		Level: slog.LevelInfo,
 	}))
 
-	cfg, err := web.LoadConfig()
+	if err := devsecrets.Load(); err != nil {
+		logger.Warn("dev secrets not loaded", "error", err)
+	}
+
+	// Loaded once: .env, config.yaml, CONFIG_PROFILE and secret references, for every package.
+	appCfg, err := config.Load(config.WithSecrets(config.NewSecrets()))
 	if err != nil {
 		logger.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
 
-	r := chi.NewRouter()
+	cfg, err := web.LoadConfigFrom(appCfg)
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+	logger = web.NewLogger(cfg)
 
-	r.Use(middleware.RequestID())
-	r.Use(middleware.RealIP())
-	r.Use(middleware.Recovery(logger))
-	r.Use(middleware.Logger(logger))
-	r.Use(chimiddleware.Compress(5))
+	drain := web.NewDrain(cfg.HTTP.DrainDelay, logger)
+
+	api := web.NewAPIRouter(cfg, logger, nil)
+	api.Use(chimiddleware.Compress(5))
+	r := api.Mux()
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		web.Success(w, r, http.StatusOK, map[string]string{
 			"service": "classroom",
-			"version": "1.0.0",
+			"version": version,
 			"status":  "running",
 		})
 	})
 
-	r.Get("/health", web.LivenessHandler)
-	r.Get("/health/ready", web.ReadinessHandler())
+	if err := web.MountDebug(r, cfg); err != nil {
+		logger.Error("failed to mount debug endpoints", "error", err)
+		os.Exit(1)
+	}
 
-	logger.Info("starting classroom service",
-		"port", cfg.HTTP.Port,
-		"service", "classroom",
-	)
+	health, err := web.NewHealthMetrics(nil)
+	if err != nil {
+		logger.Error("failed to register health metrics", "error", err)
+		os.Exit(1)
+	}
 
-	srv := web.NewServer(cfg, logger, r)
-	if err := srv.Start(); err != nil {
+	// Readiness fails until web.SetReady(true) below, once main has finished
+	// initializing; dependencies added later go through
+	// web.ExpectDependencies and web.MarkDependencyReady. Each run of the
+	// checks is exported on /metrics.
+	readiness := drain.Readiness(web.DefaultStartup.Readiness(web.NewReadiness().WithMetrics(health)))
+	admin := web.NewAdminRouter(web.AdminRoutes{
+		Readiness: readiness,
+		PreStop:   drain.PreStopHandler(),
+		Startup:   http.HandlerFunc(web.StartupHandler),
+		Routes:    api.RoutesHandler(),
+	})
+	// Probes and metrics move to the admin listener when WEB_HTTP_ADMIN_PORT is set.
+	// /admin/prestop is only served there; without it Shutdown drains on SIGTERM.
+	probes := http.Handler(admin)
+	if cfg.HTTP.Admin.Port == 0 {
+		r.Get("/health", web.LivenessHandler)
+		r.Method(http.MethodGet, "/health/ready", readiness)
+		r.Get("/health/startup", web.StartupHandler)
+		r.Method(http.MethodGet, "/metrics", web.MetricsHandler(nil))
+		probes = r
+	}
+
+	web.SetReady(true)
+
+	if len(os.Args) > 1 && os.Args[1] == "smoke" {
+		smoke(logger, r, probes)
+		return
+	}
+
+	web.LogStartup(logger, web.ServiceInfo{
+		Name:    "classroom",
+		Version: version,
+		Commit:  commit,
+	}, cfg)
+
+	srv := web.NewServer(cfg, logger, r, web.WithDrain(drain), web.WithAdmin(admin))
+	if err := srv.Run(context.Background()); err != nil {
 		logger.Error("server error", "error", err)
 		os.Exit(1)
 	}
 }
+
+// smoke sends the health probes through probes and one request per router
+// group through r, in-process and without opening a port, and exits
+// non-zero on failure. Run it as "api smoke" for container health gates and
+// post-deploy checks.
+func smoke(logger *slog.Logger, r, probes http.Handler) {
+	probeChecks := web.DefaultSmokeChecks()
+	checks := []web.SmokeCheck{
+		{Path: "/"},
+	}
+
+	err := errors.Join(
+		web.Smoke(context.Background(), probes, probeChecks...),
+		web.Smoke(context.Background(), r, checks...),
+	)
+	if err != nil {
+		logger.Error("smoke test failed", "service", "classroom", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("smoke test passed", "service", "classroom", "checks", len(probeChecks)+len(checks))
+}